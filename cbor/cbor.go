@@ -0,0 +1,609 @@
+// Package cbor encodes ckb core types in CBOR, for stores and IPLD
+// pipelines built on it.
+//
+// Structs are maps keyed by their json field names, so the layout follows
+// the rpc json form:
+//
+//	Hashes, proposal short ids and bytes are byte strings.
+//	Uint32 and Uint64 are unsigned integers.
+//	Uint128 and Uint256 are 16 and 32 bytes little-endian byte strings,
+//	like molecule, since dag-cbor has no bignum.
+//	Nil pointers and nil slices are null.
+//
+// Encoding is deterministic, map keys are sorted by length then bytes and
+// indefinite lengths are never used, so it is valid dag-cbor.
+package cbor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// CBOR major types
+const (
+	majorUint   = 0
+	majorNegInt = 1
+	majorBytes  = 2
+	majorText   = 3
+	majorArray  = 4
+	majorMap    = 5
+	majorSimple = 7
+)
+
+// CBOR simple values
+const (
+	simpleFalse = 20
+	simpleTrue  = 21
+	simpleNull  = 22
+)
+
+// maxDepth nesting limit when decoding, against stack exhaustion
+const maxDepth = 64
+
+var (
+	uint128Type = reflect.TypeOf(types.Uint128{})
+	uint256Type = reflect.TypeOf(types.Uint256{})
+)
+
+// Marshal encode value in CBOR
+func Marshal(v interface{}) ([]byte, error) {
+	b := new(bytes.Buffer)
+
+	err := encode(b, reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// Unmarshal decode CBOR into value pointed by v, unknown map keys are
+// skipped
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("invalid unmarshal target, should be non-nil pointer")
+	}
+
+	d := &decoder{data: data}
+
+	err := d.decode(rv.Elem(), 0)
+	if err != nil {
+		return err
+	}
+
+	if len(d.data) != 0 {
+		return fmt.Errorf("invalid cbor, %d trailing bytes", len(d.data))
+	}
+
+	return nil
+}
+
+func writeHead(b *bytes.Buffer, major byte, n uint64) {
+	m := major << 5
+
+	switch {
+	case n < 24:
+		b.WriteByte(m | byte(n))
+	case n <= 0xff:
+		b.WriteByte(m | 24)
+		b.WriteByte(byte(n))
+	case n <= 0xffff:
+		b.WriteByte(m | 25)
+		binary.Write(b, binary.BigEndian, uint16(n))
+	case n <= 0xffffffff:
+		b.WriteByte(m | 26)
+		binary.Write(b, binary.BigEndian, uint32(n))
+	default:
+		b.WriteByte(m | 27)
+		binary.Write(b, binary.BigEndian, n)
+	}
+}
+
+func writeBytes(b *bytes.Buffer, bs []byte) {
+	writeHead(b, majorBytes, uint64(len(bs)))
+	b.Write(bs)
+}
+
+// field json named struct field, embedded structs are flattened
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+func structFields(t reflect.Type) []field {
+	var fs []field
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		if f.Anonymous && tag == "" && f.Type.Kind() == reflect.Struct {
+			for _, ef := range structFields(f.Type) {
+				ef.index = append([]int{i}, ef.index...)
+				fs = append(fs, ef)
+			}
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		ff := field{name: f.Name, index: []int{i}}
+		if parts[0] != "" {
+			ff.name = parts[0]
+		}
+
+		for _, p := range parts[1:] {
+			ff.omitEmpty = ff.omitEmpty || p == "omitempty"
+		}
+
+		fs = append(fs, ff)
+	}
+
+	// dag-cbor key order, shorter keys first
+	sort.Slice(fs, func(i, j int) bool {
+		if len(fs[i].name) != len(fs[j].name) {
+			return len(fs[i].name) < len(fs[j].name)
+		}
+
+		return fs[i].name < fs[j].name
+	})
+
+	return fs
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return v.IsNil()
+	}
+
+	return false
+}
+
+func encode(b *bytes.Buffer, v reflect.Value) error {
+	if !v.IsValid() {
+		writeHead(b, majorSimple, simpleNull)
+		return nil
+	}
+
+	switch v.Type() {
+	case uint128Type:
+		u := v.Interface().(types.Uint128)
+		buf := make([]byte, 16)
+		binary.LittleEndian.PutUint64(buf[:8], u.Lo)
+		binary.LittleEndian.PutUint64(buf[8:], u.Hi)
+		writeBytes(b, buf)
+		return nil
+	case uint256Type:
+		u := v.Interface().(types.Uint256)
+		buf := make([]byte, 32)
+		for i := 0; i < 4; i++ {
+			binary.LittleEndian.PutUint64(buf[8*i:], u[i])
+		}
+		writeBytes(b, buf)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			writeHead(b, majorSimple, simpleNull)
+			return nil
+		}
+
+		return encode(b, v.Elem())
+	case reflect.Bool:
+		if v.Bool() {
+			writeHead(b, majorSimple, simpleTrue)
+		} else {
+			writeHead(b, majorSimple, simpleFalse)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		writeHead(b, majorUint, v.Uint())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := v.Int()
+		if n >= 0 {
+			writeHead(b, majorUint, uint64(n))
+		} else {
+			writeHead(b, majorNegInt, uint64(-(n + 1)))
+		}
+	case reflect.String:
+		writeHead(b, majorText, uint64(v.Len()))
+		b.WriteString(v.String())
+	case reflect.Slice:
+		if v.IsNil() {
+			writeHead(b, majorSimple, simpleNull)
+			return nil
+		}
+
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			writeBytes(b, v.Bytes())
+			return nil
+		}
+
+		return encodeArray(b, v)
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			buf := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(buf), v)
+			writeBytes(b, buf)
+			return nil
+		}
+
+		return encodeArray(b, v)
+	case reflect.Struct:
+		fs := structFields(v.Type())
+
+		var present []field
+		for _, f := range fs {
+			if !f.omitEmpty || !isEmpty(v.FieldByIndex(f.index)) {
+				present = append(present, f)
+			}
+		}
+
+		writeHead(b, majorMap, uint64(len(present)))
+		for _, f := range present {
+			writeHead(b, majorText, uint64(len(f.name)))
+			b.WriteString(f.name)
+
+			err := encode(b, v.FieldByIndex(f.index))
+			if err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported cbor type %s", v.Type())
+	}
+
+	return nil
+}
+
+func encodeArray(b *bytes.Buffer, v reflect.Value) error {
+	writeHead(b, majorArray, uint64(v.Len()))
+	for i := 0; i < v.Len(); i++ {
+		err := encode(b, v.Index(i))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type decoder struct {
+	data []byte
+}
+
+// head read item head, indefinite lengths are rejected
+func (d *decoder) head() (byte, uint64, error) {
+	if len(d.data) == 0 {
+		return 0, 0, fmt.Errorf("invalid cbor, unexpected end")
+	}
+
+	major, info := d.data[0]>>5, d.data[0]&0x1f
+	d.data = d.data[1:]
+
+	if info < 24 {
+		return major, uint64(info), nil
+	}
+
+	size := 0
+	switch info {
+	case 24:
+		size = 1
+	case 25:
+		size = 2
+	case 26:
+		size = 4
+	case 27:
+		size = 8
+	default:
+		return 0, 0, fmt.Errorf("invalid cbor, unsupported additional info %d", info)
+	}
+
+	if len(d.data) < size {
+		return 0, 0, fmt.Errorf("invalid cbor, unexpected end")
+	}
+
+	var n uint64
+	for _, c := range d.data[:size] {
+		n = n<<8 | uint64(c)
+	}
+	d.data = d.data[size:]
+
+	return major, n, nil
+}
+
+func (d *decoder) take(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)) {
+		return nil, fmt.Errorf("invalid cbor, length %d exceeds %d bytes", n, len(d.data))
+	}
+
+	b := d.data[:n]
+	d.data = d.data[n:]
+
+	return b, nil
+}
+
+// skip skip one item, for unknown map keys
+func (d *decoder) skip(depth int) error {
+	if depth > maxDepth {
+		return fmt.Errorf("invalid cbor, nested too deep")
+	}
+
+	major, n, err := d.head()
+	if err != nil {
+		return err
+	}
+
+	switch major {
+	case majorBytes, majorText:
+		_, err = d.take(n)
+	case majorArray:
+		for i := uint64(0); i < n && err == nil; i++ {
+			err = d.skip(depth + 1)
+		}
+	case majorMap:
+		for i := uint64(0); i < 2*n && err == nil; i++ {
+			err = d.skip(depth + 1)
+		}
+	case 6:
+		err = d.skip(depth + 1)
+	}
+
+	return err
+}
+
+func (d *decoder) isNull() bool {
+	return len(d.data) != 0 && d.data[0] == majorSimple<<5|simpleNull
+}
+
+func (d *decoder) expect(want byte, major byte, v reflect.Value) error {
+	if major != want {
+		return fmt.Errorf("invalid cbor, major type %d for %s", major, v.Type())
+	}
+
+	return nil
+}
+
+func (d *decoder) fixedBytes(v reflect.Value, n int) ([]byte, error) {
+	major, l, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.expect(majorBytes, major, v)
+	if err != nil {
+		return nil, err
+	}
+
+	if l != uint64(n) {
+		return nil, fmt.Errorf("invalid %s, should be %d bytes, got %d", v.Type(), n, l)
+	}
+
+	return d.take(l)
+}
+
+func (d *decoder) decode(v reflect.Value, depth int) error {
+	if depth > maxDepth {
+		return fmt.Errorf("invalid cbor, nested too deep")
+	}
+
+	switch v.Type() {
+	case uint128Type:
+		b, err := d.fixedBytes(v, 16)
+		if err != nil {
+			return err
+		}
+
+		v.Set(reflect.ValueOf(types.Uint128{
+			Lo: binary.LittleEndian.Uint64(b[:8]),
+			Hi: binary.LittleEndian.Uint64(b[8:]),
+		}))
+		return nil
+	case uint256Type:
+		b, err := d.fixedBytes(v, 32)
+		if err != nil {
+			return err
+		}
+
+		var u types.Uint256
+		for i := 0; i < 4; i++ {
+			u[i] = binary.LittleEndian.Uint64(b[8*i:])
+		}
+		v.Set(reflect.ValueOf(u))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if d.isNull() {
+			d.data = d.data[1:]
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+
+		e := reflect.New(v.Type().Elem())
+		err := d.decode(e.Elem(), depth+1)
+		if err != nil {
+			return err
+		}
+
+		v.Set(e)
+		return nil
+	case reflect.Slice:
+		if d.isNull() {
+			d.data = d.data[1:]
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b, err := d.fixedBytes(v, v.Len())
+			if err != nil {
+				return err
+			}
+
+			reflect.Copy(v, reflect.ValueOf(b))
+			return nil
+		}
+	}
+
+	major, n, err := d.head()
+	if err != nil {
+		return err
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if major != majorSimple || (n != simpleTrue && n != simpleFalse) {
+			return fmt.Errorf("invalid cbor, expect bool for %s", v.Type())
+		}
+
+		v.SetBool(n == simpleTrue)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		err = d.expect(majorUint, major, v)
+		if err != nil {
+			return err
+		}
+
+		if v.OverflowUint(n) {
+			return fmt.Errorf("invalid %s, %d overflow", v.Type(), n)
+		}
+		v.SetUint(n)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if major != majorUint && major != majorNegInt {
+			return fmt.Errorf("invalid cbor, major type %d for %s", major, v.Type())
+		}
+
+		if n > 1<<63-1 {
+			return fmt.Errorf("invalid %s, %d overflow", v.Type(), n)
+		}
+
+		i := int64(n)
+		if major == majorNegInt {
+			i = -i - 1
+		}
+
+		if v.OverflowInt(i) {
+			return fmt.Errorf("invalid %s, %d overflow", v.Type(), i)
+		}
+		v.SetInt(i)
+	case reflect.String:
+		err = d.expect(majorText, major, v)
+		if err != nil {
+			return err
+		}
+
+		b, err := d.take(n)
+		if err != nil {
+			return err
+		}
+
+		v.SetString(string(b))
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			err = d.expect(majorBytes, major, v)
+			if err != nil {
+				return err
+			}
+
+			b, err := d.take(n)
+			if err != nil {
+				return err
+			}
+
+			v.SetBytes(append(make([]byte, 0, len(b)), b...))
+			return nil
+		}
+
+		err = d.expect(majorArray, major, v)
+		if err != nil {
+			return err
+		}
+
+		// Every item takes at least one byte
+		if n > uint64(len(d.data)) {
+			return fmt.Errorf("invalid cbor, %d items exceed %d bytes", n, len(d.data))
+		}
+
+		s := reflect.MakeSlice(v.Type(), int(n), int(n))
+		for i := 0; i < int(n); i++ {
+			err = d.decode(s.Index(i), depth+1)
+			if err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+	case reflect.Array:
+		err = d.expect(majorArray, major, v)
+		if err != nil {
+			return err
+		}
+
+		if n != uint64(v.Len()) {
+			return fmt.Errorf("invalid %s, should have %d items, got %d", v.Type(), v.Len(), n)
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			err = d.decode(v.Index(i), depth+1)
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		err = d.expect(majorMap, major, v)
+		if err != nil {
+			return err
+		}
+
+		fs := map[string]field{}
+		for _, f := range structFields(v.Type()) {
+			fs[f.name] = f
+		}
+
+		for i := uint64(0); i < n; i++ {
+			kmajor, kn, err := d.head()
+			if err != nil {
+				return err
+			}
+
+			if kmajor != majorText {
+				return fmt.Errorf("invalid cbor, map key should be text for %s", v.Type())
+			}
+
+			k, err := d.take(kn)
+			if err != nil {
+				return err
+			}
+
+			f, ok := fs[string(k)]
+			if !ok {
+				err = d.skip(depth + 1)
+			} else {
+				err = d.decode(v.FieldByIndex(f.index), depth+1)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported cbor type %s", v.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package cbor
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+func TestOutPoint(t *testing.T) {
+	o := types.OutPoint{TxHash: types.Hash{1}, Index: 2}
+
+	got, err := Marshal(&o)
+	if err != nil {
+		t.Errorf("fail to marshal: %s\n", err)
+		return
+	}
+
+	// map(2), "index": 2, "tx_hash": bytes(32)
+	expect := "a265696e646578026774785f686173685820" + hex.EncodeToString(o.TxHash[:])
+	if hex.EncodeToString(got) != expect {
+		t.Errorf("mismatch result, expect %v, got %x", expect, got)
+		return
+	}
+
+	var back types.OutPoint
+	err = Unmarshal(got, &back)
+	if err != nil || back != o {
+		t.Errorf("mismatch result, expect %v, got %v %v", o, back, err)
+		return
+	}
+
+	for _, bad := range []string{"", "a1", "a265696e646578", "a265696e6465783b", "a1657831323334f6ff"} {
+		raw, _ := hex.DecodeString(bad)
+		err = Unmarshal(raw, &back)
+		if err == nil {
+			t.Errorf("should reject %s", bad)
+			return
+		}
+	}
+}
+
+func TestBlockView(t *testing.T) {
+	raw := `{
+	  "header": {"compact_target": "0x1e083126", "dao": "0xb5a3e047474401001bc476b9ee573000c0c387962a38000000febffacf030000", "epoch": "0x7080018000001", "extra_hash": "0x0000000000000000000000000000000000000000000000000000000000000000", "hash": "0xa5f5c85987a15de25661e5a214f2c1449cd803f071acc7999820f25246471f40", "nonce": "0x1234", "number": "0x400", "parent_hash": "0xae003585fa15309b30b31aed3dcf385e9472c3c3e93746a6c4540629a6a1ed2d", "proposals_hash": "0x0000000000000000000000000000000000000000000000000000000000000000", "timestamp": "0x5cd2b117", "transactions_root": "0xc47d5b78b3c4c4c853e2a32810818940d0ee403423bea9ec7b8e566d9595206c", "version": "0x0"},
+	  "proposals": ["0x0102030405060708090a"],
+	  "transactions": [{
+	    "cell_deps": [], "hash": "0x365698b50ca0da75dca2c87f9e7b563811d3b5813736b8cc62cc3b106faceb17", "header_deps": [],
+	    "inputs": [{"previous_output": {"index": "0xffffffff", "tx_hash": "0x0000000000000000000000000000000000000000000000000000000000000000"}, "since": "0x400"}],
+	    "outputs": [{"capacity": "0x18e64b61cf", "lock": {"code_hash": "0x28e83a1277d48add8e72fadaa9248559e1b632bab2bd60b27955ebc4c03800a5", "hash_type": "data", "args": "0x"}, "type": null}],
+	    "outputs_data": ["0x"], "version": "0x0", "witnesses": ["0x01"]
+	  }],
+	  "uncles": []
+	}`
+
+	var b types.BlockView
+	err := json.Unmarshal([]byte(raw), &b)
+	if err != nil {
+		t.Errorf("fail to unmarshal block json: %s\n", err)
+		return
+	}
+
+	data, err := Marshal(&b)
+	if err != nil {
+		t.Errorf("fail to marshal: %s\n", err)
+		return
+	}
+
+	var back types.BlockView
+	err = Unmarshal(data, &back)
+	if err != nil {
+		t.Errorf("fail to unmarshal: %s\n", err)
+		return
+	}
+
+	if !reflect.DeepEqual(&back, &b) {
+		t.Errorf("mismatch result, expect %+v, got %+v", b, back)
+		return
+	}
+}
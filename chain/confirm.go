@@ -0,0 +1,138 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// ConfirmationFetcher chain source of confirmation waiter
+type ConfirmationFetcher interface {
+	TxStatusFetcher
+	HeaderByNumberFetcher
+	GetTipHeader(ctx context.Context) (*types.HeaderView, error)
+}
+
+// ConfirmationProgress progress of transaction being confirmed
+type ConfirmationProgress struct {
+	Hash   types.Hash
+	Status types.TxStatusType
+	// Confirmations blocks on top of the committing block
+	Confirmations uint64
+	// Reorged committing block fell off canonical chain, waiting restarts
+	Reorged bool
+}
+
+// Confirmer wait for transactions to be buried under enough blocks
+type Confirmer struct {
+	f ConfirmationFetcher
+	// Interval between polls
+	Interval time.Duration
+	// OnProgress called when progress changes, may be nil
+	OnProgress func(ConfirmationProgress)
+}
+
+// NewConfirmer new confirmer polling every interval
+func NewConfirmer(f ConfirmationFetcher, interval time.Duration) *Confirmer {
+	return &Confirmer{f: f, Interval: interval}
+}
+
+// WaitForConfirmations wait until block committing transaction has n
+// descendants, use ctx deadline as timeout
+/*
+ * Committing block is checked to be canonical on every poll. If it is
+ * reorged out, progress is reported with Reorged and waiting restarts
+ * from the block transaction gets committed in again. Rejection is an
+ * error, unknown is waited on since node may not have received it yet.
+ */
+func (c *Confirmer) WaitForConfirmations(ctx context.Context, hash types.Hash, n uint64) (*types.TxStatus, error) {
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	var last *ConfirmationProgress
+	var committedIn *types.Hash
+
+	for {
+		status, p, err := c.progress(ctx, hash, committedIn)
+		if err != nil {
+			return nil, err
+		}
+
+		if status.Status == types.TxStatusRejected {
+			reason := ""
+			if status.Reason != nil {
+				reason = *status.Reason
+			}
+
+			return nil, fmt.Errorf("transaction %s rejected, %s", hash, reason)
+		}
+
+		committedIn = nil
+		if p.Status == types.TxStatusCommitted {
+			committedIn = status.BlockHash
+		}
+
+		if c.OnProgress != nil && (last == nil || *last != p) {
+			c.OnProgress(p)
+		}
+		last = &p
+
+		if p.Status == types.TxStatusCommitted && p.Confirmations >= n {
+			return status, nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for %d confirmations of %s, %s", n, hash, ctx.Err())
+		}
+	}
+}
+
+// progress fetch status and count confirmations, committedIn is the block
+// transaction was seen committed in at last poll
+func (c *Confirmer) progress(ctx context.Context, hash types.Hash, committedIn *types.Hash) (*types.TxStatus, ConfirmationProgress, error) {
+	p := ConfirmationProgress{Hash: hash}
+
+	status, err := c.f.GetTransactionStatus(ctx, hash)
+	if err != nil {
+		return nil, p, err
+	}
+	p.Status = status.Status
+
+	if status.Status != types.TxStatusCommitted {
+		p.Reorged = committedIn != nil
+		return status, p, nil
+	}
+
+	if status.BlockHash == nil || status.BlockNumber == nil {
+		return nil, p, fmt.Errorf("invalid committed status of %s, no block", hash)
+	}
+
+	p.Reorged = committedIn != nil && *committedIn != *status.BlockHash
+
+	block, err := c.f.GetHeaderByNumber(ctx, *status.BlockNumber)
+	if err != nil {
+		return nil, p, err
+	}
+
+	// Index may lag behind chain during reorg, wait for it to catch up
+	if block == nil || block.Hash != *status.BlockHash {
+		p.Status = types.TxStatusPending
+		p.Reorged = committedIn != nil
+		return status, p, nil
+	}
+
+	tip, err := c.f.GetTipHeader(ctx)
+	if err != nil {
+		return nil, p, err
+	}
+
+	if tip.Header.Number > *status.BlockNumber {
+		p.Confirmations = uint64(tip.Header.Number - *status.BlockNumber)
+	}
+
+	return status, p, nil
+}
@@ -0,0 +1,121 @@
+package chain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// fakeConfirmChain chain committing one transaction, polls call step
+// before answering so tests can grow or reorg chain
+type fakeConfirmChain struct {
+	fakeChain
+	status types.TxStatus
+	step   func(polls int)
+	polls  int
+}
+
+func (c *fakeConfirmChain) GetTransactionStatus(ctx context.Context, hash types.Hash) (*types.TxStatus, error) {
+	c.step(c.polls)
+	c.polls++
+
+	s := c.status
+	return &s, nil
+}
+
+func (c *fakeConfirmChain) GetTipHeader(ctx context.Context) (*types.HeaderView, error) {
+	return c.headers[len(c.headers)-1], nil
+}
+
+func (c *fakeConfirmChain) commitAt(number int) {
+	n := types.Uint64(number)
+	c.status = types.TxStatus{Status: types.TxStatusCommitted, BlockHash: &c.headers[number].Hash, BlockNumber: &n}
+}
+
+func TestWaitForConfirmations(t *testing.T) {
+	chain := new(fakeConfirmChain)
+	chain.extend(0, 3, 0)
+	chain.status = types.TxStatus{Status: types.TxStatusPending}
+
+	chain.step = func(polls int) {
+		switch polls {
+		case 1:
+			chain.extend(0, 4, 0)
+			chain.commitAt(3)
+		case 2:
+			// Reorg out committing block, new branch has no transaction
+			chain.extend(3, 2, 1)
+			chain.status = types.TxStatus{Status: types.TxStatusPending}
+		case 3:
+			chain.commitAt(4)
+		default:
+			chain.extend(len(chain.headers), 1, 1)
+		}
+	}
+
+	var progress []ConfirmationProgress
+	c := NewConfirmer(chain, time.Millisecond)
+	c.OnProgress = func(p ConfirmationProgress) {
+		progress = append(progress, p)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s, err := c.WaitForConfirmations(ctx, types.Hash{0x01}, 2)
+	if err != nil {
+		t.Errorf("fail to wait for confirmations: %s\n", err)
+		return
+	}
+
+	if *s.BlockNumber != 4 || len(chain.headers) != 7 {
+		t.Errorf("mismatch result, expect committed at 4 with tip 6, got %d tip %d", *s.BlockNumber, len(chain.headers)-1)
+		return
+	}
+
+	reorged := false
+	for _, p := range progress {
+		reorged = reorged || p.Reorged
+	}
+
+	if !reorged {
+		t.Errorf("expect reorg reported, got %v", progress)
+		return
+	}
+
+	last := progress[len(progress)-1]
+	if last.Status != types.TxStatusCommitted || last.Confirmations != 2 {
+		t.Errorf("mismatch result, expect 2 confirmations, got %v", last)
+		return
+	}
+}
+
+func TestWaitForConfirmationsRejected(t *testing.T) {
+	chain := new(fakeConfirmChain)
+	chain.extend(0, 1, 0)
+
+	reason := "Resolve: dead"
+	chain.status = types.TxStatus{Status: types.TxStatusRejected, Reason: &reason}
+	chain.step = func(int) {}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := NewConfirmer(chain, time.Millisecond).WaitForConfirmations(ctx, types.Hash{0x01}, 1)
+	if err == nil {
+		t.Errorf("expect error on rejected transaction")
+		return
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	chain.status = types.TxStatus{Status: types.TxStatusPending}
+	_, err = NewConfirmer(chain, time.Millisecond).WaitForConfirmations(ctx, types.Hash{0x01}, 1)
+	if err == nil {
+		t.Errorf("expect error on timeout")
+		return
+	}
+}
@@ -0,0 +1,8 @@
+// Package chain keeps track of a node's chain: headers stored for spv and
+// light clients, a follower turning polled tips into advance and rollback
+// events, trackers and waiters of transactions being committed and
+// confirmed, and watchers of committed transactions being reorged out.
+//
+// Components here hold state, files or polling loops. Package types
+// stays wire types only.
+package chain
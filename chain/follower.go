@@ -0,0 +1,110 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// HeaderByNumberFetcher fetch canonical header by number, usually backed
+// by get_header_by_number rpc, returns nil header past tip
+type HeaderByNumberFetcher interface {
+	GetHeaderByNumber(ctx context.Context, number types.Uint64) (*types.HeaderView, error)
+}
+
+// EventType chain follower event type
+type EventType int
+
+// Chain event types
+const (
+	Advance EventType = iota
+	Rollback
+)
+
+// Event chain follower event, advance to header or roll header back
+type Event struct {
+	Type   EventType
+	Header *types.HeaderView
+}
+
+// Follower follow canonical chain from checkpoint and report
+// reorganizations as rollbacks
+/*
+ * Follower keeps the last depth headers it advanced to. On each poll it
+ * checks its tip is still canonical, rolling back until it is, then
+ * advances while the next header links to its tip by parent hash.
+ */
+type Follower struct {
+	f       HeaderByNumberFetcher
+	depth   int
+	headers []*types.HeaderView
+}
+
+// NewFollower new follower resumed from checkpoint header, depth is
+// the deepest reorg it can roll back
+func NewFollower(f HeaderByNumberFetcher, checkpoint *types.HeaderView, depth int) *Follower {
+	if depth <= 0 {
+		depth = 1
+	}
+
+	return &Follower{f: f, depth: depth, headers: []*types.HeaderView{checkpoint}}
+}
+
+// Checkpoint current tip, persist it to resume later
+func (c *Follower) Checkpoint() *types.HeaderView {
+	return c.headers[len(c.headers)-1]
+}
+
+// Poll catch up with node, returns events in order they happened
+/*
+ * On fetch error, events applied so far are returned with it. On reorg
+ * deeper than depth, follower can not recover by itself, no event is
+ * returned and state is left as before poll, so caller can resume from
+ * an older checkpoint.
+ */
+func (c *Follower) Poll(ctx context.Context) ([]Event, error) {
+	var events []Event
+	saved := append([]*types.HeaderView{}, c.headers...)
+
+	for {
+		tip := c.Checkpoint()
+
+		remote, err := c.f.GetHeaderByNumber(ctx, tip.Header.Number)
+		if err != nil {
+			return events, err
+		}
+
+		if remote == nil || remote.Hash != tip.Hash {
+			if len(c.headers) == 1 {
+				c.headers = saved
+				return nil, fmt.Errorf("reorg deeper than %d blocks at %d", c.depth, tip.Header.Number)
+			}
+
+			c.headers = c.headers[:len(c.headers)-1]
+			events = append(events, Event{Type: Rollback, Header: tip})
+			continue
+		}
+
+		next, err := c.f.GetHeaderByNumber(ctx, tip.Header.Number+1)
+		if err != nil {
+			return events, err
+		}
+
+		if next == nil {
+			return events, nil
+		}
+
+		// Reorg between two fetches, check tip again
+		if next.Header.ParentHash != tip.Hash {
+			continue
+		}
+
+		c.headers = append(c.headers, next)
+		if len(c.headers) > c.depth+1 {
+			c.headers = c.headers[1:]
+		}
+
+		events = append(events, Event{Type: Advance, Header: next})
+	}
+}
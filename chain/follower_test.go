@@ -0,0 +1,98 @@
+package chain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// fakeChain canonical chain of headers by number
+type fakeChain struct {
+	headers []*types.HeaderView
+}
+
+func (c *fakeChain) GetHeaderByNumber(ctx context.Context, number types.Uint64) (*types.HeaderView, error) {
+	if int(number) >= len(c.headers) {
+		return nil, nil
+	}
+
+	return c.headers[number], nil
+}
+
+// extend append headers to chain from given number, nonce tells branches
+// apart
+func (c *fakeChain) extend(from int, count int, nonce uint64) {
+	c.headers = c.headers[:from]
+
+	for n := from; n < from+count; n++ {
+		h := types.Header{Number: types.Uint64(n), Nonce: types.NewUint128(nonce)}
+		if n > 0 {
+			h.ParentHash = c.headers[n-1].Hash
+		}
+
+		hash, _ := h.ComputeHash()
+		c.headers = append(c.headers, &types.HeaderView{Header: h, Hash: hash})
+	}
+}
+
+func TestFollower(t *testing.T) {
+	chain := new(fakeChain)
+	chain.extend(0, 5, 0)
+
+	f := NewFollower(chain, chain.headers[1], 3)
+
+	events, err := f.Poll(context.Background())
+	if err != nil || len(events) != 3 || f.Checkpoint().Hash != chain.headers[4].Hash {
+		t.Errorf("mismatch advance events, got %v %v", events, err)
+		return
+	}
+
+	// Replace 3 and 4, then grow to 6
+	chain.extend(3, 4, 1)
+
+	events, err = f.Poll(context.Background())
+	if err != nil {
+		t.Errorf("fail to poll: %s\n", err)
+		return
+	}
+
+	expect := []EventType{Rollback, Rollback, Advance, Advance, Advance, Advance}
+	if len(events) != len(expect) {
+		t.Errorf("mismatch events, expect %v, got %v", expect, events)
+		return
+	}
+
+	for i := range expect {
+		if events[i].Type != expect[i] {
+			t.Errorf("mismatch event %d, expect %v, got %v", i, expect[i], events[i].Type)
+			return
+		}
+	}
+
+	if events[0].Header.Header.Number != 4 || f.Checkpoint().Hash != chain.headers[6].Hash {
+		t.Errorf("mismatch rollback order or tip, got %v", events)
+		return
+	}
+
+	// Reorg deeper than kept headers
+	tip := f.Checkpoint()
+	chain.extend(1, 6, 2)
+	events, err = f.Poll(context.Background())
+	if err == nil {
+		t.Errorf("expect error on deep reorg")
+		return
+	}
+
+	if events != nil || f.Checkpoint() != tip || len(f.headers) != 4 {
+		t.Errorf("mismatch state after deep reorg, expect untouched tip %v, got %v and events %v", tip.Hash, f.Checkpoint().Hash, events)
+		return
+	}
+
+	// Same error again, nothing was rolled back
+	_, err = f.Poll(context.Background())
+	if err == nil || f.Checkpoint() != tip {
+		t.Errorf("expect error on deep reorg again, got %v", err)
+		return
+	}
+}
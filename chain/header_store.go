@@ -0,0 +1,202 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// ErrHeaderNotFound header is not in store
+var ErrHeaderNotFound = errors.New("header not found")
+
+// headerSize molecule header size
+const headerSize = 208
+
+// HeaderStore header persistence for spv and light client
+/*
+ * Headers are put in chain order. The last put header is tip, and
+ * canonical headers above its number are dropped, so a reorg is applied
+ * by putting the new branch from the fork point.
+ */
+type HeaderStore interface {
+	Put(h *types.Header) error
+	GetByHash(hash types.Hash) (*types.HeaderView, error)
+	GetByNumber(number types.Uint64) (*types.HeaderView, error)
+	Tip() (*types.HeaderView, error)
+}
+
+// MemoryHeaderStore in memory header store
+type MemoryHeaderStore struct {
+	mu       sync.RWMutex
+	byHash   map[types.Hash]*types.HeaderView
+	byNumber map[types.Uint64]types.Hash
+	tip      *types.HeaderView
+}
+
+// NewMemoryHeaderStore new empty in memory header store
+func NewMemoryHeaderStore() *MemoryHeaderStore {
+	return &MemoryHeaderStore{
+		byHash:   make(map[types.Hash]*types.HeaderView),
+		byNumber: make(map[types.Uint64]types.Hash),
+	}
+}
+
+// Put put header, hash is computed
+func (s *MemoryHeaderStore) Put(h *types.Header) error {
+	hash, err := h.ComputeHash()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v := &types.HeaderView{Header: *h, Hash: hash}
+	s.byHash[hash] = v
+	s.byNumber[h.Number] = hash
+
+	// Canonical headers above a replaced one belong to the old branch
+	if s.tip != nil && h.Number <= s.tip.Header.Number {
+		for n := h.Number + 1; n <= s.tip.Header.Number; n++ {
+			delete(s.byNumber, n)
+		}
+	}
+	s.tip = v
+
+	return nil
+}
+
+// GetByHash get header by hash, canonical or not
+func (s *MemoryHeaderStore) GetByHash(hash types.Hash) (*types.HeaderView, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	v, ok := s.byHash[hash]
+	if !ok {
+		return nil, ErrHeaderNotFound
+	}
+
+	return v, nil
+}
+
+// GetByNumber get canonical header by number
+func (s *MemoryHeaderStore) GetByNumber(number types.Uint64) (*types.HeaderView, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	hash, ok := s.byNumber[number]
+	if !ok {
+		return nil, ErrHeaderNotFound
+	}
+
+	return s.byHash[hash], nil
+}
+
+// Tip last put header
+func (s *MemoryHeaderStore) Tip() (*types.HeaderView, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.tip == nil {
+		return nil, ErrHeaderNotFound
+	}
+
+	return s.tip, nil
+}
+
+// FileHeaderStore file backed header store, headers are appended to the
+// file in molecule form and replayed into memory on open
+type FileHeaderStore struct {
+	*MemoryHeaderStore
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenFileHeaderStore open header store file, created if missing, a
+// partial last record left by interrupted write is truncated
+func OpenFileHeaderStore(path string) (*FileHeaderStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FileHeaderStore{MemoryHeaderStore: NewMemoryHeaderStore(), file: f}
+
+	record := make([]byte, headerSize)
+	var size int64
+	for {
+		_, err = io.ReadFull(f, record)
+		if err == io.EOF {
+			break
+		}
+
+		// Crash in the middle of Put, drop the partial record and append
+		// after the last complete one
+		if err == io.ErrUnexpectedEOF {
+			err = f.Truncate(size)
+			if err == nil {
+				_, err = f.Seek(size, io.SeekStart)
+			}
+
+			if err != nil {
+				f.Close()
+				return nil, fmt.Errorf("fail to truncate header store %s, %s", path, err)
+			}
+
+			break
+		}
+
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("invalid header store %s, %s", path, err)
+		}
+
+		var h types.Header
+		err = h.Deserialize(record)
+		if err == nil {
+			err = s.MemoryHeaderStore.Put(&h)
+		}
+
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("invalid header store %s, %s", path, err)
+		}
+
+		size += headerSize
+	}
+
+	return s, nil
+}
+
+// Put append header to file then put it in memory
+func (s *FileHeaderStore) Put(h *types.Header) error {
+	b, err := h.Serialize()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.file.Write(b)
+	if err != nil {
+		return err
+	}
+
+	return s.MemoryHeaderStore.Put(h)
+}
+
+// Sync flush written headers to disk
+func (s *FileHeaderStore) Sync() error {
+	return s.file.Sync()
+}
+
+// Close close store file
+func (s *FileHeaderStore) Close() error {
+	return s.file.Close()
+}
@@ -0,0 +1,141 @@
+package chain
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+func TestHeaderStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "header-store")
+	if err != nil {
+		t.Errorf("fail to create temp dir: %s\n", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "headers")
+	s, err := OpenFileHeaderStore(path)
+	if err != nil {
+		t.Errorf("fail to open header store: %s\n", err)
+		return
+	}
+
+	var parent types.Hash
+	for n := 0; n < 5; n++ {
+		h := &types.Header{Number: types.Uint64(n), ParentHash: parent}
+
+		err = s.Put(h)
+		if err != nil {
+			t.Errorf("fail to put header: %s\n", err)
+			return
+		}
+
+		parent, _ = h.ComputeHash()
+	}
+
+	// Reorg at 3, replaces 3 and drops 4
+	three, _ := s.GetByNumber(3)
+	fork := &types.Header{Number: 3, ParentHash: three.Header.ParentHash, Nonce: types.NewUint128(1)}
+	err = s.Put(fork)
+	if err != nil {
+		t.Errorf("fail to put header: %s\n", err)
+		return
+	}
+	s.Close()
+
+	s, err = OpenFileHeaderStore(path)
+	if err != nil {
+		t.Errorf("fail to reopen header store: %s\n", err)
+		return
+	}
+	defer s.Close()
+
+	var store HeaderStore = s
+	tip, err := store.Tip()
+	if err != nil || tip.Header.Number != 3 || tip.Header.Nonce != types.NewUint128(1) {
+		t.Errorf("mismatch tip, got %+v %v", tip, err)
+		return
+	}
+
+	_, err = store.GetByNumber(4)
+	if err != ErrHeaderNotFound {
+		t.Errorf("expect stale header removed from canonical chain, got %v", err)
+		return
+	}
+
+	_, err = store.GetByHash(three.Hash)
+	if err != nil {
+		t.Errorf("expect fork header kept by hash, got %v", err)
+		return
+	}
+}
+
+func TestHeaderStorePartialRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "header-store")
+	if err != nil {
+		t.Errorf("fail to create temp dir: %s\n", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "headers")
+	s, err := OpenFileHeaderStore(path)
+	if err != nil {
+		t.Errorf("fail to open header store: %s\n", err)
+		return
+	}
+
+	genesis := &types.Header{Number: 0}
+	err = s.Put(genesis)
+	if err != nil {
+		t.Errorf("fail to put header: %s\n", err)
+		return
+	}
+	s.Close()
+
+	// Interrupted write of second header
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Errorf("fail to open store file: %s\n", err)
+		return
+	}
+	f.Write(make([]byte, headerSize/2))
+	f.Close()
+
+	s, err = OpenFileHeaderStore(path)
+	if err != nil {
+		t.Errorf("fail to reopen header store with partial record: %s\n", err)
+		return
+	}
+
+	parent, _ := genesis.ComputeHash()
+	err = s.Put(&types.Header{Number: 1, ParentHash: parent})
+	if err != nil {
+		t.Errorf("fail to put header: %s\n", err)
+		return
+	}
+	s.Close()
+
+	info, err := os.Stat(path)
+	if err != nil || info.Size() != 2*headerSize {
+		t.Errorf("mismatch store size, expect %d, got %v %v", 2*headerSize, info, err)
+		return
+	}
+
+	s, err = OpenFileHeaderStore(path)
+	if err != nil {
+		t.Errorf("fail to reopen header store: %s\n", err)
+		return
+	}
+	defer s.Close()
+
+	tip, err := s.Tip()
+	if err != nil || tip.Header.Number != 1 || tip.Header.ParentHash != parent {
+		t.Errorf("mismatch tip, got %+v %v", tip, err)
+		return
+	}
+}
@@ -0,0 +1,251 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// CommitFetcher chain lookups of commit watcher, cell status is usually
+// backed by get_live_cell rpc
+type CommitFetcher interface {
+	TxStatusFetcher
+	GetCellStatus(ctx context.Context, o types.OutPoint) (types.CellStatus, error)
+}
+
+// CommitEventType commit watcher event type
+type CommitEventType int
+
+// Commit event types
+const (
+	// CommitReverted committing block was reorged out, transaction is back
+	// in pool or unknown
+	CommitReverted CommitEventType = iota
+	// CommitRestored reverted transaction is committed again, maybe in
+	// another block
+	CommitRestored
+	// CommitMoved transaction is committed in another block by reorg
+	CommitMoved
+	// CommitDoubleSpent an input was spent by another transaction, watched
+	// transaction can never be committed and is dropped
+	CommitDoubleSpent
+)
+
+// CommitEvent commit watcher event
+type CommitEvent struct {
+	Type CommitEventType
+	Hash types.Hash
+	// Previous committed status the watcher knew before event
+	Previous types.TxStatus
+	Status   types.TxStatus
+	// Conflict spent input, set on CommitDoubleSpent
+	Conflict *types.OutPoint
+}
+
+// CommitWatcher re-validate committed transactions after reorgs, so
+// crediting logic can undo what fell off the chain
+/*
+ * Watched transactions are checked on Revalidate, or on HandleChainEvents
+ * for the ones committed at or above the lowest rolled back block. A
+ * transaction no longer committed with an input dead on chain is double
+ * spent, since only another transaction could have spent it. Callback is
+ * called outside of watcher lock.
+ */
+type CommitWatcher struct {
+	f       CommitFetcher
+	onEvent func(CommitEvent)
+
+	mu  sync.Mutex
+	txs map[types.Hash]*watchedTx
+}
+
+// watchedTx committed transaction and its inputs
+type watchedTx struct {
+	status    types.TxStatus
+	committed bool
+	inputs    []types.OutPoint
+}
+
+// NewCommitWatcher new commit watcher
+func NewCommitWatcher(f CommitFetcher, onEvent func(CommitEvent)) *CommitWatcher {
+	return &CommitWatcher{f: f, onEvent: onEvent, txs: make(map[types.Hash]*watchedTx)}
+}
+
+// Watch watch transaction committed with status
+func (w *CommitWatcher) Watch(tx *types.TransactionView, status *types.TxStatus) error {
+	if status.Status != types.TxStatusCommitted || status.BlockHash == nil || status.BlockNumber == nil {
+		return fmt.Errorf("invalid status of %s, should be committed with block", tx.Hash)
+	}
+
+	inputs := make([]types.OutPoint, len(tx.Inputs))
+	for i := 0; i < len(tx.Inputs); i++ {
+		inputs[i] = tx.Inputs[i].PreviousOutput
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.txs[tx.Hash] = &watchedTx{status: *status, committed: true, inputs: inputs}
+	return nil
+}
+
+// Unwatch stop watching transaction, once deep enough to be final
+func (w *CommitWatcher) Unwatch(hash types.Hash) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.txs, hash)
+}
+
+// Watched hashes of watched transactions
+func (w *CommitWatcher) Watched() []types.Hash {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	hashes := make([]types.Hash, 0, len(w.txs))
+	for h := range w.txs {
+		hashes = append(hashes, h)
+	}
+
+	return hashes
+}
+
+// HandleChainEvents revalidate transactions affected by rollbacks among
+// chain follower events
+func (w *CommitWatcher) HandleChainEvents(ctx context.Context, events []Event) error {
+	var lowest *types.Uint64
+	for _, e := range events {
+		if e.Type == Rollback && (lowest == nil || e.Header.Number < *lowest) {
+			n := e.Header.Number
+			lowest = &n
+		}
+	}
+
+	if lowest == nil {
+		return nil
+	}
+
+	return w.revalidate(ctx, func(t *watchedTx) bool {
+		return !t.committed || *t.status.BlockNumber >= *lowest
+	})
+}
+
+// Revalidate check every watched transaction
+func (w *CommitWatcher) Revalidate(ctx context.Context) error {
+	return w.revalidate(ctx, func(*watchedTx) bool {
+		return true
+	})
+}
+
+// revalidate check watched transactions selected by affected
+func (w *CommitWatcher) revalidate(ctx context.Context, affected func(*watchedTx) bool) error {
+	w.mu.Lock()
+	checks := make(map[types.Hash]watchedTx)
+	for h, t := range w.txs {
+		if affected(t) {
+			checks[h] = *t
+		}
+	}
+	w.mu.Unlock()
+
+	for h, t := range checks {
+		e, err := w.check(ctx, h, &t)
+		if err != nil {
+			return err
+		}
+
+		if e != nil && w.apply(e) && w.onEvent != nil {
+			w.onEvent(*e)
+		}
+	}
+
+	return nil
+}
+
+// check fetch status of watched transaction, nil if nothing changed
+func (w *CommitWatcher) check(ctx context.Context, hash types.Hash, t *watchedTx) (*CommitEvent, error) {
+	s, err := w.f.GetTransactionStatus(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Status == types.TxStatusCommitted {
+		return committedEvent(hash, t, s)
+	}
+
+	for i := 0; i < len(t.inputs); i++ {
+		cs, err := w.f.GetCellStatus(ctx, t.inputs[i])
+		if err != nil {
+			return nil, err
+		}
+
+		if cs != types.CellStatusDead {
+			continue
+		}
+
+		// Transaction itself may have been committed again since its
+		// status was read, spending its own inputs
+		s, err = w.f.GetTransactionStatus(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+
+		if s.Status == types.TxStatusCommitted {
+			return committedEvent(hash, t, s)
+		}
+
+		return &CommitEvent{Type: CommitDoubleSpent, Hash: hash, Previous: t.status, Status: *s, Conflict: &t.inputs[i]}, nil
+	}
+
+	if !t.committed {
+		return nil, nil
+	}
+
+	return &CommitEvent{Type: CommitReverted, Hash: hash, Previous: t.status, Status: *s}, nil
+}
+
+// committedEvent event of watched transaction found committed with s, nil
+// if it is still in the same block
+func committedEvent(hash types.Hash, t *watchedTx, s *types.TxStatus) (*CommitEvent, error) {
+	if s.BlockHash == nil || s.BlockNumber == nil {
+		return nil, fmt.Errorf("invalid committed status of %s, no block", hash)
+	}
+
+	e := &CommitEvent{Hash: hash, Previous: t.status, Status: *s}
+	switch {
+	case !t.committed:
+		e.Type = CommitRestored
+	case *s.BlockHash != *t.status.BlockHash:
+		e.Type = CommitMoved
+	default:
+		return nil, nil
+	}
+
+	return e, nil
+}
+
+// apply update watched transaction by event, false if it is no longer
+// watched
+func (w *CommitWatcher) apply(e *CommitEvent) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	t, ok := w.txs[e.Hash]
+	if !ok {
+		return false
+	}
+
+	switch e.Type {
+	case CommitDoubleSpent:
+		delete(w.txs, e.Hash)
+	case CommitReverted:
+		t.committed = false
+	default:
+		t.committed = true
+		t.status = e.Status
+	}
+
+	return true
+}
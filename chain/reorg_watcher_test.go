@@ -0,0 +1,181 @@
+package chain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+type testCommitFetcher struct {
+	status map[types.Hash]types.TxStatus
+	cells  map[types.OutPoint]types.CellStatus
+	// during called before cell status is read, to race chain
+	during func()
+}
+
+func (f *testCommitFetcher) GetTransactionStatus(ctx context.Context, hash types.Hash) (*types.TxStatus, error) {
+	s, ok := f.status[hash]
+	if !ok {
+		s = types.TxStatus{Status: types.TxStatusUnknown}
+	}
+
+	return &s, nil
+}
+
+func (f *testCommitFetcher) GetCellStatus(ctx context.Context, o types.OutPoint) (types.CellStatus, error) {
+	if f.during != nil {
+		f.during()
+	}
+
+	s, ok := f.cells[o]
+	if !ok {
+		s = types.CellStatusLive
+	}
+
+	return s, nil
+}
+
+func testCommitted(block byte, number types.Uint64) types.TxStatus {
+	return types.TxStatus{Status: types.TxStatusCommitted, BlockHash: &types.Hash{block}, BlockNumber: &number}
+}
+
+func TestCommitWatcher(t *testing.T) {
+	a := &types.TransactionView{Hash: types.Hash{0x0a}, Transaction: types.Transaction{Inputs: []types.CellInput{{PreviousOutput: types.OutPoint{TxHash: types.Hash{0x01}}}}}}
+	b := &types.TransactionView{Hash: types.Hash{0x0b}, Transaction: types.Transaction{Inputs: []types.CellInput{{PreviousOutput: types.OutPoint{TxHash: types.Hash{0x02}}}}}}
+	c := &types.TransactionView{Hash: types.Hash{0x0c}, Transaction: types.Transaction{Inputs: []types.CellInput{{PreviousOutput: types.OutPoint{TxHash: types.Hash{0x03}}}}}}
+
+	f := &testCommitFetcher{
+		status: map[types.Hash]types.TxStatus{a.Hash: testCommitted(0xa1, 10), b.Hash: testCommitted(0xb1, 11), c.Hash: testCommitted(0xc1, 5)},
+		cells:  map[types.OutPoint]types.CellStatus{},
+	}
+
+	var events []CommitEvent
+	w := NewCommitWatcher(f, func(e CommitEvent) {
+		events = append(events, e)
+	})
+
+	for _, tx := range []*types.TransactionView{a, b, c} {
+		s := f.status[tx.Hash]
+		err := w.Watch(tx, &s)
+		if err != nil {
+			t.Errorf("fail to watch: %s\n", err)
+			return
+		}
+	}
+
+	// Reorg from block 10, a goes back to pool, b is double spent, c is
+	// below rollback and not checked
+	f.status[a.Hash] = types.TxStatus{Status: types.TxStatusPending}
+	delete(f.status, b.Hash)
+	f.cells[b.Inputs[0].PreviousOutput] = types.CellStatusDead
+	delete(f.status, c.Hash)
+
+	rollback := &types.HeaderView{Header: types.Header{Number: 10}}
+	err := w.HandleChainEvents(context.Background(), []Event{{Type: Advance}, {Type: Rollback, Header: rollback}})
+	if err != nil {
+		t.Errorf("fail to handle chain events: %s\n", err)
+		return
+	}
+
+	if len(events) != 2 {
+		t.Errorf("mismatch result, expect 2 events, got %v", events)
+		return
+	}
+
+	for _, e := range events {
+		if e.Hash == a.Hash && (e.Type != CommitReverted || *e.Previous.BlockHash != (types.Hash{0xa1})) {
+			t.Errorf("mismatch result, expect a reverted, got %v", e)
+			return
+		}
+
+		if e.Hash == b.Hash && (e.Type != CommitDoubleSpent || *e.Conflict != b.Inputs[0].PreviousOutput) {
+			t.Errorf("mismatch result, expect b double spent, got %v", e)
+			return
+		}
+	}
+
+	if len(w.Watched()) != 2 {
+		t.Errorf("mismatch result, expect a and c watched, got %v", w.Watched())
+		return
+	}
+
+	events = nil
+	f.status[a.Hash] = testCommitted(0xa2, 12)
+	f.status[c.Hash] = testCommitted(0xc2, 6)
+
+	err = w.Revalidate(context.Background())
+	if err != nil {
+		t.Errorf("fail to revalidate: %s\n", err)
+		return
+	}
+
+	if len(events) != 2 {
+		t.Errorf("mismatch result, expect 2 events, got %v", events)
+		return
+	}
+
+	for _, e := range events {
+		if e.Hash == a.Hash && e.Type != CommitRestored {
+			t.Errorf("mismatch result, expect a restored, got %v", e)
+			return
+		}
+
+		if e.Hash == c.Hash && e.Type != CommitMoved {
+			t.Errorf("mismatch result, expect c moved, got %v", e)
+			return
+		}
+	}
+
+	err = w.Watch(a, &types.TxStatus{Status: types.TxStatusPending})
+	if err == nil {
+		t.Errorf("expect error on watching pending transaction")
+		return
+	}
+}
+
+func TestCommitWatcherCommittedDuringCheck(t *testing.T) {
+	a := &types.TransactionView{Hash: types.Hash{0x0a}, Transaction: types.Transaction{Inputs: []types.CellInput{{PreviousOutput: types.OutPoint{TxHash: types.Hash{0x01}}}}}}
+
+	f := &testCommitFetcher{
+		status: map[types.Hash]types.TxStatus{a.Hash: testCommitted(0xa1, 10)},
+		cells:  map[types.OutPoint]types.CellStatus{},
+	}
+
+	var events []CommitEvent
+	w := NewCommitWatcher(f, func(e CommitEvent) {
+		events = append(events, e)
+	})
+
+	s := f.status[a.Hash]
+	err := w.Watch(a, &s)
+	if err != nil {
+		t.Errorf("fail to watch: %s\n", err)
+		return
+	}
+
+	// a is reorged out, then committed again in another block after its
+	// status is read, spending its own input
+	f.status[a.Hash] = types.TxStatus{Status: types.TxStatusPending}
+	f.during = func() {
+		f.during = nil
+		f.status[a.Hash] = testCommitted(0xa2, 11)
+		f.cells[a.Inputs[0].PreviousOutput] = types.CellStatusDead
+	}
+
+	err = w.Revalidate(context.Background())
+	if err != nil {
+		t.Errorf("fail to revalidate: %s\n", err)
+		return
+	}
+
+	if len(events) != 1 || events[0].Type != CommitMoved || *events[0].Status.BlockHash != (types.Hash{0xa2}) {
+		t.Errorf("mismatch result, expect a moved, got %v", events)
+		return
+	}
+
+	if len(w.Watched()) != 1 {
+		t.Errorf("mismatch result, expect a still watched, got %v", w.Watched())
+		return
+	}
+}
@@ -0,0 +1,146 @@
+package chain
+
+import (
+	"context"
+	"sync"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// TxStatusFetcher fetch transaction status, usually backed by
+// get_transaction rpc with verbosity 1
+type TxStatusFetcher interface {
+	GetTransactionStatus(ctx context.Context, hash types.Hash) (*types.TxStatus, error)
+}
+
+// TxStateChange status change of tracked transaction
+type TxStateChange struct {
+	Hash   types.Hash
+	From   types.TxStatusType
+	To     types.TxStatusType
+	Status types.TxStatus
+}
+
+// TxTracker track submitted transactions through pending, proposed and
+// committed, rejected or evicted
+/*
+ * Statuses come from Poll, or from subscription payloads fed to Apply,
+ * OnProposedTransaction and OnRejectedTransaction. Callback is called
+ * once per change, in order, outside of tracker lock. Transactions are
+ * dropped once final.
+ */
+type TxTracker struct {
+	f        TxStatusFetcher
+	onChange func(TxStateChange)
+
+	mu     sync.Mutex
+	states map[types.Hash]types.TxStatusType
+}
+
+// NewTxTracker new tracker, fetcher is used by Poll and may be nil if
+// statuses only come from subscriptions
+func NewTxTracker(f TxStatusFetcher, onChange func(TxStateChange)) *TxTracker {
+	return &TxTracker{f: f, onChange: onChange, states: make(map[types.Hash]types.TxStatusType)}
+}
+
+// Track start tracking submitted transaction as pending
+func (t *TxTracker) Track(hash types.Hash) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.states[hash]; !ok {
+		t.states[hash] = types.TxStatusPending
+	}
+}
+
+// Untrack stop tracking transaction
+func (t *TxTracker) Untrack(hash types.Hash) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.states, hash)
+}
+
+// State status of tracked transaction, false if not tracked
+func (t *TxTracker) State(hash types.Hash) (types.TxStatusType, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.states[hash]
+	return s, ok
+}
+
+// Tracked hashes of tracked transactions
+func (t *TxTracker) Tracked() []types.Hash {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	hashes := make([]types.Hash, 0, len(t.states))
+	for h := range t.states {
+		hashes = append(hashes, h)
+	}
+
+	return hashes
+}
+
+// Poll fetch status of every tracked transaction, stop on first error
+func (t *TxTracker) Poll(ctx context.Context) error {
+	for _, h := range t.Tracked() {
+		s, err := t.f.GetTransactionStatus(ctx, h)
+		if err != nil {
+			return err
+		}
+
+		t.Apply(h, s)
+	}
+
+	return nil
+}
+
+// Apply status of transaction from node, ignored if not tracked
+func (t *TxTracker) Apply(hash types.Hash, status *types.TxStatus) {
+	change, ok := t.transit(hash, status)
+	if ok && t.onChange != nil {
+		t.onChange(change)
+	}
+}
+
+// OnProposedTransaction apply proposed_transaction subscription payload
+func (t *TxTracker) OnProposedTransaction(e *types.PoolTransactionEntry) {
+	t.Apply(e.Transaction.Hash, &types.TxStatus{Status: types.TxStatusProposed})
+}
+
+// OnRejectedTransaction apply rejected_transaction subscription payload
+func (t *TxTracker) OnRejectedTransaction(r *types.RejectedTransaction) {
+	reason := string(r.Reason.Type) + ": " + r.Reason.Description
+	t.Apply(r.Entry.Transaction.Hash, &types.TxStatus{Status: types.TxStatusRejected, Reason: &reason})
+}
+
+// transit update state, unknown after being known to node means evicted
+func (t *TxTracker) transit(hash types.Hash, status *types.TxStatus) (TxStateChange, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	from, ok := t.states[hash]
+	if !ok {
+		return TxStateChange{}, false
+	}
+
+	to := status.Status
+	if to == types.TxStatusUnknown {
+		to = types.TxStatusEvicted
+	}
+
+	// Proposed may be polled after committed subscription, never go back
+	if from == to || (from == types.TxStatusProposed && to == types.TxStatusPending) {
+		return TxStateChange{}, false
+	}
+
+	if to.IsFinal() {
+		delete(t.states, hash)
+	} else {
+		t.states[hash] = to
+	}
+
+	return TxStateChange{Hash: hash, From: from, To: to, Status: *status}, true
+}
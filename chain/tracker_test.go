@@ -0,0 +1,96 @@
+package chain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+type testStatusFetcher map[types.Hash]types.TxStatusType
+
+func (f testStatusFetcher) GetTransactionStatus(ctx context.Context, hash types.Hash) (*types.TxStatus, error) {
+	s, ok := f[hash]
+	if !ok {
+		s = types.TxStatusUnknown
+	}
+
+	return &types.TxStatus{Status: s}, nil
+}
+
+func TestTxTracker(t *testing.T) {
+	committed, evicted, rejected := types.Hash{0x01}, types.Hash{0x02}, types.Hash{0x03}
+	f := testStatusFetcher{committed: types.TxStatusPending, evicted: types.TxStatusPending}
+
+	changes := []TxStateChange{}
+	tr := NewTxTracker(f, func(c TxStateChange) {
+		changes = append(changes, c)
+	})
+
+	tr.Track(committed)
+	tr.Track(evicted)
+	tr.Track(rejected)
+
+	err := tr.Poll(context.Background())
+	if err != nil {
+		t.Errorf("fail to poll: %s\n", err)
+		return
+	}
+
+	// rejected is unknown to fetcher, so it is evicted on first poll
+	if len(changes) != 1 || changes[0].Hash != rejected || changes[0].To != types.TxStatusEvicted {
+		t.Errorf("mismatch result, expect rejected evicted, got %v", changes)
+		return
+	}
+
+	tr.Track(rejected)
+	tr.OnRejectedTransaction(&types.RejectedTransaction{
+		Entry:  types.PoolTransactionEntry{Transaction: types.TransactionView{Hash: rejected}},
+		Reason: types.PoolTransactionReject{Type: types.RejectLowFeeRate, Description: "fee too low"},
+	})
+
+	last := changes[len(changes)-1]
+	if last.To != types.TxStatusRejected || last.Status.Reason == nil || *last.Status.Reason != "LowFeeRate: fee too low" {
+		t.Errorf("mismatch result, expect rejected, got %v", last)
+		return
+	}
+
+	tr.OnProposedTransaction(&types.PoolTransactionEntry{Transaction: types.TransactionView{Hash: committed}})
+	f[committed] = types.TxStatusPending
+	delete(f, evicted)
+
+	err = tr.Poll(context.Background())
+	if err != nil {
+		t.Errorf("fail to poll: %s\n", err)
+		return
+	}
+
+	s, ok := tr.State(committed)
+	if !ok || s != types.TxStatusProposed {
+		t.Errorf("mismatch result, expect %v, got %v", types.TxStatusProposed, s)
+		return
+	}
+
+	if _, ok := tr.State(evicted); ok {
+		t.Errorf("expect evicted transaction untracked")
+		return
+	}
+
+	f[committed] = types.TxStatusCommitted
+	err = tr.Poll(context.Background())
+	if err != nil {
+		t.Errorf("fail to poll: %s\n", err)
+		return
+	}
+
+	last = changes[len(changes)-1]
+	if last.Hash != committed || last.From != types.TxStatusProposed || last.To != types.TxStatusCommitted {
+		t.Errorf("mismatch result, expect proposed to committed, got %v", last)
+		return
+	}
+
+	if len(tr.Tracked()) != 0 {
+		t.Errorf("mismatch result, expect no tracked, got %v", tr.Tracked())
+		return
+	}
+}
@@ -0,0 +1,303 @@
+//go:build ckbsdk
+// +build ckbsdk
+
+// Package ckbsdk converts core types to and from nervosnetwork/ckb-sdk-go
+// v2 types, so both libraries can be used side by side.
+//
+// It is behind the ckbsdk build tag to keep ckb-sdk-go out of default
+// builds:
+//
+//	go build -tags ckbsdk ./...
+package ckbsdk
+
+import (
+	"fmt"
+
+	sdk "github.com/nervosnetwork/ckb-sdk-go/v2/types"
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// Script to sdk script
+func Script(s *types.Script) (*sdk.Script, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	if len(s.UnknownFields) != 0 {
+		return nil, fmt.Errorf("invalid script, sdk script has no unknown fields")
+	}
+
+	var t sdk.ScriptHashType
+	switch s.HashType {
+	case types.Data:
+		t = sdk.HashTypeData
+	case types.Type:
+		t = sdk.HashTypeType
+	case types.Data1:
+		t = sdk.HashTypeData1
+	case types.Data2:
+		t = sdk.HashTypeData2
+	default:
+		return nil, fmt.Errorf("invalid script hash type %s", s.HashType)
+	}
+
+	return &sdk.Script{
+		CodeHash: sdk.Hash(s.CodeHash),
+		HashType: t,
+		Args:     append([]byte{}, s.Args...),
+	}, nil
+}
+
+// FromScript from sdk script
+func FromScript(s *sdk.Script) (*types.Script, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	t := types.ScriptHashType(s.HashType)
+	switch t {
+	case types.Data, types.Type, types.Data1, types.Data2:
+	default:
+		return nil, fmt.Errorf("invalid script hash type %s", s.HashType)
+	}
+
+	return &types.Script{
+		CodeHash: types.Hash(s.CodeHash),
+		HashType: t,
+		Args:     append(types.Bytes{}, s.Args...),
+	}, nil
+}
+
+// OutPoint to sdk outpoint
+func OutPoint(o types.OutPoint) *sdk.OutPoint {
+	return &sdk.OutPoint{TxHash: sdk.Hash(o.TxHash), Index: uint32(o.Index)}
+}
+
+// FromOutPoint from sdk outpoint
+func FromOutPoint(o *sdk.OutPoint) (types.OutPoint, error) {
+	if o == nil {
+		return types.OutPoint{}, fmt.Errorf("invalid outpoint, nil")
+	}
+
+	return types.OutPoint{TxHash: types.Hash(o.TxHash), Index: types.Uint32(o.Index)}, nil
+}
+
+// CellOutput to sdk cell output
+func CellOutput(o *types.CellOutput) (*sdk.CellOutput, error) {
+	if len(o.UnknownFields) != 0 {
+		return nil, fmt.Errorf("invalid cell output, sdk cell output has no unknown fields")
+	}
+
+	lock, err := Script(&o.Lock)
+	if err != nil {
+		return nil, err
+	}
+
+	typ, err := Script(o.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdk.CellOutput{Capacity: uint64(o.Capacity), Lock: lock, Type: typ}, nil
+}
+
+// FromCellOutput from sdk cell output
+func FromCellOutput(o *sdk.CellOutput) (*types.CellOutput, error) {
+	if o.Lock == nil {
+		return nil, fmt.Errorf("invalid cell output, no lock")
+	}
+
+	lock, err := FromScript(o.Lock)
+	if err != nil {
+		return nil, err
+	}
+
+	typ, err := FromScript(o.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.CellOutput{Capacity: types.Uint64(o.Capacity), Lock: *lock, Type: typ}, nil
+}
+
+// CellDep to sdk cell dep
+func CellDep(d types.CellDep) (*sdk.CellDep, error) {
+	var t sdk.DepType
+	switch d.DepType {
+	case types.Code:
+		t = sdk.DepTypeCode
+	case types.DepGroup:
+		t = sdk.DepTypeDepGroup
+	default:
+		return nil, fmt.Errorf("invalid dep type %s", d.DepType)
+	}
+
+	return &sdk.CellDep{OutPoint: OutPoint(d.OutPoint), DepType: t}, nil
+}
+
+// FromCellDep from sdk cell dep
+func FromCellDep(d *sdk.CellDep) (types.CellDep, error) {
+	o, err := FromOutPoint(d.OutPoint)
+	if err != nil {
+		return types.CellDep{}, err
+	}
+
+	t := types.DepType(d.DepType)
+	if t != types.Code && t != types.DepGroup {
+		return types.CellDep{}, fmt.Errorf("invalid dep type %s", d.DepType)
+	}
+
+	return types.CellDep{OutPoint: o, DepType: t}, nil
+}
+
+// Transaction to sdk transaction
+func Transaction(t *types.Transaction) (*sdk.Transaction, error) {
+	if len(t.UnknownFields) != 0 {
+		return nil, fmt.Errorf("invalid transaction, sdk transaction has no unknown fields")
+	}
+
+	tx := &sdk.Transaction{
+		Version:     uint32(t.Version),
+		CellDeps:    make([]*sdk.CellDep, len(t.CellDeps)),
+		HeaderDeps:  make([]sdk.Hash, len(t.HeaderDeps)),
+		Inputs:      make([]*sdk.CellInput, len(t.Inputs)),
+		Outputs:     make([]*sdk.CellOutput, len(t.Outputs)),
+		OutputsData: make([][]byte, len(t.OutputsData)),
+		Witnesses:   make([][]byte, len(t.Witnesses)),
+	}
+
+	var err error
+	for i := range t.CellDeps {
+		tx.CellDeps[i], err = CellDep(t.CellDeps[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i, h := range t.HeaderDeps {
+		tx.HeaderDeps[i] = sdk.Hash(h)
+	}
+
+	for i, in := range t.Inputs {
+		tx.Inputs[i] = &sdk.CellInput{Since: uint64(in.Since), PreviousOutput: OutPoint(in.PreviousOutput)}
+	}
+
+	for i := range t.Outputs {
+		tx.Outputs[i], err = CellOutput(&t.Outputs[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i, d := range t.OutputsData {
+		tx.OutputsData[i] = append([]byte{}, d...)
+	}
+
+	for i, w := range t.Witnesses {
+		tx.Witnesses[i] = append([]byte{}, w...)
+	}
+
+	return tx, nil
+}
+
+// FromTransaction from sdk transaction
+func FromTransaction(t *sdk.Transaction) (*types.Transaction, error) {
+	tx := &types.Transaction{
+		Version:     types.Uint32(t.Version),
+		CellDeps:    make([]types.CellDep, len(t.CellDeps)),
+		HeaderDeps:  make([]types.Hash, len(t.HeaderDeps)),
+		Inputs:      make([]types.CellInput, len(t.Inputs)),
+		Outputs:     make([]types.CellOutput, len(t.Outputs)),
+		OutputsData: make([]types.Bytes, len(t.OutputsData)),
+		Witnesses:   make([]types.Bytes, len(t.Witnesses)),
+	}
+
+	var err error
+	for i, d := range t.CellDeps {
+		tx.CellDeps[i], err = FromCellDep(d)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i, h := range t.HeaderDeps {
+		tx.HeaderDeps[i] = types.Hash(h)
+	}
+
+	for i, in := range t.Inputs {
+		o, err := FromOutPoint(in.PreviousOutput)
+		if err != nil {
+			return nil, err
+		}
+
+		tx.Inputs[i] = types.CellInput{Since: types.Uint64(in.Since), PreviousOutput: o}
+	}
+
+	for i, o := range t.Outputs {
+		out, err := FromCellOutput(o)
+		if err != nil {
+			return nil, err
+		}
+
+		tx.Outputs[i] = *out
+	}
+
+	for i, d := range t.OutputsData {
+		tx.OutputsData[i] = append(types.Bytes{}, d...)
+	}
+
+	for i, w := range t.Witnesses {
+		tx.Witnesses[i] = append(types.Bytes{}, w...)
+	}
+
+	return tx, nil
+}
+
+// Header to sdk header, sdk header carries hash, which is computed
+func Header(h *types.Header) (*sdk.Header, error) {
+	hash, err := h.ComputeHash()
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdk.Header{
+		CompactTarget:    uint32(h.CompactTarget),
+		Dao:              sdk.Hash(h.Dao),
+		Epoch:            uint64(h.Epoch),
+		ExtraHash:        sdk.Hash(h.ExtraHash),
+		Hash:             sdk.Hash(hash),
+		Nonce:            h.Nonce.Big(),
+		Number:           uint64(h.Number),
+		ParentHash:       sdk.Hash(h.ParentHash),
+		ProposalsHash:    sdk.Hash(h.ProposalsHash),
+		Timestamp:        uint64(h.Timestamp),
+		TransactionsRoot: sdk.Hash(h.TransactionsRoot),
+		Version:          uint32(h.Version),
+	}, nil
+}
+
+// FromHeader from sdk header, hash is dropped
+func FromHeader(h *sdk.Header) (*types.Header, error) {
+	if h.Nonce == nil {
+		return nil, fmt.Errorf("invalid header, no nonce")
+	}
+
+	nonce, err := types.Uint128FromBig(h.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Header{
+		Version:          types.Uint32(h.Version),
+		CompactTarget:    types.Uint32(h.CompactTarget),
+		ParentHash:       types.Hash(h.ParentHash),
+		Timestamp:        types.Uint64(h.Timestamp),
+		Number:           types.Uint64(h.Number),
+		Epoch:            types.Uint64(h.Epoch),
+		TransactionsRoot: types.Hash(h.TransactionsRoot),
+		ProposalsHash:    types.Hash(h.ProposalsHash),
+		ExtraHash:        types.Hash(h.ExtraHash),
+		Dao:              types.Hash(h.Dao),
+		Nonce:            nonce,
+	}, nil
+}
@@ -0,0 +1,52 @@
+//go:build ckbsdk
+// +build ckbsdk
+
+package ckbsdk
+
+import (
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+func TestTransactionRoundTrip(t *testing.T) {
+	lock := types.Script{CodeHash: types.SecpSighashAllCodeHash, HashType: types.Type, Args: make(types.Bytes, 20)}
+	tx := &types.Transaction{
+		CellDeps:    []types.CellDep{{OutPoint: types.OutPoint{TxHash: types.Hash{1}}, DepType: types.DepGroup}},
+		HeaderDeps:  []types.Hash{{2}},
+		Inputs:      []types.CellInput{{Since: 1, PreviousOutput: types.OutPoint{TxHash: types.Hash{3}, Index: 1}}},
+		Outputs:     []types.CellOutput{{Capacity: 61 * types.ShannonsPerCKB, Lock: lock, Type: &lock}},
+		OutputsData: []types.Bytes{{4}},
+		Witnesses:   []types.Bytes{{5}},
+	}
+
+	s, err := Transaction(tx)
+	if err != nil {
+		t.Errorf("fail to convert transaction: %s\n", err)
+		return
+	}
+
+	got, err := FromTransaction(s)
+	if err != nil {
+		t.Errorf("fail to convert sdk transaction: %s\n", err)
+		return
+	}
+
+	if !got.Equal(tx) {
+		t.Errorf("mismatch result, expect %v, got %v", tx, got)
+		return
+	}
+
+	h := &types.Header{Number: 1, Nonce: types.Uint128{Hi: 1, Lo: 2}}
+	sh, err := Header(h)
+	if err != nil {
+		t.Errorf("fail to convert header: %s\n", err)
+		return
+	}
+
+	gh, err := FromHeader(sh)
+	if err != nil || *gh != *h {
+		t.Errorf("mismatch result, expect %v, got %v", h, gh)
+		return
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// inspect decode transaction from molecule hex, or fetch it by hash from
+// rpc, and print hashes, labeled scripts and capacity flow
+/*
+ *     ckb-types tx inspect <hex>
+ *     ckb-types tx inspect -rpc <url> <hex or tx hash>
+ *
+ * Input capacities need previous transactions, so capacity flow is only
+ * complete with rpc.
+ */
+func inspect(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("tx inspect", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	url := fs.String("rpc", "", "ckb node rpc url, to fetch transaction and resolve inputs")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("usage: ckb-types tx inspect [-rpc url] [hex or tx hash], %s", err)
+	}
+
+	input, err := readInput(fs.Args(), 0, stdin)
+	if err != nil {
+		return err
+	}
+
+	data, err := hex.DecodeString(strings.TrimPrefix(input, "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid hex, %s", err)
+	}
+
+	var c *rpcClient
+	if *url != "" {
+		c = &rpcClient{url: *url}
+	}
+
+	var tx types.Transaction
+	if len(data) == 32 {
+		if c == nil {
+			return fmt.Errorf("tx hash needs -rpc")
+		}
+
+		var h types.Hash
+		copy(h[:], data)
+
+		view, err := c.getTransaction(h)
+		if err != nil {
+			return err
+		}
+
+		tx = view.Transaction
+	} else if err = tx.Unpack(data); err != nil {
+		// Raw transaction without witnesses
+		err = tx.Deserialize(data)
+		if err != nil {
+			return fmt.Errorf("invalid transaction, %s", err)
+		}
+	}
+
+	txHash, err := tx.ComputeHash()
+	if err != nil {
+		return err
+	}
+
+	packed, err := tx.Pack()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "hash: %s\n", txHash)
+	fmt.Fprintf(stdout, "witness_hash: 0x%s\n", hex.EncodeToString(types.Blake2b256(packed)))
+	fmt.Fprintf(stdout, "size: %d bytes\n", len(packed))
+	fmt.Fprint(stdout, tx.Dump())
+
+	if c == nil || tx.IsCellbase() {
+		var outputs uint64
+		for _, o := range tx.Outputs {
+			outputs += uint64(o.Capacity)
+		}
+
+		fmt.Fprintf(stdout, "capacity: outputs %d shannons, inputs unresolved\n", outputs)
+		return nil
+	}
+
+	caps := make([]types.Uint64, len(tx.Inputs))
+	for i, in := range tx.Inputs {
+		prev, err := c.getTransaction(in.PreviousOutput.TxHash)
+		if err != nil {
+			return fmt.Errorf("fail to resolve input %d, %s", i, err)
+		}
+
+		if int(in.PreviousOutput.Index) >= len(prev.Outputs) {
+			return fmt.Errorf("fail to resolve input %d, no output %s", i, in.PreviousOutput)
+		}
+
+		caps[i] = prev.Outputs[in.PreviousOutput.Index].Capacity
+	}
+
+	r, err := tx.CheckCapacity(caps, 0)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(stdout, "capacity: %s\n", r)
+	return err
+}
+
+// rpcClient minimal ckb json-rpc client, only what inspect needs
+type rpcClient struct {
+	url string
+}
+
+type rpcRequest struct {
+	ID      int           `json:"id"`
+	JSONRPC string        `json:"jsonrpc"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (c *rpcClient) call(method string, result interface{}, params ...interface{}) error {
+	req, err := json.Marshal(&rpcRequest{ID: 1, JSONRPC: "2.0", Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(c.url, "application/json", bytes.NewReader(req))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var r rpcResponse
+	err = json.NewDecoder(resp.Body).Decode(&r)
+	if err != nil {
+		return fmt.Errorf("invalid %s response, %s", method, err)
+	}
+
+	if r.Error != nil {
+		return fmt.Errorf("%s failed, %s", method, r.Error.Message)
+	}
+
+	return json.Unmarshal(r.Result, result)
+}
+
+func (c *rpcClient) getTransaction(h types.Hash) (*types.TransactionView, error) {
+	var result struct {
+		Transaction *types.TransactionView `json:"transaction"`
+	}
+
+	err := c.call("get_transaction", &result, h)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Transaction == nil {
+		return nil, fmt.Errorf("transaction %s not found", h)
+	}
+
+	return result.Transaction, nil
+}
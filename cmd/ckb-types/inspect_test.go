@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+func TestInspect(t *testing.T) {
+	lock := types.Script{CodeHash: types.SecpSighashAllCodeHash, HashType: types.Type, Args: make(types.Bytes, 20)}
+
+	prev := types.Transaction{
+		Outputs:     []types.CellOutput{{Capacity: 200 * types.ShannonsPerCKB, Lock: lock}},
+		OutputsData: []types.Bytes{{}},
+	}
+	prevHash, err := prev.ComputeHash()
+	if err != nil {
+		t.Errorf("fail to compute hash: %s\n", err)
+		return
+	}
+
+	tx := types.Transaction{
+		Inputs:      []types.CellInput{{PreviousOutput: types.OutPoint{TxHash: prevHash}}},
+		Outputs:     []types.CellOutput{{Capacity: 199 * types.ShannonsPerCKB, Lock: lock}},
+		OutputsData: []types.Bytes{{}},
+		Witnesses:   []types.Bytes{{}},
+	}
+	packed, err := tx.Pack()
+	if err != nil {
+		t.Errorf("fail to pack: %s\n", err)
+		return
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil || req.Method != "get_transaction" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		result := map[string]interface{}{"transaction": nil}
+		if req.Params[0] == prevHash.String() {
+			result["transaction"] = &types.TransactionView{Transaction: prev, Hash: prevHash}
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	err = run([]string{"tx", "inspect", "0x" + hex.EncodeToString(packed)}, nil, &out)
+	if err != nil {
+		t.Errorf("fail to inspect: %s\n", err)
+		return
+	}
+
+	for _, expect := range []string{"secp256k1_blake160_sighash_all", "inputs unresolved"} {
+		if !strings.Contains(out.String(), expect) {
+			t.Errorf("mismatch result, expect %v, got %v", expect, out.String())
+			return
+		}
+	}
+
+	out.Reset()
+	err = run([]string{"tx", "inspect", "-rpc", srv.URL, "0x" + hex.EncodeToString(packed)}, nil, &out)
+	if err != nil {
+		t.Errorf("fail to inspect: %s\n", err)
+		return
+	}
+
+	if !strings.Contains(out.String(), "capacity: ok, fee 1 CKB") {
+		t.Errorf("mismatch result, got %v", out.String())
+		return
+	}
+
+	out.Reset()
+	err = run([]string{"tx", "inspect", "-rpc", srv.URL, prevHash.String()}, nil, &out)
+	if err != nil {
+		t.Errorf("fail to inspect: %s\n", err)
+		return
+	}
+
+	if !strings.Contains(out.String(), "hash: "+prevHash.String()) {
+		t.Errorf("mismatch result, got %v", out.String())
+		return
+	}
+
+	err = run([]string{"tx", "inspect", "-rpc", srv.URL, types.Hash{1}.String()}, nil, &out)
+	if err == nil {
+		t.Errorf("should fail on unknown transaction")
+		return
+	}
+}
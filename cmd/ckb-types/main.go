@@ -0,0 +1,182 @@
+// Command ckb-types decodes molecule hex of known ckb types into json and
+// encodes json back to molecule hex.
+//
+//	ckb-types decode <type> [hex]
+//	ckb-types encode <type> [json]
+//	ckb-types tx inspect [-rpc url] [hex or tx hash]
+//	ckb-types schema
+//
+// Input is read from stdin if omitted, hex may have 0x prefix. Known types
+// are script, witness_args, cellbase_witness, raw_transaction,
+// transaction and header.
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// molecule type with encode and decode, so transaction can choose between
+// raw and packed forms
+type molType struct {
+	new    func() interface{}
+	encode func(v interface{}) ([]byte, error)
+	decode func(data []byte, v interface{}) error
+}
+
+func serializer(v interface{}) ([]byte, error) {
+	return v.(types.MolSerializer).Serialize()
+}
+
+func deserializer(data []byte, v interface{}) error {
+	return v.(types.MolDeserializer).Deserialize(data)
+}
+
+var molTypes = map[string]molType{
+	"script": {
+		new:    func() interface{} { return new(types.Script) },
+		encode: serializer,
+		decode: deserializer,
+	},
+	"witness_args": {
+		new:    func() interface{} { return new(types.WitnessArgs) },
+		encode: serializer,
+		decode: deserializer,
+	},
+	"cellbase_witness": {
+		new:    func() interface{} { return new(types.CellbaseWitness) },
+		encode: serializer,
+		decode: deserializer,
+	},
+	"raw_transaction": {
+		new:    func() interface{} { return new(types.Transaction) },
+		encode: serializer,
+		decode: deserializer,
+	},
+	"transaction": {
+		new: func() interface{} { return new(types.Transaction) },
+		encode: func(v interface{}) ([]byte, error) {
+			return v.(*types.Transaction).Pack()
+		},
+		decode: func(data []byte, v interface{}) error {
+			return v.(*types.Transaction).Unpack(data)
+		},
+	},
+	"header": {
+		new:    func() interface{} { return new(types.Header) },
+		encode: serializer,
+		decode: deserializer,
+	},
+}
+
+func typeNames() string {
+	names := make([]string, 0, len(molTypes))
+	for n := range molTypes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ckb-types: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) >= 2 && args[0] == "tx" && args[1] == "inspect" {
+		return inspect(args[2:], stdin, stdout)
+	}
+
+	if len(args) == 1 && args[0] == "schema" {
+		s, err := types.GenerateJSONSchema()
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(stdout, "%s\n", s)
+		return err
+	}
+
+	if len(args) < 2 || len(args) > 3 {
+		return fmt.Errorf("usage: ckb-types decode|encode <type> [input], types: %s", typeNames())
+	}
+
+	t, ok := molTypes[args[1]]
+	if !ok {
+		return fmt.Errorf("unknown type %s, types: %s", args[1], typeNames())
+	}
+
+	input, err := readInput(args, 2, stdin)
+	if err != nil {
+		return err
+	}
+
+	v := t.new()
+
+	switch args[0] {
+	case "decode":
+		data, err := hex.DecodeString(strings.TrimPrefix(input, "0x"))
+		if err != nil {
+			return fmt.Errorf("invalid hex, %s", err)
+		}
+
+		err = t.decode(data, v)
+		if err != nil {
+			return fmt.Errorf("invalid %s, %s", args[1], err)
+		}
+
+		out, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(stdout, "%s\n", out)
+		return err
+	case "encode":
+		err := json.Unmarshal([]byte(input), v)
+		if err != nil {
+			return fmt.Errorf("invalid json, %s", err)
+		}
+
+		data, err := t.encode(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s, %s", args[1], err)
+		}
+
+		_, err = fmt.Fprintf(stdout, "0x%s\n", hex.EncodeToString(data))
+		return err
+	default:
+		return fmt.Errorf("unknown command %s, should be decode or encode", args[0])
+	}
+}
+
+// readInput input at args index, read from stdin if omitted
+func readInput(args []string, i int, stdin io.Reader) (string, error) {
+	if len(args) > i+1 {
+		return "", fmt.Errorf("unexpected arguments %v", args[i+1:])
+	}
+
+	if len(args) == i+1 {
+		return strings.TrimSpace(args[i]), nil
+	}
+
+	b, err := ioutil.ReadAll(stdin)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	script := `{"code_hash": "0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8", "hash_type": "type", "args": "0xc8328aabcd9b9e8e64fbc566c4385c3bdeb219d7"}`
+
+	var out bytes.Buffer
+	err := run([]string{"encode", "script"}, strings.NewReader(script), &out)
+	if err != nil {
+		t.Errorf("fail to encode: %s\n", err)
+		return
+	}
+
+	expect := "0x490000001000000030000000310000009bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce80114000000c8328aabcd9b9e8e64fbc566c4385c3bdeb219d7\n"
+	if out.String() != expect {
+		t.Errorf("mismatch result, expect %v, got %v", expect, out.String())
+		return
+	}
+
+	hex := strings.TrimSpace(out.String())
+	out.Reset()
+	err = run([]string{"decode", "script", hex}, nil, &out)
+	if err != nil {
+		t.Errorf("fail to decode: %s\n", err)
+		return
+	}
+
+	if !strings.Contains(out.String(), `"args": "0xc8328aabcd9b9e8e64fbc566c4385c3bdeb219d7"`) {
+		t.Errorf("mismatch result, got %v", out.String())
+		return
+	}
+
+	out.Reset()
+	err = run([]string{"schema"}, nil, &out)
+	if err != nil || !strings.Contains(out.String(), `"BlockView"`) {
+		t.Errorf("fail to generate schema: %v\n", err)
+		return
+	}
+
+	for _, args := range [][]string{
+		{"decode"},
+		{"decode", "unknown", "0x"},
+		{"verify", "script", "0x"},
+		{"decode", "script", "0x00"},
+		{"encode", "witness_args", "{"},
+	} {
+		err = run(args, nil, &out)
+		if err == nil {
+			t.Errorf("should reject %v", args)
+			return
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// DefaultCacheConfirmations blocks on top before a block found by number
+// or a committed transaction is cached, deep enough to outlive reorgs
+const DefaultCacheConfirmations = 24
+
+// DefaultCacheEntries responses kept by cache
+const DefaultCacheEntries = 1024
+
+// CacheRule how a response may be cached
+type CacheRule struct {
+	Cacheable bool
+	// Block number response depends on, cached only once block has
+	// Confirmations on top, nil if response is immutable as is
+	Block *types.Uint64
+}
+
+// CachePolicy rule of response to call, result is raw json-rpc result
+type CachePolicy func(method string, params []interface{}, result json.RawMessage) CacheRule
+
+// DefaultCachePolicy cache only what can not change
+/*
+ *     get_block, get_header, get_block_extension by hash: once found
+ *     get_block_by_number, get_header_by_number, get_block_hash: once
+ *         block is confirmed
+ *     get_transaction: once committed and confirmed
+ *
+ * Everything else, tip, pool, cells and null results, is volatile.
+ */
+func DefaultCachePolicy(method string, params []interface{}, result json.RawMessage) CacheRule {
+	if len(result) == 0 || bytes.Equal(result, []byte("null")) {
+		return CacheRule{}
+	}
+
+	switch method {
+	case "get_block", "get_header", "get_block_extension":
+		return CacheRule{Cacheable: true}
+	case "get_block_by_number", "get_header_by_number", "get_block_hash":
+		var n types.Uint64
+		if !decodeParam(params, 0, &n) {
+			return CacheRule{}
+		}
+
+		return CacheRule{Cacheable: true, Block: &n}
+	case "get_transaction":
+		var r struct {
+			TxStatus types.TxStatus `json:"tx_status"`
+		}
+
+		err := json.Unmarshal(result, &r)
+		if err != nil || r.TxStatus.Status != types.TxStatusCommitted || r.TxStatus.BlockNumber == nil {
+			return CacheRule{}
+		}
+
+		return CacheRule{Cacheable: true, Block: r.TxStatus.BlockNumber}
+	default:
+		return CacheRule{}
+	}
+}
+
+// Cache caller caching immutable responses by policy
+/*
+ * Responses are cached by method and json of params, kept as raw json and
+ * decoded on every hit, so callers never share decoded values. Rules with
+ * block ask get_tip_block_number of underlying caller on miss. Least
+ * recently used responses are evicted beyond MaxEntries.
+ *
+ * CachedChain caches typed blocks, headers and transactions by hash on
+ * the same lru, for code written against ChainFetcher.
+ */
+type Cache struct {
+	Caller Caller
+	Policy CachePolicy
+	// Confirmations blocks on top required by rules with block
+	Confirmations uint64
+	MaxEntries    int
+
+	mu  sync.Mutex
+	lru *lru
+}
+
+// NewCache new cache caller with default policy
+func NewCache(c Caller) *Cache {
+	return &Cache{
+		Caller:        c,
+		Policy:        DefaultCachePolicy,
+		Confirmations: DefaultCacheConfirmations,
+		MaxEntries:    DefaultCacheEntries,
+		lru:           newLRU(),
+	}
+}
+
+// Call implement Caller
+func (c *Cache) Call(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+	key, err := cacheKey(method, params)
+	if err != nil {
+		return err
+	}
+
+	if raw, ok := c.get(key); ok {
+		return decodeResult(raw, result)
+	}
+
+	var raw json.RawMessage
+	err = c.Caller.Call(ctx, &raw, method, params...)
+	if err != nil {
+		return err
+	}
+
+	rule := c.Policy(method, params, raw)
+	if rule.Cacheable && rule.Block != nil {
+		rule.Cacheable, err = c.confirmed(ctx, *rule.Block)
+		if err != nil {
+			return err
+		}
+	}
+
+	if rule.Cacheable {
+		c.put(key, raw)
+	}
+
+	return decodeResult(raw, result)
+}
+
+// Len number of cached responses
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.list.Len()
+}
+
+// Stats hit metrics since cache was created
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.stats
+}
+
+// Purge drop every cached response
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lru.purge()
+}
+
+// confirmed report whether block number has Confirmations on top
+func (c *Cache) confirmed(ctx context.Context, number types.Uint64) (bool, error) {
+	var tip types.Uint64
+	err := c.Caller.Call(ctx, &tip, "get_tip_block_number")
+	if err != nil {
+		return false, err
+	}
+
+	return tip >= number && uint64(tip-number) >= c.Confirmations, nil
+}
+
+func (c *Cache) get(key string) (json.RawMessage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.lru.get(key)
+	if !ok {
+		return nil, false
+	}
+
+	return v.(json.RawMessage), true
+}
+
+func (c *Cache) put(key string, raw json.RawMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lru.put(key, raw, c.MaxEntries)
+}
+
+// cacheKey method and json of params
+func cacheKey(method string, params []interface{}) (string, error) {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+
+	return method + string(b), nil
+}
+
+// decodeResult decode raw result into result, discarded if result is nil
+func decodeResult(raw json.RawMessage, result interface{}) error {
+	if result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(raw, result)
+}
+
+// decodeParam decode param i through json, false if missing or mistyped
+func decodeParam(params []interface{}, i int, v interface{}) bool {
+	if i >= len(params) {
+		return false
+	}
+
+	b, err := json.Marshal(params[i])
+	if err != nil {
+		return false
+	}
+
+	return json.Unmarshal(b, v) == nil
+}
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+type testRawCaller struct {
+	results map[string]string
+	calls   map[string]int
+}
+
+func (c *testRawCaller) Call(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+	c.calls[method]++
+
+	key, _ := cacheKey(method, params)
+	raw, ok := c.results[key]
+	if !ok {
+		raw = c.results[method]
+	}
+
+	return json.Unmarshal([]byte(raw), result)
+}
+
+func TestCache(t *testing.T) {
+	hash := types.Hash{1}
+	byNumber, _ := cacheKey("get_header_by_number", []interface{}{types.Uint64(90)})
+	recent, _ := cacheKey("get_header_by_number", []interface{}{types.Uint64(99)})
+
+	f := &testRawCaller{
+		results: map[string]string{
+			"get_tip_block_number": `"0x64"`,
+			"get_header":           `{"number": "0x5a"}`,
+			byNumber:               `{"number": "0x5a"}`,
+			recent:                 `{"number": "0x63"}`,
+			"get_transaction":      `{"tx_status": {"status": "committed", "block_hash": "` + hash.String() + `", "block_number": "0x5a"}}`,
+			"get_live_cell":        `{"status": "live"}`,
+		},
+		calls: make(map[string]int),
+	}
+
+	c := NewCache(f)
+	c.Confirmations = 10
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		var h struct {
+			Number types.Uint64 `json:"number"`
+		}
+
+		err := c.Call(ctx, &h, "get_header", hash)
+		if err != nil || h.Number != 90 {
+			t.Errorf("fail to call get_header: %v %v\n", err, h.Number)
+			return
+		}
+
+		c.Call(ctx, &h, "get_header_by_number", types.Uint64(90))
+		c.Call(ctx, &h, "get_header_by_number", types.Uint64(99))
+		c.Call(ctx, nil, "get_transaction", hash)
+		c.Call(ctx, nil, "get_live_cell", types.OutPoint{})
+		c.Call(ctx, nil, "get_tip_block_number")
+	}
+
+	// Immutable and confirmed responses hit, recent header and volatile
+	// ones miss
+	for method, expect := range map[string]int{
+		"get_header":           1,
+		"get_header_by_number": 3,
+		"get_transaction":      1,
+		"get_live_cell":        2,
+	} {
+		if f.calls[method] != expect {
+			t.Errorf("mismatch %s calls, expect %v, got %v", method, expect, f.calls[method])
+			return
+		}
+	}
+
+	if c.Len() != 3 {
+		t.Errorf("mismatch result, expect %v, got %v", 3, c.Len())
+		return
+	}
+
+	// Second round hit get_header, get_header_by_number 90 and
+	// get_transaction
+	if stats := c.Stats(); stats.Hits != 3 {
+		t.Errorf("mismatch stats, expect 3 hits, got %+v", stats)
+		return
+	}
+
+	// Pending transaction is volatile
+	f.results["get_transaction"] = `{"tx_status": {"status": "pending"}}`
+	c.Call(ctx, nil, "get_transaction", types.Hash{2})
+	c.Call(ctx, nil, "get_transaction", types.Hash{2})
+	if f.calls["get_transaction"] != 3 {
+		t.Errorf("mismatch result, expect %v, got %v", 3, f.calls["get_transaction"])
+		return
+	}
+
+	// Least recently used get_header_by_number 90 is evicted
+	c.MaxEntries = 2
+	c.Call(ctx, nil, "get_header", hash)
+	c.Call(ctx, nil, "get_transaction", hash)
+	c.Call(ctx, nil, "get_header", types.Hash{3})
+	if c.Len() != 2 {
+		t.Errorf("mismatch result, expect %v, got %v", 2, c.Len())
+		return
+	}
+
+	calls := f.calls["get_header_by_number"]
+	c.Call(ctx, nil, "get_header_by_number", types.Uint64(90))
+	if f.calls["get_header_by_number"] != calls+1 {
+		t.Errorf("expect evicted response fetched again")
+		return
+	}
+
+	c.Purge()
+	if c.Len() != 0 {
+		t.Errorf("mismatch result, expect %v, got %v", 0, c.Len())
+		return
+	}
+}
@@ -0,0 +1,228 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// ChainFetcher chain data by hash, immutable once found
+type ChainFetcher interface {
+	// GetBlock returns nil block if node does not know it
+	GetBlock(ctx context.Context, hash types.Hash) (*types.BlockView, error)
+	// GetHeader returns nil header if node does not know it
+	GetHeader(ctx context.Context, hash types.Hash) (*types.HeaderView, error)
+	// GetTransaction returns nil transaction if node does not know it
+	GetTransaction(ctx context.Context, hash types.Hash) (*types.TransactionView, error)
+}
+
+// Chain chain fetcher over json-rpc caller
+type Chain struct {
+	Caller Caller
+}
+
+// NewChain new chain fetcher
+func NewChain(c Caller) *Chain {
+	return &Chain{Caller: c}
+}
+
+// GetBlock get_block
+func (c *Chain) GetBlock(ctx context.Context, hash types.Hash) (*types.BlockView, error) {
+	var b *types.BlockView
+	err := c.Caller.Call(ctx, &b, "get_block", hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// GetHeader get_header
+func (c *Chain) GetHeader(ctx context.Context, hash types.Hash) (*types.HeaderView, error) {
+	var h *types.HeaderView
+	err := c.Caller.Call(ctx, &h, "get_header", hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// GetTransaction get_transaction, transaction of pending, committed or
+// rejected status alike
+func (c *Chain) GetTransaction(ctx context.Context, hash types.Hash) (*types.TransactionView, error) {
+	var tx *types.TransactionWithStatus
+	err := c.Caller.Call(ctx, &tx, "get_transaction", hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if tx == nil {
+		return nil, nil
+	}
+
+	return tx.Transaction, nil
+}
+
+// GetBlockchainInfo get_blockchain_info
+func (c *Chain) GetBlockchainInfo(ctx context.Context) (*types.ChainInfo, error) {
+	var info *types.ChainInfo
+	err := c.Caller.Call(ctx, &info, "get_blockchain_info")
+	if err != nil {
+		return nil, err
+	}
+
+	if info == nil {
+		return nil, fmt.Errorf("invalid get_blockchain_info result, null")
+	}
+
+	return info, nil
+}
+
+// GetCurrentEpoch get_current_epoch
+func (c *Chain) GetCurrentEpoch(ctx context.Context) (*types.EpochView, error) {
+	var e *types.EpochView
+	err := c.Caller.Call(ctx, &e, "get_current_epoch")
+	if err != nil {
+		return nil, err
+	}
+
+	if e == nil {
+		return nil, fmt.Errorf("invalid get_current_epoch result, null")
+	}
+
+	return e, nil
+}
+
+// GetEpochByNumber get_epoch_by_number, nil epoch if it is not reached yet
+func (c *Chain) GetEpochByNumber(ctx context.Context, number types.Uint64) (*types.EpochView, error) {
+	var e *types.EpochView
+	err := c.Caller.Call(ctx, &e, "get_epoch_by_number", number)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// CachedChain chain fetcher caching what it fetched by hash
+/*
+ * Blocks, headers and transactions never change for a given hash, so no
+ * confirmation is needed, unlike Cache rules by number. Not found results
+ * are not cached. Hits return clones, callers may mutate them. Invalidate
+ * drops one hash, for data known to be wrong, like a block from a
+ * misbehaving node.
+ */
+type CachedChain struct {
+	Fetcher    ChainFetcher
+	MaxEntries int
+
+	mu  sync.Mutex
+	lru *lru
+}
+
+// NewCachedChain new cached chain fetcher keeping DefaultCacheEntries
+func NewCachedChain(f ChainFetcher) *CachedChain {
+	return &CachedChain{Fetcher: f, MaxEntries: DefaultCacheEntries, lru: newLRU()}
+}
+
+// GetBlock implement ChainFetcher
+func (c *CachedChain) GetBlock(ctx context.Context, hash types.Hash) (*types.BlockView, error) {
+	if v, ok := c.get("block", hash); ok {
+		return v.(*types.BlockView).Clone(), nil
+	}
+
+	b, err := c.Fetcher.GetBlock(ctx, hash)
+	if err != nil || b == nil {
+		return b, err
+	}
+
+	c.put("block", hash, b.Clone())
+	return b, nil
+}
+
+// GetHeader implement ChainFetcher
+func (c *CachedChain) GetHeader(ctx context.Context, hash types.Hash) (*types.HeaderView, error) {
+	if v, ok := c.get("header", hash); ok {
+		return v.(*types.HeaderView).Clone(), nil
+	}
+
+	h, err := c.Fetcher.GetHeader(ctx, hash)
+	if err != nil || h == nil {
+		return h, err
+	}
+
+	c.put("header", hash, h.Clone())
+	return h, nil
+}
+
+// GetTransaction implement ChainFetcher
+func (c *CachedChain) GetTransaction(ctx context.Context, hash types.Hash) (*types.TransactionView, error) {
+	if v, ok := c.get("transaction", hash); ok {
+		return v.(*types.TransactionView).Clone(), nil
+	}
+
+	tx, err := c.Fetcher.GetTransaction(ctx, hash)
+	if err != nil || tx == nil {
+		return tx, err
+	}
+
+	c.put("transaction", hash, tx.Clone())
+	return tx, nil
+}
+
+// Invalidate drop cached block, header and transaction of hash, report
+// whether any was cached
+func (c *CachedChain) Invalidate(hash types.Hash) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dropped := false
+	for _, kind := range []string{"block", "header", "transaction"} {
+		if c.lru.remove(kind + hash.String()) {
+			dropped = true
+		}
+	}
+
+	return dropped
+}
+
+// Len number of cached values
+func (c *CachedChain) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.list.Len()
+}
+
+// Stats hit metrics since cache was created
+func (c *CachedChain) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.stats
+}
+
+// Purge drop every cached value
+func (c *CachedChain) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lru.purge()
+}
+
+func (c *CachedChain) get(kind string, hash types.Hash) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.get(kind + hash.String())
+}
+
+func (c *CachedChain) put(kind string, hash types.Hash, v interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lru.put(kind+hash.String(), v, c.MaxEntries)
+}
@@ -0,0 +1,183 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+type testChainFetcher struct {
+	blocks map[types.Hash]*types.BlockView
+	calls  int
+}
+
+func (f *testChainFetcher) GetBlock(ctx context.Context, hash types.Hash) (*types.BlockView, error) {
+	f.calls++
+	return f.blocks[hash], nil
+}
+
+func (f *testChainFetcher) GetHeader(ctx context.Context, hash types.Hash) (*types.HeaderView, error) {
+	f.calls++
+	if b, ok := f.blocks[hash]; ok {
+		return &b.Header, nil
+	}
+
+	return nil, nil
+}
+
+func (f *testChainFetcher) GetTransaction(ctx context.Context, hash types.Hash) (*types.TransactionView, error) {
+	f.calls++
+	return nil, nil
+}
+
+func TestCachedChain(t *testing.T) {
+	a := &types.BlockView{Header: types.HeaderView{Header: types.Header{Number: 1}, Hash: types.Hash{1}}, Proposals: []types.ProposalShortID{{1}}}
+	b := &types.BlockView{Header: types.HeaderView{Header: types.Header{Number: 2}, Hash: types.Hash{2}}}
+
+	f := &testChainFetcher{blocks: map[types.Hash]*types.BlockView{a.Header.Hash: a, b.Header.Hash: b}}
+	c := NewCachedChain(f)
+	ctx := context.Background()
+
+	// Miss then hits, callers mutating results do not touch cache
+	for i := 0; i < 3; i++ {
+		got, err := c.GetBlock(ctx, a.Header.Hash)
+		if err != nil || got.Header.Header.Number != 1 || got.Proposals[0][0] != 1 {
+			t.Errorf("fail to get block: %v %v\n", got, err)
+			return
+		}
+
+		got.Proposals[0][0] = 0xff
+	}
+
+	if f.calls != 1 {
+		t.Errorf("mismatch result, expect 1 call, got %d", f.calls)
+		return
+	}
+
+	stats := c.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 {
+		t.Errorf("mismatch stats, expect 2 hits and 1 miss, got %+v", stats)
+		return
+	}
+
+	// Not found is not cached
+	c.GetTransaction(ctx, types.Hash{3})
+	c.GetTransaction(ctx, types.Hash{3})
+	if f.calls != 3 || c.Len() != 1 {
+		t.Errorf("mismatch result, expect not found fetched twice, got %d calls %d cached", f.calls, c.Len())
+		return
+	}
+
+	c.GetHeader(ctx, a.Header.Hash)
+	if !c.Invalidate(a.Header.Hash) || c.Len() != 0 {
+		t.Errorf("mismatch result, expect block and header of a invalidated, got %d cached", c.Len())
+		return
+	}
+
+	if c.Invalidate(a.Header.Hash) {
+		t.Errorf("mismatch result, expect nothing left to invalidate")
+		return
+	}
+
+	calls := f.calls
+	c.GetBlock(ctx, a.Header.Hash)
+	if f.calls != calls+1 {
+		t.Errorf("expect invalidated block fetched again")
+		return
+	}
+
+	// Least recently used a is evicted
+	c.MaxEntries = 1
+	c.GetBlock(ctx, b.Header.Hash)
+	if c.Len() != 1 || c.Stats().Evictions != 1 {
+		t.Errorf("mismatch result, expect 1 cached and 1 eviction, got %d %+v", c.Len(), c.Stats())
+		return
+	}
+
+	c.Purge()
+	if c.Len() != 0 {
+		t.Errorf("mismatch result, expect %v, got %v", 0, c.Len())
+		return
+	}
+}
+
+func TestChain(t *testing.T) {
+	f := &testRawCaller{
+		results: map[string]string{
+			"get_transaction": `{"transaction": {"version": "0x0", "cell_deps": [], "header_deps": [], "inputs": [], "outputs": [], "outputs_data": [], "witnesses": [], "hash": "0x0100000000000000000000000000000000000000000000000000000000000000"}, "tx_status": {"status": "pending"}}`,
+			"get_header":      `null`,
+		},
+		calls: make(map[string]int),
+	}
+
+	var fetcher ChainFetcher = NewCachedChain(NewChain(f))
+	ctx := context.Background()
+
+	tx, err := fetcher.GetTransaction(ctx, types.Hash{1})
+	if err != nil || tx == nil || tx.Hash != (types.Hash{1}) {
+		t.Errorf("fail to get transaction: %v %v\n", tx, err)
+		return
+	}
+
+	h, err := fetcher.GetHeader(ctx, types.Hash{1})
+	if err != nil || h != nil {
+		t.Errorf("mismatch result, expect nil header, got %v %v", h, err)
+		return
+	}
+}
+
+func TestChainBlockchainInfo(t *testing.T) {
+	c := &testRecordCaller{result: `{"alerts":[],"chain":"ckb_testnet","difficulty":"0x1f4003","epoch":"0x7080018000001","is_initial_block_download":false,"median_time":"0x5cd2b105"}`}
+	chain := NewChain(c)
+
+	info, err := chain.GetBlockchainInfo(context.Background())
+	if err != nil {
+		t.Errorf("fail to get blockchain info: %s\n", err)
+		return
+	}
+
+	if c.method != "get_blockchain_info" || len(c.params) != 0 || info.Chain != "ckb_testnet" || info.Epoch != 0x7080018000001 {
+		t.Errorf("mismatch result, got %s %v %+v", c.method, c.params, info)
+		return
+	}
+
+	c.result = "null"
+	_, err = chain.GetBlockchainInfo(context.Background())
+	if err == nil {
+		t.Errorf("expect error on null result")
+		return
+	}
+}
+
+func TestChainEpoch(t *testing.T) {
+	c := &testRecordCaller{result: `{"compact_target":"0x1e083126","length":"0x708","number":"0x1","start_number":"0x3e8"}`}
+	chain := NewChain(c)
+	ctx := context.Background()
+
+	e, err := chain.GetCurrentEpoch(ctx)
+	if err != nil || c.method != "get_current_epoch" || e.Number != 1 || e.StartNumber != 0x3e8 || e.Length != 0x708 {
+		t.Errorf("fail to get current epoch: %v %s %+v\n", err, c.method, e)
+		return
+	}
+
+	e, err = chain.GetEpochByNumber(ctx, 1)
+	if err != nil || c.method != "get_epoch_by_number" || c.params[0] != types.Uint64(1) || e.Number != 1 {
+		t.Errorf("fail to get epoch by number: %v %s %v %+v\n", err, c.method, c.params, e)
+		return
+	}
+
+	// Epoch not reached yet
+	c.result = "null"
+	e, err = chain.GetEpochByNumber(ctx, 100)
+	if err != nil || e != nil {
+		t.Errorf("mismatch result, expect nil epoch, got %+v %v", e, err)
+		return
+	}
+
+	_, err = chain.GetCurrentEpoch(ctx)
+	if err == nil {
+		t.Errorf("expect error on null current epoch")
+		return
+	}
+}
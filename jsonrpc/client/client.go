@@ -0,0 +1,179 @@
+// Package client wraps ckb json-rpc calls behind one Caller interface, so
+// failover, logging and caching layers stack on any transport.
+//
+// HTTPClient is a minimal json-rpc over http transport. Anything with the
+// same Call method, an rpc library client or a test fake, works as well.
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Caller ckb json-rpc call, decode result into result, a pointer, or
+// discard it if result is nil
+type Caller interface {
+	Call(ctx context.Context, result interface{}, method string, params ...interface{}) error
+}
+
+// Error json-rpc error object returned by node, node answered so retrying
+// another node gives the same answer
+type Error struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Error implement error
+func (e *Error) Error() string {
+	return fmt.Sprintf("json-rpc error %d, %s", e.Code, e.Message)
+}
+
+// HTTPClient json-rpc over http caller
+/*
+ * Responses are requested gzip compressed and decompressed here, whatever
+ * transport HTTP uses, full blocks as json compress several times. The
+ * default transport keeps connections alive and attempts HTTP/2 over
+ * TLS, so concurrent calls to a https node share one connection. Plain
+ * http nodes speak HTTP/1.1, net/http has no cleartext HTTP/2.
+ */
+type HTTPClient struct {
+	URL string
+	// Header extra headers of every request, like authorization
+	Header http.Header
+	// HTTP http client, one over NewHTTPTransport if nil
+	HTTP *http.Client
+	// DisableCompression do not ask for gzip responses
+	DisableCompression bool
+	// Logger log http exchanges at debug level, headers redacted, nil
+	// logs nothing
+	Logger Logger
+
+	id uint64
+}
+
+// NewHTTPClient new http caller of node url
+func NewHTTPClient(url string) *HTTPClient {
+	return &HTTPClient{URL: url}
+}
+
+// defaultHTTP http client of HTTPClient without one
+var defaultHTTP = &http.Client{Transport: NewHTTPTransport()}
+
+// NewHTTPTransport http transport tuned for rpc, keeps idle connections
+// per host for concurrent calls and attempts HTTP/2 over TLS
+func NewHTTPTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   16,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+type request struct {
+	ID      uint64        `json:"id"`
+	JSONRPC string        `json:"jsonrpc"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type response struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *Error          `json:"error"`
+}
+
+// Call implement Caller
+func (c *HTTPClient) Call(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+
+	id := atomic.AddUint64(&c.id, 1)
+	body, err := json.Marshal(&request{ID: id, JSONRPC: "2.0", Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	for k, v := range c.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// Set explicitly, so decompression does not depend on transport
+	if !c.DisableCompression {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	h := c.HTTP
+	if h == nil {
+		h = defaultHTTP
+	}
+
+	resp, err := h.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if c.Logger != nil {
+		c.Logger.DebugContext(ctx, "rpc http request", "url", c.URL, "method", method, "header", RedactHeader(req.Header), "status", resp.StatusCode, "proto", resp.Proto)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s failed, http status %s", method, resp.Status)
+	}
+
+	var rd io.Reader = resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return fmt.Errorf("invalid %s response, %s", method, err)
+		}
+		defer zr.Close()
+
+		rd = zr
+	}
+
+	var r response
+	err = json.NewDecoder(rd).Decode(&r)
+	if err != nil {
+		return fmt.Errorf("invalid %s response, %s", method, err)
+	}
+
+	if r.ID != id {
+		return fmt.Errorf("invalid %s response, id %d for request %d", method, r.ID, id)
+	}
+
+	if r.Error != nil {
+		return r.Error
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(r.Result, result)
+}
@@ -0,0 +1,45 @@
+//go:build go1.14
+// +build go1.14
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+func TestHTTPClientHTTP2(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		json.NewDecoder(r.Body).Decode(&req)
+
+		result := "0x1"
+		if r.ProtoMajor == 2 {
+			result = "0x2"
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	// Default transport with test server certificate
+	transport := NewHTTPTransport()
+	transport.TLSClientConfig = srv.Client().Transport.(*http.Transport).TLSClientConfig
+
+	c := NewHTTPClient(srv.URL)
+	c.HTTP = &http.Client{Transport: transport}
+
+	var proto types.Uint64
+	err := c.Call(context.Background(), &proto, "get_tip_block_number")
+	if err != nil || proto != 2 {
+		t.Errorf("mismatch result, expect http/2, got http/%d %v", proto, err)
+		return
+	}
+}
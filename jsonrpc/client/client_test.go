@@ -0,0 +1,100 @@
+package client
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+func TestHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil || r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "get_tip_block_number":
+			resp["result"] = "0x400"
+		default:
+			resp["error"] = map[string]interface{}{"code": -32601, "message": "Method not found"}
+		}
+
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	c := NewHTTPClient(srv.URL)
+	c.Header = http.Header{"Authorization": {"Bearer secret"}}
+
+	var tip types.Uint64
+	err := c.Call(context.Background(), &tip, "get_tip_block_number")
+	if err != nil {
+		t.Errorf("fail to call: %s\n", err)
+		return
+	}
+
+	if tip != 0x400 {
+		t.Errorf("mismatch result, expect %v, got %v", 0x400, tip)
+		return
+	}
+
+	err = c.Call(context.Background(), nil, "get_foo", 1)
+	if e, ok := err.(*Error); !ok || e.Code != -32601 {
+		t.Errorf("mismatch result, expect json-rpc error, got %v", err)
+		return
+	}
+
+	c.Header = nil
+	err = c.Call(context.Background(), &tip, "get_tip_block_number")
+	if _, ok := err.(*Error); err == nil || ok {
+		t.Errorf("expect http error, got %v", err)
+		return
+	}
+}
+
+func TestHTTPClientCompression(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		json.NewDecoder(r.Body).Decode(&req)
+
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": "0x400"}
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			json.NewEncoder(w).Encode(resp)
+			return
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		json.NewEncoder(zw).Encode(resp)
+		zw.Close()
+	}))
+	defer srv.Close()
+
+	// Transport not decompressing, client does it
+	c := NewHTTPClient(srv.URL)
+	c.HTTP = &http.Client{Transport: &http.Transport{DisableCompression: true}}
+
+	var tip types.Uint64
+	err := c.Call(context.Background(), &tip, "get_tip_block_number")
+	if err != nil || tip != 0x400 {
+		t.Errorf("fail to call with gzip response: %v %v\n", err, tip)
+		return
+	}
+
+	c.DisableCompression = true
+	tip = 0
+	err = c.Call(context.Background(), &tip, "get_tip_block_number")
+	if err != nil || tip != 0x400 {
+		t.Errorf("fail to call without compression: %v %v\n", err, tip)
+		return
+	}
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// Experiment ckb experiment module rpc
+type Experiment struct {
+	Caller Caller
+}
+
+// NewExperiment new experiment module client
+func NewExperiment(c Caller) *Experiment {
+	return &Experiment{Caller: c}
+}
+
+// CalculateDaoMaximumWithdraw calculate_dao_maximum_withdraw, maximum
+// capacity of dao deposit cell at out point withdrawn at kind, kind is
+// checked before call
+func (e *Experiment) CalculateDaoMaximumWithdraw(ctx context.Context, outPoint types.OutPoint, kind types.DaoWithdrawingCalculationKind) (types.Uint64, error) {
+	_, err := kind.MarshalJSON()
+	if err != nil {
+		return 0, err
+	}
+
+	var capacity types.Uint64
+	err = e.Caller.Call(ctx, &capacity, "calculate_dao_maximum_withdraw", outPoint, kind)
+	if err != nil {
+		return 0, err
+	}
+
+	return capacity, nil
+}
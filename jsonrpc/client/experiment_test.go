@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+func TestCalculateDaoMaximumWithdraw(t *testing.T) {
+	c := &testRecordCaller{result: `"0x4a8b3e970d"`}
+	e := NewExperiment(c)
+	ctx := context.Background()
+
+	deposit := types.OutPoint{TxHash: types.Hash{1}}
+	header := types.Hash{2}
+	withdrawing := types.OutPoint{TxHash: types.Hash{3}, Index: 1}
+
+	for _, kind := range []struct {
+		kind   types.DaoWithdrawingCalculationKind
+		expect string
+	}{
+		{types.DaoWithdrawingCalculationKind{WithdrawingHeaderHash: &header}, `"type":"withdrawing_header_hash"`},
+		{types.DaoWithdrawingCalculationKind{WithdrawingOutPoint: &withdrawing}, `"type":"withdrawing_out_point"`},
+	} {
+		capacity, err := e.CalculateDaoMaximumWithdraw(ctx, deposit, kind.kind)
+		if err != nil || capacity != 0x4a8b3e970d {
+			t.Errorf("fail to calculate dao maximum withdraw: %v %v\n", err, capacity)
+			return
+		}
+
+		if c.method != "calculate_dao_maximum_withdraw" || len(c.params) != 2 || c.params[0] != deposit {
+			t.Errorf("mismatch call, got %s %v", c.method, c.params)
+			return
+		}
+
+		raw, err := json.Marshal(c.params[1])
+		if err != nil || !strings.Contains(string(raw), kind.expect) {
+			t.Errorf("mismatch kind param, expect %s, got %s %v", kind.expect, raw, err)
+			return
+		}
+	}
+
+	c.method = ""
+	_, err := e.CalculateDaoMaximumWithdraw(ctx, deposit, types.DaoWithdrawingCalculationKind{})
+	if err == nil || c.method != "" {
+		t.Errorf("expect error before call on empty kind")
+		return
+	}
+}
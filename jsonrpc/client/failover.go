@@ -0,0 +1,240 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// Endpoint named node caller
+type Endpoint struct {
+	// Name shown in status and errors, usually node url
+	Name   string
+	Caller Caller
+}
+
+// EndpointStatus health of endpoint
+type EndpointStatus struct {
+	Name    string
+	Healthy bool
+	// Tip tip block number seen by last health check
+	Tip types.Uint64
+	// Err last failure, nil if healthy
+	Err error
+}
+
+// Failover caller over several nodes, failing over on transport errors and
+// timeouts
+/*
+ * Healthy endpoints are tried first in given order, unhealthy ones last,
+ * so a call only fails if every node fails. An endpoint turns unhealthy
+ * when a call or health check fails and healthy again when one succeeds.
+ *
+ * Json-rpc error objects are answers from node and returned as is, while
+ * transport errors, bad http status and timeouts fail over. Writes like
+ * send_transaction may hit a second node after a timeout, which is safe
+ * for ckb since transactions are identified by hash.
+ *
+ * With LoadBalanceReads, get_ calls rotate over healthy endpoints.
+ */
+type Failover struct {
+	// Timeout per attempt, zero waits for caller context only
+	Timeout time.Duration
+	// LoadBalanceReads rotate read calls over healthy endpoints
+	LoadBalanceReads bool
+	// MaxTipLag health check marks endpoints whose tip is more than this
+	// behind the best one unhealthy, zero disables
+	MaxTipLag uint64
+
+	mu        sync.Mutex
+	endpoints []*endpoint
+	next      int
+}
+
+// endpoint endpoint and its health
+type endpoint struct {
+	Endpoint
+	healthy bool
+	tip     types.Uint64
+	err     error
+}
+
+// NewFailover new failover caller, endpoints start healthy
+func NewFailover(endpoints []Endpoint) (*Failover, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("invalid failover, no endpoint")
+	}
+
+	f := new(Failover)
+	for _, e := range endpoints {
+		if e.Caller == nil {
+			return nil, fmt.Errorf("invalid failover endpoint %s, nil caller", e.Name)
+		}
+
+		f.endpoints = append(f.endpoints, &endpoint{Endpoint: e, healthy: true})
+	}
+
+	return f, nil
+}
+
+// NewHTTPFailover new failover caller of node urls over http
+func NewHTTPFailover(urls ...string) (*Failover, error) {
+	endpoints := make([]Endpoint, len(urls))
+	for i, u := range urls {
+		endpoints[i] = Endpoint{Name: u, Caller: NewHTTPClient(u)}
+	}
+
+	return NewFailover(endpoints)
+}
+
+// Call implement Caller
+func (f *Failover) Call(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+	var errs []string
+	for _, e := range f.order(method) {
+		err := f.attempt(ctx, e, result, method, params)
+		if err != nil && ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		f.report(e, err)
+		if err == nil {
+			return nil
+		}
+
+		if _, ok := err.(*Error); ok {
+			return err
+		}
+
+		errs = append(errs, fmt.Sprintf("%s: %s", e.Name, err))
+	}
+
+	return fmt.Errorf("%s failed on every endpoint, %s", method, strings.Join(errs, "; "))
+}
+
+// CheckHealth call get_tip_block_number on every endpoint concurrently and
+// update health
+func (f *Failover) CheckHealth(ctx context.Context) {
+	f.mu.Lock()
+	endpoints := append([]*endpoint{}, f.endpoints...)
+	f.mu.Unlock()
+
+	tips := make([]types.Uint64, len(endpoints))
+	errs := make([]error, len(endpoints))
+
+	var wg sync.WaitGroup
+	for i := range endpoints {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = f.attempt(ctx, endpoints[i], &tips[i], "get_tip_block_number", nil)
+		}(i)
+	}
+	wg.Wait()
+
+	var best types.Uint64
+	for i := range endpoints {
+		if errs[i] == nil && tips[i] > best {
+			best = tips[i]
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, e := range endpoints {
+		err := errs[i]
+		if err == nil && f.MaxTipLag != 0 && uint64(best-tips[i]) > f.MaxTipLag {
+			err = fmt.Errorf("tip %d lags best %d", tips[i], best)
+		}
+
+		e.healthy, e.err = err == nil, err
+		if errs[i] == nil {
+			e.tip = tips[i]
+		}
+	}
+}
+
+// Run check health every interval until ctx is done
+func (f *Failover) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		f.CheckHealth(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Status health of endpoints, in given order
+func (f *Failover) Status() []EndpointStatus {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	status := make([]EndpointStatus, len(f.endpoints))
+	for i, e := range f.endpoints {
+		status[i] = EndpointStatus{Name: e.Name, Healthy: e.healthy, Tip: e.tip, Err: e.err}
+	}
+
+	return status
+}
+
+// order endpoints to try, healthy first, rotated for balanced reads
+func (f *Failover) order(method string) []*endpoint {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var healthy, unhealthy []*endpoint
+	for _, e := range f.endpoints {
+		if e.healthy {
+			healthy = append(healthy, e)
+		} else {
+			unhealthy = append(unhealthy, e)
+		}
+	}
+
+	if f.LoadBalanceReads && isReadMethod(method) && len(healthy) > 1 {
+		n := f.next % len(healthy)
+		f.next++
+		healthy = append(healthy[n:], healthy[:n]...)
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+// attempt call endpoint, bounded by timeout
+func (f *Failover) attempt(ctx context.Context, e *endpoint, result interface{}, method string, params []interface{}) error {
+	if f.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.Timeout)
+		defer cancel()
+	}
+
+	return e.Caller.Call(ctx, result, method, params...)
+}
+
+// report update endpoint health by call result, json-rpc errors are
+// answers and keep endpoint healthy
+func (f *Failover) report(e *endpoint, err error) {
+	if _, ok := err.(*Error); ok {
+		err = nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	e.healthy, e.err = err == nil, err
+}
+
+// isReadMethod report whether method only reads chain state
+func isReadMethod(method string) bool {
+	return strings.HasPrefix(method, "get_") || strings.HasPrefix(method, "estimate_")
+}
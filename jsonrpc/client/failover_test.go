@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+type testCaller struct {
+	tip   types.Uint64
+	err   error
+	delay time.Duration
+	calls int
+}
+
+func (c *testCaller) Call(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+	c.calls++
+
+	if c.delay != 0 {
+		select {
+		case <-time.After(c.delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if c.err != nil {
+		return c.err
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	b, _ := json.Marshal(c.tip)
+	return json.Unmarshal(b, result)
+}
+
+func TestFailover(t *testing.T) {
+	a := &testCaller{err: fmt.Errorf("connection refused")}
+	b := &testCaller{tip: 100, delay: time.Second}
+	c := &testCaller{tip: 101}
+
+	f, err := NewFailover([]Endpoint{{"a", a}, {"b", b}, {"c", c}})
+	if err != nil {
+		t.Errorf("fail to create failover: %s\n", err)
+		return
+	}
+	f.Timeout = 10 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// a refuses, b times out, c answers
+	var tip types.Uint64
+	err = f.Call(ctx, &tip, "get_tip_block_number")
+	if err != nil {
+		t.Errorf("fail to call: %s\n", err)
+		return
+	}
+
+	if tip != 101 {
+		t.Errorf("mismatch result, expect %v, got %v", 101, tip)
+		return
+	}
+
+	status := f.Status()
+	if status[0].Healthy || status[1].Healthy || !status[2].Healthy {
+		t.Errorf("mismatch health, got %v", status)
+		return
+	}
+
+	// Healthy c goes first now
+	err = f.Call(ctx, &tip, "get_tip_block_number")
+	if err != nil || c.calls != 2 || a.calls != 1 {
+		t.Errorf("expect only c called, got %v %v %v", err, a.calls, c.calls)
+		return
+	}
+
+	// Node answers are not failed over
+	c.err = &Error{Code: -1, Message: "PoolRejectedDuplicatedTransaction"}
+	err = f.Call(ctx, nil, "send_transaction")
+	if e, ok := err.(*Error); !ok || e.Code != -1 || a.calls != 1 || !f.Status()[2].Healthy {
+		t.Errorf("expect json-rpc error from c, got %v", err)
+		return
+	}
+
+	c.err = fmt.Errorf("connection reset")
+	err = f.Call(ctx, &tip, "get_tip_block_number")
+	if err == nil {
+		t.Errorf("expect error when every endpoint fails")
+		return
+	}
+}
+
+func TestFailoverHealth(t *testing.T) {
+	a := &testCaller{tip: 90}
+	b := &testCaller{tip: 100}
+	c := &testCaller{err: fmt.Errorf("connection refused")}
+
+	f, _ := NewFailover([]Endpoint{{"a", a}, {"b", b}, {"c", c}})
+	f.MaxTipLag = 5
+	f.LoadBalanceReads = true
+
+	f.CheckHealth(context.Background())
+
+	status := f.Status()
+	if status[0].Healthy || !status[1].Healthy || status[2].Healthy || status[1].Tip != 100 {
+		t.Errorf("mismatch health, got %v", status)
+		return
+	}
+
+	// Lagging a catches up
+	a.tip = 98
+	f.CheckHealth(context.Background())
+	if !f.Status()[0].Healthy {
+		t.Errorf("expect a healthy, got %v", f.Status())
+		return
+	}
+
+	// Reads rotate over healthy a and b, writes stick to first
+	a.calls, b.calls = 0, 0
+	for i := 0; i < 4; i++ {
+		f.Call(context.Background(), new(types.Uint64), "get_tip_block_number")
+	}
+
+	if a.calls != 2 || b.calls != 2 {
+		t.Errorf("mismatch balanced reads, got %v %v", a.calls, b.calls)
+		return
+	}
+
+	for i := 0; i < 2; i++ {
+		f.Call(context.Background(), new(types.Uint64), "send_transaction")
+	}
+
+	if a.calls != 4 || b.calls != 2 {
+		t.Errorf("mismatch writes, got %v %v", a.calls, b.calls)
+		return
+	}
+}
@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// ErrRichIndexerRequired search key uses filters only ckb-rich-indexer
+// supports, and node runs the standard indexer
+var ErrRichIndexerRequired = errors.New("search key requires ckb-rich-indexer")
+
+// codeInvalidParams json-rpc invalid params error code
+const codeInvalidParams = -32602
+
+// Indexer ckb indexer rpc, the same methods serve standard and rich
+// indexer
+/*
+ * Node does not report which indexer it runs. The standard one answers
+ * rich only search keys, like partial script search or output data
+ * filter, with an invalid params error, so RichIndexer probes get_cells with a
+ * partial search key once and remembers the answer. GetCells checks it
+ * before sending a rich only key, failing with ErrRichIndexerRequired.
+ */
+type Indexer struct {
+	Caller Caller
+
+	mu      sync.Mutex
+	rich    *bool
+	probing chan struct{}
+}
+
+// NewIndexer new indexer client
+func NewIndexer(c Caller) *Indexer {
+	return &Indexer{Caller: c}
+}
+
+// GetIndexerTip get_indexer_tip
+func (i *Indexer) GetIndexerTip(ctx context.Context) (*types.IndexerTip, error) {
+	var tip *types.IndexerTip
+	err := i.Caller.Call(ctx, &tip, "get_indexer_tip")
+	if err != nil {
+		return nil, err
+	}
+
+	return tip, nil
+}
+
+// GetCells get_cells, implement types.CellsFetcher, after is nil for
+// first page
+func (i *Indexer) GetCells(ctx context.Context, key *types.SearchKey, order types.SearchOrder, limit types.Uint32, after *types.Bytes) (*types.IndexerCells, error) {
+	if key.RequiresRichIndexer() {
+		rich, err := i.RichIndexer(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if !rich {
+			return nil, ErrRichIndexerRequired
+		}
+	}
+
+	params := []interface{}{key, order, limit}
+	if after != nil {
+		params = append(params, after)
+	}
+
+	var cells *types.IndexerCells
+	err := i.Caller.Call(ctx, &cells, "get_cells", params...)
+	if err != nil {
+		return nil, err
+	}
+
+	return cells, nil
+}
+
+// RichIndexer report whether node runs ckb-rich-indexer, probed on first
+// call, probe is retried if node does not answer it with a verdict
+/*
+ * Only one probe is in flight, concurrent callers wait for it without
+ * holding the lock, or give up when their ctx is done.
+ */
+func (i *Indexer) RichIndexer(ctx context.Context) (bool, error) {
+	for {
+		i.mu.Lock()
+		if i.rich != nil {
+			rich := *i.rich
+			i.mu.Unlock()
+			return rich, nil
+		}
+
+		wait := i.probing
+		if wait == nil {
+			i.probing = make(chan struct{})
+			i.mu.Unlock()
+			return i.probe(ctx)
+		}
+		i.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+}
+
+// probe get_cells with partial search key, remember verdict
+func (i *Indexer) probe(ctx context.Context) (bool, error) {
+	partial := types.Partial
+	key := &types.SearchKey{
+		Script:           types.Script{HashType: types.Data, Args: types.Bytes{}},
+		ScriptType:       types.ScriptTypeLock,
+		ScriptSearchMode: &partial,
+	}
+
+	var cells *types.IndexerCells
+	err := i.Caller.Call(ctx, &cells, "get_cells", key, types.Asc, types.Uint32(1))
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	defer func() {
+		close(i.probing)
+		i.probing = nil
+	}()
+
+	// Other errors, like indexer module disabled, say nothing about which
+	// indexer node runs
+	if err != nil && !refusesPartialSearch(err) {
+		return false, err
+	}
+
+	rich := err == nil
+	i.rich = &rich
+
+	return rich, nil
+}
+
+// refusesPartialSearch report whether err is standard indexer refusing
+// partial script search mode as invalid params
+func refusesPartialSearch(err error) bool {
+	e, ok := err.(*Error)
+	return ok && e.Code == codeInvalidParams && strings.Contains(e.Message, "partial search mode")
+}
@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+type testIndexerCaller struct {
+	rich  bool
+	err   error
+	calls int32
+	// gate blocks calls until closed, if set
+	gate chan struct{}
+}
+
+func (c *testIndexerCaller) Call(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+	atomic.AddInt32(&c.calls, 1)
+	if c.gate != nil {
+		<-c.gate
+	}
+
+	if c.err != nil {
+		return c.err
+	}
+
+	if method != "get_cells" {
+		return &Error{Code: -32601, Message: "Method not found"}
+	}
+
+	if params[0].(*types.SearchKey).RequiresRichIndexer() && !c.rich {
+		return &Error{Code: -32602, Message: "the CKB indexer doesn't support partial search mode"}
+	}
+
+	return json.Unmarshal([]byte(`{"objects": [], "last_cursor": "0x"}`), result)
+}
+
+func TestIndexerRichIndexer(t *testing.T) {
+	partial := types.Partial
+	key := &types.SearchKey{ScriptType: types.ScriptTypeLock, ScriptSearchMode: &partial}
+	ctx := context.Background()
+
+	c := &testIndexerCaller{}
+	i := NewIndexer(c)
+
+	_, err := i.GetCells(ctx, key, types.Asc, 10, nil)
+	if err != ErrRichIndexerRequired {
+		t.Errorf("mismatch result, expect %v, got %v", ErrRichIndexerRequired, err)
+		return
+	}
+
+	// Probe result is remembered, plain key does not probe
+	_, err = i.GetCells(ctx, &types.SearchKey{ScriptType: types.ScriptTypeLock}, types.Asc, 10, nil)
+	if err != nil || c.calls != 2 {
+		t.Errorf("mismatch result, expect 2 calls, got %d %v", c.calls, err)
+		return
+	}
+
+	c = &testIndexerCaller{rich: true}
+	i = NewIndexer(c)
+
+	cells, err := i.GetCells(ctx, key, types.Asc, 10, nil)
+	if err != nil || cells == nil {
+		t.Errorf("fail to get cells from rich indexer: %v\n", err)
+		return
+	}
+
+	if c.calls != 2 {
+		t.Errorf("mismatch result, expect probe and get_cells, got %d calls", c.calls)
+		return
+	}
+
+	// Failed probe is not remembered
+	c = &testIndexerCaller{err: fmt.Errorf("connection refused")}
+	i = NewIndexer(c)
+
+	_, err = i.RichIndexer(ctx)
+	if err == nil {
+		t.Errorf("expect error on failed probe")
+		return
+	}
+
+	c.err = nil
+	c.rich = true
+	rich, err := i.RichIndexer(ctx)
+	if err != nil || !rich {
+		t.Errorf("mismatch result, expect rich indexer, got %v %v", rich, err)
+		return
+	}
+}
+
+func TestIndexerRichIndexerNodeError(t *testing.T) {
+	ctx := context.Background()
+
+	// Indexer module disabled is not a verdict
+	c := &testIndexerCaller{err: &Error{Code: -32601, Message: "Method not found"}}
+	i := NewIndexer(c)
+
+	_, err := i.RichIndexer(ctx)
+	if e, ok := err.(*Error); !ok || e.Code != -32601 {
+		t.Errorf("mismatch result, expect method not found, got %v", err)
+		return
+	}
+
+	// Other invalid params neither
+	c.err = &Error{Code: -32602, Message: "Invalid params"}
+	_, err = i.RichIndexer(ctx)
+	if err == nil {
+		t.Errorf("expect error on unrelated invalid params")
+		return
+	}
+
+	c.err = nil
+	rich, err := i.RichIndexer(ctx)
+	if err != nil || rich {
+		t.Errorf("mismatch result, expect standard indexer, got %v %v", rich, err)
+		return
+	}
+
+	if c.calls != 3 {
+		t.Errorf("mismatch result, expect 3 probes, got %d", c.calls)
+		return
+	}
+}
+
+func TestIndexerRichIndexerSingleProbe(t *testing.T) {
+	c := &testIndexerCaller{rich: true, gate: make(chan struct{})}
+	i := NewIndexer(c)
+
+	var wg sync.WaitGroup
+	results := make([]bool, 4)
+	for n := range results {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			results[n], _ = i.RichIndexer(context.Background())
+		}(n)
+	}
+
+	// Waiter gives up with its ctx while probe is in flight
+	for atomic.LoadInt32(&c.calls) == 0 {
+		runtime.Gosched()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := i.RichIndexer(ctx)
+	if err != context.Canceled {
+		t.Errorf("mismatch result, expect %v, got %v", context.Canceled, err)
+		return
+	}
+
+	close(c.gate)
+	wg.Wait()
+
+	for n, rich := range results {
+		if !rich {
+			t.Errorf("mismatch result %d, expect rich indexer", n)
+			return
+		}
+	}
+
+	if c.calls != 1 {
+		t.Errorf("mismatch result, expect 1 probe, got %d", c.calls)
+		return
+	}
+}
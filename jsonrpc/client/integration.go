@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// IntegrationTest ckb integration test module rpc, enabled on dev chain
+// nodes to mine and rewind on demand in end to end tests
+type IntegrationTest struct {
+	Caller Caller
+}
+
+// NewIntegrationTest new integration test module client
+func NewIntegrationTest(c Caller) *IntegrationTest {
+	return &IntegrationTest{Caller: c}
+}
+
+// GenerateBlock generate_block, mine one block with pool transactions,
+// returns its hash
+func (i *IntegrationTest) GenerateBlock(ctx context.Context) (types.Hash, error) {
+	var hash types.Hash
+	err := i.Caller.Call(ctx, &hash, "generate_block")
+	if err != nil {
+		return types.Hash{}, err
+	}
+
+	return hash, nil
+}
+
+// GenerateEpochs generate_epochs, mine blocks for epochs, fraction
+// included, returns epoch reached
+func (i *IntegrationTest) GenerateEpochs(ctx context.Context, epochs types.EpochNumberWithFraction) (types.EpochNumberWithFraction, error) {
+	if !epochs.IsWellFormedIncrement() {
+		return types.EpochNumberWithFraction{}, fmt.Errorf("invalid generate_epochs epochs %s", epochs)
+	}
+
+	var reached types.Uint64
+	err := i.Caller.Call(ctx, &reached, "generate_epochs", epochs.Uint64())
+	if err != nil {
+		return types.EpochNumberWithFraction{}, err
+	}
+
+	return types.NewEpochNumberWithFraction(reached), nil
+}
+
+// Truncate truncate, rewind chain to block of hash, blocks above it and
+// their transactions are dropped
+func (i *IntegrationTest) Truncate(ctx context.Context, tipHash types.Hash) error {
+	return i.Caller.Call(ctx, nil, "truncate", tipHash)
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+func TestIntegrationTest(t *testing.T) {
+	c := &testRecordCaller{result: `"0x0100000000000000000000000000000000000000000000000000000000000000"`}
+	i := NewIntegrationTest(c)
+	ctx := context.Background()
+
+	hash, err := i.GenerateBlock(ctx)
+	if err != nil || c.method != "generate_block" || hash != (types.Hash{1}) {
+		t.Errorf("fail to generate block: %v %s %v\n", err, c.method, hash)
+		return
+	}
+
+	// 1.5 epochs from 1(500/1000)
+	c.result = `"0x3e80000000003"`
+	epoch, err := i.GenerateEpochs(ctx, types.EpochNumberWithFraction{Number: 1, Index: 1, Length: 2})
+	if err != nil || c.method != "generate_epochs" {
+		t.Errorf("fail to generate epochs: %v %s\n", err, c.method)
+		return
+	}
+
+	if c.params[0] != types.Uint64(0x20001000001) {
+		t.Errorf("mismatch epochs param, expect %v, got %v", types.Uint64(0x20001000001), c.params[0])
+		return
+	}
+
+	if epoch.Number != 3 || epoch.Index != 0 || epoch.Length != 1000 {
+		t.Errorf("mismatch result, expect 3(0/1000), got %s", epoch)
+		return
+	}
+
+	// Whole epochs
+	_, err = i.GenerateEpochs(ctx, types.EpochNumberWithFraction{Number: 2})
+	if err != nil || c.params[0] != types.Uint64(2) {
+		t.Errorf("fail to generate whole epochs: %v %v\n", err, c.params)
+		return
+	}
+
+	_, err = i.GenerateEpochs(ctx, types.EpochNumberWithFraction{Number: 1, Index: 2, Length: 2})
+	if err == nil {
+		t.Errorf("expect error on malformed epochs")
+		return
+	}
+
+	err = i.Truncate(ctx, types.Hash{2})
+	if err != nil || c.method != "truncate" || c.params[0] != (types.Hash{2}) {
+		t.Errorf("fail to truncate: %v %s %v\n", err, c.method, c.params)
+		return
+	}
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// LightClient ckb light client script rpc
+type LightClient struct {
+	Caller Caller
+}
+
+// NewLightClient new light client
+func NewLightClient(c Caller) *LightClient {
+	return &LightClient{Caller: c}
+}
+
+// SetScripts set_scripts, empty command is left out so older light
+// clients, which replace all, accept it
+func (l *LightClient) SetScripts(ctx context.Context, scripts []types.ScriptStatus, cmd types.SetScriptsCommand) error {
+	if scripts == nil {
+		scripts = []types.ScriptStatus{}
+	}
+
+	if cmd == "" {
+		return l.Caller.Call(ctx, nil, "set_scripts", scripts)
+	}
+
+	return l.Caller.Call(ctx, nil, "set_scripts", scripts, cmd)
+}
+
+// GetScripts get_scripts, watched scripts and their synced block numbers
+func (l *LightClient) GetScripts(ctx context.Context) ([]types.ScriptStatus, error) {
+	var scripts []types.ScriptStatus
+	err := l.Caller.Call(ctx, &scripts, "get_scripts")
+	if err != nil {
+		return nil, err
+	}
+
+	return scripts, nil
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+func TestLightClientScripts(t *testing.T) {
+	c := &testRecordCaller{}
+	l := NewLightClient(c)
+	ctx := context.Background()
+
+	scripts := []types.ScriptStatus{{Script: types.Script{HashType: types.Type}, ScriptType: types.ScriptTypeLock, BlockNumber: 7}}
+
+	err := l.SetScripts(ctx, scripts, types.SetScriptsPartial)
+	if err != nil || c.method != "set_scripts" || len(c.params) != 2 || c.params[1] != types.SetScriptsPartial {
+		t.Errorf("fail to set scripts: %v %s %v\n", err, c.method, c.params)
+		return
+	}
+
+	err = l.SetScripts(ctx, nil, "")
+	if err != nil || len(c.params) != 1 {
+		t.Errorf("mismatch params, expect command left out, got %v %v", c.params, err)
+		return
+	}
+
+	if s, ok := c.params[0].([]types.ScriptStatus); !ok || s == nil {
+		t.Errorf("mismatch params, expect empty scripts array, got %#v", c.params[0])
+		return
+	}
+
+	c.result = `[{"block_number":"0x7","script":{"args":"0x","code_hash":"0x0000000000000000000000000000000000000000000000000000000000000000","hash_type":"type"},"script_type":"lock"}]`
+	got, err := l.GetScripts(ctx)
+	if err != nil || c.method != "get_scripts" || len(got) != 1 || got[0].BlockNumber != 7 {
+		t.Errorf("fail to get scripts: %v %s %+v\n", err, c.method, got)
+		return
+	}
+}
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Redacted placeholder of redacted values in logs
+const Redacted = "[redacted]"
+
+// DefaultMaxLogBytes json bytes of params or result logged before
+// truncation, blocks run to megabytes
+const DefaultMaxLogBytes = 1024
+
+// Logger structured logger taking key value pairs, *slog.Logger satisfies
+// it
+type Logger interface {
+	DebugContext(ctx context.Context, msg string, args ...interface{})
+	ErrorContext(ctx context.Context, msg string, args ...interface{})
+}
+
+// Logging caller logging every call, params and result, with redaction
+/*
+ * Calls are logged at debug level, failures at error level, as:
+ *
+ *     "rpc call" method=... params=... duration=... result=...
+ *     "rpc call failed" method=... params=... duration=... error=...
+ *
+ * Params of methods in RedactParams are logged as [redacted], by default
+ * the ones carrying transactions before broadcast. Params and result are
+ * logged as json truncated to MaxLogBytes.
+ */
+type Logging struct {
+	Caller Caller
+	Logger Logger
+	// RedactParams methods whose params are not logged
+	RedactParams map[string]bool
+	// RedactResult methods whose result is not logged
+	RedactResult map[string]bool
+	// MaxLogBytes truncate params and result json, zero logs none of them
+	MaxLogBytes int
+}
+
+// NewLogging new logging caller, redacting transaction params of
+// send_transaction and its dry run relatives
+func NewLogging(c Caller, l Logger) *Logging {
+	return &Logging{
+		Caller: c,
+		Logger: l,
+		RedactParams: map[string]bool{
+			"send_transaction":    true,
+			"test_tx_pool_accept": true,
+			"estimate_cycles":     true,
+			"dry_run_transaction": true,
+		},
+		RedactResult: map[string]bool{},
+		MaxLogBytes:  DefaultMaxLogBytes,
+	}
+}
+
+// Call implement Caller
+func (l *Logging) Call(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+	start := time.Now()
+	err := l.Caller.Call(ctx, result, method, params...)
+
+	args := []interface{}{"method", method}
+	if l.RedactParams[method] {
+		args = append(args, "params", Redacted)
+	} else {
+		args = append(args, "params", l.truncate(params))
+	}
+	args = append(args, "duration", time.Since(start))
+
+	if err != nil {
+		l.Logger.ErrorContext(ctx, "rpc call failed", append(args, "error", err.Error())...)
+		return err
+	}
+
+	if l.RedactResult[method] {
+		args = append(args, "result", Redacted)
+	} else {
+		args = append(args, "result", l.truncate(result))
+	}
+
+	l.Logger.DebugContext(ctx, "rpc call", args...)
+	return nil
+}
+
+// truncate json of v cut to MaxLogBytes, with total size noted
+func (l *Logging) truncate(v interface{}) string {
+	if l.MaxLogBytes <= 0 {
+		return ""
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("unmarshalable %T", v)
+	}
+
+	if len(b) <= l.MaxLogBytes {
+		return string(b)
+	}
+
+	return fmt.Sprintf("%s...(%d bytes)", b[:l.MaxLogBytes], len(b))
+}
+
+// sensitiveHeaders canonical keys of headers RedactHeader hides
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"X-Api-Key":           true,
+}
+
+// RedactHeader copy of header with credentials replaced by [redacted], for
+// logging http requests
+func RedactHeader(h http.Header) http.Header {
+	r := make(http.Header, len(h))
+	for k, v := range h {
+		if sensitiveHeaders[http.CanonicalHeaderKey(k)] {
+			r[k] = []string{Redacted}
+			continue
+		}
+
+		r[k] = append([]string{}, v...)
+	}
+
+	return r
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+type testLogRecord struct {
+	level string
+	msg   string
+	args  map[string]interface{}
+}
+
+type testLogger struct {
+	records []testLogRecord
+}
+
+func (l *testLogger) log(level string, msg string, args []interface{}) {
+	r := testLogRecord{level: level, msg: msg, args: make(map[string]interface{})}
+	for i := 0; i+1 < len(args); i += 2 {
+		r.args[args[i].(string)] = args[i+1]
+	}
+
+	l.records = append(l.records, r)
+}
+
+func (l *testLogger) DebugContext(ctx context.Context, msg string, args ...interface{}) {
+	l.log("debug", msg, args)
+}
+
+func (l *testLogger) ErrorContext(ctx context.Context, msg string, args ...interface{}) {
+	l.log("error", msg, args)
+}
+
+func TestLogging(t *testing.T) {
+	c := &testCaller{tip: 0x400}
+	l := new(testLogger)
+
+	lc := NewLogging(c, l)
+	lc.MaxLogBytes = 16
+
+	var tip types.Uint64
+	err := lc.Call(context.Background(), &tip, "get_tip_block_number")
+	if err != nil || tip != 0x400 {
+		t.Errorf("fail to call: %v %v\n", err, tip)
+		return
+	}
+
+	r := l.records[0]
+	if r.level != "debug" || r.args["method"] != "get_tip_block_number" || r.args["result"] != `"0x400"` || r.args["params"] != "null" {
+		t.Errorf("mismatch log record, got %v", r)
+		return
+	}
+
+	// Transaction before broadcast is redacted, large params truncated
+	err = lc.Call(context.Background(), nil, "send_transaction", strings.Repeat("a", 100))
+	if err != nil || l.records[1].args["params"] != Redacted {
+		t.Errorf("expect redacted params, got %v %v", err, l.records[1])
+		return
+	}
+
+	err = lc.Call(context.Background(), nil, "get_block", strings.Repeat("a", 100))
+	expect := `["aaaaaaaaaaaaaa...(104 bytes)`
+	if err != nil || l.records[2].args["params"] != expect {
+		t.Errorf("mismatch result, expect %v, got %v", expect, l.records[2].args["params"])
+		return
+	}
+
+	c.err = fmt.Errorf("connection refused")
+	err = lc.Call(context.Background(), &tip, "get_tip_block_number")
+	r = l.records[3]
+	if err == nil || r.level != "error" || r.args["error"] != "connection refused" {
+		t.Errorf("mismatch log record, got %v", r)
+		return
+	}
+}
+
+func TestRedactHeader(t *testing.T) {
+	h := http.Header{"Authorization": {"Bearer secret"}, "X-API-Key": {"secret"}, "Content-Type": {"application/json"}}
+
+	r := RedactHeader(h)
+	if r.Get("Authorization") != Redacted || r["X-API-Key"][0] != Redacted || r.Get("Content-Type") != "application/json" {
+		t.Errorf("mismatch redacted header, got %v", r)
+		return
+	}
+
+	if h.Get("Authorization") != "Bearer secret" {
+		t.Errorf("expect original header untouched, got %v", h)
+		return
+	}
+}
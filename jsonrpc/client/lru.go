@@ -0,0 +1,78 @@
+package client
+
+import (
+	"container/list"
+)
+
+// CacheStats cache hit metrics
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// lru least recently used entries with stats, caller holds lock
+type lru struct {
+	list    *list.List
+	entries map[string]*list.Element
+	stats   CacheStats
+}
+
+// lruEntry cached value
+type lruEntry struct {
+	key   string
+	value interface{}
+}
+
+func newLRU() *lru {
+	return &lru{list: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// get get value and count hit or miss
+func (l *lru) get(key string) (interface{}, bool) {
+	e, ok := l.entries[key]
+	if !ok {
+		l.stats.Misses++
+		return nil, false
+	}
+
+	l.stats.Hits++
+	l.list.MoveToFront(e)
+	return e.Value.(*lruEntry).value, true
+}
+
+// put put value, evict least recently used ones beyond max, zero max
+// keeps everything
+func (l *lru) put(key string, value interface{}, max int) {
+	if e, ok := l.entries[key]; ok {
+		e.Value.(*lruEntry).value = value
+		l.list.MoveToFront(e)
+		return
+	}
+
+	l.entries[key] = l.list.PushFront(&lruEntry{key: key, value: value})
+	for max > 0 && l.list.Len() > max {
+		oldest := l.list.Back()
+		l.list.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruEntry).key)
+		l.stats.Evictions++
+	}
+}
+
+// remove drop value, report whether it was cached
+func (l *lru) remove(key string) bool {
+	e, ok := l.entries[key]
+	if !ok {
+		return false
+	}
+
+	l.list.Remove(e)
+	delete(l.entries, key)
+	return true
+}
+
+// purge drop every value, stats are kept
+func (l *lru) purge() {
+	l.list.Init()
+	l.entries = make(map[string]*list.Element)
+}
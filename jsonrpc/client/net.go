@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zeroqn/ckb-types-go/protocol"
+)
+
+// Net ckb net module rpc, peer management for devnet orchestration
+type Net struct {
+	Caller Caller
+}
+
+// NewNet new net module client
+func NewNet(c Caller) *Net {
+	return &Net{Caller: c}
+}
+
+// AddNode add_node, connect to peer at address and keep reconnecting,
+// address is a multiaddr like /ip4/192.168.0.2/tcp/8115, checked before
+// call
+func (n *Net) AddNode(ctx context.Context, peerID string, address string) error {
+	m, err := protocol.ParseMultiaddr(address)
+	if err != nil {
+		return err
+	}
+
+	if m.PeerID != "" && m.PeerID != peerID {
+		return fmt.Errorf("invalid add_node address %s, peer id mismatch %s", address, peerID)
+	}
+
+	return n.Caller.Call(ctx, nil, "add_node", peerID, address)
+}
+
+// RemoveNode remove_node, disconnect peer and stop reconnecting
+func (n *Net) RemoveNode(ctx context.Context, peerID string) error {
+	return n.Caller.Call(ctx, nil, "remove_node", peerID)
+}
+
+// PingPeers ping_peers, ask connected peers for latency, read back by
+// get_peers
+func (n *Net) PingPeers(ctx context.Context) error {
+	return n.Caller.Call(ctx, nil, "ping_peers")
+}
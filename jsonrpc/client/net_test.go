@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// testRecordCaller record last call and answer with result json
+type testRecordCaller struct {
+	method string
+	params []interface{}
+	result string
+}
+
+func (c *testRecordCaller) Call(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+	c.method = method
+	c.params = params
+
+	if result == nil || c.result == "" {
+		return nil
+	}
+
+	return json.Unmarshal([]byte(c.result), result)
+}
+
+func TestNet(t *testing.T) {
+	peerID := "QmUsZHPbjjzU627UZFt4k8j6ycEcNvXRnVGxCPKqwbAfQS"
+	c := &testRecordCaller{}
+	n := NewNet(c)
+	ctx := context.Background()
+
+	err := n.AddNode(ctx, peerID, "/ip4/192.168.2.100/tcp/8114")
+	if err != nil || c.method != "add_node" || len(c.params) != 2 || c.params[0] != peerID {
+		t.Errorf("fail to add node: %v %s %v\n", err, c.method, c.params)
+		return
+	}
+
+	err = n.AddNode(ctx, peerID, "/ip4/192.168.2.100/tcp/8114/p2p/"+peerID)
+	if err != nil {
+		t.Errorf("fail to add node with peer id in address: %s\n", err)
+		return
+	}
+
+	c.method = ""
+	for _, bad := range []string{
+		"192.168.2.100:8114",
+		"/ip4/192.168.2.100/udp/8114",
+		"/ip4/192.168.2.100/tcp/8114/p2p/QmOther",
+	} {
+		err = n.AddNode(ctx, peerID, bad)
+		if err == nil || c.method != "" {
+			t.Errorf("expect error before call on address %s", bad)
+			return
+		}
+	}
+
+	err = n.RemoveNode(ctx, peerID)
+	if err != nil || c.method != "remove_node" || c.params[0] != peerID {
+		t.Errorf("fail to remove node: %v %s %v\n", err, c.method, c.params)
+		return
+	}
+
+	err = n.PingPeers(ctx)
+	if err != nil || c.method != "ping_peers" || len(c.params) != 0 {
+		t.Errorf("fail to ping peers: %v %s %v\n", err, c.method, c.params)
+		return
+	}
+}
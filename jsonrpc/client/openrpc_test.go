@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// TestOpenRPCMethods check method names and param counts of every call
+// made by this package against the checked in ckb openrpc document
+func TestOpenRPCMethods(t *testing.T) {
+	raw, err := ioutil.ReadFile("../testdata/openrpc.json")
+	if err != nil {
+		t.Errorf("fail to read openrpc document: %s\n", err)
+		return
+	}
+
+	var doc struct {
+		Methods []struct {
+			Name   string `json:"name"`
+			Params []struct {
+				Name     string `json:"name"`
+				Required bool   `json:"required"`
+			} `json:"params"`
+		} `json:"methods"`
+	}
+
+	err = json.Unmarshal(raw, &doc)
+	if err != nil {
+		t.Errorf("fail to parse openrpc document: %s\n", err)
+		return
+	}
+
+	c := &testRecordCaller{}
+	ctx := context.Background()
+	cursor := types.Bytes{}
+	net, it, pool := NewNet(c), NewIntegrationTest(c), NewPool(c)
+	chain, indexer, exp := NewChain(c), NewIndexer(c), NewExperiment(c)
+	header := types.Hash{}
+
+	calls := []func(){
+		func() { c.Call(ctx, nil, "get_tip_block_number") },
+		func() { chain.GetBlock(ctx, types.Hash{}) },
+		func() { chain.GetHeader(ctx, types.Hash{}) },
+		func() { chain.GetTransaction(ctx, types.Hash{}) },
+		func() { chain.GetBlockchainInfo(ctx) },
+		func() { chain.GetCurrentEpoch(ctx) },
+		func() { chain.GetEpochByNumber(ctx, 1) },
+		func() {
+			exp.CalculateDaoMaximumWithdraw(ctx, types.OutPoint{}, types.DaoWithdrawingCalculationKind{WithdrawingHeaderHash: &header})
+		},
+		func() { indexer.GetIndexerTip(ctx) },
+		func() { indexer.GetCells(ctx, &types.SearchKey{}, types.Asc, 1, nil) },
+		func() { indexer.GetCells(ctx, &types.SearchKey{}, types.Asc, 1, &cursor) },
+		func() { net.AddNode(ctx, "QmPeer", "/ip4/127.0.0.1/tcp/8115") },
+		func() { net.RemoveNode(ctx, "QmPeer") },
+		func() { net.PingPeers(ctx) },
+		func() { it.GenerateBlock(ctx) },
+		func() { it.GenerateEpochs(ctx, types.EpochNumberWithFraction{Number: 1}) },
+		func() { it.Truncate(ctx, types.Hash{}) },
+		func() { pool.RemoveTransaction(ctx, types.Hash{}) },
+		func() { pool.ClearTxPool(ctx) },
+		func() { pool.ClearTxVerifyQueue(ctx) },
+	}
+
+	for _, call := range calls {
+		c.method, c.params = "", nil
+		call()
+
+		found := false
+		for _, m := range doc.Methods {
+			if m.Name != c.method {
+				continue
+			}
+			found = true
+
+			required := 0
+			for _, p := range m.Params {
+				if p.Required {
+					required++
+				}
+			}
+
+			if len(c.params) < required || len(c.params) > len(m.Params) {
+				t.Errorf("mismatch %s params, openrpc takes %d to %d, got %d", c.method, required, len(m.Params), len(c.params))
+			}
+		}
+
+		if !found {
+			t.Errorf("method %q not in openrpc document", c.method)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// Pool ckb pool module maintenance rpc, for evicting stuck transactions
+type Pool struct {
+	Caller Caller
+}
+
+// NewPool new pool module client
+func NewPool(c Caller) *Pool {
+	return &Pool{Caller: c}
+}
+
+// RemoveTransaction remove_transaction, evict transaction and its
+// descendants from pool, false if it was not in pool
+func (p *Pool) RemoveTransaction(ctx context.Context, hash types.Hash) (bool, error) {
+	var removed bool
+	err := p.Caller.Call(ctx, &removed, "remove_transaction", hash)
+	if err != nil {
+		return false, err
+	}
+
+	return removed, nil
+}
+
+// ClearTxPool clear_tx_pool, drop every pool transaction
+func (p *Pool) ClearTxPool(ctx context.Context) error {
+	return p.Caller.Call(ctx, nil, "clear_tx_pool")
+}
+
+// ClearTxVerifyQueue clear_tx_verify_queue, drop transactions waiting for
+// verification before they enter pool
+func (p *Pool) ClearTxVerifyQueue(ctx context.Context) error {
+	return p.Caller.Call(ctx, nil, "clear_tx_verify_queue")
+}
@@ -0,0 +1,39 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+func TestPool(t *testing.T) {
+	c := &testRecordCaller{result: `true`}
+	p := NewPool(c)
+	ctx := context.Background()
+
+	removed, err := p.RemoveTransaction(ctx, types.Hash{1})
+	if err != nil || !removed || c.method != "remove_transaction" || c.params[0] != (types.Hash{1}) {
+		t.Errorf("fail to remove transaction: %v %v %s %v\n", err, removed, c.method, c.params)
+		return
+	}
+
+	c.result = `false`
+	removed, err = p.RemoveTransaction(ctx, types.Hash{2})
+	if err != nil || removed {
+		t.Errorf("mismatch result, expect transaction not in pool, got %v %v", removed, err)
+		return
+	}
+
+	err = p.ClearTxPool(ctx)
+	if err != nil || c.method != "clear_tx_pool" || len(c.params) != 0 {
+		t.Errorf("fail to clear tx pool: %v %s %v\n", err, c.method, c.params)
+		return
+	}
+
+	err = p.ClearTxVerifyQueue(ctx)
+	if err != nil || c.method != "clear_tx_verify_queue" {
+		t.Errorf("fail to clear tx verify queue: %v %s\n", err, c.method)
+		return
+	}
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// Subscriber ckb json-rpc subscription transport, like a websocket or tcp
+// client, payloads of topic are delivered as raw json in order, channel
+// is closed when ctx is done or transport fails
+type Subscriber interface {
+	Subscribe(ctx context.Context, topic string) (<-chan json.RawMessage, error)
+}
+
+// SubscribeTipHeaders subscribe new_tip_header and decode payloads into
+// headers, with their hashes
+/*
+ * A new tip supersedes older ones, so a slow reader is not queued every
+ * tip it missed: while it is busy, only the latest decoded header is kept
+ * and delivered next. Headers are still delivered in order they arrived,
+ * some may be skipped.
+ *
+ * A payload that is not a header is skipped, subscription goes on.
+ * Channel is closed only when ctx is done or subscription ends, ctx.Err
+ * tells them apart.
+ */
+func SubscribeTipHeaders(ctx context.Context, s Subscriber) (<-chan *types.HeaderView, error) {
+	payloads, err := s.Subscribe(ctx, "new_tip_header")
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *types.HeaderView)
+
+	go func() {
+		defer close(out)
+
+		var latest *types.HeaderView
+		for {
+			// Only offer a header once there is one
+			var send chan<- *types.HeaderView
+			if latest != nil {
+				send = out
+			}
+
+			select {
+			case raw, ok := <-payloads:
+				if !ok {
+					return
+				}
+
+				var h types.HeaderView
+				err := json.Unmarshal(raw, &h)
+				if err != nil {
+					continue
+				}
+				latest = &h
+			case send <- latest:
+				latest = nil
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// SubscribeNewTransactions subscribe new_transaction, entries of
+// transactions entering pool
+/*
+ * Unlike tips, every entry matters, so none is skipped: a slow reader
+ * holds up the subscription instead. Payloads that are not entries are
+ * skipped. Channel is closed only when ctx is done or subscription ends.
+ */
+func SubscribeNewTransactions(ctx context.Context, s Subscriber) (<-chan *types.PoolTransactionEntry, error) {
+	return subscribeEntries(ctx, s, "new_transaction")
+}
+
+// SubscribeProposedTransactions subscribe proposed_transaction, entries of
+// pool transactions being proposed, delivered like SubscribeNewTransactions
+func SubscribeProposedTransactions(ctx context.Context, s Subscriber) (<-chan *types.PoolTransactionEntry, error) {
+	return subscribeEntries(ctx, s, "proposed_transaction")
+}
+
+// SubscribeRejectedTransactions subscribe rejected_transaction, entries
+// rejected by pool with reasons, delivered like SubscribeNewTransactions
+func SubscribeRejectedTransactions(ctx context.Context, s Subscriber) (<-chan *types.RejectedTransaction, error) {
+	payloads, err := s.Subscribe(ctx, "rejected_transaction")
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *types.RejectedTransaction)
+
+	go func() {
+		defer close(out)
+
+		forward(ctx, payloads, func(raw json.RawMessage) bool {
+			var r types.RejectedTransaction
+			if json.Unmarshal(raw, &r) != nil {
+				return true
+			}
+
+			select {
+			case out <- &r:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+
+	return out, nil
+}
+
+func subscribeEntries(ctx context.Context, s Subscriber, topic string) (<-chan *types.PoolTransactionEntry, error) {
+	payloads, err := s.Subscribe(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *types.PoolTransactionEntry)
+
+	go func() {
+		defer close(out)
+
+		forward(ctx, payloads, func(raw json.RawMessage) bool {
+			var e types.PoolTransactionEntry
+			if json.Unmarshal(raw, &e) != nil {
+				return true
+			}
+
+			select {
+			case out <- &e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+
+	return out, nil
+}
+
+// forward pass payloads to deliver in order until ctx is done,
+// subscription ends or deliver returns false
+func forward(ctx context.Context, payloads <-chan json.RawMessage, deliver func(json.RawMessage) bool) {
+	for {
+		select {
+		case raw, ok := <-payloads:
+			if !ok || !deliver(raw) {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
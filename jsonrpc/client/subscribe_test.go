@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+type testSubscriber struct {
+	payloads chan json.RawMessage
+	topic    string
+	err      error
+}
+
+func (s *testSubscriber) Subscribe(ctx context.Context, topic string) (<-chan json.RawMessage, error) {
+	s.topic = topic
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return s.payloads, nil
+}
+
+func testTipHeader(number uint64) json.RawMessage {
+	h := types.HeaderView{Header: types.Header{Number: types.Uint64(number)}, Hash: types.Hash{byte(number)}}
+	b, _ := json.Marshal(h)
+	return b
+}
+
+func TestSubscribeTipHeaders(t *testing.T) {
+	s := &testSubscriber{payloads: make(chan json.RawMessage, 8)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	headers, err := SubscribeTipHeaders(ctx, s)
+	if err != nil || s.topic != "new_tip_header" {
+		t.Errorf("fail to subscribe tip headers: %v %s\n", err, s.topic)
+		return
+	}
+
+	s.payloads <- testTipHeader(1)
+	h := <-headers
+	if h.Header.Number != 1 || h.Hash != (types.Hash{1}) {
+		t.Errorf("mismatch result, expect header 1, got %+v", h)
+		return
+	}
+
+	// Slow reader gets the latest tip, never an older one after it
+	for n := uint64(2); n <= 5; n++ {
+		s.payloads <- testTipHeader(n)
+	}
+
+	last := uint64(1)
+	for last < 5 {
+		h = <-headers
+		if uint64(h.Header.Number) <= last {
+			t.Errorf("mismatch order, expect after %d, got %d", last, h.Header.Number)
+			return
+		}
+		last = uint64(h.Header.Number)
+	}
+
+	// Malformed payloads are skipped, subscription goes on
+	s.payloads <- json.RawMessage(`"0x1"`)
+	s.payloads <- json.RawMessage(`{"number":`)
+	s.payloads <- testTipHeader(6)
+	h, ok := <-headers
+	if !ok || h.Header.Number != 6 {
+		t.Errorf("mismatch result, expect header 6 after malformed payloads, got %+v %v", h, ok)
+		return
+	}
+}
+
+func TestSubscribeTipHeadersClose(t *testing.T) {
+	s := &testSubscriber{payloads: make(chan json.RawMessage)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	headers, err := SubscribeTipHeaders(ctx, s)
+	if err != nil {
+		t.Errorf("fail to subscribe tip headers: %s\n", err)
+		return
+	}
+
+	cancel()
+	for range headers {
+	}
+
+	s = &testSubscriber{err: fmt.Errorf("connection refused")}
+	_, err = SubscribeTipHeaders(context.Background(), s)
+	if err == nil {
+		t.Errorf("expect error on failed subscribe")
+		return
+	}
+
+	// Transport closing ends subscription too
+	s = &testSubscriber{payloads: make(chan json.RawMessage)}
+	headers, _ = SubscribeTipHeaders(context.Background(), s)
+	close(s.payloads)
+	if _, ok := <-headers; ok {
+		t.Errorf("expect channel closed with subscription")
+		return
+	}
+}
+
+func testPoolEntry(version uint32) json.RawMessage {
+	e := types.PoolTransactionEntry{Transaction: types.TransactionView{Transaction: types.Transaction{Version: types.Uint32(version)}, Hash: types.Hash{byte(version)}}, Fee: 1000}
+	b, _ := json.Marshal(e)
+	return b
+}
+
+func TestSubscribeTransactions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for topic, subscribe := range map[string]func(context.Context, Subscriber) (<-chan *types.PoolTransactionEntry, error){
+		"new_transaction":      SubscribeNewTransactions,
+		"proposed_transaction": SubscribeProposedTransactions,
+	} {
+		s := &testSubscriber{payloads: make(chan json.RawMessage, 8)}
+		entries, err := subscribe(ctx, s)
+		if err != nil || s.topic != topic {
+			t.Errorf("fail to subscribe %s: %v %s\n", topic, err, s.topic)
+			return
+		}
+
+		// Every entry in order, malformed payloads skipped
+		s.payloads <- testPoolEntry(1)
+		s.payloads <- json.RawMessage(`"0x1"`)
+		s.payloads <- testPoolEntry(2)
+		s.payloads <- testPoolEntry(3)
+		close(s.payloads)
+
+		var got []uint32
+		for e := range entries {
+			got = append(got, uint32(e.Transaction.Transaction.Version))
+		}
+
+		if len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+			t.Errorf("mismatch %s entries, expect [1 2 3], got %v", topic, got)
+			return
+		}
+	}
+}
+
+func TestSubscribeRejectedTransactions(t *testing.T) {
+	s := &testSubscriber{payloads: make(chan json.RawMessage, 8)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rejected, err := SubscribeRejectedTransactions(ctx, s)
+	if err != nil || s.topic != "rejected_transaction" {
+		t.Errorf("fail to subscribe rejected transactions: %v %s\n", err, s.topic)
+		return
+	}
+
+	s.payloads <- json.RawMessage(`[` + string(testPoolEntry(1)) + `]`)
+	s.payloads <- json.RawMessage(`[` + string(testPoolEntry(1)) + `,{"type":"Resolve","description":"dead"}]`)
+
+	r := <-rejected
+	if r.Entry.Transaction.Hash != (types.Hash{1}) || r.Reason.Type != types.RejectResolve || r.Reason.Description != "dead" {
+		t.Errorf("mismatch result, expect rejected entry 1, got %+v", r)
+		return
+	}
+
+	cancel()
+	for range rejected {
+	}
+}
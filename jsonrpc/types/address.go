@@ -0,0 +1,249 @@
+package types
+
+import (
+	"fmt"
+)
+
+// Network ckb network, address human-readable part
+type Network string
+
+// Networks
+const (
+	Mainnet Network = "ckb"
+	Testnet Network = "ckt"
+)
+
+// Address payload formats
+const (
+	addressFull      = 0x00
+	addressShort     = 0x01
+	addressFullData  = 0x02
+	addressFullType  = 0x04
+	shortSighashAll  = 0x00
+	shortMultisigAll = 0x01
+)
+
+// EncodeAddress encode lock script into full format address, bech32m
+/*
+ *     0x00 | code_hash | hash_type | args
+ */
+func EncodeAddress(network Network, lock *Script) (string, error) {
+	if network != Mainnet && network != Testnet {
+		return "", fmt.Errorf("invalid network %q", network)
+	}
+
+	hashType, err := lock.HashType.Serialize()
+	if err != nil {
+		return "", err
+	}
+
+	payload := []byte{addressFull}
+	payload = append(payload, lock.CodeHash[:]...)
+	payload = append(payload, hashType...)
+	payload = append(payload, lock.Args...)
+
+	data, err := convertBits(payload, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	return bech32Encode(string(network), data, bech32mConst), nil
+}
+
+// AddressFormat address payload format
+type AddressFormat string
+
+// Address formats, only full is current
+const (
+	// AddressFormatFull full format with bech32m checksum
+	AddressFormatFull AddressFormat = "full"
+	// AddressFormatShort deprecated short format of secp256k1 locks
+	AddressFormatShort AddressFormat = "short"
+	// AddressFormatFullBech32 deprecated full data and full type formats,
+	// with bech32 checksum
+	AddressFormatFullBech32 AddressFormat = "full_bech32"
+)
+
+// AddressInfo decoded address with format metadata
+type AddressInfo struct {
+	Network Network
+	Format  AddressFormat
+	Script  Script
+	// Warnings why address format is deprecated, empty for full format
+	Warnings []string
+}
+
+// Deprecated report whether address is in a deprecated format
+func (i *AddressInfo) Deprecated() bool {
+	return i.Format != AddressFormatFull
+}
+
+// DecodeAddress decode address into network and lock script, accept full
+// format and deprecated short, full data and full type formats
+func DecodeAddress(s string) (Network, *Script, error) {
+	info, err := DecodeAddressInfo(s)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return info.Network, &info.Script, nil
+}
+
+// DecodeAddressInfo decode address like DecodeAddress, also report format
+// and warnings, so wallets can ask users to switch to full format address
+func DecodeAddressInfo(s string) (*AddressInfo, error) {
+	hrp, data, constant, err := bech32Decode(s)
+	if err != nil {
+		return nil, err
+	}
+
+	network := Network(hrp)
+	if network != Mainnet && network != Testnet {
+		return nil, fmt.Errorf("invalid address, unknown network %q", hrp)
+	}
+
+	payload, err := convertBits(data, 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(payload) == 0 {
+		return nil, fmt.Errorf("invalid address, empty payload")
+	}
+
+	format := payload[0]
+	if (format == addressFull) != (constant == bech32mConst) {
+		return nil, fmt.Errorf("invalid address, wrong checksum variant for format %d", format)
+	}
+
+	info := &AddressInfo{Network: network, Format: AddressFormatFull}
+	lock := &info.Script
+	switch format {
+	case addressFull:
+		if len(payload) < 1+hashSize+byteSize {
+			return nil, fmt.Errorf("invalid address, payload too short")
+		}
+
+		copy(lock.CodeHash[:], payload[1:1+hashSize])
+		err = lock.HashType.Deserialize(payload[1+hashSize : 2+hashSize])
+		if err != nil {
+			return nil, err
+		}
+		lock.Args = Bytes(payload[2+hashSize:]).Clone()
+	case addressShort:
+		if len(payload) != 2+Blake160Size {
+			return nil, fmt.Errorf("invalid address, short payload should be 22 bytes")
+		}
+
+		switch payload[1] {
+		case shortSighashAll:
+			lock.CodeHash = SecpSighashAllCodeHash
+		case shortMultisigAll:
+			lock.CodeHash = SecpMultisigCodeHash
+		default:
+			return nil, fmt.Errorf("invalid address, unsupported code hash index %d", payload[1])
+		}
+
+		lock.HashType = Type
+		lock.Args = Bytes(payload[2:]).Clone()
+
+		info.Format = AddressFormatShort
+		info.Warnings = append(info.Warnings, "short format address is deprecated, use full format")
+	case addressFullData, addressFullType:
+		if len(payload) < 1+hashSize {
+			return nil, fmt.Errorf("invalid address, payload too short")
+		}
+
+		copy(lock.CodeHash[:], payload[1:1+hashSize])
+		lock.HashType = Data
+		if format == addressFullType {
+			lock.HashType = Type
+		}
+		lock.Args = Bytes(payload[1+hashSize:]).Clone()
+
+		info.Format = AddressFormatFullBech32
+		info.Warnings = append(info.Warnings, "full data and full type format address with bech32 checksum is deprecated, use full format with bech32m")
+	default:
+		return nil, fmt.Errorf("invalid address, unknown format %d", format)
+	}
+
+	return info, nil
+}
+
+// Address ckb address, lock script on a network
+type Address struct {
+	Network Network
+	Script  Script
+}
+
+// ParseAddress parse address in any format DecodeAddress accepts
+func ParseAddress(s string) (Address, error) {
+	network, lock, err := DecodeAddress(s)
+	if err != nil {
+		return Address{}, err
+	}
+
+	return Address{Network: network, Script: *lock}, nil
+}
+
+// ToAddress address of lock script on network, Address.Script goes back
+func (s *Script) ToAddress(network Network) (Address, error) {
+	if network != Mainnet && network != Testnet {
+		return Address{}, fmt.Errorf("invalid network %q", network)
+	}
+
+	return Address{Network: network, Script: *s.Clone()}, nil
+}
+
+// PubkeyToLock default secp256k1 sighash all lock of compressed or
+// uncompressed public key
+func PubkeyToLock(pubkey []byte) (*Script, error) {
+	args, err := PubkeyToLockArgs(pubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: args}, nil
+}
+
+// PubkeyToAddress address of default secp256k1 lock of public key
+func PubkeyToAddress(network Network, pubkey []byte) (Address, error) {
+	lock, err := PubkeyToLock(pubkey)
+	if err != nil {
+		return Address{}, err
+	}
+
+	return lock.ToAddress(network)
+}
+
+// String full format address, empty if address is invalid
+func (a Address) String() string {
+	s, err := EncodeAddress(a.Network, &a.Script)
+	if err != nil {
+		return ""
+	}
+
+	return s
+}
+
+// MarshalText marshal address to full format, so address works as json
+// string, map key and in text configs
+func (a Address) MarshalText() ([]byte, error) {
+	s, err := EncodeAddress(a.Network, &a.Script)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(s), nil
+}
+
+// UnmarshalText unmarshal address in any format DecodeAddress accepts
+func (a *Address) UnmarshalText(text []byte) error {
+	aa, err := ParseAddress(string(text))
+	if err != nil {
+		return err
+	}
+
+	*a = aa
+	return nil
+}
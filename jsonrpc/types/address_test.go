@@ -0,0 +1,161 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+)
+
+func TestAddress(t *testing.T) {
+	args, _ := ParseBytes("0xb39bbc0b3673c7d36450bc14cfcdad2d559c6c64")
+	lock := &Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: args}
+
+	full := "ckb1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsqdnnw7qkdnnclfkg59uzn8umtfd2kwxceqxwquc4"
+	short := "ckb1qyqt8xaupvm8837nv3gtc9x0ekkj64vud3jqfwyw5v"
+
+	addr, err := EncodeAddress(Mainnet, lock)
+	if err != nil {
+		t.Errorf("fail to encode address: %s\n", err)
+		return
+	}
+
+	if addr != full {
+		t.Errorf("mismatch result, expect %v, got %v", full, addr)
+		return
+	}
+
+	for _, s := range []string{full, short} {
+		network, decoded, err := DecodeAddress(s)
+		if err != nil {
+			t.Errorf("fail to decode address %v: %s\n", s, err)
+			return
+		}
+
+		if network != Mainnet || !decoded.Equal(lock) {
+			t.Errorf("mismatch decoded address %v, got %v %v", s, network, decoded)
+			return
+		}
+	}
+
+	// Flip last checksum character
+	_, _, err = DecodeAddress(short[:len(short)-1] + "q")
+	if err == nil {
+		t.Errorf("expect error on bad checksum")
+		return
+	}
+}
+
+func TestAddressText(t *testing.T) {
+	full := "ckb1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsqdnnw7qkdnnclfkg59uzn8umtfd2kwxceqxwquc4"
+	short := "ckb1qyqt8xaupvm8837nv3gtc9x0ekkj64vud3jqfwyw5v"
+
+	var cfg struct {
+		Owner    Address         `json:"owner"`
+		Balances map[Hash]Uint64 `json:"balances"`
+	}
+
+	raw := `{"owner": "` + short + `", "balances": {"` + SecpSighashAllCodeHash.String() + `": "0x64"}}`
+	err := json.Unmarshal([]byte(raw), &cfg)
+	if err != nil {
+		t.Errorf("fail to unmarshal json: %s\n", err)
+		return
+	}
+
+	if cfg.Owner.Network != Mainnet || cfg.Owner.String() != full || cfg.Balances[SecpSighashAllCodeHash] != 100 {
+		t.Errorf("mismatch result, got %+v", cfg)
+		return
+	}
+
+	out, err := json.Marshal(&cfg)
+	if err != nil {
+		t.Errorf("fail to marshal json: %s\n", err)
+		return
+	}
+
+	expect := `{"owner":"` + full + `","balances":{"` + SecpSighashAllCodeHash.String() + `":"0x64"}}`
+	if string(out) != expect {
+		t.Errorf("mismatch result, expect %v, got %v", expect, string(out))
+		return
+	}
+
+	var a Address
+	if a.UnmarshalText([]byte("ckb1invalid")) == nil {
+		t.Errorf("should reject invalid address")
+		return
+	}
+
+	if _, err = (Address{}).MarshalText(); err == nil {
+		t.Errorf("should reject address without network")
+		return
+	}
+}
+
+func TestDecodeAddressInfo(t *testing.T) {
+	args, _ := ParseBytes("0xb39bbc0b3673c7d36450bc14cfcdad2d559c6c64")
+	lock := &Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: args}
+
+	for s, format := range map[string]AddressFormat{
+		"ckb1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsqdnnw7qkdnnclfkg59uzn8umtfd2kwxceqxwquc4": AddressFormatFull,
+		"ckb1qyqt8xaupvm8837nv3gtc9x0ekkj64vud3jqfwyw5v":                                                    AddressFormatShort,
+		"ckb1qjda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xw3vumhs9nvu786dj9p0q5elx66t24n3kxgj53qks":   AddressFormatFullBech32,
+	} {
+		info, err := DecodeAddressInfo(s)
+		if err != nil {
+			t.Errorf("fail to decode address %v: %s\n", s, err)
+			return
+		}
+
+		if info.Network != Mainnet || info.Format != format || !info.Script.Equal(lock) {
+			t.Errorf("mismatch decoded address %v, got %v %v %v", s, info.Network, info.Format, info.Script)
+			return
+		}
+
+		if info.Deprecated() != (len(info.Warnings) != 0) || info.Deprecated() != (format != AddressFormatFull) {
+			t.Errorf("mismatch warnings of %v, got %v", format, info.Warnings)
+			return
+		}
+	}
+}
+
+func TestPubkeyToAddress(t *testing.T) {
+	// Generator point, public key of private key 1
+	pubkey, _ := hex.DecodeString("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	args, _ := ParseBytes("0x75178f34549c5fe9cd1a0c57aebd01e7ddf9249e")
+
+	addr, err := PubkeyToAddress(Testnet, pubkey)
+	if err != nil {
+		t.Errorf("fail to convert pubkey to address: %s\n", err)
+		return
+	}
+
+	lock := &Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: args}
+	if addr.Network != Testnet || !addr.Script.Equal(lock) {
+		t.Errorf("mismatch result, expect %v, got %v", lock, addr.Script)
+		return
+	}
+
+	parsed, err := ParseAddress(addr.String())
+	if err != nil {
+		t.Errorf("fail to parse address: %s\n", err)
+		return
+	}
+
+	if !parsed.Script.Equal(lock) {
+		t.Errorf("mismatch result, expect %v, got %v", lock, parsed.Script)
+		return
+	}
+
+	// Address owns its args
+	addr, _ = lock.ToAddress(Mainnet)
+	lock.Args[0] = 0
+	if addr.Script.Args[0] != 0x75 {
+		t.Errorf("expect address not aliasing script args")
+		return
+	}
+
+	_, err = lock.ToAddress(Network("ckx"))
+	if err == nil {
+		t.Errorf("expect error on unknown network")
+		return
+	}
+}
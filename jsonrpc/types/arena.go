@@ -0,0 +1,191 @@
+package types
+
+import (
+	"fmt"
+)
+
+// arenaChunk items per slab chunk, a chunk holds a few typical blocks
+const arenaChunk = 4096
+
+// Arena slab allocator for bulk block decoding
+/*
+ * Objects decoded through the same arena share a few large backing
+ * arrays instead of one allocation per slice, and are released together
+ * by Reset. Decoded objects must not be used after Reset, their memory is
+ * reused by the next decode.
+ *
+ * Arena is not safe for concurrent use, give each decoding worker its own.
+ */
+type Arena struct {
+	bytes        []byte
+	bytesVecs    []Bytes
+	hashes       []Hash
+	cellDeps     []CellDep
+	cellInputs   []CellInput
+	cellOutputs  []CellOutput
+	scripts      []Script
+	transactions []Transaction
+	uncles       []UncleBlock
+	proposals    []ProposalShortID
+	blocks       []Block
+}
+
+// NewArena new empty arena
+func NewArena() *Arena {
+	return new(Arena)
+}
+
+// DecodeBlock decode molecule block, memory is owned by arena
+func (a *Arena) DecodeBlock(data []byte) (*Block, error) {
+	i, j := arenaReserve(len(a.blocks), cap(a.blocks), 1, arenaChunk/64, func(c int) { a.blocks = make([]Block, 0, c) })
+	a.blocks = a.blocks[:j]
+	b := &a.blocks[i]
+
+	err := b.deserialize(data, decoder{arena: a})
+	if err != nil {
+		return nil, fmt.Errorf("invalid block, %s", err)
+	}
+
+	return b, nil
+}
+
+// Reset release all decoded objects at once, the latest chunks are kept
+// for reuse
+func (a *Arena) Reset() {
+	a.bytes = a.bytes[:0]
+	a.bytesVecs = a.bytesVecs[:0]
+	a.hashes = a.hashes[:0]
+	a.cellDeps = a.cellDeps[:0]
+	a.cellInputs = a.cellInputs[:0]
+	a.cellOutputs = a.cellOutputs[:0]
+	a.scripts = a.scripts[:0]
+	a.transactions = a.transactions[:0]
+	a.uncles = a.uncles[:0]
+	a.proposals = a.proposals[:0]
+	a.blocks = a.blocks[:0]
+}
+
+// arenaReserve reserve n items at end of chunk with length and capacity,
+// returns their span, grow is called with capacity of a new empty chunk
+// first if they do not fit, large requests get their own chunk
+/*
+ * Only len and cap of chunk are needed, so one helper serves every item
+ * type, grow closure replaces the typed chunk. A nil chunk always grows,
+ * so empty spans are never nil.
+ */
+func arenaReserve(length int, capacity int, n int, chunk int, grow func(c int)) (int, int) {
+	if capacity != 0 && length+n <= capacity {
+		return length, length + n
+	}
+
+	if n > chunk {
+		chunk = n
+	}
+
+	grow(chunk)
+	return 0, n
+}
+
+// Allocators falling back to heap on nil arena, so decoders share one
+// code path. Slices are capped to their length so appends never write
+// into neighbours, and empty slices are never nil, same as make.
+
+func (a *Arena) allocBytes(n int) []byte {
+	if a == nil {
+		return make([]byte, n)
+	}
+
+	i, j := arenaReserve(len(a.bytes), cap(a.bytes), n, arenaChunk*64, func(c int) { a.bytes = make([]byte, 0, c) })
+	a.bytes = a.bytes[:j]
+	return a.bytes[i:j:j]
+}
+
+func (a *Arena) allocBytesVec(n int) []Bytes {
+	if a == nil {
+		return make([]Bytes, n)
+	}
+
+	i, j := arenaReserve(len(a.bytesVecs), cap(a.bytesVecs), n, arenaChunk, func(c int) { a.bytesVecs = make([]Bytes, 0, c) })
+	a.bytesVecs = a.bytesVecs[:j]
+	return a.bytesVecs[i:j:j]
+}
+
+func (a *Arena) allocHashes(n int) []Hash {
+	if a == nil {
+		return make([]Hash, n)
+	}
+
+	i, j := arenaReserve(len(a.hashes), cap(a.hashes), n, arenaChunk, func(c int) { a.hashes = make([]Hash, 0, c) })
+	a.hashes = a.hashes[:j]
+	return a.hashes[i:j:j]
+}
+
+func (a *Arena) allocCellDeps(n int) []CellDep {
+	if a == nil {
+		return make([]CellDep, n)
+	}
+
+	i, j := arenaReserve(len(a.cellDeps), cap(a.cellDeps), n, arenaChunk, func(c int) { a.cellDeps = make([]CellDep, 0, c) })
+	a.cellDeps = a.cellDeps[:j]
+	return a.cellDeps[i:j:j]
+}
+
+func (a *Arena) allocCellInputs(n int) []CellInput {
+	if a == nil {
+		return make([]CellInput, n)
+	}
+
+	i, j := arenaReserve(len(a.cellInputs), cap(a.cellInputs), n, arenaChunk, func(c int) { a.cellInputs = make([]CellInput, 0, c) })
+	a.cellInputs = a.cellInputs[:j]
+	return a.cellInputs[i:j:j]
+}
+
+func (a *Arena) allocCellOutputs(n int) []CellOutput {
+	if a == nil {
+		return make([]CellOutput, n)
+	}
+
+	i, j := arenaReserve(len(a.cellOutputs), cap(a.cellOutputs), n, arenaChunk, func(c int) { a.cellOutputs = make([]CellOutput, 0, c) })
+	a.cellOutputs = a.cellOutputs[:j]
+	return a.cellOutputs[i:j:j]
+}
+
+func (a *Arena) allocScript() *Script {
+	if a == nil {
+		return new(Script)
+	}
+
+	i, j := arenaReserve(len(a.scripts), cap(a.scripts), 1, arenaChunk, func(c int) { a.scripts = make([]Script, 0, c) })
+	a.scripts = a.scripts[:j]
+	return &a.scripts[i]
+}
+
+func (a *Arena) allocTransactions(n int) []Transaction {
+	if a == nil {
+		return make([]Transaction, n)
+	}
+
+	i, j := arenaReserve(len(a.transactions), cap(a.transactions), n, arenaChunk, func(c int) { a.transactions = make([]Transaction, 0, c) })
+	a.transactions = a.transactions[:j]
+	return a.transactions[i:j:j]
+}
+
+func (a *Arena) allocUncles(n int) []UncleBlock {
+	if a == nil {
+		return make([]UncleBlock, n)
+	}
+
+	i, j := arenaReserve(len(a.uncles), cap(a.uncles), n, arenaChunk/64, func(c int) { a.uncles = make([]UncleBlock, 0, c) })
+	a.uncles = a.uncles[:j]
+	return a.uncles[i:j:j]
+}
+
+func (a *Arena) allocProposals(n int) []ProposalShortID {
+	if a == nil {
+		return make([]ProposalShortID, n)
+	}
+
+	i, j := arenaReserve(len(a.proposals), cap(a.proposals), n, arenaChunk, func(c int) { a.proposals = make([]ProposalShortID, 0, c) })
+	a.proposals = a.proposals[:j]
+	return a.proposals[i:j:j]
+}
@@ -0,0 +1,97 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestArenaDecodeBlock(t *testing.T) {
+	var view BlockView
+	err := json.Unmarshal([]byte(recordedBlock), &view)
+	if err != nil {
+		t.Errorf("fail to unmarshal block: %s\n", err)
+		return
+	}
+
+	b := Block{Header: view.Header.Header, Proposals: view.Proposals}
+	for _, tx := range view.Transactions {
+		b.Transactions = append(b.Transactions, tx.Transaction)
+	}
+
+	data, err := b.Serialize()
+	if err != nil {
+		t.Errorf("fail to serialize: %s\n", err)
+		return
+	}
+
+	a := NewArena()
+	for round := 0; round < 2; round++ {
+		for i := 0; i < 100; i++ {
+			decoded, err := a.DecodeBlock(data)
+			if err != nil {
+				t.Errorf("fail to decode block: %s\n", err)
+				return
+			}
+
+			again, err := decoded.Serialize()
+			if err != nil || !bytes.Equal(again, data) {
+				t.Errorf("mismatch arena decoded block, got %v", err)
+				return
+			}
+
+			// Slices are capped, append must not clobber neighbours
+			w := decoded.Transactions[0].Witnesses
+			if len(w) != cap(w) {
+				t.Errorf("arena slice not capped, len %v cap %v", len(w), cap(w))
+				return
+			}
+		}
+
+		a.Reset()
+	}
+
+	_, err = a.DecodeBlock(data[:len(data)-1])
+	if err == nil {
+		t.Errorf("expect error on truncated block")
+		return
+	}
+}
+
+func TestArenaAlloc(t *testing.T) {
+	a := NewArena()
+
+	if a.allocCellDeps(0) == nil || a.allocBytes(0) == nil {
+		t.Errorf("expect empty slices not nil")
+		return
+	}
+
+	// Spans fill one chunk back to back, capped to their length
+	x, y := a.allocHashes(arenaChunk-1), a.allocHashes(1)
+	if len(y) != 1 || cap(y) != 1 || &a.hashes[0] != &x[0] || &a.hashes[arenaChunk-1] != &y[0] {
+		t.Errorf("mismatch result, expect spans in one chunk")
+		return
+	}
+
+	// Full chunk grows a new one, large request gets its own
+	z := a.allocHashes(1)
+	big := a.allocHashes(arenaChunk * 2)
+	if &z[0] == &x[0] || len(big) != arenaChunk*2 || cap(a.hashes) != arenaChunk*2 {
+		t.Errorf("mismatch result, expect new chunks, got cap %d", cap(a.hashes))
+		return
+	}
+
+	// Reset reuses latest chunk
+	a.Reset()
+	again := a.allocHashes(1)
+	if &again[0] != &big[0] {
+		t.Errorf("mismatch result, expect latest chunk reused after reset")
+		return
+	}
+
+	var heap *Arena
+	if h := heap.allocHashes(2); len(h) != 2 || heap.allocScript() == nil {
+		t.Errorf("mismatch result, expect heap allocation on nil arena")
+		return
+	}
+}
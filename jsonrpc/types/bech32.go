@@ -0,0 +1,129 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Bech32 checksum variants
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+func bech32Polymod(values []byte) uint32 {
+	gen := []uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	b := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		b = append(b, hrp[i]>>5)
+	}
+
+	b = append(b, 0)
+	for i := 0; i < len(hrp); i++ {
+		b = append(b, hrp[i]&31)
+	}
+
+	return b
+}
+
+// bech32Encode encode 5 bits data with given checksum constant, ckb
+// addresses are longer than 90 characters so there is no length limit
+func bech32Encode(hrp string, data []byte, constant uint32) string {
+	values := append(bech32HRPExpand(hrp), data...)
+	mod := bech32Polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ constant
+
+	b := new(strings.Builder)
+	b.WriteString(hrp)
+	b.WriteByte('1')
+
+	for _, d := range data {
+		b.WriteByte(bech32Charset[d])
+	}
+
+	for i := 0; i < 6; i++ {
+		b.WriteByte(bech32Charset[(mod>>uint(5*(5-i)))&31])
+	}
+
+	return b.String()
+}
+
+// bech32Decode decode bech32 or bech32m string, returns hrp, 5 bits data
+// and checksum constant
+func bech32Decode(s string) (string, []byte, uint32, error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, 0, fmt.Errorf("invalid bech32, mixed case")
+	}
+	s = strings.ToLower(s)
+
+	pos := strings.LastIndexByte(s, '1')
+	if pos < 1 || pos+7 > len(s) {
+		return "", nil, 0, fmt.Errorf("invalid bech32, bad separator position")
+	}
+
+	hrp := s[:pos]
+	data := make([]byte, len(s)-pos-1)
+
+	for i := 0; i < len(data); i++ {
+		d := strings.IndexByte(bech32Charset, s[pos+1+i])
+		if d < 0 {
+			return "", nil, 0, fmt.Errorf("invalid bech32, bad character %q", s[pos+1+i])
+		}
+
+		data[i] = byte(d)
+	}
+
+	constant := bech32Polymod(append(bech32HRPExpand(hrp), data...))
+	if constant != bech32Const && constant != bech32mConst {
+		return "", nil, 0, fmt.Errorf("invalid bech32, bad checksum")
+	}
+
+	return hrp, data[:len(data)-6], constant, nil
+}
+
+// convertBits regroup bits, 8 to 5 with padding or 5 to 8 without
+func convertBits(data []byte, from, to uint, pad bool) ([]byte, error) {
+	var acc, bits uint
+	var out []byte
+
+	maxv := uint(1)<<to - 1
+	for _, v := range data {
+		if uint(v)>>from != 0 {
+			return nil, fmt.Errorf("invalid bech32 data, value out of range")
+		}
+
+		acc = acc<<from | uint(v)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte((acc>>bits)&maxv))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			out = append(out, byte((acc<<(to-bits))&maxv))
+		}
+	} else if bits >= from || (acc<<(to-bits))&maxv != 0 {
+		return nil, fmt.Errorf("invalid bech32 data, bad padding")
+	}
+
+	return out, nil
+}
@@ -1,19 +1,5 @@
 package types
 
-// Basic
-
-// Uint32 ckb uint32, '0x' prefix hex number
-type Uint32 string
-
-// Uint64 ckb uint64, '0x' prefix hex number
-type Uint64 string
-
-// Uint128 ckb uint128, '0x' prefix hex number
-type Uint128 string
-
-// Hash ckb hash, '0x' prefix hex string
-type Hash string
-
 // Enum type
 
 // ScriptHashType ckb script hash type
@@ -22,16 +8,12 @@ type ScriptHashType string
 // DepType ckb dep type
 type DepType string
 
-// Bytes ckb json bytes
-type Bytes string
-
-// ProposalShortID ckb proposal short id
-type ProposalShortID string
-
 // Enum values
 const (
-	Data ScriptHashType = "data"
-	Type ScriptHashType = "type"
+	Data  ScriptHashType = "data"
+	Type  ScriptHashType = "type"
+	Data1 ScriptHashType = "data1"
+	Data2 ScriptHashType = "data2"
 
 	Code     DepType = "code"
 	DepGroup DepType = "dep_group"
@@ -39,15 +21,18 @@ const (
 
 // Script ckb script
 type Script struct {
-	CodeHash Hash           `json:"code_hash"`
-	HashType ScriptHashType `json:"hash_type"`
-	Args     Bytes          `json:"args"`
+	CodeHash Hash           `json:"code_hash" yaml:"code_hash" toml:"code_hash"`
+	HashType ScriptHashType `json:"hash_type" yaml:"hash_type" toml:"hash_type"`
+	Args     Bytes          `json:"args" yaml:"args" toml:"args"`
+	// UnknownFields trailing table fields unknown to this version, kept
+	// so serialization and hash are unchanged
+	UnknownFields [][]byte `json:"-" yaml:"-" toml:"-"`
 }
 
-// OutPoint ckb outpoint
+// OutPoint ckb outpoint, comparable so it can be used as map key
 type OutPoint struct {
-	TxHash Hash   `json:"tx_hash"`
-	Index  Uint32 `json:"index"`
+	TxHash Hash   `json:"tx_hash" yaml:"tx_hash" toml:"tx_hash"`
+	Index  Uint32 `json:"index" yaml:"index" toml:"index"`
 }
 
 // CellInput ckb cell input
@@ -61,12 +46,14 @@ type CellOutput struct {
 	Capacity Uint64  `json:"capacity"`
 	Lock     Script  `json:"lock"`
 	Type     *Script `json:"type"`
+	// UnknownFields trailing table fields unknown to this version
+	UnknownFields [][]byte `json:"-"`
 }
 
 // CellDep ckb cell dep
 type CellDep struct {
-	OutPoint OutPoint `json:"out_point"`
-	DepType  DepType  `json:"dep_type"`
+	OutPoint OutPoint `json:"out_point" yaml:"out_point" toml:"out_point"`
+	DepType  DepType  `json:"dep_type" yaml:"dep_type" toml:"dep_type"`
 }
 
 // Transaction ckb transaction
@@ -78,6 +65,8 @@ type Transaction struct {
 	Outputs     []CellOutput `json:"outputs"`
 	Witnesses   []Bytes      `json:"witnesses"`
 	OutputsData []Bytes      `json:"outputs_data"`
+	// UnknownFields trailing RawTransaction fields unknown to this version
+	UnknownFields [][]byte `json:"-"`
 }
 
 // Header ckb header
@@ -90,8 +79,8 @@ type Header struct {
 	Epoch            Uint64  `json:"epoch"`
 	TransactionsRoot Hash    `json:"transactions_root"`
 	ProposalsHash    Hash    `json:"proposals_hash"`
-	UnclesHash       Hash    `json:"uncles_hash"`
-	Dao              string  `json:"dao"`
+	ExtraHash        Hash    `json:"extra_hash"`
+	Dao              Hash    `json:"dao"`
 	Nonce            Uint128 `json:"nonce"`
 }
 
@@ -99,6 +88,8 @@ type Header struct {
 type UncleBlock struct {
 	Header    Header            `json:"header"`
 	Proposals []ProposalShortID `json:"proposals"`
+	// UnknownFields trailing table fields unknown to this version
+	UnknownFields [][]byte `json:"-"`
 }
 
 // Block ckb block
@@ -107,4 +98,36 @@ type Block struct {
 	Uncles       []UncleBlock      `json:"uncles"`
 	Transactions []Transaction     `json:"transactions"`
 	Proposals    []ProposalShortID `json:"proposals"`
+	Extension    *Bytes            `json:"extension,omitempty"`
+	// UnknownFields trailing BlockV1 fields unknown to this version
+	UnknownFields [][]byte `json:"-"`
+}
+
+// Views, as returned by the node, carry the hashes calculated by the node
+
+// TransactionView ckb transaction with its hash
+type TransactionView struct {
+	Transaction
+	Hash Hash `json:"hash"`
+}
+
+// HeaderView ckb header with its hash
+type HeaderView struct {
+	Header
+	Hash Hash `json:"hash"`
+}
+
+// UncleBlockView ckb uncle block with header hash
+type UncleBlockView struct {
+	Header    HeaderView        `json:"header"`
+	Proposals []ProposalShortID `json:"proposals"`
+}
+
+// BlockView ckb block with hashes
+type BlockView struct {
+	Header       HeaderView        `json:"header"`
+	Uncles       []UncleBlockView  `json:"uncles"`
+	Transactions []TransactionView `json:"transactions"`
+	Proposals    []ProposalShortID `json:"proposals"`
+	Extension    *Bytes            `json:"extension,omitempty"`
 }
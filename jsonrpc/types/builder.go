@@ -0,0 +1,338 @@
+package types
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// LiveCell live cell with its outpoint, as returned by indexer get_cells
+type LiveCell struct {
+	OutPoint OutPoint
+	Output   CellOutput
+	Data     Bytes
+}
+
+// CellCollector live cells source, usually backed by indexer get_cells
+type CellCollector interface {
+	// LiveCells live cells locked by lock, collected in returned order
+	LiveCells(lock *Script) ([]LiveCell, error)
+}
+
+// Skeleton transaction being built with resolved input cells, so capacity
+// and lock groups are known before signing
+type Skeleton struct {
+	Tx *Transaction
+	// Cells resolved previous outputs, in input order
+	Cells []LiveCell
+}
+
+// NewSkeleton new skeleton of empty transaction, fields are empty instead
+// of nil since node rejects json null
+func NewSkeleton() *Skeleton {
+	return &Skeleton{
+		Tx: &Transaction{
+			CellDeps:    []CellDep{},
+			HeaderDeps:  []Hash{},
+			Inputs:      []CellInput{},
+			Outputs:     []CellOutput{},
+			Witnesses:   []Bytes{},
+			OutputsData: []Bytes{},
+		},
+		Cells: []LiveCell{},
+	}
+}
+
+// AddInput spend live cell with since, witness is left empty
+func (s *Skeleton) AddInput(c LiveCell, since Uint64) {
+	s.Tx.Inputs = append(s.Tx.Inputs, CellInput{Since: since, PreviousOutput: c.OutPoint})
+	s.Tx.expandWitnesses(len(s.Tx.Inputs))
+	s.Cells = append(s.Cells, c)
+}
+
+// AddOutput add output with data
+func (s *Skeleton) AddOutput(o CellOutput, data Bytes) {
+	s.Tx.Outputs = append(s.Tx.Outputs, o)
+	s.Tx.OutputsData = append(s.Tx.OutputsData, data)
+}
+
+// AddCellDep add cell dep if not added yet
+func (s *Skeleton) AddCellDep(d CellDep) {
+	for _, dd := range s.Tx.CellDeps {
+		if dd == d {
+			return
+		}
+	}
+
+	s.Tx.CellDeps = append(s.Tx.CellDeps, d)
+}
+
+// HasInput report whether outpoint is spent by skeleton
+func (s *Skeleton) HasInput(o OutPoint) bool {
+	for _, i := range s.Tx.Inputs {
+		if i.PreviousOutput == o {
+			return true
+		}
+	}
+
+	return false
+}
+
+// InputsCapacity capacity of resolved inputs
+func (s *Skeleton) InputsCapacity() (Uint64, error) {
+	var sum, carry uint64
+	for _, c := range s.Cells {
+		sum, carry = bits.Add64(sum, uint64(c.Output.Capacity), 0)
+		if carry != 0 {
+			return 0, fmt.Errorf("inputs capacity overflow")
+		}
+	}
+
+	return Uint64(sum), nil
+}
+
+// OutputsCapacity capacity of outputs
+func (s *Skeleton) OutputsCapacity() (Uint64, error) {
+	var sum, carry uint64
+	for _, o := range s.Tx.Outputs {
+		sum, carry = bits.Add64(sum, uint64(o.Capacity), 0)
+		if carry != 0 {
+			return 0, fmt.Errorf("outputs capacity overflow")
+		}
+	}
+
+	return Uint64(sum), nil
+}
+
+// Groups input groups by resolved lock
+func (s *Skeleton) Groups() [][]int {
+	locks := make([]Script, len(s.Cells))
+	for i := 0; i < len(s.Cells); i++ {
+		locks[i] = s.Cells[i].Output.Lock
+	}
+
+	return GroupInputsByLock(locks)
+}
+
+// Builder transaction builder, collects live cells to pay outputs and fee,
+// locks and their cell deps are resolved by registry
+type Builder struct {
+	Collector CellCollector
+	Registry  *ScriptRegistry
+}
+
+// NewBuilder new builder
+func NewBuilder(c CellCollector, r *ScriptRegistry) *Builder {
+	return &Builder{Collector: c, Registry: r}
+}
+
+// BuildTransfer build transaction sending amount shannons from one address
+// to another, returned transaction has witness lock placeholders and is
+// ready to sign
+/*
+ * Only plain cells of sender, without type script and data, are spent.
+ * Change goes back to sender, it is left out only if inputs match outputs
+ * plus fee exactly.
+ */
+func (b *Builder) BuildTransfer(from Address, to Address, amount Uint64, feeRate Uint64) (*Transaction, error) {
+	if from.Network != to.Network {
+		return nil, fmt.Errorf("mismatch network, from %s to %s", from.Network, to.Network)
+	}
+
+	s := NewSkeleton()
+	s.AddOutput(CellOutput{Capacity: amount, Lock: *to.Script.Clone()}, Bytes{})
+
+	occupied, err := s.Tx.Outputs[0].OccupiedCapacity(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if amount < occupied {
+		return nil, fmt.Errorf("invalid amount, less than %s CKB occupied by receiver cell", formatCKB(occupied))
+	}
+
+	err = b.Balance(s, from, feeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Tx, nil
+}
+
+// Balance add plain cells of address as inputs until inputs pay outputs
+// and fee, change goes back to address
+func (b *Builder) Balance(s *Skeleton, from Address, feeRate Uint64) error {
+	return b.balance(s, from, func(size uint64) (Uint64, error) {
+		return CalculateFee(size, feeRate)
+	})
+}
+
+// balance add cells until inputs pay outputs and fee of final size
+func (b *Builder) balance(s *Skeleton, from Address, fee func(size uint64) (Uint64, error)) error {
+	if b.Registry.Network != from.Network {
+		return fmt.Errorf("mismatch network, registry is %s, address is %s", b.Registry.Network, from.Network)
+	}
+
+	cells, err := b.Collector.LiveCells(&from.Script)
+	if err != nil {
+		return err
+	}
+
+	change := CellOutput{Lock: *from.Script.Clone()}
+
+	next := 0
+	for {
+		done, err := b.settle(s, change, fee)
+		if err != nil {
+			return err
+		}
+
+		if done {
+			return nil
+		}
+
+		for next < len(cells) && (!isPlainCell(&cells[next]) || s.HasInput(cells[next].OutPoint)) {
+			next++
+		}
+
+		if next == len(cells) {
+			return fmt.Errorf("insufficient capacity, not enough live cells of %s", from)
+		}
+
+		s.AddInput(cells[next], 0)
+		next++
+	}
+}
+
+// settle try to finish skeleton with current inputs, change is added if
+// left capacity can hold it
+func (b *Builder) settle(s *Skeleton, change CellOutput, feeOf func(size uint64) (Uint64, error)) (bool, error) {
+	err := b.resolveLocks(s)
+	if err != nil {
+		return false, err
+	}
+
+	inputs, err := s.InputsCapacity()
+	if err != nil {
+		return false, err
+	}
+
+	outputs, err := s.OutputsCapacity()
+	if err != nil {
+		return false, err
+	}
+
+	if inputs < outputs {
+		return false, nil
+	}
+	left := inputs - outputs
+
+	size, err := s.Tx.SizeInBlock()
+	if err != nil {
+		return false, err
+	}
+
+	fee, err := feeOf(size)
+	if err != nil {
+		return false, err
+	}
+
+	if left == fee {
+		return true, nil
+	}
+
+	s.AddOutput(change, Bytes{})
+
+	size, err = s.Tx.SizeInBlock()
+	if err != nil {
+		return false, err
+	}
+
+	fee, err = feeOf(size)
+	if err != nil {
+		return false, err
+	}
+
+	occupied, err := change.OccupiedCapacity(0)
+	if err != nil {
+		return false, err
+	}
+
+	last := len(s.Tx.Outputs) - 1
+	if left >= fee && left-fee >= occupied {
+		s.Tx.Outputs[last].Capacity = left - fee
+		return true, nil
+	}
+
+	s.Tx.Outputs = s.Tx.Outputs[:last]
+	s.Tx.OutputsData = s.Tx.OutputsData[:last]
+
+	return false, nil
+}
+
+// resolveLocks add cell deps of input locks and fill witness lock
+// placeholders, so transaction size is final before signing
+func (b *Builder) resolveLocks(s *Skeleton) error {
+	for _, g := range s.Groups() {
+		lock := &s.Cells[g[0]].Output.Lock
+
+		sys := b.Registry.Find(lock)
+		if sys == nil {
+			return fmt.Errorf("unknown lock script %s", lock.CodeHash)
+		}
+		s.AddCellDep(sys.CellDep)
+
+		size, err := b.witnessLockSize(lock)
+		if err != nil {
+			return err
+		}
+
+		if size == 0 {
+			continue
+		}
+
+		w, err := s.Tx.WitnessArgsAt(g[0])
+		if err != nil {
+			return err
+		}
+
+		placeholder := make(Bytes, size)
+		w.Lock = &placeholder
+
+		err = s.Tx.SetWitnessArgsAt(g[0], w)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// witnessLockSize witness lock size of signed lock, zero if lock is
+// unlocked without signature, anyone can pay cells builders only pay into
+func (b *Builder) witnessLockSize(lock *Script) (int, error) {
+	if lock.CodeHash == SecpSighashAllCodeHash && lock.HashType == Type {
+		return SignatureSize, nil
+	}
+
+	if b.isAnyoneCanPay(lock) {
+		return 0, nil
+	}
+
+	return 0, fmt.Errorf("unknown witness lock size of lock script %s", lock.CodeHash)
+}
+
+// isAnyoneCanPay report whether lock is anyone can pay lock of registry
+func (b *Builder) isAnyoneCanPay(lock *Script) bool {
+	acp, err := b.Registry.Lookup("anyone_can_pay")
+	if err != nil {
+		return false
+	}
+
+	return lock.CodeHash == acp.CodeHash && lock.HashType == acp.HashType
+}
+
+// isPlainCell report whether cell only holds capacity, no type script or
+// data, so it is safe to spend for fee and change
+func isPlainCell(c *LiveCell) bool {
+	return c.Output.Type == nil && len(c.Data) == 0
+}
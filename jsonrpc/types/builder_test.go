@@ -0,0 +1,132 @@
+package types
+
+import (
+	"testing"
+)
+
+// testCollector live cells keyed by lock args
+type testCollector map[string][]LiveCell
+
+func (c testCollector) LiveCells(lock *Script) ([]LiveCell, error) {
+	return c[lock.Args.String()], nil
+}
+
+func testRegistry() *ScriptRegistry {
+	return &ScriptRegistry{
+		Network: Testnet,
+		Scripts: []SystemScript{{
+			Name:     "secp256k1_blake160_sighash_all",
+			CodeHash: SecpSighashAllCodeHash,
+			HashType: Type,
+			CellDep:  CellDep{OutPoint: OutPoint{TxHash: Hash{0xde}}, DepType: DepGroup},
+		}},
+	}
+}
+
+func testPlainCell(txHash byte, lock Script, ckb Uint64) LiveCell {
+	return LiveCell{
+		OutPoint: OutPoint{TxHash: Hash{txHash}},
+		Output:   CellOutput{Capacity: ckb * ShannonsPerCKB, Lock: lock},
+		Data:     Bytes{},
+	}
+}
+
+func TestBuildTransfer(t *testing.T) {
+	seckey := make([]byte, 32)
+	seckey[31] = 1
+
+	signer, err := NewKeySigner(seckey)
+	if err != nil {
+		t.Errorf("fail to create signer: %s\n", err)
+		return
+	}
+
+	pub, _ := signer.GetPublicKey()
+	args, _ := PubkeyToLockArgs(pub)
+
+	from := Address{Network: Testnet, Script: Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: args}}
+	to := Address{Network: Testnet, Script: Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: make(Bytes, 20)}}
+
+	withData := testPlainCell(2, from.Script, 1000)
+	withData.Data = Bytes{0x01}
+
+	c := testCollector{
+		args.String(): {
+			testPlainCell(1, from.Script, 70),
+			withData,
+			testPlainCell(3, from.Script, 200),
+		},
+	}
+
+	b := NewBuilder(c, testRegistry())
+
+	tx, err := b.BuildTransfer(from, to, 100*ShannonsPerCKB, DefaultMinFeeRate)
+	if err != nil {
+		t.Errorf("fail to build transfer: %s\n", err)
+		return
+	}
+
+	if len(tx.Inputs) != 2 || tx.Inputs[1].PreviousOutput.TxHash != (Hash{3}) {
+		t.Errorf("mismatch inputs, got %v", tx.Inputs)
+		return
+	}
+
+	if len(tx.Outputs) != 2 || !tx.Outputs[1].Lock.Equal(&from.Script) {
+		t.Errorf("mismatch outputs, got %v", tx.Outputs)
+		return
+	}
+
+	if len(tx.CellDeps) != 1 || len(tx.Witnesses) != 2 {
+		t.Errorf("mismatch cell deps or witnesses, got %v %v", tx.CellDeps, tx.Witnesses)
+		return
+	}
+
+	fee, err := tx.Fee(DefaultMinFeeRate)
+	if err != nil {
+		t.Errorf("fail to calculate fee: %s\n", err)
+		return
+	}
+
+	r, err := tx.CheckCapacity([]Uint64{70 * ShannonsPerCKB, 200 * ShannonsPerCKB}, fee)
+	if err != nil {
+		t.Errorf("fail to check capacity: %s\n", err)
+		return
+	}
+
+	if !r.OK() || r.Fee != fee {
+		t.Errorf("mismatch capacity report, expect fee %v, got %s", fee, r)
+		return
+	}
+
+	// Signing replaces placeholder of the same size, fee is still exact
+	err = signer.SignTransaction(tx, []int{0, 1})
+	if err != nil {
+		t.Errorf("fail to sign transaction: %s\n", err)
+		return
+	}
+
+	signed, _ := tx.Fee(DefaultMinFeeRate)
+	if signed != fee {
+		t.Errorf("mismatch result, expect %v, got %v", fee, signed)
+		return
+	}
+
+	_, err = b.BuildTransfer(from, to, 300*ShannonsPerCKB, DefaultMinFeeRate)
+	if err == nil {
+		t.Errorf("expect error on insufficient capacity")
+		return
+	}
+
+	_, err = b.BuildTransfer(from, to, 60*ShannonsPerCKB, DefaultMinFeeRate)
+	if err == nil {
+		t.Errorf("expect error on amount below occupied capacity")
+		return
+	}
+
+	to.Network = Mainnet
+	_, err = b.BuildTransfer(from, to, 100*ShannonsPerCKB, DefaultMinFeeRate)
+	if err == nil {
+		t.Errorf("expect error on mismatch network")
+		return
+	}
+}
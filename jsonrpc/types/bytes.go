@@ -0,0 +1,69 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+)
+
+// Bytes ckb bytes, '0x' prefix hex string in json
+type Bytes []byte
+
+// ParseBytes parse bytes from '0x' prefix hex string
+func ParseBytes(s string) (Bytes, error) {
+	err := check0xPrefix(s)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return nil, err
+	}
+
+	return Bytes(b), nil
+}
+
+// String '0x' prefix hex string
+func (b Bytes) String() string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+// MarshalJSON marshal bytes to '0x' prefix hex string
+func (b Bytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.String())
+}
+
+// UnmarshalJSON unmarshal bytes from '0x' prefix hex string
+func (b *Bytes) UnmarshalJSON(data []byte) error {
+	var s string
+
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+
+	bb, err := ParseBytes(s)
+	if err != nil {
+		return err
+	}
+
+	*b = bb
+	return nil
+}
+
+// MarshalText marshal bytes to '0x' prefix hex, so bytes are hex instead
+// of base64 or binary in yaml and toml
+func (b Bytes) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText unmarshal bytes from '0x' prefix hex
+func (b *Bytes) UnmarshalText(text []byte) error {
+	bb, err := ParseBytes(string(text))
+	if err != nil {
+		return err
+	}
+
+	*b = bb
+	return nil
+}
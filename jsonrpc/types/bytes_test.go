@@ -0,0 +1,43 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBytesJSON(t *testing.T) {
+	raw := `["0x", "0xc8328aabcd9b9e8e64fbc566c4385c3bdeb219d7"]`
+
+	var bs []Bytes
+
+	err := json.Unmarshal([]byte(raw), &bs)
+	if err != nil {
+		t.Errorf("fail to unmarshal bytes json: %s\n", err)
+		return
+	}
+
+	if len(bs[0]) != 0 || len(bs[1]) != 20 {
+		t.Errorf("mismatch bytes length, got %v and %v", len(bs[0]), len(bs[1]))
+		return
+	}
+
+	got, err := json.Marshal(bs)
+	if err != nil {
+		t.Errorf("fail to marshal bytes: %s\n", err)
+		return
+	}
+
+	expect := `["0x","0xc8328aabcd9b9e8e64fbc566c4385c3bdeb219d7"]`
+	if string(got) != expect {
+		t.Errorf("mismatch result, expect %v, got %v", expect, string(got))
+		return
+	}
+
+	var b Bytes
+
+	err = json.Unmarshal([]byte(`"c8328aab"`), &b)
+	if err == nil {
+		t.Errorf("expect error on missing 0x prefix")
+		return
+	}
+}
@@ -0,0 +1,279 @@
+package types
+
+import (
+	"fmt"
+)
+
+// MolKind molecule type kind
+type MolKind int
+
+// Molecule kinds
+const (
+	MolByte MolKind = iota
+	MolArray
+	MolStruct
+	MolFixVec
+	MolDynVec
+	MolTable
+	MolOption
+	MolUnion
+)
+
+// MolSchema molecule type layout checked by VerifyCanonical
+type MolSchema struct {
+	Kind MolKind
+	// Count items of array
+	Count int
+	// Item of array, fixvec, dynvec and option
+	Item *MolSchema
+	// Fields of struct and table, items of union
+	Fields []*MolSchema
+	// UnionIDs ids of union items, in fields order
+	UnionIDs []uint32
+}
+
+// MolArrayOf schema of array of count items
+func MolArrayOf(item *MolSchema, count int) *MolSchema {
+	return &MolSchema{Kind: MolArray, Item: item, Count: count}
+}
+
+// MolStructOf schema of struct of fields
+func MolStructOf(fields ...*MolSchema) *MolSchema {
+	return &MolSchema{Kind: MolStruct, Fields: fields}
+}
+
+// MolFixVecOf schema of fixvec of fixed size item
+func MolFixVecOf(item *MolSchema) *MolSchema {
+	return &MolSchema{Kind: MolFixVec, Item: item}
+}
+
+// MolDynVecOf schema of dynvec of item
+func MolDynVecOf(item *MolSchema) *MolSchema {
+	return &MolSchema{Kind: MolDynVec, Item: item}
+}
+
+// MolTableOf schema of table of fields
+func MolTableOf(fields ...*MolSchema) *MolSchema {
+	return &MolSchema{Kind: MolTable, Fields: fields}
+}
+
+// MolOptionOf schema of option of item
+func MolOptionOf(item *MolSchema) *MolSchema {
+	return &MolSchema{Kind: MolOption, Item: item}
+}
+
+// MolUnionOf schema of union of items with ids
+func MolUnionOf(ids []uint32, items ...*MolSchema) *MolSchema {
+	return &MolSchema{Kind: MolUnion, Fields: items, UnionIDs: ids}
+}
+
+// Schemas of ckb blockchain.mol types
+var (
+	MolByteSchema    = &MolSchema{Kind: MolByte}
+	MolUint32Schema  = MolArrayOf(MolByteSchema, 4)
+	MolUint64Schema  = MolArrayOf(MolByteSchema, 8)
+	MolUint128Schema = MolArrayOf(MolByteSchema, 16)
+	MolByte32Schema  = MolArrayOf(MolByteSchema, 32)
+	MolUint256Schema = MolArrayOf(MolByteSchema, 32)
+	MolBytesSchema   = MolFixVecOf(MolByteSchema)
+
+	ProposalShortIDSchema = MolArrayOf(MolByteSchema, 10)
+
+	ScriptSchema     = MolTableOf(MolByte32Schema, MolByteSchema, MolBytesSchema)
+	OutPointSchema   = MolStructOf(MolByte32Schema, MolUint32Schema)
+	CellInputSchema  = MolStructOf(MolUint64Schema, OutPointSchema)
+	CellOutputSchema = MolTableOf(MolUint64Schema, ScriptSchema, MolOptionOf(ScriptSchema))
+	CellDepSchema    = MolStructOf(OutPointSchema, MolByteSchema)
+
+	RawTransactionSchema = MolTableOf(
+		MolUint32Schema,
+		MolFixVecOf(CellDepSchema),
+		MolFixVecOf(MolByte32Schema),
+		MolFixVecOf(CellInputSchema),
+		MolDynVecOf(CellOutputSchema),
+		MolDynVecOf(MolBytesSchema),
+	)
+	TransactionSchema = MolTableOf(RawTransactionSchema, MolDynVecOf(MolBytesSchema))
+	WitnessArgsSchema = MolTableOf(MolOptionOf(MolBytesSchema), MolOptionOf(MolBytesSchema), MolOptionOf(MolBytesSchema))
+
+	RawHeaderSchema = MolStructOf(
+		MolUint32Schema,
+		MolUint32Schema,
+		MolUint64Schema,
+		MolUint64Schema,
+		MolUint64Schema,
+		MolByte32Schema,
+		MolByte32Schema,
+		MolByte32Schema,
+		MolByte32Schema,
+		MolByte32Schema,
+	)
+	HeaderSchema     = MolStructOf(RawHeaderSchema, MolUint128Schema)
+	UncleBlockSchema = MolTableOf(HeaderSchema, MolFixVecOf(ProposalShortIDSchema))
+	BlockSchema      = MolTableOf(
+		HeaderSchema,
+		MolDynVecOf(UncleBlockSchema),
+		MolDynVecOf(TransactionSchema),
+		MolFixVecOf(ProposalShortIDSchema),
+	)
+	BlockV1Schema = MolTableOf(
+		HeaderSchema,
+		MolDynVecOf(UncleBlockSchema),
+		MolDynVecOf(TransactionSchema),
+		MolFixVecOf(ProposalShortIDSchema),
+		MolBytesSchema,
+	)
+)
+
+// VerifyCanonical check data is the one canonical encoding of schema
+/*
+ * Molecule decoders, this package's included, accept encodings that hash
+ * differently from what a node produces:
+ *
+ *     Tables with trailing fields unknown to the schema.
+ *     Bytes after the declared size, when padding is allowed.
+ *
+ * Canonical data has exact sizes everywhere: headers match full size,
+ * offsets start right after header and never go back, fields tile the
+ * body with no gap or overlap, tables have exactly the schema fields,
+ * fixvecs hold exactly count items, unions use known ids. Consensus
+ * adjacent services should reject anything else.
+ */
+func VerifyCanonical(data []byte, s *MolSchema) error {
+	switch s.Kind {
+	case MolByte, MolArray, MolStruct:
+		size, err := s.fixedSize()
+		if err != nil {
+			return err
+		}
+
+		if len(data) != size {
+			return fmt.Errorf("invalid %s, should be %d bytes, got %d", s.kindName(), size, len(data))
+		}
+
+		return nil
+	case MolFixVec:
+		size, err := s.Item.fixedSize()
+		if err != nil {
+			return err
+		}
+
+		n, err := deserializeUint32(data)
+		if err != nil {
+			return fmt.Errorf("invalid fixvec, %s", err)
+		}
+
+		if uint64(len(data)) != uint64(u32Size)+uint64(n)*uint64(size) {
+			return fmt.Errorf("invalid fixvec, %d items of %d bytes mismatch %d bytes", n, size, len(data))
+		}
+
+		return nil
+	case MolDynVec:
+		items, err := deserializeOffsets(data, "dynvec")
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(items); i++ {
+			err = VerifyCanonical(items[i], s.Item)
+			if err != nil {
+				return fmt.Errorf("dynvec item %d, %s", i, err)
+			}
+		}
+
+		return nil
+	case MolTable:
+		fields, err := DeserializeTable(data, len(s.Fields))
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(fields); i++ {
+			err = VerifyCanonical(fields[i], s.Fields[i])
+			if err != nil {
+				return fmt.Errorf("table field %d, %s", i, err)
+			}
+		}
+
+		return nil
+	case MolOption:
+		if len(data) == 0 {
+			return nil
+		}
+
+		return VerifyCanonical(data, s.Item)
+	case MolUnion:
+		id, item, err := DeserializeUnion(data)
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(s.UnionIDs) && i < len(s.Fields); i++ {
+			if s.UnionIDs[i] == id {
+				err = VerifyCanonical(item, s.Fields[i])
+				if err != nil {
+					return fmt.Errorf("union item %d, %s", id, err)
+				}
+
+				return nil
+			}
+		}
+
+		return fmt.Errorf("invalid union, unknown item id %d", id)
+	default:
+		return fmt.Errorf("invalid schema, unknown kind %d", s.Kind)
+	}
+}
+
+// fixedSize size of byte, array and struct, error for dynamic kinds
+func (s *MolSchema) fixedSize() (int, error) {
+	switch s.Kind {
+	case MolByte:
+		return byteSize, nil
+	case MolArray:
+		size, err := s.Item.fixedSize()
+		if err != nil {
+			return 0, err
+		}
+
+		return size * s.Count, nil
+	case MolStruct:
+		total := 0
+		for _, f := range s.Fields {
+			size, err := f.fixedSize()
+			if err != nil {
+				return 0, err
+			}
+
+			total += size
+		}
+
+		return total, nil
+	default:
+		return 0, fmt.Errorf("invalid schema, %s has no fixed size", s.kindName())
+	}
+}
+
+// kindName molecule name of kind
+func (s *MolSchema) kindName() string {
+	switch s.Kind {
+	case MolByte:
+		return "byte"
+	case MolArray:
+		return "array"
+	case MolStruct:
+		return "struct"
+	case MolFixVec:
+		return "fixvec"
+	case MolDynVec:
+		return "dynvec"
+	case MolTable:
+		return "table"
+	case MolOption:
+		return "option"
+	case MolUnion:
+		return "union"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,85 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestVerifyCanonical(t *testing.T) {
+	lock := Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: make(Bytes, 20)}
+	tx := DefaultTransaction()
+	tx.Inputs = []CellInput{DefaultCellInput()}
+	tx.Outputs = []CellOutput{{Capacity: 6100000000, Lock: lock, Type: &lock}}
+	tx.OutputsData = []Bytes{{0x01}}
+	tx.Witnesses = []Bytes{{0x01, 0x02}}
+
+	packed, _ := tx.Pack()
+	err := VerifyCanonical(packed, TransactionSchema)
+	if err != nil {
+		t.Errorf("fail to verify canonical transaction: %s\n", err)
+		return
+	}
+
+	var b Block
+	err = json.Unmarshal([]byte(recordedBlock), &b)
+	if err != nil {
+		t.Errorf("fail to unmarshal block json: %s\n", err)
+		return
+	}
+
+	data, _ := b.Serialize()
+	err = VerifyCanonical(data, BlockSchema)
+	if err != nil {
+		t.Errorf("fail to verify canonical block: %s\n", err)
+		return
+	}
+
+	// Extra field, padding and short data
+	script, _ := lock.Serialize()
+	extra := SerializeTable([][]byte{script[16:48], script[48:49], script[49:], {0x00}})
+	padded := append(append([]byte{}, script...), 0x00)
+	for _, c := range []struct {
+		data   []byte
+		schema *MolSchema
+	}{
+		{extra, ScriptSchema},
+		{padded, ScriptSchema},
+		{script[:len(script)-1], ScriptSchema},
+		{packed, RawTransactionSchema},
+		{data, BlockV1Schema},
+	} {
+		err = VerifyCanonical(c.data, c.schema)
+		if err == nil {
+			t.Errorf("expect error on non canonical %x", c.data)
+			return
+		}
+	}
+
+	// Field with fixvec count mismatch, item count says 2 for 1 byte
+	bad := SerializeTable([][]byte{script[16:48], script[48:49], {0x02, 0x00, 0x00, 0x00, 0x01}})
+	err = VerifyCanonical(bad, ScriptSchema)
+	if err == nil {
+		t.Errorf("expect error on fixvec count mismatch")
+		return
+	}
+
+	union := MolUnionOf([]uint32{0, 0xff000001}, MolUint32Schema, MolBytesSchema)
+	for data, ok := range map[string]bool{
+		"\x00\x00\x00\x00\x01\x00\x00\x00":             true,
+		"\x01\x00\x00\xff\x01\x00\x00\x00\xaa":         true,
+		"\x01\x00\x00\x00\x01\x00\x00\x00":             false,
+		"\x00\x00\x00\x00\x01\x00\x00\x00\x00\x00\x00": false,
+	} {
+		err = VerifyCanonical([]byte(data), union)
+		if (err == nil) != ok {
+			t.Errorf("mismatch union %x, expect ok %v, got %v", data, ok, err)
+			return
+		}
+	}
+
+	err = VerifyCanonical([]byte{0x04, 0x00, 0x00, 0x00}, MolFixVecOf(ScriptSchema))
+	if err == nil {
+		t.Errorf("expect error on fixvec of table")
+		return
+	}
+}
@@ -0,0 +1,273 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// ShannonsPerCKB shannons in one CKB
+const ShannonsPerCKB = 100000000
+
+// ckbDecimals fraction digits of one CKB in shannons
+const ckbDecimals = 8
+
+// ErrCapacityPrecision amount has more than 8 fraction digits, it can not
+// be represented in shannons without rounding
+var ErrCapacityPrecision = errors.New("capacity finer than one shannon")
+
+// OccupiedCapacity bytes occupied by script, in shannons
+/*
+ *     code_hash 32 bytes, hash_type 1 byte, args length.
+ */
+func (s *Script) OccupiedCapacity() (Uint64, error) {
+	return bytesToCapacity(uint64(hashSize + byteSize + len(s.Args)))
+}
+
+// OccupiedCapacity bytes occupied by cell output with given data length,
+// in shannons, capacity must be at least this much
+/*
+ *     capacity 8 bytes, lock script, type script if any, data length.
+ */
+func (o *CellOutput) OccupiedCapacity(dataLen int) (Uint64, error) {
+	if dataLen < 0 {
+		return 0, fmt.Errorf("invalid data length %d", dataLen)
+	}
+
+	size := uint64(uint64Size + hashSize + byteSize + len(o.Lock.Args) + dataLen)
+	if o.Type != nil {
+		size += uint64(hashSize + byteSize + len(o.Type.Args))
+	}
+
+	return bytesToCapacity(size)
+}
+
+// IsLackOfCapacity report whether cell output capacity is less than
+// occupied capacity
+func (o *CellOutput) IsLackOfCapacity(dataLen int) (bool, error) {
+	occupied, err := o.OccupiedCapacity(dataLen)
+	if err != nil {
+		return false, err
+	}
+
+	return o.Capacity < occupied, nil
+}
+
+func bytesToCapacity(size uint64) (Uint64, error) {
+	hi, lo := bits.Mul64(size, ShannonsPerCKB)
+	if hi != 0 {
+		return 0, fmt.Errorf("capacity overflow")
+	}
+
+	return Uint64(lo), nil
+}
+
+// CapacityReport result of transaction capacity check
+type CapacityReport struct {
+	InputsCapacity  Uint64
+	OutputsCapacity Uint64
+	// Fee inputs capacity minus outputs capacity, zero if inputs do not
+	// cover outputs
+	Fee Uint64
+	// Shortfall capacity missing to cover outputs plus minimal fee
+	Shortfall Uint64
+	// LackOfCapacity indexes of outputs below their occupied capacity
+	LackOfCapacity []int
+}
+
+// OK report whether transaction passes capacity check
+func (r *CapacityReport) OK() bool {
+	return r.Shortfall == 0 && len(r.LackOfCapacity) == 0
+}
+
+// String human-readable summary of problems
+func (r *CapacityReport) String() string {
+	if r.OK() {
+		return fmt.Sprintf("ok, fee %s CKB", formatCKB(r.Fee))
+	}
+
+	var problems []string
+	if r.Shortfall != 0 {
+		problems = append(problems, fmt.Sprintf("inputs %s CKB short of outputs plus fee by %s CKB",
+			formatCKB(r.InputsCapacity), formatCKB(r.Shortfall)))
+	}
+
+	for _, i := range r.LackOfCapacity {
+		problems = append(problems, fmt.Sprintf("output %d below occupied capacity", i))
+	}
+
+	return strings.Join(problems, "; ")
+}
+
+// CheckCapacity check transaction capacity before broadcast
+/*
+ * inputCapacities are capacities of resolved previous outputs, in input
+ * order. Inputs must cover outputs plus minFee, and every output including
+ * change must hold at least its occupied capacity.
+ */
+func (t *Transaction) CheckCapacity(inputCapacities []Uint64, minFee Uint64) (*CapacityReport, error) {
+	if len(inputCapacities) != len(t.Inputs) {
+		return nil, fmt.Errorf("invalid input capacities, %d for %d inputs", len(inputCapacities), len(t.Inputs))
+	}
+
+	if len(t.OutputsData) != len(t.Outputs) {
+		return nil, fmt.Errorf("invalid transaction, %d outputs data for %d outputs", len(t.OutputsData), len(t.Outputs))
+	}
+
+	r := new(CapacityReport)
+
+	var sum, carry uint64
+	for _, c := range inputCapacities {
+		sum, carry = bits.Add64(uint64(r.InputsCapacity), uint64(c), 0)
+		if carry != 0 {
+			return nil, fmt.Errorf("inputs capacity overflow")
+		}
+
+		r.InputsCapacity = Uint64(sum)
+	}
+
+	for i := 0; i < len(t.Outputs); i++ {
+		sum, carry = bits.Add64(uint64(r.OutputsCapacity), uint64(t.Outputs[i].Capacity), 0)
+		if carry != 0 {
+			return nil, fmt.Errorf("outputs capacity overflow")
+		}
+		r.OutputsCapacity = Uint64(sum)
+
+		lack, err := t.Outputs[i].IsLackOfCapacity(len(t.OutputsData[i]))
+		if err != nil {
+			return nil, err
+		}
+
+		if lack {
+			r.LackOfCapacity = append(r.LackOfCapacity, i)
+		}
+	}
+
+	if r.InputsCapacity >= r.OutputsCapacity {
+		r.Fee = r.InputsCapacity - r.OutputsCapacity
+	}
+
+	required, carry := bits.Add64(uint64(r.OutputsCapacity), uint64(minFee), 0)
+	if carry != 0 {
+		return nil, fmt.Errorf("outputs capacity overflow")
+	}
+
+	if uint64(r.InputsCapacity) < required {
+		r.Shortfall = Uint64(required - uint64(r.InputsCapacity))
+	}
+
+	return r, nil
+}
+
+// FormatCapacity format shannons as CKB with thousands separators and unit,
+// like "1,234.5678 CKB", trailing fraction zeros trimmed
+/*
+ * Output is locale-neutral, always ',' for groups and '.' for fraction,
+ * and ParseCapacity reads it back exactly.
+ */
+func FormatCapacity(c Uint64) string {
+	s := formatCKB(c)
+
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i:]
+	}
+
+	b := new(strings.Builder)
+	for i := 0; i < len(whole); i++ {
+		if i != 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte(whole[i])
+	}
+
+	return b.String() + frac + " CKB"
+}
+
+// ParseCapacity parse user-entered CKB amount into shannons
+/*
+ * Accepted form, no float64 on the way:
+ *
+ *     digits, optionally grouped by ',' every 3 digits, like "1,234"
+ *     '.' then up to 8 fraction digits, like "1,234.5678"
+ *     " CKB" unit, optional, case-insensitive
+ *
+ * Signs, exponents, empty parts like "1." or ".5", and stray spaces are
+ * rejected. More than 8 fraction digits return ErrCapacityPrecision even
+ * if the extra ones are zeros, instead of rounding silently.
+ */
+func ParseCapacity(s string) (Uint64, error) {
+	amount := s
+	if i := strings.IndexByte(amount, ' '); i >= 0 {
+		if !strings.EqualFold(amount[i+1:], "CKB") {
+			return 0, fmt.Errorf("invalid capacity %q, unknown unit", s)
+		}
+		amount = amount[:i]
+	}
+
+	whole, frac := amount, ""
+	if i := strings.IndexByte(amount, '.'); i >= 0 {
+		whole, frac = amount[:i], amount[i+1:]
+		if frac == "" {
+			return 0, fmt.Errorf("invalid capacity %q, empty fraction", s)
+		}
+	}
+
+	digits, err := ungroupDigits(whole)
+	if err != nil {
+		return 0, fmt.Errorf("invalid capacity %q, %s", s, err)
+	}
+
+	if !isDigits(frac) {
+		return 0, fmt.Errorf("invalid capacity %q, fraction should be digits", s)
+	}
+
+	if len(frac) > ckbDecimals {
+		return 0, ErrCapacityPrecision
+	}
+	frac += strings.Repeat("0", ckbDecimals-len(frac))
+
+	var c uint64
+	for _, d := range digits + frac {
+		hi, lo := bits.Mul64(c, 10)
+		sum, carry := bits.Add64(lo, uint64(d-'0'), 0)
+		if hi != 0 || carry != 0 {
+			return 0, fmt.Errorf("invalid capacity %q, overflow", s)
+		}
+		c = sum
+	}
+
+	return Uint64(c), nil
+}
+
+// ungroupDigits digits of whole part, with ',' groups removed if grouped
+func ungroupDigits(whole string) (string, error) {
+	if whole == "" {
+		return "", fmt.Errorf("empty whole part")
+	}
+
+	groups := strings.Split(whole, ",")
+	for i, g := range groups {
+		if !isDigits(g) || g == "" {
+			return "", fmt.Errorf("whole part should be digits")
+		}
+
+		if len(groups) > 1 && ((i == 0 && len(g) > 3) || (i != 0 && len(g) != 3)) {
+			return "", fmt.Errorf("misplaced group separator")
+		}
+	}
+
+	return strings.Join(groups, ""), nil
+}
+
+// isDigits report whether s has only ascii digits, true for empty s
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,157 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestOccupiedCapacity(t *testing.T) {
+	o := &CellOutput{
+		Capacity: 61 * ShannonsPerCKB,
+		Lock: Script{
+			CodeHash: SecpSighashAllCodeHash,
+			HashType: Type,
+			Args:     make(Bytes, 20),
+		},
+	}
+
+	// Secp256k1 sighash all cell without data, the well known 61 CKB
+	c, err := o.OccupiedCapacity(0)
+	if err != nil {
+		t.Errorf("fail to calculate occupied capacity: %s\n", err)
+		return
+	}
+
+	if c != 61*ShannonsPerCKB {
+		t.Errorf("mismatch result, expect %v, got %v", 61*ShannonsPerCKB, uint64(c))
+		return
+	}
+
+	o.Type = &Script{CodeHash: DaoCodeHash, HashType: Type, Args: Bytes{}}
+	c, err = o.OccupiedCapacity(8)
+	if err != nil {
+		t.Errorf("fail to calculate occupied capacity: %s\n", err)
+		return
+	}
+
+	if c != 102*ShannonsPerCKB {
+		t.Errorf("mismatch result, expect %v, got %v", 102*ShannonsPerCKB, uint64(c))
+		return
+	}
+
+	lack, err := o.IsLackOfCapacity(8)
+	if err != nil || !lack {
+		t.Errorf("expect lack of capacity, got %v %v", lack, err)
+		return
+	}
+}
+
+func TestCheckCapacity(t *testing.T) {
+	lock := Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: make(Bytes, 20)}
+
+	tx := &Transaction{
+		Inputs: []CellInput{{}, {}},
+		Outputs: []CellOutput{
+			{Capacity: 100 * ShannonsPerCKB, Lock: lock},
+			{Capacity: 60 * ShannonsPerCKB, Lock: lock},
+		},
+		OutputsData: []Bytes{{}, {}},
+	}
+
+	r, err := tx.CheckCapacity([]Uint64{100 * ShannonsPerCKB, 60 * ShannonsPerCKB}, 1000)
+	if err != nil {
+		t.Errorf("fail to check capacity: %s\n", err)
+		return
+	}
+
+	if r.OK() || r.Shortfall != 1000 || len(r.LackOfCapacity) != 1 || r.LackOfCapacity[0] != 1 {
+		t.Errorf("mismatch report, got %+v", r)
+		return
+	}
+
+	tx.Outputs[1].Capacity = 61 * ShannonsPerCKB
+	r, err = tx.CheckCapacity([]Uint64{100 * ShannonsPerCKB, 62 * ShannonsPerCKB}, 1000)
+	if err != nil {
+		t.Errorf("fail to check capacity: %s\n", err)
+		return
+	}
+
+	if !r.OK() || r.Fee != ShannonsPerCKB {
+		t.Errorf("mismatch report, got %+v", r)
+		return
+	}
+
+	_, err = tx.CheckCapacity([]Uint64{100 * ShannonsPerCKB}, 0)
+	if err == nil {
+		t.Errorf("expect error on input capacities mismatch")
+		return
+	}
+}
+
+func TestFormatCapacity(t *testing.T) {
+	for c, expect := range map[Uint64]string{
+		0:                            "0 CKB",
+		123456780000:                 "1,234.5678 CKB",
+		1:                            "0.00000001 CKB",
+		999 * ShannonsPerCKB:         "999 CKB",
+		1000000 * ShannonsPerCKB:     "1,000,000 CKB",
+		Uint64(18446744073709551615): "184,467,440,737.09551615 CKB",
+	} {
+		s := FormatCapacity(c)
+		if s != expect {
+			t.Errorf("mismatch result, expect %v, got %v", expect, s)
+			return
+		}
+
+		back, err := ParseCapacity(s)
+		if err != nil {
+			t.Errorf("fail to parse capacity: %s\n", err)
+			return
+		}
+
+		if back != c {
+			t.Errorf("mismatch result, expect %v, got %v", c, back)
+			return
+		}
+	}
+}
+
+func TestParseCapacity(t *testing.T) {
+	for s, expect := range map[string]Uint64{
+		"1234.5678":      123456780000,
+		"1,234.5678 ckb": 123456780000,
+		"61":             61 * ShannonsPerCKB,
+		"0.1":            10000000,
+		"0.00000001":     1,
+	} {
+		c, err := ParseCapacity(s)
+		if err != nil {
+			t.Errorf("fail to parse capacity %q: %s\n", s, err)
+			return
+		}
+
+		if c != expect {
+			t.Errorf("mismatch result, expect %v, got %v", expect, c)
+			return
+		}
+	}
+
+	_, err := ParseCapacity("0.000000001")
+	if err != ErrCapacityPrecision {
+		t.Errorf("mismatch result, expect %v, got %v", ErrCapacityPrecision, err)
+		return
+	}
+
+	_, err = ParseCapacity("1.000000000")
+	if err != ErrCapacityPrecision {
+		t.Errorf("mismatch result, expect %v, got %v", ErrCapacityPrecision, err)
+		return
+	}
+
+	for _, s := range []string{"", "-1", "+1", "1e8", "1.", ".5", "1,23", "12,3456", "1 shannon", " 1", "1  CKB", "1,,234", "184467440737.09551616"} {
+		_, err = ParseCapacity(s)
+		if err == nil {
+			t.Errorf("expect error on %q", s)
+			return
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package types
+
+import (
+	"fmt"
+)
+
+// DepGroupFetcher chain lookups needed to expand dep groups, usually
+// backed by get_live_cell rpc with data
+type DepGroupFetcher interface {
+	// CellData data of the cell at given outpoint
+	CellData(o OutPoint) (Bytes, error)
+}
+
+// DedupCellDeps remove identical cell deps, keep the first occurrence
+func DedupCellDeps(deps []CellDep) []CellDep {
+	seen := make(map[CellDep]bool)
+	dd := make([]CellDep, 0, len(deps))
+
+	for _, d := range deps {
+		if !seen[d] {
+			seen[d] = true
+			dd = append(dd, d)
+		}
+	}
+
+	return dd
+}
+
+// CheckCellDeps report duplicated cell deps, and the same outpoint used
+// with different dep types
+/*
+ * The node rejects only exact duplicates. The same outpoint as both code
+ * and dep group passes verification, but it is almost always a mistake,
+ * cell data can not be both a script and a member list. So this check is
+ * stricter than the node on purpose, use DuplicatedCellDep alone to
+ * match node exactly.
+ */
+func CheckCellDeps(deps []CellDep) error {
+	err := DuplicatedCellDep(deps)
+	if err != nil {
+		return err
+	}
+
+	depTypes := make(map[OutPoint]DepType)
+
+	for _, d := range deps {
+		t, ok := depTypes[d.OutPoint]
+		if !ok {
+			depTypes[d.OutPoint] = d.DepType
+			continue
+		}
+
+		return fmt.Errorf("conflicting cell dep %s, used as both %s and %s", d.OutPoint, t, d.DepType)
+	}
+
+	return nil
+}
+
+// DuplicatedCellDep report identical cell deps, which the node rejects
+func DuplicatedCellDep(deps []CellDep) error {
+	seen := make(map[CellDep]bool)
+
+	for _, d := range deps {
+		if seen[d] {
+			return fmt.Errorf("duplicated cell dep %s", d.OutPoint)
+		}
+		seen[d] = true
+	}
+
+	return nil
+}
+
+// ExpandCellDeps resolve cell deps into the outpoints of code cells,
+// dep groups are replaced by their members
+/*
+ * Result is in dep order and has no duplicates, which is the set of
+ * cells scripts can load code from.
+ */
+func ExpandCellDeps(deps []CellDep, f DepGroupFetcher) ([]OutPoint, error) {
+	err := CheckCellDeps(deps)
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []OutPoint
+	seen := make(map[OutPoint]bool)
+
+	add := func(o OutPoint) {
+		if !seen[o] {
+			seen[o] = true
+			ops = append(ops, o)
+		}
+	}
+
+	for _, d := range deps {
+		if d.DepType == Code {
+			add(d.OutPoint)
+			continue
+		}
+
+		data, err := f.CellData(d.OutPoint)
+		if err != nil {
+			return nil, err
+		}
+
+		members, err := deserializeOutPoints(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dep group %s, %s", d.OutPoint, err)
+		}
+
+		for _, m := range members {
+			add(m)
+		}
+	}
+
+	return ops, nil
+}
+
+// deserializeOutPoints deserialize fixvec of outpoints, dep group cell data
+func deserializeOutPoints(data []byte) ([]OutPoint, error) {
+	items, err := DeserializeFixVec(data, outPointSize)
+	if err != nil {
+		return nil, err
+	}
+
+	ops := make([]OutPoint, len(items))
+	for i := 0; i < len(items); i++ {
+		err = ops[i].Deserialize(items[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ops, nil
+}
+
+// NewDepGroupData dep group cell data, serialized fixvec of member outpoints
+func NewDepGroupData(members []OutPoint) (Bytes, error) {
+	if len(members) == 0 {
+		return nil, fmt.Errorf("invalid dep group, no members")
+	}
+
+	seen := make(map[OutPoint]bool)
+	items := make([][]byte, len(members))
+
+	for i := 0; i < len(members); i++ {
+		if seen[members[i]] {
+			return nil, fmt.Errorf("invalid dep group, duplicated member %s", members[i])
+		}
+		seen[members[i]] = true
+
+		b, err := members[i].Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		items[i] = b
+	}
+
+	return SerializeFixVec(items), nil
+}
+
+// ParseDepGroupData member outpoints of dep group cell data
+func ParseDepGroupData(data Bytes) ([]OutPoint, error) {
+	return deserializeOutPoints(data)
+}
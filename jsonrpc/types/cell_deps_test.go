@@ -0,0 +1,89 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+)
+
+type fakeDepGroupFetcher map[OutPoint]Bytes
+
+func (f fakeDepGroupFetcher) CellData(o OutPoint) (Bytes, error) {
+	data, ok := f[o]
+	if !ok {
+		return nil, fmt.Errorf("unknown cell %s", o)
+	}
+
+	return data, nil
+}
+
+func TestExpandCellDeps(t *testing.T) {
+	code := OutPoint{TxHash: Hash{1}, Index: 0}
+	secp := OutPoint{TxHash: Hash{2}, Index: 0}
+	group := OutPoint{TxHash: Hash{2}, Index: 1}
+
+	groupData, err := NewDepGroupData([]OutPoint{secp, code})
+	if err != nil {
+		t.Errorf("fail to serialize dep group: %s\n", err)
+		return
+	}
+
+	members, err := ParseDepGroupData(groupData)
+	if err != nil || len(members) != 2 || members[0] != secp {
+		t.Errorf("mismatch dep group members, got %v", members)
+		return
+	}
+
+	_, err = NewDepGroupData([]OutPoint{secp, secp})
+	if err == nil {
+		t.Errorf("expect error on duplicated dep group member")
+		return
+	}
+
+	deps := []CellDep{
+		{OutPoint: code, DepType: Code},
+		{OutPoint: group, DepType: DepGroup},
+		{OutPoint: code, DepType: Code},
+	}
+
+	if len(DedupCellDeps(deps)) != 2 {
+		t.Errorf("mismatch dedup result, got %v", DedupCellDeps(deps))
+		return
+	}
+
+	_, err = ExpandCellDeps(deps, fakeDepGroupFetcher{group: groupData})
+	if err == nil {
+		t.Errorf("expect error on duplicated cell deps")
+		return
+	}
+
+	ops, err := ExpandCellDeps(DedupCellDeps(deps), fakeDepGroupFetcher{group: groupData})
+	if err != nil {
+		t.Errorf("fail to expand cell deps: %s\n", err)
+		return
+	}
+
+	if len(ops) != 2 || ops[0] != code || ops[1] != secp {
+		t.Errorf("mismatch result, expect %v, got %v", []OutPoint{code, secp}, ops)
+		return
+	}
+
+	conflicting := []CellDep{{OutPoint: group, DepType: Code}, {OutPoint: group, DepType: DepGroup}}
+	err = CheckCellDeps(conflicting)
+	if err == nil {
+		t.Errorf("expect error on conflicting cell deps")
+		return
+	}
+
+	// Node accepts them
+	err = DuplicatedCellDep(conflicting)
+	if err != nil {
+		t.Errorf("fail to accept cell deps with different dep types: %s\n", err)
+		return
+	}
+
+	err = DuplicatedCellDep(deps)
+	if err == nil {
+		t.Errorf("expect error on duplicated cell deps")
+		return
+	}
+}
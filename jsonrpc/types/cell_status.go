@@ -0,0 +1,11 @@
+package types
+
+// CellStatus ckb get_live_cell cell status
+type CellStatus string
+
+// Cell statuses
+const (
+	CellStatusLive    CellStatus = "live"
+	CellStatusDead    CellStatus = "dead"
+	CellStatusUnknown CellStatus = "unknown"
+)
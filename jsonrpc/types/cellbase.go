@@ -0,0 +1,130 @@
+package types
+
+import (
+	"fmt"
+)
+
+// CellbaseWitness ckb cellbase witness, the miner lock script and an
+// arbitrary message chosen by the miner
+type CellbaseWitness struct {
+	Lock    Script `json:"lock"`
+	Message Bytes  `json:"message"`
+	// UnknownFields trailing table fields unknown to this version
+	UnknownFields [][]byte `json:"-"`
+}
+
+// Serialize cellbase witness
+func (w *CellbaseWitness) Serialize() ([]byte, error) {
+	lock, err := w.Lock.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := w.Message.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return SerializeTable(append([][]byte{lock, msg}, w.UnknownFields...)), nil
+}
+
+// Deserialize cellbase witness
+func (w *CellbaseWitness) Deserialize(data []byte) error {
+	return w.deserialize(data, decoder{})
+}
+
+func (w *CellbaseWitness) deserialize(data []byte, d decoder) error {
+	fields, extra, err := d.table(data, 2)
+	if err != nil {
+		return err
+	}
+	w.UnknownFields = unknownFields(extra, d)
+
+	err = w.Lock.deserialize(fields[0], d)
+	if err != nil {
+		return err
+	}
+
+	return w.Message.deserialize(fields[1], d)
+}
+
+// cellbaseInputIndex index of cellbase null outpoint
+const cellbaseInputIndex = 0xffffffff
+
+// NewCellbaseTransaction build cellbase transaction for block number,
+// reward goes to target lock, which is the lock in cellbase witness of the
+// block the reward is finalized for, nil before any reward is finalized
+/*
+ *     Single input spending the null outpoint, since holds block number.
+ *     Single reward output, omitted if there is no target or reward can't
+ *     cover its occupied capacity.
+ *     Single witness, the cellbase witness of this block.
+ */
+func NewCellbaseTransaction(number Uint64, reward Uint64, target *Script, witness *CellbaseWitness) (*Transaction, error) {
+	w, err := witness.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &Transaction{
+		Version:    0,
+		CellDeps:   []CellDep{},
+		HeaderDeps: []Hash{},
+		Inputs: []CellInput{{
+			Since:          number,
+			PreviousOutput: NullOutPoint(),
+		}},
+		Outputs:     []CellOutput{},
+		OutputsData: []Bytes{},
+		Witnesses:   []Bytes{w},
+	}
+
+	if target == nil {
+		return tx, nil
+	}
+
+	output := CellOutput{Capacity: reward, Lock: *target}
+	lack, err := output.IsLackOfCapacity(0)
+	if err != nil {
+		return nil, err
+	}
+	if lack {
+		return tx, nil
+	}
+
+	tx.Outputs = append(tx.Outputs, output)
+	tx.OutputsData = append(tx.OutputsData, Bytes{})
+
+	return tx, nil
+}
+
+// IsCellbase report whether transaction is a cellbase, which has a single
+// input pointing to the null outpoint
+func (t *Transaction) IsCellbase() bool {
+	if len(t.Inputs) != 1 {
+		return false
+	}
+
+	o := t.Inputs[0].PreviousOutput
+	return o == NullOutPoint()
+}
+
+// CellbaseWitness decode cellbase witness of block, the first transaction
+func (b *Block) CellbaseWitness() (*CellbaseWitness, error) {
+	if len(b.Transactions) == 0 || !b.Transactions[0].IsCellbase() {
+		return nil, fmt.Errorf("invalid block, no cellbase")
+	}
+
+	cellbase := &b.Transactions[0]
+	if len(cellbase.Witnesses) != 1 {
+		return nil, fmt.Errorf("invalid cellbase, should have 1 witness, got %d", len(cellbase.Witnesses))
+	}
+
+	w := new(CellbaseWitness)
+	err := w.Deserialize(cellbase.Witnesses[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
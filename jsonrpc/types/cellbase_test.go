@@ -0,0 +1,93 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestCellbaseWitness(t *testing.T) {
+	var b Block
+
+	err := json.Unmarshal([]byte(recordedBlock), &b)
+	if err != nil {
+		t.Errorf("fail to unmarshal block json: %s\n", err)
+		return
+	}
+
+	lock := b.Transactions[0].Outputs[0].Lock
+	raw, err := (&CellbaseWitness{Lock: lock, Message: Bytes("miner")}).Serialize()
+	if err != nil {
+		t.Errorf("fail to serialize cellbase witness: %s\n", err)
+		return
+	}
+	b.Transactions[0].Witnesses = []Bytes{raw}
+
+	w, err := b.CellbaseWitness()
+	if err != nil {
+		t.Errorf("fail to decode cellbase witness: %s\n", err)
+		return
+	}
+
+	if !w.Lock.Equal(&lock) || !bytes.Equal(w.Message, Bytes("miner")) {
+		t.Errorf("mismatch result, expect %v, got %v", lock, w)
+		return
+	}
+
+	b.Transactions[0].Inputs[0].PreviousOutput.Index = 0
+	_, err = b.CellbaseWitness()
+	if err == nil {
+		t.Errorf("should reject block without cellbase")
+		return
+	}
+}
+
+func TestNewCellbaseTransaction(t *testing.T) {
+	var b BlockView
+
+	err := json.Unmarshal([]byte(recordedBlock), &b)
+	if err != nil {
+		t.Errorf("fail to unmarshal block json: %s\n", err)
+		return
+	}
+
+	expect := b.Transactions[0]
+	output := expect.Outputs[0]
+	witness := &CellbaseWitness{Lock: output.Lock}
+
+	tx, err := NewCellbaseTransaction(b.Header.Number, output.Capacity, &output.Lock, witness)
+	if err != nil {
+		t.Errorf("fail to build cellbase: %s\n", err)
+		return
+	}
+
+	h, err := tx.ComputeHash()
+	if err != nil {
+		t.Errorf("fail to compute hash: %s\n", err)
+		return
+	}
+
+	if h != expect.Hash || !tx.IsCellbase() {
+		t.Errorf("mismatch result, expect %v, got %v", expect.Hash, h)
+		return
+	}
+
+	for _, c := range []struct {
+		reward Uint64
+		target *Script
+	}{
+		{output.Capacity, nil},
+		{ShannonsPerCKB, &output.Lock},
+	} {
+		tx, err = NewCellbaseTransaction(b.Header.Number, c.reward, c.target, witness)
+		if err != nil {
+			t.Errorf("fail to build cellbase: %s\n", err)
+			return
+		}
+
+		if len(tx.Outputs) != 0 || len(tx.OutputsData) != 0 || len(tx.Witnesses) != 1 {
+			t.Errorf("mismatch result, expect no outputs, got %v", tx.Outputs)
+			return
+		}
+	}
+}
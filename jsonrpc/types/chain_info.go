@@ -0,0 +1,19 @@
+package types
+
+// AlertMessage ckb network alert
+type AlertMessage struct {
+	ID          Uint32 `json:"id"`
+	Message     string `json:"message"`
+	NoticeUntil Uint64 `json:"notice_until"`
+	Priority    Uint32 `json:"priority"`
+}
+
+// ChainInfo result of get_blockchain_info
+type ChainInfo struct {
+	Chain                  string         `json:"chain"`
+	MedianTime             Uint64         `json:"median_time"`
+	Epoch                  Uint64         `json:"epoch"`
+	Difficulty             Uint256        `json:"difficulty"`
+	IsInitialBlockDownload bool           `json:"is_initial_block_download"`
+	Alerts                 []AlertMessage `json:"alerts"`
+}
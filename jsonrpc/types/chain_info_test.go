@@ -0,0 +1,31 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestChainInfoJSON(t *testing.T) {
+	raw := `{
+  "alerts": [
+    {
+      "id": "0x2a",
+      "message": "An example alert message!",
+      "notice_until": "0x24bcca57c00",
+      "priority": "0x1"
+    }
+  ],
+  "chain": "ckb",
+  "difficulty": "0x1f4003",
+  "epoch": "0x7080018000001",
+  "is_initial_block_download": true,
+  "median_time": "0x5cd2b105"
+}`
+
+	var info ChainInfo
+	strictRoundTrip(t, raw, &info)
+
+	if NewEpochNumberWithFraction(info.Epoch).Number != 1 || len(info.Alerts) != 1 {
+		t.Errorf("mismatch chain info, got %+v", info)
+		return
+	}
+}
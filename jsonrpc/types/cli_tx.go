@@ -0,0 +1,137 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CLITxFile transaction file used by ckb-cli tx subcommands
+/*
+ * Map keys are '0x' prefix hex strings:
+ *
+ *     multisig_configs: blake160 of multisig script, the multisig lock args
+ *     signatures:       lock args of the signed input group
+ */
+type CLITxFile struct {
+	Transaction     Transaction                  `json:"transaction"`
+	MultisigConfigs map[string]CLIMultisigConfig `json:"multisig_configs"`
+	Signatures      map[string][]Bytes           `json:"signatures"`
+}
+
+// CLIMultisigConfig multisig config in ckb-cli form, signers are given as
+// sighash addresses
+type CLIMultisigConfig struct {
+	SighashAddresses []string `json:"sighash_addresses"`
+	RequireFirstN    uint8    `json:"require_first_n"`
+	Threshold        uint8    `json:"threshold"`
+}
+
+// NewCLITxFile new ckb-cli transaction file without signatures
+func NewCLITxFile(tx *Transaction) *CLITxFile {
+	return &CLITxFile{
+		Transaction:     *tx.Clone(),
+		MultisigConfigs: make(map[string]CLIMultisigConfig),
+		Signatures:      make(map[string][]Bytes),
+	}
+}
+
+// ParseCLITxFile parse ckb-cli transaction file
+func ParseCLITxFile(data []byte) (*CLITxFile, error) {
+	f := new(CLITxFile)
+
+	err := json.Unmarshal(data, f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ckb-cli tx file, %s", err)
+	}
+
+	if f.MultisigConfigs == nil {
+		f.MultisigConfigs = make(map[string]CLIMultisigConfig)
+	}
+
+	if f.Signatures == nil {
+		f.Signatures = make(map[string][]Bytes)
+	}
+
+	return f, nil
+}
+
+// Encode encode ckb-cli transaction file, indented like ckb-cli does
+func (f *CLITxFile) Encode() ([]byte, error) {
+	return json.MarshalIndent(f, "", "  ")
+}
+
+// AddSignature add signature of input group locked by given lock args,
+// duplicated signature is ignored
+func (f *CLITxFile) AddSignature(lockArgs Bytes, sig []byte) {
+	key := lockArgs.String()
+
+	for _, s := range f.Signatures[key] {
+		if s.Equal(sig) {
+			return
+		}
+	}
+
+	f.Signatures[key] = append(f.Signatures[key], Bytes(sig).Clone())
+}
+
+// SignaturesOf signatures of input group locked by given lock args
+func (f *CLITxFile) SignaturesOf(lockArgs Bytes) []Bytes {
+	return f.Signatures[lockArgs.String()]
+}
+
+// NewCLIMultisigConfig convert multisig config into ckb-cli form, signers
+// are encoded as sighash addresses of given network
+func NewCLIMultisigConfig(c *MultisigConfig, network Network) (CLIMultisigConfig, error) {
+	err := c.Validate()
+	if err != nil {
+		return CLIMultisigConfig{}, err
+	}
+
+	addrs := make([]string, len(c.PubkeyHashes))
+	for i, h := range c.PubkeyHashes {
+		lock := &Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: h}
+
+		addrs[i], err = EncodeAddress(network, lock)
+		if err != nil {
+			return CLIMultisigConfig{}, err
+		}
+	}
+
+	return CLIMultisigConfig{
+		SighashAddresses: addrs,
+		RequireFirstN:    uint8(c.RequireFirstN),
+		Threshold:        uint8(c.Threshold),
+	}, nil
+}
+
+// MultisigConfig convert ckb-cli multisig config, every address must be
+// a sighash address
+func (c CLIMultisigConfig) MultisigConfig() (*MultisigConfig, error) {
+	hashes := make([]Bytes, len(c.SighashAddresses))
+
+	for i, addr := range c.SighashAddresses {
+		_, lock, err := DecodeAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		if lock.CodeHash != SecpSighashAllCodeHash || lock.HashType != Type {
+			return nil, fmt.Errorf("invalid multisig config, %s is not a sighash address", addr)
+		}
+
+		hashes[i] = lock.Args
+	}
+
+	return NewMultisigConfig(int(c.RequireFirstN), int(c.Threshold), hashes)
+}
+
+// AddMultisigConfig add multisig config, keyed by its lock args
+func (f *CLITxFile) AddMultisigConfig(c *MultisigConfig, network Network) error {
+	cc, err := NewCLIMultisigConfig(c, network)
+	if err != nil {
+		return err
+	}
+
+	f.MultisigConfigs[c.LockArgs().String()] = cc
+	return nil
+}
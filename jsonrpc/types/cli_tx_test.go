@@ -0,0 +1,149 @@
+package types
+
+import (
+	"testing"
+)
+
+const cliTxFile = `{
+  "transaction": {
+    "version": "0x0",
+    "cell_deps": [
+      {
+        "out_point": {
+          "tx_hash": "0x71a7ba8fc96349fea0ed3a5c47992e3b4084b031a42264a018e0072e8172e46c",
+          "index": "0x1"
+        },
+        "dep_type": "dep_group"
+      }
+    ],
+    "header_deps": [],
+    "inputs": [
+      {
+        "since": "0x0",
+        "previous_output": {
+          "tx_hash": "0x8e7aa6d4d4bf6f1c8e6fd2e4f3d1e1a1e3a4120a2d8c7dbb3a4e5ee0f0d0c0b0",
+          "index": "0x0"
+        }
+      }
+    ],
+    "outputs": [
+      {
+        "capacity": "0x174876e800",
+        "lock": {
+          "code_hash": "0x5c5069eb0857efc65e1bca0c07df34c31663b3622fd3876c876320fc9634e2a8",
+          "hash_type": "type",
+          "args": "0x9b41c025515b00c24e2e2042df7b221af5c1891f"
+        },
+        "type": null
+      }
+    ],
+    "outputs_data": [
+      "0x"
+    ],
+    "witnesses": [
+      "0x"
+    ]
+  },
+  "multisig_configs": {
+    "0x9b41c025515b00c24e2e2042df7b221af5c1891f": {
+      "sighash_addresses": [
+        "ckt1qyqt8xaupvm8837nv3gtc9x0ekkj64vud3jqfwyw5v",
+        "ckt1qyqvsv5240xeh85wvnau2eky8pwrhh4jr8ts8vyj37"
+      ],
+      "require_first_n": 0,
+      "threshold": 2
+    }
+  },
+  "signatures": {
+    "0x9b41c025515b00c24e2e2042df7b221af5c1891f": [
+      "0x5d2bbd9e2f2b1d2c3b2736ad4682d51ff1a6d4a9729ebd3f4e9a7e3209453c6b1e70b7bc7aa5d4e5c4d8fce0ed0971ad8b3a9a0ef1e0f8bb6e20f45d0886a07600"
+    ]
+  }
+}`
+
+func TestCLITxFile(t *testing.T) {
+	var f CLITxFile
+	strictRoundTrip(t, cliTxFile, &f)
+
+	parsed, err := ParseCLITxFile([]byte(cliTxFile))
+	if err != nil {
+		t.Errorf("fail to parse ckb-cli tx file: %s\n", err)
+		return
+	}
+
+	args, _ := ParseBytes("0x9b41c025515b00c24e2e2042df7b221af5c1891f")
+	if parsed.MultisigConfigs[args.String()].Threshold != 2 || len(parsed.SignaturesOf(args)) != 1 {
+		t.Errorf("mismatch parsed file, got %+v", parsed)
+		return
+	}
+
+	sig := make([]byte, SignatureSize)
+	parsed.AddSignature(args, sig)
+	parsed.AddSignature(args, sig)
+	if len(parsed.SignaturesOf(args)) != 2 {
+		t.Errorf("mismatch signatures, got %v", parsed.SignaturesOf(args))
+		return
+	}
+
+	encoded, err := parsed.Encode()
+	if err != nil {
+		t.Errorf("fail to encode ckb-cli tx file: %s\n", err)
+		return
+	}
+
+	again, err := ParseCLITxFile(encoded)
+	if err != nil || len(again.SignaturesOf(args)) != 2 {
+		t.Errorf("mismatch encoded file, got %s", encoded)
+		return
+	}
+
+	empty := NewCLITxFile(&parsed.Transaction)
+	encoded, _ = empty.Encode()
+	again, err = ParseCLITxFile(encoded)
+	if err != nil || len(again.Signatures) != 0 {
+		t.Errorf("mismatch empty file, got %s", encoded)
+		return
+	}
+}
+
+func TestCLIMultisigConfig(t *testing.T) {
+	h1, _ := ParseBytes("0xb39bbc0b3673c7d36450bc14cfcdad2d559c6c64")
+	h2, _ := ParseBytes("0xc8328aabcd9b9e8e64fbc566c4385c3bdeb219d7")
+
+	c, err := NewMultisigConfig(1, 2, []Bytes{h1, h2})
+	if err != nil {
+		t.Errorf("fail to create multisig config: %s\n", err)
+		return
+	}
+
+	f := NewCLITxFile(&Transaction{})
+	err = f.AddMultisigConfig(c, Testnet)
+	if err != nil {
+		t.Errorf("fail to add multisig config: %s\n", err)
+		return
+	}
+
+	cc, ok := f.MultisigConfigs[c.LockArgs().String()]
+	if !ok || len(cc.SighashAddresses) != 2 || cc.SighashAddresses[0][:3] != "ckt" {
+		t.Errorf("mismatch ckb-cli multisig config, got %+v", f.MultisigConfigs)
+		return
+	}
+
+	back, err := cc.MultisigConfig()
+	if err != nil {
+		t.Errorf("fail to convert multisig config: %s\n", err)
+		return
+	}
+
+	if !back.LockArgs().Equal(c.LockArgs()) {
+		t.Errorf("mismatch result, expect %v, got %v", c.LockArgs(), back.LockArgs())
+		return
+	}
+
+	cc.SighashAddresses[0], _ = EncodeAddress(Testnet, &Script{CodeHash: DaoCodeHash, HashType: Type, Args: h1})
+	_, err = cc.MultisigConfig()
+	if err == nil {
+		t.Errorf("expect error on non sighash address")
+		return
+	}
+}
@@ -0,0 +1,259 @@
+package types
+
+// Clone helpers keep nil slices nil and empty slices empty, since they
+// marshal to different json values ("null" and "[]").
+
+// Clone deep copy bytes
+func (b Bytes) Clone() Bytes {
+	if b == nil {
+		return nil
+	}
+
+	c := make(Bytes, len(b))
+	copy(c, b)
+
+	return c
+}
+
+// Clone deep copy script
+func (s *Script) Clone() *Script {
+	if s == nil {
+		return nil
+	}
+
+	return &Script{
+		CodeHash:      s.CodeHash,
+		HashType:      s.HashType,
+		Args:          s.Args.Clone(),
+		UnknownFields: cloneUnknownFields(s.UnknownFields),
+	}
+}
+
+// Clone deep copy cell output
+func (o *CellOutput) Clone() *CellOutput {
+	if o == nil {
+		return nil
+	}
+
+	return &CellOutput{
+		Capacity:      o.Capacity,
+		Lock:          *o.Lock.Clone(),
+		Type:          o.Type.Clone(),
+		UnknownFields: cloneUnknownFields(o.UnknownFields),
+	}
+}
+
+// Clone deep copy transaction
+func (t *Transaction) Clone() *Transaction {
+	if t == nil {
+		return nil
+	}
+
+	c := &Transaction{Version: t.Version}
+
+	if t.CellDeps != nil {
+		c.CellDeps = make([]CellDep, len(t.CellDeps))
+		copy(c.CellDeps, t.CellDeps)
+	}
+
+	if t.HeaderDeps != nil {
+		c.HeaderDeps = make([]Hash, len(t.HeaderDeps))
+		copy(c.HeaderDeps, t.HeaderDeps)
+	}
+
+	if t.Inputs != nil {
+		c.Inputs = make([]CellInput, len(t.Inputs))
+		copy(c.Inputs, t.Inputs)
+	}
+
+	if t.Outputs != nil {
+		c.Outputs = make([]CellOutput, len(t.Outputs))
+		for i := 0; i < len(t.Outputs); i++ {
+			c.Outputs[i] = *t.Outputs[i].Clone()
+		}
+	}
+
+	c.Witnesses = cloneBytesSlice(t.Witnesses)
+	c.OutputsData = cloneBytesSlice(t.OutputsData)
+	c.UnknownFields = cloneUnknownFields(t.UnknownFields)
+
+	return c
+}
+
+// Clone deep copy transaction view
+func (t *TransactionView) Clone() *TransactionView {
+	if t == nil {
+		return nil
+	}
+
+	return &TransactionView{
+		Transaction: *t.Transaction.Clone(),
+		Hash:        t.Hash,
+	}
+}
+
+// Clone deep copy header, header has no reference fields so this is a
+// plain copy, kept for symmetry with views
+func (h *Header) Clone() *Header {
+	if h == nil {
+		return nil
+	}
+
+	c := *h
+	return &c
+}
+
+// Clone deep copy header view
+func (h *HeaderView) Clone() *HeaderView {
+	if h == nil {
+		return nil
+	}
+
+	c := *h
+	return &c
+}
+
+// Clone deep copy witness args
+func (w *WitnessArgs) Clone() *WitnessArgs {
+	if w == nil {
+		return nil
+	}
+
+	return &WitnessArgs{
+		Lock:          cloneBytesPtr(w.Lock),
+		InputType:     cloneBytesPtr(w.InputType),
+		OutputType:    cloneBytesPtr(w.OutputType),
+		UnknownFields: cloneUnknownFields(w.UnknownFields),
+	}
+}
+
+// Clone deep copy uncle block
+func (u *UncleBlock) Clone() *UncleBlock {
+	if u == nil {
+		return nil
+	}
+
+	return &UncleBlock{
+		Header:        u.Header,
+		Proposals:     cloneProposals(u.Proposals),
+		UnknownFields: cloneUnknownFields(u.UnknownFields),
+	}
+}
+
+// Clone deep copy block
+func (b *Block) Clone() *Block {
+	if b == nil {
+		return nil
+	}
+
+	c := &Block{
+		Header:        b.Header,
+		Proposals:     cloneProposals(b.Proposals),
+		UnknownFields: cloneUnknownFields(b.UnknownFields),
+	}
+
+	c.Extension = cloneBytesPtr(b.Extension)
+
+	if b.Uncles != nil {
+		c.Uncles = make([]UncleBlock, len(b.Uncles))
+		for i := 0; i < len(b.Uncles); i++ {
+			c.Uncles[i] = *b.Uncles[i].Clone()
+		}
+	}
+
+	if b.Transactions != nil {
+		c.Transactions = make([]Transaction, len(b.Transactions))
+		for i := 0; i < len(b.Transactions); i++ {
+			c.Transactions[i] = *b.Transactions[i].Clone()
+		}
+	}
+
+	return c
+}
+
+// Clone deep copy uncle block view
+func (u *UncleBlockView) Clone() *UncleBlockView {
+	if u == nil {
+		return nil
+	}
+
+	return &UncleBlockView{
+		Header:    u.Header,
+		Proposals: cloneProposals(u.Proposals),
+	}
+}
+
+// Clone deep copy block view
+func (b *BlockView) Clone() *BlockView {
+	if b == nil {
+		return nil
+	}
+
+	c := &BlockView{
+		Header:    b.Header,
+		Proposals: cloneProposals(b.Proposals),
+		Extension: cloneBytesPtr(b.Extension),
+	}
+
+	if b.Uncles != nil {
+		c.Uncles = make([]UncleBlockView, len(b.Uncles))
+		for i := 0; i < len(b.Uncles); i++ {
+			c.Uncles[i] = *b.Uncles[i].Clone()
+		}
+	}
+
+	if b.Transactions != nil {
+		c.Transactions = make([]TransactionView, len(b.Transactions))
+		for i := 0; i < len(b.Transactions); i++ {
+			c.Transactions[i] = *b.Transactions[i].Clone()
+		}
+	}
+
+	return c
+}
+
+func cloneBytesPtr(b *Bytes) *Bytes {
+	if b == nil {
+		return nil
+	}
+
+	c := b.Clone()
+	return &c
+}
+
+func cloneBytesSlice(bs []Bytes) []Bytes {
+	if bs == nil {
+		return nil
+	}
+
+	c := make([]Bytes, len(bs))
+	for i := 0; i < len(bs); i++ {
+		c[i] = bs[i].Clone()
+	}
+
+	return c
+}
+
+func cloneProposals(ps []ProposalShortID) []ProposalShortID {
+	if ps == nil {
+		return nil
+	}
+
+	c := make([]ProposalShortID, len(ps))
+	copy(c, ps)
+
+	return c
+}
+
+func cloneUnknownFields(fs [][]byte) [][]byte {
+	if fs == nil {
+		return nil
+	}
+
+	c := make([][]byte, len(fs))
+	for i := 0; i < len(fs); i++ {
+		c[i] = Bytes(fs[i]).Clone()
+	}
+
+	return c
+}
@@ -0,0 +1,141 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestCloneTransaction(t *testing.T) {
+	transaction := `{
+	  "cell_deps": [],
+	  "header_deps": [],
+	  "inputs": [],
+	  "outputs": [
+		{
+		  "capacity": "0x1c6bf52634000",
+		  "lock": {
+			"args": "0x470dcdc5e44064909650113a274b3b36aecb6dc7",
+			"code_hash": "0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8",
+			"hash_type": "type"
+		  },
+		  "type": {
+			"args": "0x",
+			"code_hash": "0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8",
+			"hash_type": "data"
+		  }
+		}
+	  ],
+	  "outputs_data": ["0x1234"],
+	  "version": "0x0",
+	  "witnesses": ["0x5678"]
+	}`
+
+	var tx Transaction
+
+	err := json.Unmarshal([]byte(transaction), &tx)
+	if err != nil {
+		t.Errorf("fail to unmarshal test transaction json: %s\n", err)
+		return
+	}
+
+	expect, _ := json.Marshal(tx)
+
+	c := tx.Clone()
+	c.Outputs[0].Lock.Args[0] = 0xff
+	c.Outputs[0].Type.HashType = Type
+	c.OutputsData[0][0] = 0xff
+	c.Witnesses[0][0] = 0xff
+
+	got, _ := json.Marshal(tx)
+	if !bytes.Equal(expect, got) {
+		t.Errorf("mutate clone changes original, expect %s, got %s", expect, got)
+		return
+	}
+
+	// Empty slices should stay empty instead of becoming null
+	cloned, _ := json.Marshal(tx.Clone())
+	if !bytes.Equal(expect, cloned) {
+		t.Errorf("mismatch clone json, expect %s, got %s", expect, cloned)
+		return
+	}
+}
+
+func TestCloneBlockView(t *testing.T) {
+	ext := Bytes{0x01}
+	b := &BlockView{
+		Header: HeaderView{Header: Header{Number: 1}, Hash: Hash{0x01}},
+		Uncles: []UncleBlockView{{
+			Header:    HeaderView{Header: Header{Number: 0}, Hash: Hash{0x02}},
+			Proposals: []ProposalShortID{{0x01}},
+		}},
+		Transactions: []TransactionView{{
+			Transaction: Transaction{Witnesses: []Bytes{{0x01}}},
+			Hash:        Hash{0x03},
+		}},
+		Proposals: []ProposalShortID{{0x02}},
+		Extension: &ext,
+	}
+
+	expect, _ := json.Marshal(b)
+
+	c := b.Clone()
+	c.Header.Header.Number = 2
+	c.Uncles[0].Header.Hash[0] = 0xff
+	c.Uncles[0].Proposals[0][0] = 0xff
+	c.Transactions[0].Witnesses[0][0] = 0xff
+	c.Proposals[0][0] = 0xff
+	(*c.Extension)[0] = 0xff
+
+	got, _ := json.Marshal(b)
+	if !bytes.Equal(expect, got) {
+		t.Errorf("mutate clone changes original, expect %s, got %s", expect, got)
+		return
+	}
+
+	u := b.Uncles[0].Clone()
+	u.Proposals[0][0] = 0xff
+	if b.Uncles[0].Proposals[0][0] != 0x01 {
+		t.Errorf("mutate uncle clone changes original, got %v", b.Uncles[0])
+		return
+	}
+
+	h := b.Header.Clone()
+	h.Header.Nonce = NewUint128(1)
+	if b.Header.Header.Nonce != NewUint128(0) {
+		t.Errorf("mutate header view clone changes original, got %v", b.Header)
+		return
+	}
+
+	raw := b.Header.Header.Clone()
+	raw.Number = 3
+	if b.Header.Header.Number != 1 {
+		t.Errorf("mutate header clone changes original, got %v", b.Header.Header)
+		return
+	}
+
+	var nilView *BlockView
+	if nilView.Clone() != nil {
+		t.Errorf("mismatch result, expect nil clone of nil block view")
+		return
+	}
+}
+
+func TestCloneWitnessArgs(t *testing.T) {
+	lock := Bytes{0x01}
+	w := &WitnessArgs{Lock: &lock, UnknownFields: [][]byte{{0x02}}}
+
+	c := w.Clone()
+	(*c.Lock)[0] = 0xff
+	c.UnknownFields[0][0] = 0xff
+
+	if (*w.Lock)[0] != 0x01 || w.UnknownFields[0][0] != 0x02 {
+		t.Errorf("mutate clone changes original, got %v", w)
+		return
+	}
+
+	if c.InputType != nil || c.OutputType != nil {
+		t.Errorf("mismatch result, expect nil fields kept nil, got %v", c)
+		return
+	}
+}
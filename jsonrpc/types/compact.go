@@ -0,0 +1,91 @@
+package types
+
+// CompactToTarget convert compact target to uint256 target, also report
+// whether it overflows
+/*
+ * Compact target is a 32 bit floating number:
+ *
+ *     The highest byte is the exponent, base 256.
+ *     The lower 3 bytes are the mantissa.
+ *
+ *     target = mantissa * 256^(exponent - 3)
+ */
+func CompactToTarget(compact uint32) (Uint256, bool) {
+	exponent := uint(compact >> 24)
+	mantissa := NewUint256(uint64(compact & 0x00ffffff))
+
+	var target Uint256
+	if exponent <= 3 {
+		mantissa = mantissa.Rsh(8 * (3 - exponent))
+		target = mantissa
+	} else {
+		target = mantissa.Lsh(8 * (exponent - 3))
+	}
+
+	overflow := !mantissa.IsZero() && exponent > 32
+
+	return target, overflow
+}
+
+// TargetToCompact convert uint256 target to compact target
+func TargetToCompact(target Uint256) uint32 {
+	exponent := uint((target.BitLen() + 7) / 8)
+
+	var compact uint32
+	if exponent <= 3 {
+		compact = uint32(target[0] << (8 * (3 - exponent)))
+	} else {
+		compact = uint32(target.Rsh(8 * (exponent - 3))[0])
+	}
+
+	return compact | uint32(exponent<<24)
+}
+
+// TargetToDifficulty convert target to difficulty, 2^256 / target
+func TargetToDifficulty(target Uint256) Uint256 {
+	return inverseHashSpace(target)
+}
+
+// DifficultyToTarget convert difficulty to target, 2^256 / difficulty
+func DifficultyToTarget(difficulty Uint256) Uint256 {
+	return inverseHashSpace(difficulty)
+}
+
+// CompactToDifficulty convert compact target to difficulty, zero for
+// zero or overflow target
+func CompactToDifficulty(compact uint32) Uint256 {
+	target, overflow := CompactToTarget(compact)
+	if target.IsZero() || overflow {
+		return Uint256{}
+	}
+
+	return TargetToDifficulty(target)
+}
+
+// DifficultyToCompact convert difficulty to compact target
+func DifficultyToCompact(difficulty Uint256) uint32 {
+	return TargetToCompact(DifficultyToTarget(difficulty))
+}
+
+// inverseHashSpace 2^256 / n, saturated max uint256 for zero and one
+func inverseHashSpace(n Uint256) Uint256 {
+	one := NewUint256(1)
+	if n.Cmp(one) <= 0 {
+		return MaxUint256()
+	}
+
+	// 2^256 / n == (2^256 - 1) / n unless n divides 2^256, in which case
+	// the remainder is n - 1
+	q, r, _ := MaxUint256().DivMod(n)
+	if r.Cmp(mustSub(n, one)) == 0 {
+		q, _ = q.Add(one)
+	}
+
+	return q
+}
+
+// mustSub u - v for callers which already checked u >= v
+func mustSub(u, v Uint256) Uint256 {
+	r, _ := u.Sub(v)
+	return r
+}
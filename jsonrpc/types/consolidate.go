@@ -0,0 +1,108 @@
+package types
+
+import (
+	"fmt"
+)
+
+// DefaultMaxTxSize node default tx pool max_tx_size, in bytes
+const DefaultMaxTxSize = 512000
+
+// inputWitnessSize bytes added by one more input with empty witness, cell
+// input plus empty bytes and its offset in witnesses dynvec
+const inputWitnessSize = cellInputSize + uint32Size + uint32Size
+
+// BuildConsolidation build transactions merging plain cells of address,
+// each one spends as many cells as fit in maxTxSize into one cell of the
+// same address
+/*
+ * Cells are merged in collecting order. A trailing batch of a single cell
+ * has nothing to merge and is left alone.
+ */
+func (b *Builder) BuildConsolidation(addr Address, maxTxSize uint64, feeRate Uint64) ([]*Transaction, error) {
+	if b.Registry.Network != addr.Network {
+		return nil, fmt.Errorf("mismatch network, registry is %s, address is %s", b.Registry.Network, addr.Network)
+	}
+
+	cells, err := b.Collector.LiveCells(&addr.Script)
+	if err != nil {
+		return nil, err
+	}
+
+	plain := make([]LiveCell, 0, len(cells))
+	for i := 0; i < len(cells); i++ {
+		if isPlainCell(&cells[i]) {
+			plain = append(plain, cells[i])
+		}
+	}
+
+	txs := []*Transaction{}
+	for len(plain) > 1 {
+		s, err := b.consolidate(addr, plain, maxTxSize, feeRate)
+		if err != nil {
+			return nil, err
+		}
+
+		n := len(s.Cells)
+		if n < 2 {
+			return nil, fmt.Errorf("invalid max tx size %d, can not merge two cells", maxTxSize)
+		}
+
+		txs = append(txs, s.Tx)
+		plain = plain[n:]
+	}
+
+	return txs, nil
+}
+
+// consolidate merge leading cells into one output, as many as fit
+func (b *Builder) consolidate(addr Address, cells []LiveCell, maxTxSize uint64, feeRate Uint64) (*Skeleton, error) {
+	s := NewSkeleton()
+	s.AddInput(cells[0], 0)
+	s.AddOutput(CellOutput{Lock: *addr.Script.Clone()}, Bytes{})
+
+	err := b.resolveLocks(s)
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := s.Tx.SizeInBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 1; i < len(cells) && size+inputWitnessSize <= maxTxSize; i++ {
+		s.AddInput(cells[i], 0)
+		size += inputWitnessSize
+	}
+
+	actual, err := s.Tx.SizeInBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if actual != size {
+		return nil, fmt.Errorf("mismatch estimated size %d, got %d", size, actual)
+	}
+
+	inputs, err := s.InputsCapacity()
+	if err != nil {
+		return nil, err
+	}
+
+	fee, err := CalculateFee(size, feeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	occupied, err := s.Tx.Outputs[0].OccupiedCapacity(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if inputs < fee || inputs-fee < occupied {
+		return nil, fmt.Errorf("insufficient capacity, %s CKB of %d cells can not pay fee", formatCKB(inputs), len(s.Cells))
+	}
+
+	s.Tx.Outputs[0].Capacity = inputs - fee
+	return s, nil
+}
@@ -0,0 +1,60 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestBuildConsolidation(t *testing.T) {
+	addr := Address{Network: Testnet, Script: Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: make(Bytes, 20)}}
+
+	cells := make([]LiveCell, 0, 26)
+	for i := 0; i < 25; i++ {
+		cells = append(cells, testPlainCell(byte(i+1), addr.Script, 100))
+	}
+
+	withData := testPlainCell(0xff, addr.Script, 100)
+	withData.Data = Bytes{0x01}
+	cells = append(cells, withData)
+
+	b := NewBuilder(testCollector{addr.Script.Args.String(): cells}, testRegistry())
+
+	// Room for 10 inputs
+	s := NewSkeleton()
+	s.AddInput(cells[0], 0)
+	s.AddOutput(CellOutput{Lock: addr.Script}, Bytes{})
+	err := b.resolveLocks(s)
+	if err != nil {
+		t.Errorf("fail to resolve locks: %s\n", err)
+		return
+	}
+
+	base, _ := s.Tx.SizeInBlock()
+	maxTxSize := base + 9*inputWitnessSize
+
+	txs, err := b.BuildConsolidation(addr, maxTxSize, DefaultMinFeeRate)
+	if err != nil {
+		t.Errorf("fail to build consolidation: %s\n", err)
+		return
+	}
+
+	if len(txs) != 3 || len(txs[0].Inputs) != 10 || len(txs[1].Inputs) != 10 || len(txs[2].Inputs) != 5 {
+		t.Errorf("mismatch consolidation, got %d transactions", len(txs))
+		return
+	}
+
+	for _, tx := range txs {
+		size, _ := tx.SizeInBlock()
+		fee, _ := tx.Fee(DefaultMinFeeRate)
+
+		if size > maxTxSize || len(tx.Outputs) != 1 || tx.Outputs[0].Capacity != Uint64(len(tx.Inputs))*100*ShannonsPerCKB-fee {
+			t.Errorf("mismatch consolidation transaction, size %d, outputs %v", size, tx.Outputs)
+			return
+		}
+	}
+
+	_, err = b.BuildConsolidation(addr, base, DefaultMinFeeRate)
+	if err == nil {
+		t.Errorf("expect error on max tx size of one input")
+		return
+	}
+}
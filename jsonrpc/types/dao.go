@@ -0,0 +1,129 @@
+package types
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// DaoField decoded header dao field, four uint64 in little-endian
+type DaoField struct {
+	// C total issued capacity
+	C Uint64
+	// AR accumulated rate, 10^16 based
+	AR Uint64
+	// S secondary issuance not going to dao
+	S Uint64
+	// U occupied capacity
+	U Uint64
+}
+
+// ParseDaoField decode header dao field
+func ParseDaoField(dao Hash) DaoField {
+	return DaoField{
+		C:  Uint64(binary.LittleEndian.Uint64(dao[0:8])),
+		AR: Uint64(binary.LittleEndian.Uint64(dao[8:16])),
+		S:  Uint64(binary.LittleEndian.Uint64(dao[16:24])),
+		U:  Uint64(binary.LittleEndian.Uint64(dao[24:32])),
+	}
+}
+
+// CalculateDaoMaximumWithdraw maximum capacity withdrawable from dao
+// deposit cell, the local counterpart of calculate_dao_maximum_withdraw
+/*
+ * Only the free part earns interest:
+ *
+ *     (capacity - occupied) * withdrawing AR / deposit AR + occupied
+ */
+func CalculateDaoMaximumWithdraw(output *CellOutput, dataLen int, deposit *Header, withdrawing *Header) (Uint64, error) {
+	occupied, err := output.OccupiedCapacity(dataLen)
+	if err != nil {
+		return 0, err
+	}
+
+	if output.Capacity < occupied {
+		return 0, fmt.Errorf("invalid dao cell, capacity below occupied capacity")
+	}
+
+	depositAR := ParseDaoField(deposit.Dao).AR
+	withdrawingAR := ParseDaoField(withdrawing.Dao).AR
+	if depositAR == 0 {
+		return 0, fmt.Errorf("invalid deposit header, zero accumulated rate")
+	}
+
+	free := new(big.Int).SetUint64(uint64(output.Capacity - occupied))
+	free.Mul(free, new(big.Int).SetUint64(uint64(withdrawingAR)))
+	free.Quo(free, new(big.Int).SetUint64(uint64(depositAR)))
+	free.Add(free, new(big.Int).SetUint64(uint64(occupied)))
+
+	if !free.IsUint64() {
+		return 0, fmt.Errorf("dao maximum withdraw overflow")
+	}
+
+	return Uint64(free.Uint64()), nil
+}
+
+// DaoWithdrawingCalculationKind second param of
+// calculate_dao_maximum_withdraw, either withdrawing header hash or
+// withdrawing cell outpoint
+type DaoWithdrawingCalculationKind struct {
+	WithdrawingHeaderHash *Hash
+	WithdrawingOutPoint   *OutPoint
+}
+
+type daoWithdrawingCalculationKindJSON struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// MarshalJSON marshal calculation kind to tagged json
+func (k DaoWithdrawingCalculationKind) MarshalJSON() ([]byte, error) {
+	var tag string
+	var value interface{}
+
+	switch {
+	case k.WithdrawingHeaderHash != nil && k.WithdrawingOutPoint == nil:
+		tag, value = "withdrawing_header_hash", k.WithdrawingHeaderHash
+	case k.WithdrawingOutPoint != nil && k.WithdrawingHeaderHash == nil:
+		tag, value = "withdrawing_out_point", k.WithdrawingOutPoint
+	default:
+		return nil, fmt.Errorf("invalid dao withdrawing calculation kind, exactly one field should be set")
+	}
+
+	v, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(daoWithdrawingCalculationKindJSON{Type: tag, Value: v})
+}
+
+// UnmarshalJSON unmarshal calculation kind from tagged json, a bare
+// header hash accepted by older nodes is also accepted
+func (k *DaoWithdrawingCalculationKind) UnmarshalJSON(data []byte) error {
+	*k = DaoWithdrawingCalculationKind{}
+
+	var hash Hash
+	if json.Unmarshal(data, &hash) == nil {
+		k.WithdrawingHeaderHash = &hash
+		return nil
+	}
+
+	var tagged daoWithdrawingCalculationKindJSON
+	err := json.Unmarshal(data, &tagged)
+	if err != nil {
+		return err
+	}
+
+	switch tagged.Type {
+	case "withdrawing_header_hash":
+		k.WithdrawingHeaderHash = new(Hash)
+		return json.Unmarshal(tagged.Value, k.WithdrawingHeaderHash)
+	case "withdrawing_out_point":
+		k.WithdrawingOutPoint = new(OutPoint)
+		return json.Unmarshal(tagged.Value, k.WithdrawingOutPoint)
+	}
+
+	return fmt.Errorf("invalid dao withdrawing calculation kind %q", tagged.Type)
+}
@@ -0,0 +1,220 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// DaoLockPeriodEpochs epochs of one dao lock period, withdraw is allowed
+// at the end of periods counted from deposit
+const DaoLockPeriodEpochs = 180
+
+// DaoHeaderFetcher chain lookups of dao withdraw, usually backed by
+// get_transaction and get_header_by_number rpc
+type DaoHeaderFetcher interface {
+	// CellHeader header of the block cell was committed in
+	CellHeader(o OutPoint) (*HeaderView, error)
+	// HeaderByNumber canonical header with given number
+	HeaderByNumber(number Uint64) (*HeaderView, error)
+}
+
+// DaoTypeScript nervos dao type script, args are empty
+func DaoTypeScript() *Script {
+	return &Script{CodeHash: DaoCodeHash, HashType: Type, Args: Bytes{}}
+}
+
+// BuildDaoDeposit build transaction depositing amount shannons of address
+// into nervos dao, the deposit cell is locked by the same address
+/*
+ * Deposit cell data is 8 zero bytes, withdraw phase 1 replaces it with
+ * the deposit block number.
+ */
+func (b *Builder) BuildDaoDeposit(from Address, amount Uint64, feeRate Uint64) (*Transaction, error) {
+	dao := b.Registry.Find(DaoTypeScript())
+	if dao == nil {
+		return nil, fmt.Errorf("unknown dao script on %s", b.Registry.Network)
+	}
+
+	deposit := CellOutput{Capacity: amount, Lock: *from.Script.Clone(), Type: DaoTypeScript()}
+
+	occupied, err := deposit.OccupiedCapacity(uint64Size)
+	if err != nil {
+		return nil, err
+	}
+
+	if amount < occupied {
+		return nil, fmt.Errorf("invalid amount, less than %s CKB occupied by deposit cell", formatCKB(occupied))
+	}
+
+	s := NewSkeleton()
+	s.AddCellDep(dao.CellDep)
+	s.AddOutput(deposit, make(Bytes, uint64Size))
+
+	err = b.Balance(s, from, feeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Tx, nil
+}
+
+// BuildDaoPrepare build withdraw phase 1 transaction, turn deposit cell
+// into withdrawing cell, fee is paid by plain cells of the same lock
+/*
+ * Withdrawing cell keeps index, capacity, lock and type of deposit cell,
+ * data becomes deposit block number, and deposit block is the header dep.
+ */
+func (b *Builder) BuildDaoPrepare(deposit LiveCell, f DaoHeaderFetcher, feeRate Uint64) (*Transaction, error) {
+	err := checkDaoCell(&deposit)
+	if err != nil {
+		return nil, err
+	}
+
+	if binary.LittleEndian.Uint64(deposit.Data) != 0 {
+		return nil, fmt.Errorf("invalid deposit cell %s, already withdrawing", deposit.OutPoint)
+	}
+
+	dao := b.Registry.Find(DaoTypeScript())
+	if dao == nil {
+		return nil, fmt.Errorf("unknown dao script on %s", b.Registry.Network)
+	}
+
+	header, err := f.CellHeader(deposit.OutPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(Bytes, uint64Size)
+	binary.LittleEndian.PutUint64(data, uint64(header.Number))
+
+	s := NewSkeleton()
+	s.AddCellDep(dao.CellDep)
+	s.Tx.AppendHeaderDeps(header.Hash)
+	s.AddInput(deposit, 0)
+	s.AddOutput(*deposit.Output.Clone(), data)
+
+	err = b.Balance(s, Address{Network: b.Registry.Network, Script: deposit.Output.Lock}, feeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Tx, nil
+}
+
+// BuildDaoWithdraw build withdraw phase 2 transaction, spend withdrawing
+// cell with compensation into a plain cell of the same lock, fee is taken
+// from withdrawn capacity
+/*
+ * Header deps are withdrawing block then deposit block, witness input
+ * type is the deposit header dep index as uint64 in little-endian. Input
+ * since is the absolute epoch ending the lock period.
+ */
+func (b *Builder) BuildDaoWithdraw(withdrawing LiveCell, f DaoHeaderFetcher, feeRate Uint64) (*Transaction, error) {
+	err := checkDaoCell(&withdrawing)
+	if err != nil {
+		return nil, err
+	}
+
+	depositNumber := binary.LittleEndian.Uint64(withdrawing.Data)
+	if depositNumber == 0 {
+		return nil, fmt.Errorf("invalid withdrawing cell %s, still a deposit", withdrawing.OutPoint)
+	}
+
+	dao := b.Registry.Find(DaoTypeScript())
+	if dao == nil {
+		return nil, fmt.Errorf("unknown dao script on %s", b.Registry.Network)
+	}
+
+	withdrawingHeader, err := f.CellHeader(withdrawing.OutPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	depositHeader, err := f.HeaderByNumber(Uint64(depositNumber))
+	if err != nil {
+		return nil, err
+	}
+
+	if withdrawingHeader.Number <= depositHeader.Number {
+		return nil, fmt.Errorf("invalid withdrawing cell %s, committed before deposit", withdrawing.OutPoint)
+	}
+
+	capacity, err := CalculateDaoMaximumWithdraw(&withdrawing.Output, len(withdrawing.Data), &depositHeader.Header, &withdrawingHeader.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := DaoMinimalUnlockEpoch(NewEpochNumberWithFraction(depositHeader.Epoch), NewEpochNumberWithFraction(withdrawingHeader.Epoch))
+	since := Since{Metric: SinceEpoch, Value: uint64(unlock.Uint64())}
+
+	s := NewSkeleton()
+	s.AddCellDep(dao.CellDep)
+	s.Tx.AppendHeaderDeps(withdrawingHeader.Hash, depositHeader.Hash)
+	s.AddInput(withdrawing, since.Encode())
+	s.AddOutput(CellOutput{Lock: *withdrawing.Output.Lock.Clone()}, Bytes{})
+
+	index := make(Bytes, uint64Size)
+	binary.LittleEndian.PutUint64(index, 1)
+
+	err = s.Tx.SetWitnessArgsAt(0, &WitnessArgs{InputType: &index})
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.resolveLocks(s)
+	if err != nil {
+		return nil, err
+	}
+
+	fee, err := s.Tx.Fee(feeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	occupied, err := s.Tx.Outputs[0].OccupiedCapacity(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if capacity < fee || capacity-fee < occupied {
+		return nil, fmt.Errorf("insufficient capacity, %s CKB withdrawn can not pay fee", formatCKB(capacity))
+	}
+
+	s.Tx.Outputs[0].Capacity = capacity - fee
+	return s.Tx, nil
+}
+
+// DaoMinimalUnlockEpoch earliest epoch withdrawing cell can be spent, end
+// of the first lock period covering deposit to withdraw phase 1
+/*
+ * Deposited epochs are counted from deposit to phase 1, a started epoch
+ * counts as a whole one, then rounded up to lock periods. Unlock epoch
+ * keeps the fraction of deposit epoch.
+ */
+func DaoMinimalUnlockEpoch(deposit, withdrawing EpochNumberWithFraction) EpochNumberWithFraction {
+	epochs := withdrawing.Number - deposit.Number
+	if withdrawing.Index*deposit.Length > deposit.Index*withdrawing.Length {
+		epochs++
+	}
+
+	periods := (epochs + DaoLockPeriodEpochs - 1) / DaoLockPeriodEpochs
+
+	return EpochNumberWithFraction{
+		Number: deposit.Number + periods*DaoLockPeriodEpochs,
+		Index:  deposit.Index,
+		Length: deposit.Length,
+	}
+}
+
+// checkDaoCell check cell is a dao cell with 8 bytes data
+func checkDaoCell(c *LiveCell) error {
+	if SystemScriptName(c.Output.Type) != "dao" {
+		return fmt.Errorf("invalid dao cell %s, type script is not dao", c.OutPoint)
+	}
+
+	if len(c.Data) != uint64Size {
+		return fmt.Errorf("invalid dao cell %s, data should be 8 bytes", c.OutPoint)
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+)
+
+type testDaoFetcher struct {
+	cells   map[OutPoint]*HeaderView
+	numbers map[Uint64]*HeaderView
+}
+
+func (f *testDaoFetcher) CellHeader(o OutPoint) (*HeaderView, error) {
+	h, ok := f.cells[o]
+	if !ok {
+		return nil, fmt.Errorf("unknown cell %s", o)
+	}
+
+	return h, nil
+}
+
+func (f *testDaoFetcher) HeaderByNumber(number Uint64) (*HeaderView, error) {
+	h, ok := f.numbers[number]
+	if !ok {
+		return nil, fmt.Errorf("unknown header %d", number)
+	}
+
+	return h, nil
+}
+
+func testDaoRegistry() *ScriptRegistry {
+	r := testRegistry()
+	r.Scripts = append(r.Scripts, SystemScript{
+		Name:     "dao",
+		CodeHash: DaoCodeHash,
+		HashType: Type,
+		CellDep:  CellDep{OutPoint: OutPoint{TxHash: Hash{0xda}, Index: 2}, DepType: Code},
+	})
+
+	return r
+}
+
+func TestBuildDaoDeposit(t *testing.T) {
+	from := Address{Network: Testnet, Script: Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: make(Bytes, 20)}}
+
+	c := testCollector{
+		from.Script.Args.String(): {testPlainCell(1, from.Script, 1000)},
+	}
+
+	b := NewBuilder(c, testDaoRegistry())
+
+	tx, err := b.BuildDaoDeposit(from, 500*ShannonsPerCKB, DefaultMinFeeRate)
+	if err != nil {
+		t.Errorf("fail to build dao deposit: %s\n", err)
+		return
+	}
+
+	if len(tx.Outputs) != 2 || SystemScriptName(tx.Outputs[0].Type) != "dao" || tx.Outputs[0].Capacity != 500*ShannonsPerCKB {
+		t.Errorf("mismatch outputs, got %v", tx.Outputs)
+		return
+	}
+
+	if tx.OutputsData[0].String() != "0x0000000000000000" {
+		t.Errorf("mismatch result, expect %v, got %v", "0x0000000000000000", tx.OutputsData[0])
+		return
+	}
+
+	if len(tx.CellDeps) != 2 || tx.CellDeps[0].OutPoint.TxHash != (Hash{0xda}) {
+		t.Errorf("mismatch cell deps, got %v", tx.CellDeps)
+		return
+	}
+
+	// 102 CKB occupied by sighash all locked deposit cell
+	_, err = b.BuildDaoDeposit(from, 101*ShannonsPerCKB, DefaultMinFeeRate)
+	if err == nil {
+		t.Errorf("expect error on amount below occupied capacity")
+		return
+	}
+
+	_, err = NewBuilder(c, testRegistry()).BuildDaoDeposit(from, 500*ShannonsPerCKB, DefaultMinFeeRate)
+	if err == nil {
+		t.Errorf("expect error on registry without dao")
+		return
+	}
+}
+
+func TestBuildDaoWithdraw(t *testing.T) {
+	owner := Address{Network: Testnet, Script: Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: make(Bytes, 20)}}
+
+	deposit := LiveCell{
+		OutPoint: OutPoint{TxHash: Hash{0x10}},
+		Output:   CellOutput{Capacity: 1000 * ShannonsPerCKB, Lock: owner.Script, Type: DaoTypeScript()},
+		Data:     make(Bytes, 8),
+	}
+
+	depositHeader := &HeaderView{Header: Header{Number: 100, Epoch: EpochNumberWithFraction{Number: 5, Index: 100, Length: 1000}.Uint64()}, Hash: Hash{0xa1}}
+	binary.LittleEndian.PutUint64(depositHeader.Dao[8:16], 10000000000000000)
+
+	withdrawingHeader := &HeaderView{Header: Header{Number: 5000, Epoch: EpochNumberWithFraction{Number: 100, Index: 200, Length: 1000}.Uint64()}, Hash: Hash{0xa2}}
+	binary.LittleEndian.PutUint64(withdrawingHeader.Dao[8:16], 11000000000000000)
+
+	c := testCollector{
+		owner.Script.Args.String(): {testPlainCell(1, owner.Script, 100)},
+	}
+
+	f := &testDaoFetcher{
+		cells:   map[OutPoint]*HeaderView{deposit.OutPoint: depositHeader},
+		numbers: map[Uint64]*HeaderView{100: depositHeader},
+	}
+
+	b := NewBuilder(c, testDaoRegistry())
+
+	prepare, err := b.BuildDaoPrepare(deposit, f, DefaultMinFeeRate)
+	if err != nil {
+		t.Errorf("fail to build dao prepare: %s\n", err)
+		return
+	}
+
+	if len(prepare.Inputs) != 2 || prepare.Inputs[0].PreviousOutput != deposit.OutPoint {
+		t.Errorf("mismatch inputs, got %v", prepare.Inputs)
+		return
+	}
+
+	if !prepare.Outputs[0].Equal(&deposit.Output) || binary.LittleEndian.Uint64(prepare.OutputsData[0]) != 100 {
+		t.Errorf("mismatch withdrawing cell, got %v %v", prepare.Outputs[0], prepare.OutputsData[0])
+		return
+	}
+
+	if len(prepare.HeaderDeps) != 1 || prepare.HeaderDeps[0] != depositHeader.Hash {
+		t.Errorf("mismatch header deps, got %v", prepare.HeaderDeps)
+		return
+	}
+
+	withdrawing := LiveCell{
+		OutPoint: OutPoint{TxHash: Hash{0x20}},
+		Output:   prepare.Outputs[0],
+		Data:     prepare.OutputsData[0],
+	}
+	f.cells[withdrawing.OutPoint] = withdrawingHeader
+
+	_, err = b.BuildDaoPrepare(withdrawing, f, DefaultMinFeeRate)
+	if err == nil {
+		t.Errorf("expect error on preparing withdrawing cell")
+		return
+	}
+
+	tx, err := b.BuildDaoWithdraw(withdrawing, f, DefaultMinFeeRate)
+	if err != nil {
+		t.Errorf("fail to build dao withdraw: %s\n", err)
+		return
+	}
+
+	if len(tx.HeaderDeps) != 2 || tx.HeaderDeps[0] != withdrawingHeader.Hash || tx.HeaderDeps[1] != depositHeader.Hash {
+		t.Errorf("mismatch header deps, got %v", tx.HeaderDeps)
+		return
+	}
+
+	since, err := DecodeSince(tx.Inputs[0].Since)
+	if err != nil || since.Relative || since.Metric != SinceEpoch || NewEpochNumberWithFraction(Uint64(since.Value)).String() != "185(100/1000)" {
+		t.Errorf("mismatch since, got %v", since)
+		return
+	}
+
+	w, err := tx.WitnessArgsAt(0)
+	if err != nil {
+		t.Errorf("fail to decode witness: %s\n", err)
+		return
+	}
+
+	if w.Lock == nil || len(*w.Lock) != SignatureSize || w.InputType == nil || w.InputType.String() != "0x0100000000000000" {
+		t.Errorf("mismatch witness, got %v", w)
+		return
+	}
+
+	fee, _ := tx.Fee(DefaultMinFeeRate)
+	expect := Uint64(102*ShannonsPerCKB+9878*ShannonsPerCKB/10) - fee
+	if len(tx.Outputs) != 1 || tx.Outputs[0].Type != nil || tx.Outputs[0].Capacity != expect {
+		t.Errorf("mismatch result, expect %v, got %v", expect, tx.Outputs)
+		return
+	}
+
+	_, err = b.BuildDaoWithdraw(deposit, f, DefaultMinFeeRate)
+	if err == nil {
+		t.Errorf("expect error on withdrawing deposit cell")
+		return
+	}
+}
+
+func TestDaoMinimalUnlockEpoch(t *testing.T) {
+	deposit := EpochNumberWithFraction{Number: 5, Index: 100, Length: 1000}
+
+	for _, c := range []struct {
+		withdrawing EpochNumberWithFraction
+		expect      string
+	}{
+		{EpochNumberWithFraction{Number: 5, Index: 101, Length: 1000}, "185(100/1000)"},
+		{EpochNumberWithFraction{Number: 185, Index: 50, Length: 1000}, "185(100/1000)"},
+		{EpochNumberWithFraction{Number: 185, Index: 100, Length: 1000}, "185(100/1000)"},
+		{EpochNumberWithFraction{Number: 185, Index: 101, Length: 1000}, "365(100/1000)"},
+		{EpochNumberWithFraction{Number: 185, Index: 1, Length: 10}, "185(100/1000)"},
+		{EpochNumberWithFraction{Number: 185, Index: 2, Length: 10}, "365(100/1000)"},
+	} {
+		e := DaoMinimalUnlockEpoch(deposit, c.withdrawing)
+		if e.String() != c.expect {
+			t.Errorf("mismatch result, expect %v, got %v", c.expect, e)
+			return
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package types
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+)
+
+func TestCalculateDaoMaximumWithdraw(t *testing.T) {
+	lock := Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: make(Bytes, 20)}
+	dao := &Script{CodeHash: DaoCodeHash, HashType: Type, Args: Bytes{}}
+	output := &CellOutput{Capacity: 1000 * ShannonsPerCKB, Lock: lock, Type: dao}
+
+	// AR grows from 1.0 to 1.1
+	var depositDao, withdrawingDao Hash
+	binary.LittleEndian.PutUint64(depositDao[8:16], 10000000000000000)
+	binary.LittleEndian.PutUint64(withdrawingDao[8:16], 11000000000000000)
+
+	if ParseDaoField(depositDao).AR != 10000000000000000 || ParseDaoField(withdrawingDao).AR != 11000000000000000 {
+		t.Errorf("mismatch dao field, got %v %v", ParseDaoField(depositDao), ParseDaoField(withdrawingDao))
+		return
+	}
+
+	c, err := CalculateDaoMaximumWithdraw(output, 8, &Header{Dao: depositDao}, &Header{Dao: withdrawingDao})
+	if err != nil {
+		t.Errorf("fail to calculate dao maximum withdraw: %s\n", err)
+		return
+	}
+
+	// Occupied 102 CKB earns nothing, free 898 CKB earns 10%
+	expect := Uint64(102*ShannonsPerCKB + 9878*ShannonsPerCKB/10)
+	if c != expect {
+		t.Errorf("mismatch result, expect %v, got %v", expect, c)
+		return
+	}
+}
+
+func TestDaoWithdrawingCalculationKindJSON(t *testing.T) {
+	raw := `{"type":"withdrawing_out_point","value":{"tx_hash":"0xa4037a893eb48e18ed4ef61034ce26eba9c585f15c9cee102ae58505565eccc3","index":"0x0"}}`
+
+	var k DaoWithdrawingCalculationKind
+	strictRoundTrip(t, raw, &k)
+
+	if k.WithdrawingOutPoint == nil || k.WithdrawingHeaderHash != nil {
+		t.Errorf("mismatch calculation kind, got %+v", k)
+		return
+	}
+
+	err := json.Unmarshal([]byte(`"0xa5f5c85987a15de25661e5a214f2c1449cd803f071acc7999820f25246471f40"`), &k)
+	if err != nil || k.WithdrawingHeaderHash == nil || k.WithdrawingOutPoint != nil {
+		t.Errorf("mismatch bare header hash kind, got %+v %v", k, err)
+		return
+	}
+
+	_, err = json.Marshal(DaoWithdrawingCalculationKind{})
+	if err == nil {
+		t.Errorf("expect error on empty calculation kind")
+		return
+	}
+}
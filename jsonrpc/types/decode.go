@@ -0,0 +1,165 @@
+package types
+
+import (
+	"fmt"
+)
+
+// DecodeOptions molecule decoding strictness, selectable per call, the
+// zero value is what Deserialize does: unknown trailing table fields and
+// union ids are kept, padding and unknown script hash types are rejected
+/*
+ * Offsets of tables and dynvecs must always be canonical, the first
+ * offset right after the header and the rest in order, no option relaxes
+ * that.
+ */
+type DecodeOptions struct {
+	// RejectUnknownFields reject tables with trailing fields unknown to
+	// this version, like consensus verification does
+	RejectUnknownFields bool
+	// AllowPadding ignore bytes after the declared size of tables and
+	// vectors instead of failing
+	AllowPadding bool
+	// AllowUnknownHashTypes keep unknown script hash type bytes as hex
+	// string like "0x03" instead of failing, serialized back to the same
+	// byte so script hash is unchanged
+	AllowUnknownHashTypes bool
+	// RejectUnknownUnionIDs reject unions with item ids unknown to this
+	// version, like consensus verification does
+	RejectUnknownUnionIDs bool
+}
+
+// Decode option presets
+var (
+	// StrictDecodeOptions for consensus critical verifiers
+	StrictDecodeOptions = DecodeOptions{RejectUnknownFields: true, RejectUnknownUnionIDs: true}
+	// PermissiveDecodeOptions for explorers and indexers, which prefer to
+	// show what they can
+	PermissiveDecodeOptions = DecodeOptions{AllowPadding: true, AllowUnknownHashTypes: true}
+)
+
+// DeserializeWith deserialize molecule data into v with options, like
+// Deserialize transaction only covers RawTransaction, types without
+// tables or vectors ignore options
+func DeserializeWith(data []byte, v MolDeserializer, opts DecodeOptions) error {
+	d := decoder{opts: opts}
+
+	switch v := v.(type) {
+	case *Bytes:
+		return v.deserialize(data, d)
+	case *ScriptHashType:
+		return v.deserialize(data, d)
+	case *Script:
+		return v.deserialize(data, d)
+	case *CellOutput:
+		return v.deserialize(data, d)
+	case *Transaction:
+		return v.deserialize(data, d)
+	case *UncleBlock:
+		return v.deserialize(data, d)
+	case *Block:
+		return v.deserialize(data, d)
+	case *WitnessArgs:
+		return v.deserialize(data, d)
+	case *CellbaseWitness:
+		return v.deserialize(data, d)
+	default:
+		return v.Deserialize(data)
+	}
+}
+
+// DeserializeUnionWith deserialize union into item id and inner item with
+// options, knownIDs are the item ids of union in this version
+func DeserializeUnionWith(data []byte, knownIDs []uint32, opts DecodeOptions) (uint32, []byte, error) {
+	return decoder{opts: opts}.union(data, knownIDs)
+}
+
+// UnpackWith deserialize transaction with witnesses with options
+func (t *Transaction) UnpackWith(data []byte, opts DecodeOptions) error {
+	return t.unpack(data, decoder{opts: opts})
+}
+
+// decoder state threaded through decoding, zero value allocates on heap
+// with default options
+type decoder struct {
+	arena *Arena
+	opts  DecodeOptions
+}
+
+// table deserialize table with at least field count fields, trailing
+// fields are returned unless options reject them
+func (d decoder) table(data []byte, fieldCount int) ([][]byte, [][]byte, error) {
+	fields, extra, err := DeserializeTableCompatible(d.trimOffsets(data), fieldCount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if d.opts.RejectUnknownFields && len(extra) != 0 {
+		return nil, nil, fmt.Errorf("invalid table, should have %d fields, got %d", fieldCount, fieldCount+len(extra))
+	}
+
+	return fields, extra, nil
+}
+
+// dynvec deserialize dynvec
+func (d decoder) dynvec(data []byte) ([][]byte, error) {
+	return DeserializeDynVec(d.trimOffsets(data))
+}
+
+// fixvec deserialize fixvec
+func (d decoder) fixvec(data []byte, itemSize int) ([][]byte, error) {
+	return DeserializeFixVec(d.trimFixVec(data, itemSize), itemSize)
+}
+
+// union deserialize union, unknown item id is returned unless options
+// reject it
+func (d decoder) union(data []byte, knownIDs []uint32) (uint32, []byte, error) {
+	id, item, err := DeserializeUnion(data)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if !d.opts.RejectUnknownUnionIDs {
+		return id, item, nil
+	}
+
+	for _, known := range knownIDs {
+		if id == known {
+			return id, item, nil
+		}
+	}
+
+	return 0, nil, fmt.Errorf("invalid union, unknown item id %d", id)
+}
+
+// trimOffsets drop padding after the full size in table and dynvec header
+func (d decoder) trimOffsets(data []byte) []byte {
+	if !d.opts.AllowPadding {
+		return data
+	}
+
+	size, err := deserializeUint32(data)
+	if err != nil || uint64(size) >= uint64(len(data)) {
+		return data
+	}
+
+	return data[:size]
+}
+
+// trimFixVec drop padding after the items in fixvec
+func (d decoder) trimFixVec(data []byte, itemSize int) []byte {
+	if !d.opts.AllowPadding {
+		return data
+	}
+
+	n, err := deserializeUint32(data)
+	if err != nil {
+		return data
+	}
+
+	size := uint64(u32Size) + uint64(n)*uint64(itemSize)
+	if size >= uint64(len(data)) {
+		return data
+	}
+
+	return data[:size]
+}
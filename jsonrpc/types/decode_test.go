@@ -0,0 +1,126 @@
+package types
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDeserializeWith(t *testing.T) {
+	lock := Script{CodeHash: Hash{1}, HashType: Type, Args: Bytes{2}}
+	known, err := lock.Serialize()
+	if err != nil {
+		t.Errorf("fail to serialize: %s\n", err)
+		return
+	}
+
+	fields, err := DeserializeTable(known, 3)
+	if err != nil {
+		t.Errorf("fail to deserialize table: %s\n", err)
+		return
+	}
+
+	var s Script
+
+	// Unknown fields
+	extended := SerializeTable(append(fields, []byte{0xab}))
+	err = DeserializeWith(extended, &s, DecodeOptions{})
+	if err != nil || len(s.UnknownFields) != 1 {
+		t.Errorf("mismatch result, expect 1 unknown field, got %v %v", s.UnknownFields, err)
+		return
+	}
+
+	err = DeserializeWith(extended, &s, StrictDecodeOptions)
+	if err == nil {
+		t.Errorf("strict options should reject unknown fields")
+		return
+	}
+
+	// Padding
+	padded := append(append([]byte{}, known...), 0, 0)
+	err = DeserializeWith(padded, &s, DecodeOptions{})
+	if err == nil {
+		t.Errorf("default options should reject padding")
+		return
+	}
+
+	err = DeserializeWith(padded, &s, PermissiveDecodeOptions)
+	if err != nil || !s.Equal(&lock) {
+		t.Errorf("mismatch result, expect %v, got %v %v", lock, s, err)
+		return
+	}
+
+	// Unknown hash type
+	unknown := SerializeTable([][]byte{fields[0], {3}, fields[2]})
+	err = DeserializeWith(unknown, &s, DecodeOptions{})
+	if err == nil {
+		t.Errorf("default options should reject unknown hash type")
+		return
+	}
+
+	err = DeserializeWith(unknown, &s, PermissiveDecodeOptions)
+	if err != nil || s.HashType != "0x03" {
+		t.Errorf("mismatch result, expect hash type 0x03, got %v %v", s.HashType, err)
+		return
+	}
+
+	// Unknown hash type serializes back to the same bytes
+	b, err := s.Serialize()
+	if err != nil || !bytes.Equal(b, unknown) {
+		t.Errorf("mismatch result, expect %x, got %x %v", unknown, b, err)
+		return
+	}
+}
+
+func TestDeserializeUnionWith(t *testing.T) {
+	known := []uint32{0, 1}
+	data := SerializeUnion(2, []byte{0xab})
+
+	id, item, err := DeserializeUnionWith(data, known, DecodeOptions{})
+	if err != nil || id != 2 || !bytes.Equal(item, []byte{0xab}) {
+		t.Errorf("mismatch result, expect item 2, got %d %x %v", id, item, err)
+		return
+	}
+
+	_, _, err = DeserializeUnionWith(data, known, StrictDecodeOptions)
+	if err == nil {
+		t.Errorf("strict options should reject unknown union id")
+		return
+	}
+
+	id, _, err = DeserializeUnionWith(SerializeUnion(1, []byte{}), known, StrictDecodeOptions)
+	if err != nil || id != 1 {
+		t.Errorf("mismatch result, expect item 1, got %d %v", id, err)
+		return
+	}
+}
+
+func TestDeserializeWithBlockExtension(t *testing.T) {
+	e := Bytes{1}
+	b := Block{Extension: &e}
+
+	raw, err := b.Serialize()
+	if err != nil {
+		t.Errorf("fail to serialize: %s\n", err)
+		return
+	}
+
+	var got Block
+	err = DeserializeWith(raw, &got, StrictDecodeOptions)
+	if err != nil || got.Extension == nil {
+		t.Errorf("strict options should accept block extension: %v", err)
+		return
+	}
+
+	b.UnknownFields = [][]byte{{2}}
+	raw, err = b.Serialize()
+	if err != nil {
+		t.Errorf("fail to serialize: %s\n", err)
+		return
+	}
+
+	err = DeserializeWith(raw, &got, StrictDecodeOptions)
+	if err == nil {
+		t.Errorf("strict options should reject unknown block fields")
+		return
+	}
+}
@@ -0,0 +1,77 @@
+package types
+
+// Default constructors return values serializing to moleculec DEFAULT
+// constants: fixed fields all zero, vectors and options empty. Go zero
+// values are not always valid, empty hash type and dep type do not
+// serialize, and nil slices marshal to json null, which node rejects.
+
+// DefaultScript default Script, zero code hash, data hash type, empty args
+func DefaultScript() Script {
+	return Script{HashType: Data, Args: Bytes{}}
+}
+
+// DefaultOutPoint default OutPoint, zero tx hash and index
+func DefaultOutPoint() OutPoint {
+	return OutPoint{}
+}
+
+// NullOutPoint cellbase input outpoint, zero tx hash and index 0xffffffff,
+// unlike molecule default
+func NullOutPoint() OutPoint {
+	return OutPoint{Index: cellbaseInputIndex}
+}
+
+// DefaultCellInput default CellInput, zero since and default outpoint
+func DefaultCellInput() CellInput {
+	return CellInput{PreviousOutput: DefaultOutPoint()}
+}
+
+// DefaultCellOutput default CellOutput, zero capacity, default lock, no
+// type
+func DefaultCellOutput() CellOutput {
+	return CellOutput{Lock: DefaultScript()}
+}
+
+// DefaultCellDep default CellDep, default outpoint, code dep type
+func DefaultCellDep() CellDep {
+	return CellDep{OutPoint: DefaultOutPoint(), DepType: Code}
+}
+
+// DefaultWitnessArgs default WitnessArgs, every field none
+func DefaultWitnessArgs() WitnessArgs {
+	return WitnessArgs{}
+}
+
+// DefaultTransaction default Transaction, version zero and every vector
+// empty
+func DefaultTransaction() Transaction {
+	return Transaction{
+		CellDeps:    []CellDep{},
+		HeaderDeps:  []Hash{},
+		Inputs:      []CellInput{},
+		Outputs:     []CellOutput{},
+		OutputsData: []Bytes{},
+		Witnesses:   []Bytes{},
+	}
+}
+
+// DefaultHeader default Header, every field zero
+func DefaultHeader() Header {
+	return Header{}
+}
+
+// DefaultUncleBlock default UncleBlock, default header, no proposal
+func DefaultUncleBlock() UncleBlock {
+	return UncleBlock{Header: DefaultHeader(), Proposals: []ProposalShortID{}}
+}
+
+// DefaultBlock default Block, default header, no uncle, transaction or
+// proposal, no extension
+func DefaultBlock() Block {
+	return Block{
+		Header:       DefaultHeader(),
+		Uncles:       []UncleBlock{},
+		Transactions: []Transaction{},
+		Proposals:    []ProposalShortID{},
+	}
+}
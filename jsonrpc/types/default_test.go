@@ -0,0 +1,70 @@
+package types
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestDefault(t *testing.T) {
+	script := DefaultScript()
+	output := DefaultCellOutput()
+	cellDep := DefaultCellDep()
+	input := DefaultCellInput()
+	outPoint := DefaultOutPoint()
+	witness := DefaultWitnessArgs()
+	tx := DefaultTransaction()
+	header := DefaultHeader()
+	uncle := DefaultUncleBlock()
+	block := DefaultBlock()
+
+	// moleculec DEFAULT constants
+	defaultScript := "35000000" + "10000000" + "30000000" + "31000000" + strings.Repeat("00", 33) + "00000000"
+	for _, c := range []struct {
+		name   string
+		v      MolSerializer
+		expect string
+	}{
+		{"Script", &script, defaultScript},
+		{"OutPoint", &outPoint, strings.Repeat("00", 36)},
+		{"CellInput", &input, strings.Repeat("00", 44)},
+		{"CellOutput", &output, "4d000000" + "10000000" + "18000000" + "4d000000" + strings.Repeat("00", 8) + defaultScript},
+		{"CellDep", &cellDep, strings.Repeat("00", 37)},
+		{"WitnessArgs", &witness, "10000000" + "10000000" + "10000000" + "10000000"},
+		{"RawTransaction", &tx, "34000000" + "1c000000" + "20000000" + "24000000" + "28000000" + "2c000000" + "30000000" +
+			"00000000" + "00000000" + "00000000" + "00000000" + "04000000" + "04000000"},
+		{"Header", &header, strings.Repeat("00", 208)},
+		{"UncleBlock", &uncle, "e0000000" + "0c000000" + "dc000000" + strings.Repeat("00", 208) + "00000000"},
+		{"Block", &block, "f0000000" + "14000000" + "e4000000" + "e8000000" + "ec000000" + strings.Repeat("00", 208) +
+			"04000000" + "04000000" + "00000000"},
+	} {
+		b, err := c.v.Serialize()
+		if err != nil {
+			t.Errorf("fail to serialize default %s: %s\n", c.name, err)
+			return
+		}
+
+		if hex.EncodeToString(b) != c.expect {
+			t.Errorf("mismatch default %s, expect %v, got %v", c.name, c.expect, hex.EncodeToString(b))
+			return
+		}
+	}
+
+	b, _ := hex.DecodeString(defaultScript)
+	var s Script
+	err := s.Deserialize(b)
+	if err != nil {
+		t.Errorf("fail to deserialize default script: %s\n", err)
+		return
+	}
+
+	if !s.Equal(&script) {
+		t.Errorf("mismatch result, expect %v, got %v", script, s)
+		return
+	}
+
+	if DefaultOutPoint() == NullOutPoint() || NullOutPoint().Index != 0xffffffff {
+		t.Errorf("mismatch null outpoint, got %v", NullOutPoint())
+		return
+	}
+}
@@ -0,0 +1,163 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// MolDeserializer molecule deserialize interface
+type MolDeserializer interface {
+	Deserialize(data []byte) error
+}
+
+// deserializeUint32 deserialize uint32
+func deserializeUint32(b []byte) (uint32, error) {
+	if len(b) < 4 {
+		return 0, fmt.Errorf("invalid uint32, should be 4 bytes")
+	}
+
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+// DeserializeStruct deserialize struct into fields with given sizes
+func DeserializeStruct(data []byte, sizes []int) ([][]byte, error) {
+	total := 0
+	for i := 0; i < len(sizes); i++ {
+		total += sizes[i]
+	}
+
+	if len(data) != total {
+		return nil, fmt.Errorf("invalid struct, should be %d bytes, got %d", total, len(data))
+	}
+
+	fields := make([][]byte, len(sizes))
+	start := 0
+	for i := 0; i < len(sizes); i++ {
+		fields[i] = data[start : start+sizes[i]]
+		start += sizes[i]
+	}
+
+	return fields, nil
+}
+
+// DeserializeFixVec deserialize fixvec into items with given size
+func DeserializeFixVec(data []byte, itemSize int) ([][]byte, error) {
+	n, err := deserializeUint32(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if uint64(len(data)) != uint64(u32Size)+uint64(n)*uint64(itemSize) {
+		return nil, fmt.Errorf("invalid fixvec, %d items of %d bytes mismatch %d bytes", n, itemSize, len(data))
+	}
+
+	items := make([][]byte, n)
+	for i := 0; i < int(n); i++ {
+		start := int(u32Size) + i*itemSize
+		items[i] = data[start : start+itemSize]
+	}
+
+	return items, nil
+}
+
+// DeserializeDynVec deserialize dynvec into items
+func DeserializeDynVec(data []byte) ([][]byte, error) {
+	return deserializeOffsets(data, "dynvec")
+}
+
+// DeserializeTable deserialize table into fields, field count must match
+func DeserializeTable(data []byte, fieldCount int) ([][]byte, error) {
+	fields, err := deserializeOffsets(data, "table")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) != fieldCount {
+		return nil, fmt.Errorf("invalid table, should have %d fields, got %d", fieldCount, len(fields))
+	}
+
+	return fields, nil
+}
+
+// DeserializeTableCompatible deserialize table with at least field count
+// fields, trailing fields appended by newer versions are returned as extra
+func DeserializeTableCompatible(data []byte, fieldCount int) ([][]byte, [][]byte, error) {
+	fields, err := deserializeOffsets(data, "table")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(fields) < fieldCount {
+		return nil, nil, fmt.Errorf("invalid table, should have at least %d fields, got %d", fieldCount, len(fields))
+	}
+
+	return fields[:fieldCount], fields[fieldCount:], nil
+}
+
+// DeserializeOption deserialize option, empty bytes is none
+func DeserializeOption(data []byte, o MolDeserializer) (bool, error) {
+	if len(data) == 0 {
+		return false, nil
+	}
+
+	return true, o.Deserialize(data)
+}
+
+// deserializeOffsets deserialize dynvec and table, they share the same layout
+/*
+ *     Full size in bytes as a 32 bit unsigned integer in little-endian.
+ *     Offsets of items as 32 bit unsigned integer in little-endian.
+ *     Items.
+ */
+func deserializeOffsets(data []byte, kind string) ([][]byte, error) {
+	size, err := deserializeUint32(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s, %s", kind, err)
+	}
+
+	if uint64(size) != uint64(len(data)) {
+		return nil, fmt.Errorf("invalid %s, header size %d mismatch %d bytes", kind, size, len(data))
+	}
+
+	if size == u32Size {
+		return [][]byte{}, nil
+	}
+
+	first, err := deserializeUint32(data[u32Size:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s, %s", kind, err)
+	}
+
+	if first%u32Size != 0 || first < u32Size*2 || first > size {
+		return nil, fmt.Errorf("invalid %s, bad first offset %d", kind, first)
+	}
+
+	count := int(first/u32Size) - 1
+
+	offsets := make([]uint32, count+1)
+	for i := 0; i < count; i++ {
+		offsets[i] = binary.LittleEndian.Uint32(data[int(u32Size)*(i+1):])
+	}
+	offsets[count] = size
+
+	items := make([][]byte, count)
+	for i := 0; i < count; i++ {
+		if offsets[i] > offsets[i+1] {
+			return nil, fmt.Errorf("invalid %s, offsets are not in order", kind)
+		}
+
+		items[i] = data[offsets[i]:offsets[i+1]]
+	}
+
+	return items, nil
+}
+
+// DeserializeUnion deserialize union into item id and inner item
+func DeserializeUnion(data []byte) (uint32, []byte, error) {
+	id, err := deserializeUint32(data)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid union, %s", err)
+	}
+
+	return id, data[u32Size:], nil
+}
@@ -0,0 +1,473 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Molecule fixed sizes
+const (
+	hashSize            = 32
+	uint32Size          = 4
+	uint64Size          = 8
+	uint128Size         = 16
+	proposalShortIDSize = 10
+	byteSize            = 1
+	outPointSize        = hashSize + uint32Size
+	cellInputSize       = uint64Size + outPointSize
+	cellDepSize         = outPointSize + byteSize
+)
+
+// Deserialize hash
+func (h *Hash) Deserialize(data []byte) error {
+	if len(data) != hashSize {
+		return fmt.Errorf("invalid hash, should be 32 bytes")
+	}
+
+	copy(h[:], data)
+	return nil
+}
+
+// Deserialize script hash type
+func (t *ScriptHashType) Deserialize(data []byte) error {
+	return t.deserialize(data, decoder{})
+}
+
+func (t *ScriptHashType) deserialize(data []byte, d decoder) error {
+	if len(data) != byteSize {
+		return fmt.Errorf("invalid script hash type, should be 1 byte")
+	}
+
+	switch data[0] {
+	case 0:
+		*t = Data
+	case 1:
+		*t = Type
+	case 2:
+		*t = Data1
+	case 4:
+		*t = Data2
+	default:
+		if !d.opts.AllowUnknownHashTypes {
+			return fmt.Errorf("invalid script hash type")
+		}
+
+		*t = ScriptHashType(fmt.Sprintf("0x%02x", data[0]))
+	}
+
+	return nil
+}
+
+// Deserialize dep type
+func (t *DepType) Deserialize(data []byte) error {
+	if len(data) != byteSize {
+		return fmt.Errorf("invalid dep type, should be 1 byte")
+	}
+
+	switch data[0] {
+	case 0:
+		*t = Code
+	case 1:
+		*t = DepGroup
+	default:
+		return fmt.Errorf("invalid dep group")
+	}
+
+	return nil
+}
+
+// Deserialize bytes
+func (b *Bytes) Deserialize(data []byte) error {
+	return b.deserialize(data, decoder{})
+}
+
+func (b *Bytes) deserialize(data []byte, d decoder) error {
+	data = d.trimFixVec(data, byteSize)
+
+	n, err := deserializeUint32(data)
+	if err != nil {
+		return err
+	}
+
+	if uint64(len(data)) != uint64(u32Size)+uint64(n) {
+		return fmt.Errorf("invalid fixvec, %d items of %d bytes mismatch %d bytes", n, byteSize, len(data))
+	}
+
+	bb := Bytes(d.arena.allocBytes(int(n)))
+	copy(bb, data[u32Size:])
+
+	*b = bb
+	return nil
+}
+
+// Deserialize uint32
+func (u *Uint32) Deserialize(data []byte) error {
+	if len(data) != uint32Size {
+		return fmt.Errorf("invalid uint32, should be 4 bytes")
+	}
+
+	*u = Uint32(binary.LittleEndian.Uint32(data))
+	return nil
+}
+
+// Deserialize uint64
+func (u *Uint64) Deserialize(data []byte) error {
+	if len(data) != uint64Size {
+		return fmt.Errorf("invalid uint64, should be 8 bytes")
+	}
+
+	*u = Uint64(binary.LittleEndian.Uint64(data))
+	return nil
+}
+
+// Deserialize script
+func (s *Script) Deserialize(data []byte) error {
+	return s.deserialize(data, decoder{})
+}
+
+func (s *Script) deserialize(data []byte, d decoder) error {
+	fields, extra, err := d.table(data, 3)
+	if err != nil {
+		return err
+	}
+	s.UnknownFields = unknownFields(extra, d)
+
+	err = s.CodeHash.Deserialize(fields[0])
+	if err != nil {
+		return err
+	}
+
+	err = s.HashType.deserialize(fields[1], d)
+	if err != nil {
+		return err
+	}
+
+	return s.Args.deserialize(fields[2], d)
+}
+
+// Deserialize outpoint
+func (o *OutPoint) Deserialize(data []byte) error {
+	fields, err := DeserializeStruct(data, []int{hashSize, uint32Size})
+	if err != nil {
+		return err
+	}
+
+	err = o.TxHash.Deserialize(fields[0])
+	if err != nil {
+		return err
+	}
+
+	return o.Index.Deserialize(fields[1])
+}
+
+// Deserialize cell input
+func (i *CellInput) Deserialize(data []byte) error {
+	fields, err := DeserializeStruct(data, []int{uint64Size, outPointSize})
+	if err != nil {
+		return err
+	}
+
+	err = i.Since.Deserialize(fields[0])
+	if err != nil {
+		return err
+	}
+
+	return i.PreviousOutput.Deserialize(fields[1])
+}
+
+// Deserialize cell output
+func (o *CellOutput) Deserialize(data []byte) error {
+	return o.deserialize(data, decoder{})
+}
+
+func (o *CellOutput) deserialize(data []byte, d decoder) error {
+	fields, extra, err := d.table(data, 3)
+	if err != nil {
+		return err
+	}
+	o.UnknownFields = unknownFields(extra, d)
+
+	err = o.Capacity.Deserialize(fields[0])
+	if err != nil {
+		return err
+	}
+
+	err = o.Lock.deserialize(fields[1], d)
+	if err != nil {
+		return err
+	}
+
+	o.Type = nil
+	if len(fields[2]) != 0 {
+		o.Type = d.arena.allocScript()
+		return o.Type.deserialize(fields[2], d)
+	}
+
+	return nil
+}
+
+// Deserialize cell dep
+func (d *CellDep) Deserialize(data []byte) error {
+	fields, err := DeserializeStruct(data, []int{outPointSize, byteSize})
+	if err != nil {
+		return err
+	}
+
+	err = d.OutPoint.Deserialize(fields[0])
+	if err != nil {
+		return err
+	}
+
+	return d.DepType.Deserialize(fields[1])
+}
+
+// Deserialize transaction, the inverse of Serialize, so witnesses are
+// left untouched
+func (t *Transaction) Deserialize(data []byte) error {
+	return t.deserialize(data, decoder{})
+}
+
+func (t *Transaction) deserialize(data []byte, d decoder) error {
+	fields, extra, err := d.table(data, 6)
+	if err != nil {
+		return err
+	}
+	t.UnknownFields = unknownFields(extra, d)
+
+	err = t.Version.Deserialize(fields[0])
+	if err != nil {
+		return err
+	}
+
+	cds, err := d.fixvec(fields[1], cellDepSize)
+	if err != nil {
+		return err
+	}
+	t.CellDeps = d.arena.allocCellDeps(len(cds))
+	for i := 0; i < len(cds); i++ {
+		err = t.CellDeps[i].Deserialize(cds[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	t.HeaderDeps, err = deserializeByte32Vec(fields[2], d)
+	if err != nil {
+		return err
+	}
+
+	ips, err := d.fixvec(fields[3], cellInputSize)
+	if err != nil {
+		return err
+	}
+	t.Inputs = d.arena.allocCellInputs(len(ips))
+	for i := 0; i < len(ips); i++ {
+		err = t.Inputs[i].Deserialize(ips[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	ops, err := d.dynvec(fields[4])
+	if err != nil {
+		return err
+	}
+	t.Outputs = d.arena.allocCellOutputs(len(ops))
+	for i := 0; i < len(ops); i++ {
+		err = t.Outputs[i].deserialize(ops[i], d)
+		if err != nil {
+			return err
+		}
+	}
+
+	t.OutputsData, err = deserializeBytesVec(fields[5], d)
+	return err
+}
+
+// unknownFields copy trailing table fields out of decoded data, nil if
+// there is none
+func unknownFields(extra [][]byte, d decoder) [][]byte {
+	if len(extra) == 0 {
+		return nil
+	}
+
+	fs := make([][]byte, len(extra))
+	for i := 0; i < len(extra); i++ {
+		fs[i] = d.arena.allocBytes(len(extra[i]))
+		copy(fs[i], extra[i])
+	}
+
+	return fs
+}
+
+// deserializeBytesVec deserialize dynvec of bytes
+func deserializeBytesVec(data []byte, d decoder) ([]Bytes, error) {
+	items, err := d.dynvec(data)
+	if err != nil {
+		return nil, err
+	}
+
+	bs := d.arena.allocBytesVec(len(items))
+	for i := 0; i < len(items); i++ {
+		err = bs[i].deserialize(items[i], d)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bs, nil
+}
+
+// Deserialize header, molecule Header struct with RawHeader and nonce
+func (h *Header) Deserialize(data []byte) error {
+	sizes := []int{
+		uint32Size, uint32Size, uint64Size, uint64Size, uint64Size,
+		hashSize, hashSize, hashSize, hashSize, hashSize,
+		uint128Size,
+	}
+
+	fields, err := DeserializeStruct(data, sizes)
+	if err != nil {
+		return err
+	}
+
+	for i, f := range []MolDeserializer{
+		&h.Version,
+		&h.CompactTarget,
+		&h.Timestamp,
+		&h.Number,
+		&h.Epoch,
+		&h.ParentHash,
+		&h.TransactionsRoot,
+		&h.ProposalsHash,
+		&h.ExtraHash,
+		&h.Dao,
+		&h.Nonce,
+	} {
+		err = f.Deserialize(fields[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Deserialize proposal short id
+func (p *ProposalShortID) Deserialize(data []byte) error {
+	if len(data) != proposalShortIDSize {
+		return fmt.Errorf("invalid proposal short id, should be 10 bytes")
+	}
+
+	copy(p[:], data)
+	return nil
+}
+
+// deserializeProposals deserialize fixvec of proposal short ids
+func deserializeProposals(data []byte, d decoder) ([]ProposalShortID, error) {
+	items, err := d.fixvec(data, proposalShortIDSize)
+	if err != nil {
+		return nil, err
+	}
+
+	ps := d.arena.allocProposals(len(items))
+	for i := 0; i < len(items); i++ {
+		err = ps[i].Deserialize(items[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ps, nil
+}
+
+// Deserialize uncle block
+func (u *UncleBlock) Deserialize(data []byte) error {
+	return u.deserialize(data, decoder{})
+}
+
+func (u *UncleBlock) deserialize(data []byte, d decoder) error {
+	fields, extra, err := d.table(data, 2)
+	if err != nil {
+		return err
+	}
+	u.UnknownFields = unknownFields(extra, d)
+
+	err = u.Header.Deserialize(fields[0])
+	if err != nil {
+		return err
+	}
+
+	u.Proposals, err = deserializeProposals(fields[1], d)
+	return err
+}
+
+// Deserialize block, accept both Block and BlockV1, fields after BlockV1
+// extension are kept as unknown fields
+func (b *Block) Deserialize(data []byte) error {
+	return b.deserialize(data, decoder{})
+}
+
+func (b *Block) deserialize(data []byte, d decoder) error {
+	fields, extra, err := DeserializeTableCompatible(d.trimOffsets(data), 4)
+	if err != nil {
+		return err
+	}
+
+	if d.opts.RejectUnknownFields && len(extra) > 1 {
+		return fmt.Errorf("invalid block, should have at most 5 fields, got %d", len(fields)+len(extra))
+	}
+
+	b.UnknownFields = nil
+	if len(extra) > 0 {
+		fields = append(fields, extra[0])
+		b.UnknownFields = unknownFields(extra[1:], d)
+	}
+
+	err = b.Header.Deserialize(fields[0])
+	if err != nil {
+		return err
+	}
+
+	us, err := d.dynvec(fields[1])
+	if err != nil {
+		return err
+	}
+	b.Uncles = d.arena.allocUncles(len(us))
+	for i := 0; i < len(us); i++ {
+		err = b.Uncles[i].deserialize(us[i], d)
+		if err != nil {
+			return err
+		}
+	}
+
+	txs, err := d.dynvec(fields[2])
+	if err != nil {
+		return err
+	}
+	b.Transactions = d.arena.allocTransactions(len(txs))
+	for i := 0; i < len(txs); i++ {
+		err = b.Transactions[i].unpack(txs[i], d)
+		if err != nil {
+			return err
+		}
+	}
+
+	b.Proposals, err = deserializeProposals(fields[3], d)
+	if err != nil {
+		return err
+	}
+
+	b.Extension = nil
+	if len(fields) == 5 {
+		e := new(Bytes)
+		err = e.deserialize(fields[4], d)
+		if err != nil {
+			return err
+		}
+
+		b.Extension = e
+	}
+
+	return nil
+}
@@ -0,0 +1,250 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+)
+
+func TestDeserializeTransaction(t *testing.T) {
+	rawHex := "5f0100001c00000020000000490000004d0000007d0000004b0100000000000001000000b815a396c5226009670e89ee514850dcde452bca746cdd6b41c104b50e559c70000000000100000000010000000000000000000000ee046ce2baeda575266d4164f394c53f66009f64759f7a9f12a014c692e7939006000000ce0000000c0000006d0000006100000010000000180000006100000000406352bfc60100490000001000000030000000310000009bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce80114000000470dcdc5e44064909650113a274b3b36aecb6dc76100000010000000180000006100000000406352bfc60100490000001000000030000000310000009bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce80114000000c8328aabcd9b9e8e64fbc566c4385c3bdeb219d7140000000c000000100000000000000000000000"
+
+	raw, _ := hex.DecodeString(rawHex)
+
+	var tx Transaction
+
+	err := tx.Deserialize(raw)
+	if err != nil {
+		t.Errorf("fail to deserialize: %s\n", err)
+		return
+	}
+
+	if len(tx.Outputs) != 2 || tx.Outputs[1].Capacity != 0x1c6bf52634000 || tx.Inputs[0].PreviousOutput.Index != 6 {
+		t.Errorf("mismatch deserialized transaction %+v", tx)
+		return
+	}
+
+	got, err := tx.Serialize()
+	if err != nil {
+		t.Errorf("fail to serialize: %s\n", err)
+		return
+	}
+
+	gotHex := hex.EncodeToString(got)
+
+	if gotHex != rawHex {
+		t.Errorf("mismatch result, expect %v, got %v", rawHex, gotHex)
+		return
+	}
+
+	err = tx.Deserialize(raw[:len(raw)-1])
+	if err == nil {
+		t.Errorf("expect error on truncated data")
+		return
+	}
+}
+
+func TestPackTransaction(t *testing.T) {
+	var tx Transaction
+
+	lock := Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: make(Bytes, 20)}
+	tx.Outputs = []CellOutput{{Capacity: 6100000000, Lock: lock, Type: &lock}}
+	tx.OutputsData = []Bytes{{}}
+	tx.Witnesses = []Bytes{{0x01, 0x02}, {}}
+
+	packed, err := tx.Pack()
+	if err != nil {
+		t.Errorf("fail to pack: %s\n", err)
+		return
+	}
+
+	var unpacked Transaction
+
+	err = unpacked.Unpack(packed)
+	if err != nil {
+		t.Errorf("fail to unpack: %s\n", err)
+		return
+	}
+
+	if !unpacked.Equal(&tx) {
+		t.Errorf("mismatch unpacked transaction, expect %+v, got %+v", tx, unpacked)
+		return
+	}
+
+	h, err := tx.ComputeHash()
+	if err != nil {
+		t.Errorf("fail to compute hash: %s\n", err)
+		return
+	}
+
+	raw, _ := tx.Serialize()
+	if hex.EncodeToString(h[:]) != hex.EncodeToString(Blake2b256(raw)) {
+		t.Errorf("mismatch transaction hash, got %v", h)
+		return
+	}
+}
+
+func TestReplaceWitnesses(t *testing.T) {
+	lock := Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: make(Bytes, 20)}
+	tx := DefaultTransaction()
+	tx.Outputs = []CellOutput{{Capacity: 6100000000, Lock: lock}}
+	tx.OutputsData = []Bytes{{}}
+	tx.Witnesses = []Bytes{{0x01, 0x02}, {}}
+
+	packed, _ := tx.Pack()
+
+	tx.Witnesses = []Bytes{{0x03}, {0x04, 0x05}, make(Bytes, 85)}
+	expect, _ := tx.Pack()
+
+	replaced, err := ReplaceWitnesses(packed, tx.Witnesses)
+	if err != nil {
+		t.Errorf("fail to replace witnesses: %s\n", err)
+		return
+	}
+
+	if hex.EncodeToString(replaced) != hex.EncodeToString(expect) {
+		t.Errorf("mismatch result, expect %x, got %x", expect, replaced)
+		return
+	}
+
+	tx.Witnesses[1] = Bytes{0x06}
+	expect, _ = tx.Pack()
+
+	replaced, err = ReplaceWitness(replaced, 1, tx.Witnesses[1])
+	if err != nil {
+		t.Errorf("fail to replace witness: %s\n", err)
+		return
+	}
+
+	if hex.EncodeToString(replaced) != hex.EncodeToString(expect) {
+		t.Errorf("mismatch result, expect %x, got %x", expect, replaced)
+		return
+	}
+
+	_, err = ReplaceWitness(replaced, 3, Bytes{})
+	if err == nil {
+		t.Errorf("expect error on witness index out of range")
+		return
+	}
+
+	_, err = ReplaceWitnesses(replaced[:20], nil)
+	if err == nil {
+		t.Errorf("expect error on truncated transaction")
+		return
+	}
+}
+
+func TestDeserializeBlock(t *testing.T) {
+	var b Block
+
+	err := json.Unmarshal([]byte(recordedBlock), &b)
+	if err != nil {
+		t.Errorf("fail to unmarshal block json: %s\n", err)
+		return
+	}
+
+	ext := Bytes{0x01}
+	b.Extension = &ext
+
+	data, err := b.Serialize()
+	if err != nil {
+		t.Errorf("fail to serialize: %s\n", err)
+		return
+	}
+
+	var got Block
+
+	err = got.Deserialize(data)
+	if err != nil {
+		t.Errorf("fail to deserialize: %s\n", err)
+		return
+	}
+
+	if got.Header != b.Header || !got.Transactions[0].Equal(&b.Transactions[0]) || !got.Extension.Equal(ext) {
+		t.Errorf("mismatch block, expect %+v, got %+v", b, got)
+		return
+	}
+}
+
+func TestByte32Vec(t *testing.T) {
+	hs := []Hash{{1}, {2}, {3}}
+
+	b := SerializeByte32Vec(hs)
+	if len(b) != 4+3*32 {
+		t.Errorf("mismatch byte32 vec length, got %v", len(b))
+		return
+	}
+
+	decoded, err := DeserializeByte32Vec(b)
+	if err != nil {
+		t.Errorf("fail to deserialize: %s\n", err)
+		return
+	}
+
+	if len(decoded) != 3 || decoded[2] != hs[2] {
+		t.Errorf("mismatch result, expect %v, got %v", hs, decoded)
+		return
+	}
+
+	ps := []ProposalShortID{{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}}
+	b = SerializeProposalShortIDVec(ps)
+
+	decodedPs, err := DeserializeProposalShortIDVec(b)
+	if err != nil || len(decodedPs) != 1 || decodedPs[0] != ps[0] {
+		t.Errorf("mismatch result, expect %v, got %v", ps, decodedPs)
+		return
+	}
+
+	_, err = DeserializeByte32Vec(b)
+	if err == nil {
+		t.Errorf("expect error on item size mismatch")
+		return
+	}
+}
+
+func TestDeserializeUnknownFields(t *testing.T) {
+	lock := Script{CodeHash: Hash{1}, HashType: Type, Args: Bytes{2}}
+	known, err := lock.Serialize()
+	if err != nil {
+		t.Errorf("fail to serialize: %s\n", err)
+		return
+	}
+
+	// Script from a newer version with one more field
+	fields, err := DeserializeTable(known, 3)
+	if err != nil {
+		t.Errorf("fail to deserialize table: %s\n", err)
+		return
+	}
+	raw := SerializeTable(append(fields, []byte{0xab, 0xcd}))
+
+	var s Script
+	err = s.Deserialize(raw)
+	if err != nil {
+		t.Errorf("fail to deserialize: %s\n", err)
+		return
+	}
+
+	if !s.Args.Equal(lock.Args) || len(s.UnknownFields) != 1 || hex.EncodeToString(s.UnknownFields[0]) != "abcd" {
+		t.Errorf("mismatch deserialized script %+v", s)
+		return
+	}
+
+	if s.Equal(&lock) || !s.Equal(s.Clone()) {
+		t.Errorf("unknown fields should be compared")
+		return
+	}
+
+	got, err := s.Serialize()
+	if err != nil || hex.EncodeToString(got) != hex.EncodeToString(raw) {
+		t.Errorf("mismatch result, expect %x, got %x", raw, got)
+		return
+	}
+
+	// Tables with missing fields are still rejected
+	_, _, err = DeserializeTableCompatible(known, 4)
+	if err == nil {
+		t.Errorf("should reject table with fewer fields")
+		return
+	}
+}
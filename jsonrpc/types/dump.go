@@ -0,0 +1,90 @@
+package types
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// dumpBytesLimit bytes shown before truncation
+const dumpBytesLimit = 32
+
+// Dump render transaction in human-readable form, for debugging
+/*
+ * Differences from json form:
+ *
+ *     Capacities are shown in CKB.
+ *     Known system scripts are labeled by name.
+ *     Witnesses and outputs data longer than 32 bytes are truncated.
+ */
+func (t *Transaction) Dump() string {
+	b := new(strings.Builder)
+
+	fmt.Fprintf(b, "version: %s\n", t.Version)
+
+	fmt.Fprintf(b, "cell_deps: %d\n", len(t.CellDeps))
+	for i, d := range t.CellDeps {
+		fmt.Fprintf(b, "  [%d] %s %s\n", i, d.OutPoint, d.DepType)
+	}
+
+	fmt.Fprintf(b, "header_deps: %d\n", len(t.HeaderDeps))
+	for i, h := range t.HeaderDeps {
+		fmt.Fprintf(b, "  [%d] %s\n", i, h)
+	}
+
+	fmt.Fprintf(b, "inputs: %d\n", len(t.Inputs))
+	for i, in := range t.Inputs {
+		fmt.Fprintf(b, "  [%d] %s since %s\n", i, in.PreviousOutput, in.Since)
+	}
+
+	fmt.Fprintf(b, "outputs: %d\n", len(t.Outputs))
+	for i := range t.Outputs {
+		o := &t.Outputs[i]
+
+		fmt.Fprintf(b, "  [%d] capacity: %s CKB\n", i, formatCKB(o.Capacity))
+		fmt.Fprintf(b, "      lock: %s\n", dumpScript(&o.Lock))
+		fmt.Fprintf(b, "      type: %s\n", dumpScript(o.Type))
+		if i < len(t.OutputsData) {
+			fmt.Fprintf(b, "      data: %s\n", dumpBytes(t.OutputsData[i]))
+		}
+	}
+
+	fmt.Fprintf(b, "witnesses: %d\n", len(t.Witnesses))
+	for i, w := range t.Witnesses {
+		fmt.Fprintf(b, "  [%d] %s\n", i, dumpBytes(w))
+	}
+
+	return b.String()
+}
+
+func dumpScript(s *Script) string {
+	if s == nil {
+		return "none"
+	}
+
+	name := SystemScriptName(s)
+	if name == "" {
+		name = fmt.Sprintf("%s %s", s.CodeHash, s.HashType)
+	}
+
+	return fmt.Sprintf("%s args %s", name, dumpBytes(s.Args))
+}
+
+func dumpBytes(b Bytes) string {
+	if len(b) <= dumpBytesLimit {
+		return b.String()
+	}
+
+	return fmt.Sprintf("0x%s...(%d bytes)", hex.EncodeToString(b[:dumpBytesLimit]), len(b))
+}
+
+// formatCKB format shannons as CKB, trailing zeros trimmed
+func formatCKB(c Uint64) string {
+	whole := uint64(c) / ShannonsPerCKB
+	frac := uint64(c) % ShannonsPerCKB
+	if frac == 0 {
+		return fmt.Sprintf("%d", whole)
+	}
+
+	return strings.TrimRight(fmt.Sprintf("%d.%08d", whole, frac), "0")
+}
@@ -0,0 +1,80 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDumpTransaction(t *testing.T) {
+	transaction := `{
+	  "cell_deps": [
+		{
+		  "out_point": {
+			"tx_hash": "0xb815a396c5226009670e89ee514850dcde452bca746cdd6b41c104b50e559c70",
+			"index": "0x0"
+		  },
+		  "dep_type": "dep_group"
+		}
+	  ],
+	  "header_deps": [],
+	  "inputs": [
+		{
+		  "previous_output": {
+			"tx_hash": "0xee046ce2baeda575266d4164f394c53f66009f64759f7a9f12a014c692e79390",
+			"index": "0x6"
+		  },
+		  "since": "0x0"
+		}
+	  ],
+	  "outputs": [
+		{
+		  "capacity": "0x1c6bf5263e450",
+		  "lock": {
+			"args": "0x470dcdc5e44064909650113a274b3b36aecb6dc7",
+			"code_hash": "0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8",
+			"hash_type": "type"
+		  },
+		  "type": null
+		}
+	  ],
+	  "outputs_data": ["0x"],
+	  "version": "0x0",
+	  "witnesses": ["0x55000000100000005500000055000000410000004a975e08ff99fa0001"]
+	}`
+
+	expect := `version: 0x0
+cell_deps: 1
+  [0] 0xb815a396c5226009670e89ee514850dcde452bca746cdd6b41c104b50e559c70:0 dep_group
+header_deps: 0
+inputs: 1
+  [0] 0xee046ce2baeda575266d4164f394c53f66009f64759f7a9f12a014c692e79390:6 since 0x0
+outputs: 1
+  [0] capacity: 5000000.00042064 CKB
+      lock: secp256k1_blake160_sighash_all args 0x470dcdc5e44064909650113a274b3b36aecb6dc7
+      type: none
+      data: 0x
+witnesses: 1
+  [0] 0x55000000100000005500000055000000410000004a975e08ff99fa0001
+`
+
+	var tx Transaction
+
+	err := json.Unmarshal([]byte(transaction), &tx)
+	if err != nil {
+		t.Errorf("fail to unmarshal test transaction json: %s\n", err)
+		return
+	}
+
+	got := tx.Dump()
+	if got != expect {
+		t.Errorf("mismatch result, expect\n%v\ngot\n%v", expect, got)
+		return
+	}
+
+	tx.Witnesses[0] = make(Bytes, 40)
+	expectWitness := "0x" + "0000000000000000000000000000000000000000000000000000000000000000" + "...(40 bytes)"
+	if dumpBytes(tx.Witnesses[0]) != expectWitness {
+		t.Errorf("mismatch truncated witness, expect %v, got %v", expectWitness, dumpBytes(tx.Witnesses[0]))
+		return
+	}
+}
@@ -0,0 +1,120 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// EpochNumberWithFraction ckb epoch with block index in it, packed into
+// uint64 in header epoch and since
+/*
+ *     number: lowest 24 bits
+ *     index:  next 16 bits
+ *     length: next 16 bits
+ */
+type EpochNumberWithFraction struct {
+	Number uint64
+	Index  uint64
+	Length uint64
+}
+
+// NewEpochNumberWithFraction unpack epoch from uint64
+func NewEpochNumberWithFraction(v Uint64) EpochNumberWithFraction {
+	return EpochNumberWithFraction{
+		Number: uint64(v) & 0xffffff,
+		Index:  (uint64(v) >> 24) & 0xffff,
+		Length: (uint64(v) >> 40) & 0xffff,
+	}
+}
+
+// Uint64 pack epoch into uint64
+func (e EpochNumberWithFraction) Uint64() Uint64 {
+	return Uint64(e.Number&0xffffff | (e.Index&0xffff)<<24 | (e.Length&0xffff)<<40)
+}
+
+// IsWellFormed report whether epoch is valid, length must be positive and
+// index below it, zero value is allowed
+func (e EpochNumberWithFraction) IsWellFormed() bool {
+	if e.Length == 0 {
+		return e.Number == 0 && e.Index == 0
+	}
+
+	return e.Index < e.Length
+}
+
+// IsWellFormedIncrement report whether epoch is valid as relative since,
+// zero length and index add whole epochs, any number of them
+func (e EpochNumberWithFraction) IsWellFormedIncrement() bool {
+	if e.Length == 0 && e.Index == 0 {
+		return true
+	}
+
+	return e.IsWellFormed()
+}
+
+// String epoch in "number(index/length)" form
+func (e EpochNumberWithFraction) String() string {
+	return fmt.Sprintf("%d(%d/%d)", e.Number, e.Index, e.Length)
+}
+
+// Rat epoch as rational number, number + index / length
+func (e EpochNumberWithFraction) Rat() *big.Rat {
+	r := new(big.Rat).SetInt64(int64(e.Number))
+	if e.Length == 0 {
+		return r
+	}
+
+	return r.Add(r, big.NewRat(int64(e.Index), int64(e.Length)))
+}
+
+// RationalU256 epoch as consensus rational number, number + index / length
+func (e EpochNumberWithFraction) RationalU256() (RationalU256, error) {
+	r := RationalU256FromU256(NewUint256(e.Number))
+	if e.Length == 0 {
+		return r, nil
+	}
+
+	f, err := NewRationalU256(NewUint256(e.Index), NewUint256(e.Length))
+	if err != nil {
+		return RationalU256{}, err
+	}
+
+	return r.Add(f)
+}
+
+// EpochView ckb epoch, result of get_current_epoch and get_epoch_by_number
+type EpochView struct {
+	Number        Uint64 `json:"number"`
+	StartNumber   Uint64 `json:"start_number"`
+	Length        Uint64 `json:"length"`
+	CompactTarget Uint32 `json:"compact_target"`
+}
+
+// EndNumber last block number in epoch
+func (e *EpochView) EndNumber() Uint64 {
+	return e.StartNumber + e.Length - 1
+}
+
+// NextStartNumber first block number of next epoch
+func (e *EpochView) NextStartNumber() Uint64 {
+	return e.StartNumber + e.Length
+}
+
+// Contains report whether block number is in epoch
+func (e *EpochView) Contains(number Uint64) bool {
+	return number >= e.StartNumber && number < e.NextStartNumber()
+}
+
+// EpochOf epoch with fraction of block number in epoch, the value of
+// header epoch field
+func (e *EpochView) EpochOf(number Uint64) (EpochNumberWithFraction, error) {
+	if !e.Contains(number) {
+		return EpochNumberWithFraction{}, fmt.Errorf("block %d not in epoch %d", number, e.Number)
+	}
+
+	return EpochNumberWithFraction{
+		Number: uint64(e.Number),
+		Index:  uint64(number - e.StartNumber),
+		Length: uint64(e.Length),
+	}, nil
+}
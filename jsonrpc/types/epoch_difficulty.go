@@ -0,0 +1,361 @@
+package types
+
+import (
+	"fmt"
+)
+
+// Epoch adjustment consensus defaults, same on mainnet and testnet
+const (
+	// DefaultEpochDurationTarget epoch duration target in seconds
+	DefaultEpochDurationTarget = 4 * 60 * 60
+	// MinBlockInterval seconds, bounds max epoch length
+	MinBlockInterval = 8
+	// MaxBlockInterval seconds, bounds min epoch length
+	MaxBlockInterval = 48
+
+	// epochTau max ratio of epoch length and hash rate between epochs
+	epochTau = 2
+)
+
+// EpochConsensus consensus parameters of epoch adjustment
+type EpochConsensus struct {
+	// EpochDurationTarget seconds
+	EpochDurationTarget uint64
+	OrphanRateTarget    RationalU256
+	// PermanentDifficulty dev chains keep length and difficulty
+	PermanentDifficulty bool
+}
+
+// DefaultEpochConsensus mainnet and testnet epoch parameters, orphan rate
+// target is 1/40
+func DefaultEpochConsensus() EpochConsensus {
+	orphan, _ := NewRationalU256(NewUint256(1), NewUint256(40))
+	return EpochConsensus{EpochDurationTarget: DefaultEpochDurationTarget, OrphanRateTarget: orphan}
+}
+
+// MaxEpochLength epoch duration target over min block interval
+func (c *EpochConsensus) MaxEpochLength() uint64 {
+	return c.EpochDurationTarget / MinBlockInterval
+}
+
+// MinEpochLength epoch duration target over max block interval
+func (c *EpochConsensus) MinEpochLength() uint64 {
+	return c.EpochDurationTarget / MaxBlockInterval
+}
+
+// EpochStats stats of the last epoch needed for adjustment
+type EpochStats struct {
+	Epoch EpochView
+	// PreviousEpochHashRate adjusted hash rate of the epoch before, zero for
+	// genesis epoch
+	PreviousEpochHashRate Uint256
+	// Duration milliseconds from the last block before epoch to the last
+	// block of epoch
+	Duration uint64
+	// UnclesCount uncles included by blocks of epoch
+	UnclesCount uint64
+	// LastCompactTarget compact target of the last block of epoch
+	LastCompactTarget Uint32
+}
+
+// NextEpochParams length and difficulty of the next epoch
+type NextEpochParams struct {
+	Number        Uint64
+	StartNumber   Uint64
+	Length        Uint64
+	CompactTarget Uint32
+	// PreviousEpochHashRate adjusted hash rate of the last epoch, input of
+	// the next adjustment
+	PreviousEpochHashRate Uint256
+}
+
+// NextEpoch next epoch length and compact target, as ckb computes in
+// consensus next_epoch_ext
+/*
+ *     Hash rate of last epoch, bounded by tau of the previous one:
+ *         difficulty * (uncles + length) / duration
+ *
+ *     Length, bounded by tau of last length and [min, max] length:
+ *         o_ideal * (1 + o) * L_ideal * length / (o * (1 + o_ideal) * duration)
+ *         twice last length if there was no uncle
+ *
+ *     Difficulty, unbounded length:
+ *         hash rate * L_ideal / ((1 + o_ideal) * next length)
+ *
+ * Bounded length estimates the orphan rate it will produce instead.
+ */
+func (c *EpochConsensus) NextEpoch(s *EpochStats) (*NextEpochParams, error) {
+	e := &s.Epoch
+	if e.Length == 0 {
+		return nil, fmt.Errorf("invalid epoch %d, zero length", e.Number)
+	}
+
+	next := &NextEpochParams{
+		Number:        e.Number + 1,
+		StartNumber:   e.NextStartNumber(),
+		Length:        e.Length,
+		CompactTarget: e.CompactTarget,
+	}
+
+	lastDifficulty := CompactToDifficulty(uint32(s.LastCompactTarget))
+	if c.PermanentDifficulty {
+		next.PreviousEpochHashRate = s.PreviousEpochHashRate
+		return next, nil
+	}
+
+	length := uint64(e.Length)
+	duration := NewUint256(s.Duration)
+	if s.Duration == 0 {
+		duration = NewUint256(1)
+	}
+
+	// Saturating like ckb
+	rate, err := lastDifficulty.Mul(NewUint256(s.UnclesCount + length))
+	if err != nil {
+		rate = MaxUint256()
+	}
+
+	rate, _, err = rate.DivMod(duration)
+	if err != nil {
+		return nil, err
+	}
+
+	rate = boundHashRate(rate, s.PreviousEpochHashRate)
+	if rate.IsZero() {
+		rate = NewUint256(1)
+	}
+	next.PreviousEpochHashRate = rate
+
+	target := NewUint256(c.EpochDurationTarget * 1000)
+
+	orphan, err := NewRationalU256(NewUint256(s.UnclesCount), NewUint256(length))
+	if err != nil {
+		return nil, err
+	}
+
+	nextLength, bound := minUint64(c.MaxEpochLength(), length*epochTau), true
+	if s.UnclesCount != 0 {
+		raw, err := c.rawEpochLength(orphan, NewUint256(length), duration)
+		if err != nil {
+			return nil, err
+		}
+
+		nextLength, bound = c.boundEpochLength(raw, length)
+	}
+	next.Length = Uint64(nextLength)
+
+	// hash rate * L_ideal
+	numer, err := RationalU256FromU256(rate).MulU256(target)
+	if err != nil {
+		return nil, err
+	}
+
+	var diff RationalU256
+	switch {
+	case !bound:
+		diff, err = c.idealDifficulty(numer, nextLength)
+	case orphan.IsZero():
+		diff, err = numer.DivU256(NewUint256(nextLength))
+	default:
+		diff, err = c.boundedDifficulty(numer, orphan, NewUint256(length), duration, nextLength)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := diff.IntoU256()
+	if err != nil {
+		return nil, err
+	}
+
+	next.CompactTarget = Uint32(DifficultyToCompact(d))
+	return next, nil
+}
+
+// rawEpochLength o_ideal * (1 + o) * L_ideal * length / (o * (1 +
+// o_ideal) * duration)
+func (c *EpochConsensus) rawEpochLength(orphan RationalU256, length Uint256, duration Uint256) (uint64, error) {
+	numer, err := orphan.AddU256(NewUint256(1))
+	if err != nil {
+		return 0, err
+	}
+
+	numer, err = numer.Mul(c.OrphanRateTarget)
+	if err != nil {
+		return 0, err
+	}
+
+	numer, err = numer.MulU256(NewUint256(c.EpochDurationTarget * 1000))
+	if err != nil {
+		return 0, err
+	}
+
+	numer, err = numer.MulU256(length)
+	if err != nil {
+		return 0, err
+	}
+
+	denom, err := c.OrphanRateTarget.AddU256(NewUint256(1))
+	if err != nil {
+		return 0, err
+	}
+
+	denom, err = denom.Mul(orphan)
+	if err != nil {
+		return 0, err
+	}
+
+	denom, err = denom.MulU256(duration)
+	if err != nil {
+		return 0, err
+	}
+
+	raw, err := numer.Div(denom)
+	if err != nil {
+		return 0, err
+	}
+
+	u, err := raw.IntoU256()
+	if err != nil {
+		return 0, err
+	}
+
+	// Low 64 bits like ckb
+	return u[0], nil
+}
+
+// idealDifficulty hash rate * L_ideal / ((1 + o_ideal) * next length)
+func (c *EpochConsensus) idealDifficulty(numer RationalU256, nextLength uint64) (RationalU256, error) {
+	denom, err := c.OrphanRateTarget.AddU256(NewUint256(1))
+	if err != nil {
+		return RationalU256{}, err
+	}
+
+	denom, err = denom.MulU256(NewUint256(nextLength))
+	if err != nil {
+		return RationalU256{}, err
+	}
+
+	return numer.Div(denom)
+}
+
+// boundedDifficulty difficulty of bounded length, hash rate * L_ideal /
+// ((1 + o_estimated) * next length)
+/*
+ * Orphan rate the bounded length produces is estimated from:
+ *
+ *     1 / o_estimated = (1 + o) * L_ideal * length / (o * duration * next length) - 1
+ *
+ * Zero reciprocal is a small probability event, o_ideal is used then.
+ */
+func (c *EpochConsensus) boundedDifficulty(numer RationalU256, orphan RationalU256, length Uint256, duration Uint256, nextLength uint64) (RationalU256, error) {
+	one := RationalU256FromU256(NewUint256(1))
+
+	recip, err := orphan.Add(one)
+	if err != nil {
+		return RationalU256{}, err
+	}
+
+	recip, err = recip.MulU256(NewUint256(c.EpochDurationTarget * 1000))
+	if err != nil {
+		return RationalU256{}, err
+	}
+
+	recip, err = recip.MulU256(length)
+	if err != nil {
+		return RationalU256{}, err
+	}
+
+	denom, err := orphan.MulU256(duration)
+	if err != nil {
+		return RationalU256{}, err
+	}
+
+	denom, err = denom.MulU256(NewUint256(nextLength))
+	if err != nil {
+		return RationalU256{}, err
+	}
+
+	recip, err = recip.Div(denom)
+	if err != nil {
+		return RationalU256{}, err
+	}
+
+	recip, err = recip.SaturatingSub(one)
+	if err != nil {
+		return RationalU256{}, err
+	}
+
+	if recip.IsZero() {
+		return c.idealDifficulty(numer, nextLength)
+	}
+
+	estimation, err := one.Div(recip)
+	if err != nil {
+		return RationalU256{}, err
+	}
+
+	estimation, err = estimation.Add(one)
+	if err != nil {
+		return RationalU256{}, err
+	}
+
+	estimation, err = estimation.MulU256(NewUint256(nextLength))
+	if err != nil {
+		return RationalU256{}, err
+	}
+
+	return numer.Div(estimation)
+}
+
+// boundEpochLength bound length by tau of last length and [min, max]
+// length, also report whether it was bounded
+func (c *EpochConsensus) boundEpochLength(length uint64, last uint64) (uint64, bool) {
+	max := minUint64(c.MaxEpochLength(), last*epochTau)
+	min := last / epochTau
+	if m := c.MinEpochLength(); m > min {
+		min = m
+	}
+
+	switch {
+	case length > max:
+		return max, true
+	case length < min:
+		return min, true
+	default:
+		return length, false
+	}
+}
+
+// boundHashRate bound hash rate by tau of previous one, unbounded if
+// previous is zero
+func boundHashRate(rate Uint256, previous Uint256) Uint256 {
+	if previous.IsZero() {
+		return rate
+	}
+
+	lower := previous.Rsh(1)
+	if rate.Cmp(lower) < 0 {
+		return lower
+	}
+
+	upper, err := previous.Mul(NewUint256(epochTau))
+	if err != nil {
+		upper = MaxUint256()
+	}
+
+	if rate.Cmp(upper) > 0 {
+		return upper
+	}
+
+	return rate
+}
+
+// minUint64 smaller of a and b
+func minUint64(a uint64, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+
+	return b
+}
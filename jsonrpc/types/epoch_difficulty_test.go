@@ -0,0 +1,103 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestNextEpoch(t *testing.T) {
+	c := DefaultEpochConsensus()
+	compact := Uint32(0x1a08a97e)
+	difficulty := CompactToDifficulty(uint32(compact))
+
+	// Ideal orphan rate and duration keep length and difficulty
+	s := &EpochStats{
+		Epoch:             EpochView{Number: 10, StartNumber: 10000, Length: 1000, CompactTarget: compact},
+		Duration:          DefaultEpochDurationTarget * 1000,
+		UnclesCount:       25,
+		LastCompactTarget: compact,
+	}
+
+	next, err := c.NextEpoch(s)
+	if err != nil {
+		t.Errorf("fail to compute next epoch: %s\n", err)
+		return
+	}
+
+	if next.Number != 11 || next.StartNumber != 11000 || next.Length != 1000 {
+		t.Errorf("mismatch result, expect epoch 11 at 11000 of 1000, got %v", next)
+		return
+	}
+
+	if next.CompactTarget != Uint32(DifficultyToCompact(difficulty)) {
+		t.Errorf("mismatch result, expect %#x, got %#x", DifficultyToCompact(difficulty), uint32(next.CompactTarget))
+		return
+	}
+
+	// No uncle, length is bounded by max length
+	s.UnclesCount = 0
+	next, err = c.NextEpoch(s)
+	if err != nil {
+		t.Errorf("fail to compute next epoch: %s\n", err)
+		return
+	}
+
+	if uint64(next.Length) != c.MaxEpochLength() {
+		t.Errorf("mismatch result, expect length %d, got %d", c.MaxEpochLength(), next.Length)
+		return
+	}
+
+	rate, _ := difficulty.Mul(NewUint256(1000))
+	rate, _, _ = rate.DivMod(NewUint256(s.Duration))
+	expect, _ := rate.Mul(NewUint256(s.Duration))
+	expect, _, _ = expect.DivMod(NewUint256(c.MaxEpochLength()))
+
+	if next.CompactTarget != Uint32(DifficultyToCompact(expect)) {
+		t.Errorf("mismatch result, expect %#x, got %#x", DifficultyToCompact(expect), uint32(next.CompactTarget))
+		return
+	}
+
+	// Hash rate is bounded by twice the previous epoch
+	s.PreviousEpochHashRate = NewUint256(1)
+	next, err = c.NextEpoch(s)
+	if err != nil {
+		t.Errorf("fail to compute next epoch: %s\n", err)
+		return
+	}
+
+	if next.PreviousEpochHashRate != NewUint256(2) {
+		t.Errorf("mismatch result, expect bounded hash rate 2, got %s", next.PreviousEpochHashRate)
+		return
+	}
+
+	c.PermanentDifficulty = true
+	next, err = c.NextEpoch(s)
+	if err != nil {
+		t.Errorf("fail to compute next epoch: %s\n", err)
+		return
+	}
+
+	if next.Length != 1000 || next.CompactTarget != compact {
+		t.Errorf("mismatch result, expect unchanged epoch, got %v", next)
+		return
+	}
+}
+
+func TestBoundEpochLength(t *testing.T) {
+	c := DefaultEpochConsensus()
+
+	for _, tt := range []struct {
+		length, last, expect uint64
+		bound                bool
+	}{
+		{1000, 1000, 1000, false},
+		{5000, 1000, 1800, true},
+		{100, 1000, 500, true},
+		{100, 400, 300, true},
+	} {
+		l, b := c.boundEpochLength(tt.length, tt.last)
+		if l != tt.expect || b != tt.bound {
+			t.Errorf("mismatch result, expect %d %v, got %d %v", tt.expect, tt.bound, l, b)
+			return
+		}
+	}
+}
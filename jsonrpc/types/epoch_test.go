@@ -0,0 +1,60 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestEpochView(t *testing.T) {
+	raw := `{
+  "compact_target": "0x1e083126",
+  "length": "0x708",
+  "number": "0x1",
+  "start_number": "0x3e8"
+}`
+
+	var e EpochView
+	strictRoundTrip(t, raw, &e)
+
+	if e.EndNumber() != 0x3e8+0x708-1 || e.Contains(e.NextStartNumber()) || !e.Contains(e.StartNumber) {
+		t.Errorf("mismatch epoch boundaries, got %+v", e)
+		return
+	}
+
+	// Header epoch of block 0x400 in recorded block
+	epoch, err := e.EpochOf(0x400)
+	if err != nil {
+		t.Errorf("fail to get epoch of block: %s\n", err)
+		return
+	}
+
+	if epoch.Uint64() != 0x7080018000001 {
+		t.Errorf("mismatch result, expect %v, got %v", Uint64(0x7080018000001), epoch.Uint64())
+		return
+	}
+
+	_, err = e.EpochOf(1)
+	if err == nil {
+		t.Errorf("expect error on block outside epoch")
+		return
+	}
+}
+
+func TestEpochIsWellFormed(t *testing.T) {
+	for _, c := range []struct {
+		epoch EpochNumberWithFraction
+		valid bool
+	}{
+		{EpochNumberWithFraction{}, true},
+		{EpochNumberWithFraction{Number: 1, Index: 0, Length: 1}, true},
+		{EpochNumberWithFraction{Number: 1, Index: 1799, Length: 1800}, true},
+		// Index equal to length is next epoch, not a fraction of this one
+		{EpochNumberWithFraction{Number: 1, Index: 1800, Length: 1800}, false},
+		{EpochNumberWithFraction{Number: 1, Index: 1801, Length: 1800}, false},
+		{EpochNumberWithFraction{Number: 2, Index: 0, Length: 0}, false},
+	} {
+		if c.epoch.IsWellFormed() != c.valid {
+			t.Errorf("mismatch result of %s, expect %v, got %v", c.epoch, c.valid, !c.valid)
+			return
+		}
+	}
+}
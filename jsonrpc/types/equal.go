@@ -0,0 +1,114 @@
+package types
+
+import "bytes"
+
+// Equal helpers compare semantically: nil and empty slices are the same,
+// since both serialize to the same molecule bytes.
+
+// Equal report whether two bytes are the same
+func (b Bytes) Equal(other Bytes) bool {
+	return bytes.Equal(b, other)
+}
+
+// Equal report whether two scripts are the same, nil scripts are equal
+func (s *Script) Equal(other *Script) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+
+	return s.CodeHash == other.CodeHash &&
+		s.HashType == other.HashType &&
+		s.Args.Equal(other.Args) &&
+		equalUnknownFields(s.UnknownFields, other.UnknownFields)
+}
+
+// Equal report whether two cell deps are the same
+func (d *CellDep) Equal(other *CellDep) bool {
+	if d == nil || other == nil {
+		return d == other
+	}
+
+	return *d == *other
+}
+
+// Equal report whether two cell outputs are the same
+func (o *CellOutput) Equal(other *CellOutput) bool {
+	if o == nil || other == nil {
+		return o == other
+	}
+
+	return o.Capacity == other.Capacity &&
+		o.Lock.Equal(&other.Lock) &&
+		o.Type.Equal(other.Type) &&
+		equalUnknownFields(o.UnknownFields, other.UnknownFields)
+}
+
+// Equal report whether two transactions are the same
+func (t *Transaction) Equal(other *Transaction) bool {
+	if t == nil || other == nil {
+		return t == other
+	}
+
+	if t.Version != other.Version ||
+		len(t.CellDeps) != len(other.CellDeps) ||
+		len(t.HeaderDeps) != len(other.HeaderDeps) ||
+		len(t.Inputs) != len(other.Inputs) ||
+		len(t.Outputs) != len(other.Outputs) ||
+		len(t.Witnesses) != len(other.Witnesses) ||
+		len(t.OutputsData) != len(other.OutputsData) ||
+		!equalUnknownFields(t.UnknownFields, other.UnknownFields) {
+		return false
+	}
+
+	for i := 0; i < len(t.CellDeps); i++ {
+		if t.CellDeps[i] != other.CellDeps[i] {
+			return false
+		}
+	}
+
+	for i := 0; i < len(t.HeaderDeps); i++ {
+		if t.HeaderDeps[i] != other.HeaderDeps[i] {
+			return false
+		}
+	}
+
+	for i := 0; i < len(t.Inputs); i++ {
+		if t.Inputs[i] != other.Inputs[i] {
+			return false
+		}
+	}
+
+	for i := 0; i < len(t.Outputs); i++ {
+		if !t.Outputs[i].Equal(&other.Outputs[i]) {
+			return false
+		}
+	}
+
+	for i := 0; i < len(t.Witnesses); i++ {
+		if !t.Witnesses[i].Equal(other.Witnesses[i]) {
+			return false
+		}
+	}
+
+	for i := 0; i < len(t.OutputsData); i++ {
+		if !t.OutputsData[i].Equal(other.OutputsData[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalUnknownFields(fs [][]byte, other [][]byte) bool {
+	if len(fs) != len(other) {
+		return false
+	}
+
+	for i := 0; i < len(fs); i++ {
+		if !bytes.Equal(fs[i], other[i]) {
+			return false
+		}
+	}
+
+	return true
+}
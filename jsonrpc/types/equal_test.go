@@ -0,0 +1,64 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTransactionEqual(t *testing.T) {
+	transaction := `{
+	  "cell_deps": [],
+	  "header_deps": [],
+	  "inputs": [],
+	  "outputs": [
+		{
+		  "capacity": "0x1c6bf52634000",
+		  "lock": {
+			"args": "0x",
+			"code_hash": "0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8",
+			"hash_type": "type"
+		  },
+		  "type": null
+		}
+	  ],
+	  "outputs_data": ["0x"],
+	  "version": "0x0",
+	  "witnesses": []
+	}`
+
+	var tx Transaction
+
+	err := json.Unmarshal([]byte(transaction), &tx)
+	if err != nil {
+		t.Errorf("fail to unmarshal test transaction json: %s\n", err)
+		return
+	}
+
+	// Same transaction built by hand, with nil instead of empty slices
+	codeHash, _ := ParseHash("0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8")
+	built := Transaction{
+		Outputs: []CellOutput{
+			{
+				Capacity: 0x1c6bf52634000,
+				Lock:     Script{CodeHash: codeHash, HashType: Type},
+			},
+		},
+		OutputsData: []Bytes{nil},
+	}
+
+	if !tx.Equal(&built) {
+		t.Errorf("expect equal transactions")
+		return
+	}
+
+	built.Outputs[0].Type = &Script{CodeHash: codeHash, HashType: Data}
+	if tx.Equal(&built) {
+		t.Errorf("expect different type script")
+		return
+	}
+
+	if !built.Outputs[0].Type.Equal(built.Outputs[0].Type.Clone()) {
+		t.Errorf("expect equal cloned script")
+		return
+	}
+}
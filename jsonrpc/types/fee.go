@@ -0,0 +1,46 @@
+package types
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// DefaultMinFeeRate node default min_fee_rate, shannons per 1000 bytes
+const DefaultMinFeeRate = 1000
+
+// SizeInBlock transaction size counted by fee rate, packed transaction
+// plus its 4 bytes offset in block transactions dynvec
+func (t *Transaction) SizeInBlock() (uint64, error) {
+	packed, err := t.Pack()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(len(packed)) + uint32Size, nil
+}
+
+// CalculateFee fee of given size at fee rate, shannons per 1000 bytes,
+// rounded up like the node does
+func CalculateFee(size uint64, feeRate Uint64) (Uint64, error) {
+	hi, lo := bits.Mul64(size, uint64(feeRate))
+	if hi != 0 {
+		return 0, fmt.Errorf("fee overflow")
+	}
+
+	fee := lo / 1000
+	if lo%1000 != 0 {
+		fee++
+	}
+
+	return Uint64(fee), nil
+}
+
+// Fee fee of transaction at fee rate
+func (t *Transaction) Fee(feeRate Uint64) (Uint64, error) {
+	size, err := t.SizeInBlock()
+	if err != nil {
+		return 0, err
+	}
+
+	return CalculateFee(size, feeRate)
+}
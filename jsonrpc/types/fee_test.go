@@ -0,0 +1,44 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestCalculateFee(t *testing.T) {
+	for _, c := range []struct {
+		size    uint64
+		feeRate Uint64
+		expect  Uint64
+	}{
+		{1000, 1000, 1000},
+		{1001, 1000, 1001},
+		{333, 1, 1},
+		{0, 1000, 0},
+		{1245, 3000, 3735},
+	} {
+		fee, err := CalculateFee(c.size, c.feeRate)
+		if err != nil {
+			t.Errorf("fail to calculate fee: %s\n", err)
+			return
+		}
+
+		if fee != c.expect {
+			t.Errorf("mismatch result, expect %v, got %v", c.expect, fee)
+			return
+		}
+	}
+
+	if _, err := CalculateFee(1<<63, 4); err == nil {
+		t.Errorf("expect error on fee overflow")
+		return
+	}
+
+	tx := &Transaction{}
+	packed, _ := tx.Pack()
+
+	size, err := tx.SizeInBlock()
+	if err != nil || size != uint64(len(packed))+4 {
+		t.Errorf("mismatch result, expect %v, got %v", len(packed)+4, size)
+		return
+	}
+}
@@ -0,0 +1,135 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+
+	"github.com/minio/blake2b-simd"
+)
+
+// Hash ckb hash, '0x' prefix hex string in json
+type Hash [32]byte
+
+// ParseHash parse hash from '0x' prefix hex string
+func ParseHash(s string) (Hash, error) {
+	var h Hash
+
+	err := check0xPrefix(s)
+	if err != nil {
+		return h, err
+	}
+
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return h, err
+	}
+
+	if len(b) != len(h) {
+		return h, fmt.Errorf("invalid hash, should be 32 bytes")
+	}
+
+	copy(h[:], b)
+	return h, nil
+}
+
+// String '0x' prefix hex string
+func (h Hash) String() string {
+	return "0x" + hex.EncodeToString(h[:])
+}
+
+// MarshalJSON marshal hash to '0x' prefix hex string
+func (h Hash) MarshalJSON() ([]byte, error) {
+	return json.Marshal(h.String())
+}
+
+// MarshalText marshal hash to '0x' prefix hex, so hash works as json map
+// key and in text configs
+func (h Hash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText unmarshal hash from '0x' prefix hex
+func (h *Hash) UnmarshalText(text []byte) error {
+	hh, err := ParseHash(string(text))
+	if err != nil {
+		return err
+	}
+
+	*h = hh
+	return nil
+}
+
+// UnmarshalJSON unmarshal hash from '0x' prefix hex string
+func (h *Hash) UnmarshalJSON(data []byte) error {
+	var s string
+
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+
+	hh, err := ParseHash(s)
+	if err != nil {
+		return err
+	}
+
+	*h = hh
+	return nil
+}
+
+// Blake2bPersonalization ckb blake2b hash personalization
+const Blake2bPersonalization = "ckb-default-hash"
+
+// Blake160Size blake160 hash size in bytes
+const Blake160Size = 20
+
+// NewBlake2b new ckb blake2b-256 hasher
+func NewBlake2b() hash.Hash {
+	config := &blake2b.Config{
+		Size:   32,
+		Person: []byte(Blake2bPersonalization),
+	}
+
+	// Config is constant and valid, error never happens
+	h, err := blake2b.New(config)
+	if err != nil {
+		panic(err)
+	}
+
+	return h
+}
+
+// Blake2b256 ckb blake2b-256 hash
+func Blake2b256(data []byte) []byte {
+	h := NewBlake2b()
+	h.Write(data)
+
+	return h.Sum(nil)
+}
+
+// Blake160 first 20 bytes of ckb blake2b-256 hash
+func Blake160(data []byte) []byte {
+	return Blake2b256(data)[:Blake160Size]
+}
+
+// PubkeyToLockArgs calculate default secp256k1 lock args from compressed
+// or uncompressed public key, args are hashed from compressed form
+func PubkeyToLockArgs(pubkey []byte) (Bytes, error) {
+	compressed, err := CompressPubkey(pubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	return Bytes(Blake160(compressed)), nil
+}
+
+// ValidateLockArgs validate default secp256k1 lock args
+func ValidateLockArgs(args Bytes) error {
+	if len(args) != Blake160Size {
+		return fmt.Errorf("invalid lock args, should be 20 bytes")
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestBlake2b256(t *testing.T) {
+	expectHex := "44f4c69744d5f8c55d642062949dcae49bc4e7ef43d388c5a12f42b5633d163e"
+
+	gotHex := hex.EncodeToString(Blake2b256([]byte{}))
+
+	if gotHex != expectHex {
+		t.Errorf("mismatch result, expect %v, got %v", expectHex, gotHex)
+		return
+	}
+
+	if hex.EncodeToString(Blake160([]byte{})) != expectHex[:40] {
+		t.Errorf("mismatch blake160, expect %v", expectHex[:40])
+		return
+	}
+}
+
+func TestPubkeyToLockArgs(t *testing.T) {
+	// Generator point, public key of private key 1
+	pubkey, _ := hex.DecodeString("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+
+	args, err := PubkeyToLockArgs(pubkey)
+	if err != nil {
+		t.Errorf("fail to calculate lock args: %s\n", err)
+		return
+	}
+
+	err = ValidateLockArgs(args)
+	if err != nil {
+		t.Errorf("fail to validate lock args: %s\n", err)
+		return
+	}
+
+	_, err = PubkeyToLockArgs(make([]byte, 65))
+	if err == nil {
+		t.Errorf("expect error on malformed uncompressed pubkey")
+		return
+	}
+
+	err = ValidateLockArgs(args[:19])
+	if err == nil {
+		t.Errorf("expect error on 19 bytes args")
+		return
+	}
+}
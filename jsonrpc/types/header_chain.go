@@ -0,0 +1,200 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Header chain consensus constants
+const (
+	// MedianTimeBlockCount blocks whose median timestamp child must exceed
+	MedianTimeBlockCount = 37
+	// AllowedFutureBlockTime milliseconds header may be ahead of local time
+	AllowedFutureBlockTime = 15000
+)
+
+// PowEngine ckb pow hash function, eaglesong on mainnet and testnet
+type PowEngine interface {
+	PowHash(message []byte) (Hash, error)
+}
+
+// PowMessage pow input of header, blake2b-256 of molecule RawHeader then
+// nonce in little-endian
+func PowMessage(h *Header) ([]byte, error) {
+	b, err := h.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	raw := b[:len(b)-uint128Size]
+
+	return append(Blake2b256(raw), b[len(raw):]...), nil
+}
+
+// CheckPowHash check pow output, as big-endian uint256, meets compact
+// target
+func CheckPowHash(output Hash, compact Uint32) error {
+	target, overflow := CompactToTarget(uint32(compact))
+	if target.IsZero() || overflow {
+		return fmt.Errorf("invalid compact target %#x", uint32(compact))
+	}
+
+	var u Uint256
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 8; j++ {
+			u[3-i] = u[3-i]<<8 | uint64(output[8*i+j])
+		}
+	}
+
+	if u.Cmp(target) > 0 {
+		return fmt.Errorf("invalid pow, hash %s above target %s", output, target)
+	}
+
+	return nil
+}
+
+// HeaderChainVerifier verify headers from untrusted sources against
+// trusted ancestors
+/*
+ * Each header is checked against its parent:
+ *
+ *     Hash matches header and parent hash links to parent.
+ *     Number follows parent.
+ *     Timestamp is above median of last MedianTimeBlockCount headers.
+ *     Epoch is the next block of parent epoch, or starts the next epoch.
+ *     Compact target is unchanged within an epoch.
+ *     Pow hash meets compact target, if Pow is set.
+ *
+ * Compact target and length of a new epoch depend on the whole previous
+ * epoch and are checked by NextEpoch, if set.
+ */
+type HeaderChainVerifier struct {
+	// Pow pow engine, nil skips pow check
+	Pow PowEngine
+	// Now local unix time in milliseconds, nil skips future time check
+	Now func() uint64
+	// NextEpoch check first header of a new epoch, nil skips it
+	NextEpoch func(parent *HeaderView, h *HeaderView) error
+}
+
+// VerifyHeaders verify headers in order, ancestors are trusted headers
+// right before them, oldest first, at least the parent of the first
+// header and up to MedianTimeBlockCount of them for the median rule
+func (v *HeaderChainVerifier) VerifyHeaders(ancestors []*HeaderView, headers []*HeaderView) error {
+	if len(ancestors) == 0 {
+		return fmt.Errorf("invalid header chain, no parent of first header")
+	}
+
+	chain := append(append([]*HeaderView{}, ancestors...), headers...)
+	for i := len(ancestors); i < len(chain); i++ {
+		err := v.verifyHeader(chain[:i], chain[i])
+		if err != nil {
+			return fmt.Errorf("invalid header %d %s, %s", chain[i].Number, chain[i].Hash, err)
+		}
+	}
+
+	return nil
+}
+
+// verifyHeader verify header against ancestors, parent last
+func (v *HeaderChainVerifier) verifyHeader(ancestors []*HeaderView, h *HeaderView) error {
+	parent := ancestors[len(ancestors)-1]
+
+	hash, err := h.Header.ComputeHash()
+	if err != nil {
+		return err
+	}
+
+	if hash != h.Hash {
+		return fmt.Errorf("mismatch hash, computed %s", hash)
+	}
+
+	if h.ParentHash != parent.Hash {
+		return fmt.Errorf("parent hash %s does not link to %s", h.ParentHash, parent.Hash)
+	}
+
+	if h.Number != parent.Number+1 {
+		return fmt.Errorf("number does not follow parent %d", parent.Number)
+	}
+
+	median := medianTimestamp(ancestors)
+	if h.Timestamp <= median {
+		return fmt.Errorf("timestamp %d not above median %d", h.Timestamp, median)
+	}
+
+	if v.Now != nil && uint64(h.Timestamp) > v.Now()+AllowedFutureBlockTime {
+		return fmt.Errorf("timestamp %d too far in future", h.Timestamp)
+	}
+
+	err = verifyEpochTransition(parent, h)
+	if err != nil {
+		return err
+	}
+
+	if v.NextEpoch != nil && NewEpochNumberWithFraction(h.Epoch).Index == 0 {
+		err = v.NextEpoch(parent, h)
+		if err != nil {
+			return err
+		}
+	}
+
+	if v.Pow == nil {
+		return nil
+	}
+
+	msg, err := PowMessage(&h.Header)
+	if err != nil {
+		return err
+	}
+
+	out, err := v.Pow.PowHash(msg)
+	if err != nil {
+		return err
+	}
+
+	return CheckPowHash(out, h.CompactTarget)
+}
+
+// verifyEpochTransition check epoch of header follows parent epoch
+func verifyEpochTransition(parent *HeaderView, h *HeaderView) error {
+	pe := NewEpochNumberWithFraction(parent.Epoch)
+	e := NewEpochNumberWithFraction(h.Epoch)
+
+	if !e.IsWellFormed() || e.Length == 0 {
+		return fmt.Errorf("malformed epoch %s", e)
+	}
+
+	if pe.Index+1 < pe.Length {
+		expect := EpochNumberWithFraction{Number: pe.Number, Index: pe.Index + 1, Length: pe.Length}
+		if e != expect {
+			return fmt.Errorf("epoch %s does not follow parent %s, expect %s", e, pe, expect)
+		}
+
+		if h.CompactTarget != parent.CompactTarget {
+			return fmt.Errorf("compact target %#x changed within epoch, parent %#x", uint32(h.CompactTarget), uint32(parent.CompactTarget))
+		}
+
+		return nil
+	}
+
+	if e.Number != pe.Number+1 || e.Index != 0 {
+		return fmt.Errorf("epoch %s does not start epoch after parent %s", e, pe)
+	}
+
+	return nil
+}
+
+// medianTimestamp median timestamp of last MedianTimeBlockCount headers
+func medianTimestamp(headers []*HeaderView) Uint64 {
+	if len(headers) > MedianTimeBlockCount {
+		headers = headers[len(headers)-MedianTimeBlockCount:]
+	}
+
+	ts := make([]Uint64, len(headers))
+	for i := 0; i < len(headers); i++ {
+		ts[i] = headers[i].Timestamp
+	}
+	sort.Slice(ts, func(i, j int) bool { return ts[i] < ts[j] })
+
+	return ts[len(ts)>>1]
+}
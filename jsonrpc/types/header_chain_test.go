@@ -0,0 +1,136 @@
+package types
+
+import (
+	"testing"
+)
+
+type testPow Hash
+
+func (p testPow) PowHash(message []byte) (Hash, error) {
+	return Hash(p), nil
+}
+
+// testHeaderChain headers with epochs of length 3, compact target changes
+// at every epoch start
+func testHeaderChain(count int) []*HeaderView {
+	headers := []*HeaderView{}
+
+	for n := 0; n < count; n++ {
+		h := Header{
+			Number:        Uint64(n),
+			Timestamp:     Uint64(20000 + n),
+			Epoch:         EpochNumberWithFraction{Number: uint64(n / 3), Index: uint64(n % 3), Length: 3}.Uint64(),
+			CompactTarget: Uint32(0x20010000 + n/3),
+		}
+		if n > 0 {
+			h.ParentHash = headers[n-1].Hash
+		}
+
+		hash, _ := h.ComputeHash()
+		headers = append(headers, &HeaderView{Header: h, Hash: hash})
+	}
+
+	return headers
+}
+
+func rehash(h *HeaderView) *HeaderView {
+	h.Hash, _ = h.Header.ComputeHash()
+	return h
+}
+
+func TestVerifyHeaders(t *testing.T) {
+	headers := testHeaderChain(8)
+	v := &HeaderChainVerifier{Pow: testPow{0x00, 0xff}}
+
+	err := v.VerifyHeaders(headers[:1], headers[1:])
+	if err != nil {
+		t.Errorf("fail to verify headers: %s\n", err)
+		return
+	}
+
+	err = v.VerifyHeaders(nil, headers)
+	if err == nil {
+		t.Errorf("expect error without parent")
+		return
+	}
+
+	bad := *headers[4]
+	bad.Nonce = NewUint128(1)
+	err = v.VerifyHeaders(headers[:4], []*HeaderView{&bad})
+	if err == nil {
+		t.Errorf("expect error on mismatch hash")
+		return
+	}
+
+	bad = *headers[4]
+	bad.Timestamp = headers[2].Timestamp
+	err = v.VerifyHeaders(headers[:4], []*HeaderView{rehash(&bad)})
+	if err == nil {
+		t.Errorf("expect error on timestamp below median")
+		return
+	}
+
+	bad = *headers[4]
+	bad.CompactTarget++
+	err = v.VerifyHeaders(headers[:4], []*HeaderView{rehash(&bad)})
+	if err == nil {
+		t.Errorf("expect error on compact target changed within epoch")
+		return
+	}
+
+	bad = *headers[3]
+	bad.Epoch = EpochNumberWithFraction{Number: 0, Index: 3, Length: 4}.Uint64()
+	err = v.VerifyHeaders(headers[:3], []*HeaderView{rehash(&bad)})
+	if err == nil {
+		t.Errorf("expect error on epoch not started")
+		return
+	}
+
+	v.Now = func() uint64 { return 20000 }
+	err = v.VerifyHeaders(headers[:1], headers[1:])
+	if err != nil {
+		t.Errorf("fail to verify headers within future time: %s\n", err)
+		return
+	}
+
+	v.Now = func() uint64 { return 0 }
+	err = v.VerifyHeaders(headers[:1], headers[1:])
+	if err == nil {
+		t.Errorf("expect error on future timestamp")
+		return
+	}
+
+	v = &HeaderChainVerifier{Pow: testPow{0xff}}
+	err = v.VerifyHeaders(headers[:1], headers[1:])
+	if err == nil {
+		t.Errorf("expect error on pow above target")
+		return
+	}
+}
+
+func TestPowMessage(t *testing.T) {
+	h := Header{Nonce: NewUint128(0x0102)}
+
+	msg, err := PowMessage(&h)
+	if err != nil {
+		t.Errorf("fail to build pow message: %s\n", err)
+		return
+	}
+
+	if len(msg) != 48 || msg[32] != 0x02 || msg[33] != 0x01 {
+		t.Errorf("mismatch result, expect pow hash then le nonce, got %x", msg)
+		return
+	}
+
+	err = CheckPowHash(Hash{0x00, 0xff}, 0x20010000)
+	if err != nil {
+		t.Errorf("fail to check pow hash: %s\n", err)
+		return
+	}
+
+	err = CheckPowHash(Hash{}, 0)
+	if err == nil {
+		t.Errorf("expect error on zero target")
+		return
+	}
+}
@@ -0,0 +1,98 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// HeaderDepFetcher chain lookups needed to resolve header deps, usually
+// backed by get_transaction and get_block_hash rpc
+type HeaderDepFetcher interface {
+	// InputCell previous output, its data and the hash of the block it
+	// was committed in
+	InputCell(o OutPoint) (*CellOutput, Bytes, Hash, error)
+	// BlockHash hash of the main chain block with given number
+	BlockHash(number Uint64) (Hash, error)
+}
+
+// RequiredHeaderDeps header deps required by transaction inputs
+/*
+ * DAO inputs are the only ones the node insists on:
+ *
+ *     Deposit cell (data is zero), needs the deposit block header.
+ *     Withdrawing cell (data is deposit block number), needs both the
+ *     withdrawing block header and the deposit block header.
+ *
+ * Result is in input order and has no duplicates.
+ */
+func (t *Transaction) RequiredHeaderDeps(f HeaderDepFetcher) ([]Hash, error) {
+	var deps []Hash
+	seen := make(map[Hash]bool)
+
+	add := func(h Hash) {
+		if !seen[h] {
+			seen[h] = true
+			deps = append(deps, h)
+		}
+	}
+
+	for i := 0; i < len(t.Inputs); i++ {
+		output, data, blockHash, err := f.InputCell(t.Inputs[i].PreviousOutput)
+		if err != nil {
+			return nil, err
+		}
+
+		if SystemScriptName(output.Type) != "dao" {
+			continue
+		}
+
+		if len(data) != uint64Size {
+			return nil, fmt.Errorf("invalid dao cell %s, data should be 8 bytes", t.Inputs[i].PreviousOutput)
+		}
+
+		add(blockHash)
+
+		depositNumber := binary.LittleEndian.Uint64(data)
+		if depositNumber == 0 {
+			continue
+		}
+
+		depositHash, err := f.BlockHash(Uint64(depositNumber))
+		if err != nil {
+			return nil, err
+		}
+
+		add(depositHash)
+	}
+
+	return deps, nil
+}
+
+// ResolveHeaderDeps append missing required header deps to transaction,
+// existing header deps keep their positions
+func (t *Transaction) ResolveHeaderDeps(f HeaderDepFetcher) error {
+	deps, err := t.RequiredHeaderDeps(f)
+	if err != nil {
+		return err
+	}
+
+	t.AppendHeaderDeps(deps...)
+	return nil
+}
+
+// AppendHeaderDeps append header deps, skipping those already present
+func (t *Transaction) AppendHeaderDeps(hashes ...Hash) {
+	for _, h := range hashes {
+		found := false
+		for i := 0; i < len(t.HeaderDeps); i++ {
+			if t.HeaderDeps[i] == h {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.HeaderDeps = append(t.HeaderDeps, h)
+		}
+	}
+}
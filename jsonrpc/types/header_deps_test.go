@@ -0,0 +1,87 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+)
+
+type fakeHeaderDepFetcher struct {
+	cells  map[OutPoint]CellOutput
+	data   map[OutPoint]Bytes
+	blocks map[OutPoint]Hash
+	hashes map[Uint64]Hash
+}
+
+func (f *fakeHeaderDepFetcher) InputCell(o OutPoint) (*CellOutput, Bytes, Hash, error) {
+	c, ok := f.cells[o]
+	if !ok {
+		return nil, nil, Hash{}, fmt.Errorf("unknown cell %s", o)
+	}
+
+	return &c, f.data[o], f.blocks[o], nil
+}
+
+func (f *fakeHeaderDepFetcher) BlockHash(number Uint64) (Hash, error) {
+	h, ok := f.hashes[number]
+	if !ok {
+		return Hash{}, fmt.Errorf("unknown block %d", number)
+	}
+
+	return h, nil
+}
+
+func TestResolveHeaderDeps(t *testing.T) {
+	dao := &Script{CodeHash: DaoCodeHash, HashType: Type, Args: Bytes{}}
+
+	plain := OutPoint{TxHash: Hash{1}, Index: 0}
+	deposit := OutPoint{TxHash: Hash{2}, Index: 0}
+	withdrawing := OutPoint{TxHash: Hash{3}, Index: 1}
+
+	f := &fakeHeaderDepFetcher{
+		cells: map[OutPoint]CellOutput{
+			plain:       {Capacity: 100},
+			deposit:     {Capacity: 100, Type: dao},
+			withdrawing: {Capacity: 100, Type: dao},
+		},
+		data: map[OutPoint]Bytes{
+			deposit:     {0, 0, 0, 0, 0, 0, 0, 0},
+			withdrawing: {0x10, 0, 0, 0, 0, 0, 0, 0},
+		},
+		blocks: map[OutPoint]Hash{
+			plain:       {0xa1},
+			deposit:     {0xa2},
+			withdrawing: {0xa3},
+		},
+		hashes: map[Uint64]Hash{
+			0x10: {0xa2},
+		},
+	}
+
+	tx := &Transaction{
+		HeaderDeps: []Hash{{0xa3}},
+		Inputs: []CellInput{
+			{PreviousOutput: plain},
+			{PreviousOutput: deposit},
+			{PreviousOutput: withdrawing},
+		},
+	}
+
+	err := tx.ResolveHeaderDeps(f)
+	if err != nil {
+		t.Errorf("fail to resolve header deps: %s\n", err)
+		return
+	}
+
+	expect := []Hash{{0xa3}, {0xa2}}
+	if len(tx.HeaderDeps) != len(expect) || tx.HeaderDeps[0] != expect[0] || tx.HeaderDeps[1] != expect[1] {
+		t.Errorf("mismatch result, expect %v, got %v", expect, tx.HeaderDeps)
+		return
+	}
+
+	f.data[deposit] = Bytes{0}
+	_, err = tx.RequiredHeaderDeps(f)
+	if err == nil {
+		t.Errorf("expect error on malformed dao data")
+		return
+	}
+}
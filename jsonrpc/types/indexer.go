@@ -0,0 +1,86 @@
+package types
+
+// ScriptType ckb indexer script type
+type ScriptType string
+
+// SearchMode ckb indexer search mode, for both script and output data
+type SearchMode string
+
+// Enum values
+const (
+	ScriptTypeLock ScriptType = "lock"
+	ScriptTypeType ScriptType = "type"
+
+	Prefix  SearchMode = "prefix"
+	Exact   SearchMode = "exact"
+	Partial SearchMode = "partial"
+)
+
+// Range ckb indexer [start, end) range
+type Range [2]Uint64
+
+// SearchKeyFilter ckb indexer search key filter
+type SearchKeyFilter struct {
+	Script               *Script     `json:"script,omitempty"`
+	ScriptLenRange       *Range      `json:"script_len_range,omitempty"`
+	OutputData           *Bytes      `json:"output_data,omitempty"`
+	OutputDataFilterMode *SearchMode `json:"output_data_filter_mode,omitempty"`
+	OutputDataLenRange   *Range      `json:"output_data_len_range,omitempty"`
+	OutputCapacityRange  *Range      `json:"output_capacity_range,omitempty"`
+	BlockRange           *Range      `json:"block_range,omitempty"`
+}
+
+// SearchKey ckb indexer search key
+type SearchKey struct {
+	Script             Script           `json:"script"`
+	ScriptType         ScriptType       `json:"script_type"`
+	ScriptSearchMode   *SearchMode      `json:"script_search_mode,omitempty"`
+	Filter             *SearchKeyFilter `json:"filter,omitempty"`
+	WithData           *bool            `json:"with_data,omitempty"`
+	GroupByTransaction *bool            `json:"group_by_transaction,omitempty"`
+}
+
+// RequiresRichIndexer report whether search key uses fields only supported
+// by ckb-rich-indexer
+/*
+ * Rich indexer only features:
+ *
+ *     Script search in partial mode.
+ *     Output data filter, in any mode.
+ */
+func (k *SearchKey) RequiresRichIndexer() bool {
+	if k.ScriptSearchMode != nil && *k.ScriptSearchMode == Partial {
+		return true
+	}
+
+	if k.Filter != nil && (k.Filter.OutputData != nil || k.Filter.OutputDataFilterMode != nil) {
+		return true
+	}
+
+	return false
+}
+
+// IndexerTip result of get_indexer_tip
+type IndexerTip struct {
+	BlockHash   Hash   `json:"block_hash"`
+	BlockNumber Uint64 `json:"block_number"`
+}
+
+// Lag blocks indexer is behind node tip, zero if caught up or ahead
+func (t *IndexerTip) Lag(nodeTip *HeaderView) Uint64 {
+	if t.BlockNumber >= nodeTip.Header.Number {
+		return 0
+	}
+
+	return nodeTip.Header.Number - t.BlockNumber
+}
+
+// InSync report whether indexer is within maxLag blocks of node tip and
+// on the same chain when at the same height
+func (t *IndexerTip) InSync(nodeTip *HeaderView, maxLag Uint64) bool {
+	if t.BlockNumber == nodeTip.Header.Number {
+		return t.BlockHash == nodeTip.Hash
+	}
+
+	return t.Lag(nodeTip) <= maxLag
+}
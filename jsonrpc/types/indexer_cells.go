@@ -0,0 +1,194 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+)
+
+// SearchOrder ckb indexer result order
+type SearchOrder string
+
+// Search orders
+const (
+	Asc  SearchOrder = "asc"
+	Desc SearchOrder = "desc"
+)
+
+// DefaultCellsPageSize get_cells limit used by CollectCells
+const DefaultCellsPageSize = 100
+
+// IndexerCell ckb indexer get_cells object
+type IndexerCell struct {
+	Output      CellOutput `json:"output"`
+	OutputData  *Bytes     `json:"output_data,omitempty"`
+	OutPoint    OutPoint   `json:"out_point"`
+	BlockNumber Uint64     `json:"block_number"`
+	TxIndex     Uint32     `json:"tx_index"`
+}
+
+// IndexerCells ckb indexer get_cells result page, fewer objects than limit
+// once pages run out
+type IndexerCells struct {
+	Objects    []IndexerCell `json:"objects"`
+	LastCursor Bytes         `json:"last_cursor"`
+}
+
+// CellsFetcher indexer cells source, usually backed by get_cells rpc,
+// after is nil for first page
+type CellsFetcher interface {
+	GetCells(ctx context.Context, key *SearchKey, order SearchOrder, limit Uint32, after *Bytes) (*IndexerCells, error)
+}
+
+// CellAccumulator cells collected so far and their totals
+type CellAccumulator struct {
+	Cells    []IndexerCell
+	Capacity Uint64
+	// UdtAmount sum of sudt amounts in cell data, cells with data shorter
+	// than an amount count as zero, needs search key with data
+	UdtAmount Uint128
+	// Reached whether goal predicate returned true
+	Reached bool
+}
+
+// CollectOptions CollectCells options, zero value pages ascending by
+// DefaultCellsPageSize and keeps every cell
+type CollectOptions struct {
+	Order    SearchOrder
+	PageSize Uint32
+	// Filter client side filter, only cells it accepts are collected
+	Filter func(*IndexerCell) bool
+}
+
+// CapacityGoal goal predicate of collected capacity at least c
+func CapacityGoal(c Uint64) func(*CellAccumulator) bool {
+	return func(acc *CellAccumulator) bool {
+		return acc.Capacity >= c
+	}
+}
+
+// UdtGoal goal predicate of collected sudt amount at least a
+func UdtGoal(a Uint128) func(*CellAccumulator) bool {
+	return func(acc *CellAccumulator) bool {
+		return acc.UdtAmount.Cmp(a) >= 0
+	}
+}
+
+// CollectCells page through get_cells until goal is reached or cells run
+// out, nil goal collects every cell
+/*
+ * Next page is fetched in background while current one is checked, and
+ * left unfetched once goal is reached. Check Reached of result, running
+ * out of cells before goal is not an error.
+ */
+func CollectCells(ctx context.Context, f CellsFetcher, key *SearchKey, until func(*CellAccumulator) bool, opts *CollectOptions) (*CellAccumulator, error) {
+	o := CollectOptions{Order: Asc, PageSize: DefaultCellsPageSize}
+	if opts != nil {
+		if opts.Order != "" {
+			o.Order = opts.Order
+		}
+		if opts.PageSize != 0 {
+			o.PageSize = opts.PageSize
+		}
+		o.Filter = opts.Filter
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	pages := make(chan cellsPage, 1)
+	go fetchCellPages(ctx, f, key, &o, pages)
+
+	// Stop prefetch and wait for it, so fetcher is not called after return
+	defer func() {
+		cancel()
+		for range pages {
+		}
+	}()
+
+	acc := new(CellAccumulator)
+	for p := range pages {
+		if p.err != nil {
+			return nil, p.err
+		}
+
+		for i := 0; i < len(p.cells.Objects); i++ {
+			c := &p.cells.Objects[i]
+			if o.Filter != nil && !o.Filter(c) {
+				continue
+			}
+
+			err := acc.add(c)
+			if err != nil {
+				return nil, err
+			}
+
+			if until != nil && until(acc) {
+				acc.Reached = true
+				return acc, nil
+			}
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return acc, nil
+}
+
+// cellsPage fetched page or error
+type cellsPage struct {
+	cells *IndexerCells
+	err   error
+}
+
+// fetchCellPages fetch pages into channel until pages run out, error or
+// ctx is done, channel is closed on return
+func fetchCellPages(ctx context.Context, f CellsFetcher, key *SearchKey, o *CollectOptions, pages chan<- cellsPage) {
+	defer close(pages)
+
+	var after *Bytes
+	for {
+		cells, err := f.GetCells(ctx, key, o.Order, o.PageSize, after)
+		if err == nil && cells == nil {
+			err = fmt.Errorf("invalid get_cells result, null")
+		}
+
+		select {
+		case pages <- cellsPage{cells: cells, err: err}:
+		case <-ctx.Done():
+			return
+		}
+
+		// Short page is the last one
+		if err != nil || len(cells.Objects) < int(o.PageSize) {
+			return
+		}
+
+		cursor := cells.LastCursor
+		after = &cursor
+	}
+}
+
+// add add cell to totals
+func (acc *CellAccumulator) add(c *IndexerCell) error {
+	sum, carry := bits.Add64(uint64(acc.Capacity), uint64(c.Output.Capacity), 0)
+	if carry != 0 {
+		return fmt.Errorf("collected capacity overflow")
+	}
+
+	if c.OutputData != nil && len(*c.OutputData) >= SudtAmountSize {
+		amount, err := ParseSudtAmount(*c.OutputData)
+		if err != nil {
+			return err
+		}
+
+		acc.UdtAmount, err = acc.UdtAmount.Add(amount)
+		if err != nil {
+			return fmt.Errorf("collected udt amount overflow")
+		}
+	}
+
+	acc.Capacity = Uint64(sum)
+	acc.Cells = append(acc.Cells, *c)
+	return nil
+}
@@ -0,0 +1,95 @@
+package types
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+)
+
+type testCellsFetcher struct {
+	cells []IndexerCell
+	pages int
+}
+
+func (f *testCellsFetcher) GetCells(ctx context.Context, key *SearchKey, order SearchOrder, limit Uint32, after *Bytes) (*IndexerCells, error) {
+	f.pages++
+
+	start := 0
+	if after != nil {
+		start = int(binary.LittleEndian.Uint32(*after))
+	}
+
+	end := start + int(limit)
+	if end > len(f.cells) {
+		end = len(f.cells)
+	}
+
+	cursor := make(Bytes, 4)
+	binary.LittleEndian.PutUint32(cursor, uint32(end))
+
+	return &IndexerCells{Objects: f.cells[start:end], LastCursor: cursor}, nil
+}
+
+func testIndexerCells(n int) []IndexerCell {
+	cells := make([]IndexerCell, n)
+	for i := 0; i < n; i++ {
+		data := make(Bytes, SudtAmountSize)
+		data[0] = 10
+
+		cells[i] = IndexerCell{
+			Output:     CellOutput{Capacity: Uint64(100 * (i + 1))},
+			OutputData: &data,
+			OutPoint:   OutPoint{TxHash: Hash{byte(i)}},
+		}
+	}
+
+	return cells
+}
+
+func TestCollectCells(t *testing.T) {
+	f := &testCellsFetcher{cells: testIndexerCells(10)}
+	key := &SearchKey{ScriptType: ScriptTypeLock}
+	ctx := context.Background()
+
+	// 100 + 200 + 300 + 400 reaches 1000 in second page of 3
+	acc, err := CollectCells(ctx, f, key, CapacityGoal(1000), &CollectOptions{PageSize: 3})
+	if err != nil {
+		t.Errorf("fail to collect cells: %s\n", err)
+		return
+	}
+
+	if !acc.Reached || len(acc.Cells) != 4 || acc.Capacity != 1000 || acc.UdtAmount != NewUint128(40) {
+		t.Errorf("mismatch result, expect 4 cells of 1000, got %v %v %v", acc.Reached, len(acc.Cells), acc.Capacity)
+		return
+	}
+
+	// Filter skips odd cells, collect everything
+	f.pages = 0
+	acc, err = CollectCells(ctx, f, key, nil, &CollectOptions{
+		PageSize: 4,
+		Filter: func(c *IndexerCell) bool {
+			return c.OutPoint.TxHash[0]%2 == 0
+		},
+	})
+	if err != nil {
+		t.Errorf("fail to collect cells: %s\n", err)
+		return
+	}
+
+	if acc.Reached || len(acc.Cells) != 5 || acc.Capacity != 2500 || f.pages != 3 {
+		t.Errorf("mismatch result, expect 5 even cells in 3 pages, got %v %v %v", len(acc.Cells), acc.Capacity, f.pages)
+		return
+	}
+
+	// Goal out of reach
+	acc, err = CollectCells(ctx, f, key, UdtGoal(NewUint128(1000)), nil)
+	if err != nil {
+		t.Errorf("fail to collect cells: %s\n", err)
+		return
+	}
+
+	if acc.Reached || acc.UdtAmount != NewUint128(100) {
+		t.Errorf("mismatch result, expect goal not reached, got %v %v", acc.Reached, acc.UdtAmount)
+		return
+	}
+}
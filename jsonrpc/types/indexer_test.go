@@ -0,0 +1,77 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestIndexerTip(t *testing.T) {
+	raw := `{
+  "block_hash": "0xa5f5c85987a15de25661e5a214f2c1449cd803f071acc7999820f25246471f40",
+  "block_number": "0x400"
+}`
+
+	var tip IndexerTip
+	strictRoundTrip(t, raw, &tip)
+
+	node := &HeaderView{Header: Header{Number: 0x400}, Hash: tip.BlockHash}
+	if tip.Lag(node) != 0 || !tip.InSync(node, 0) {
+		t.Errorf("expect indexer in sync")
+		return
+	}
+
+	node.Hash = Hash{1}
+	if tip.InSync(node, 10) {
+		t.Errorf("expect indexer on other chain not in sync")
+		return
+	}
+
+	node.Header.Number = 0x40a
+	if tip.Lag(node) != 10 || !tip.InSync(node, 10) || tip.InSync(node, 9) {
+		t.Errorf("mismatch indexer lag, got %v", tip.Lag(node))
+		return
+	}
+}
+
+func TestRequiresRichIndexer(t *testing.T) {
+	prefix, partial := Prefix, Partial
+	data := Bytes{0x01}
+
+	for _, c := range []struct {
+		key  SearchKey
+		rich bool
+	}{
+		{SearchKey{ScriptType: ScriptTypeLock}, false},
+		{SearchKey{ScriptType: ScriptTypeLock, ScriptSearchMode: &prefix}, false},
+		{SearchKey{ScriptType: ScriptTypeLock, ScriptSearchMode: &partial}, true},
+		{SearchKey{ScriptType: ScriptTypeLock, Filter: &SearchKeyFilter{BlockRange: &Range{0, 10}}}, false},
+		{SearchKey{ScriptType: ScriptTypeLock, Filter: &SearchKeyFilter{OutputData: &data}}, true},
+		{SearchKey{ScriptType: ScriptTypeLock, Filter: &SearchKeyFilter{OutputDataFilterMode: &prefix}}, true},
+	} {
+		if c.key.RequiresRichIndexer() != c.rich {
+			t.Errorf("mismatch result of %+v, expect %v, got %v", c.key, c.rich, !c.rich)
+			return
+		}
+	}
+
+	raw := `{
+  "filter": {
+    "output_data": "0x01",
+    "output_data_filter_mode": "partial"
+  },
+  "script": {
+    "args": "0x",
+    "code_hash": "0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8",
+    "hash_type": "type"
+  },
+  "script_search_mode": "partial",
+  "script_type": "lock"
+}`
+
+	var key SearchKey
+	strictRoundTrip(t, raw, &key)
+
+	if !key.RequiresRichIndexer() {
+		t.Errorf("expect decoded key to require rich indexer")
+		return
+	}
+}
@@ -0,0 +1,263 @@
+package types
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// JoyIDAuthMode how JoyID lock is unlocked, first byte of witness lock
+type JoyIDAuthMode byte
+
+// JoyID auth modes
+const (
+	// JoyIDMainKey unlock with passkey hashed in lock args
+	JoyIDMainKey JoyIDAuthMode = 0x01
+	// JoyIDSubKey unlock with passkey registered as sub key, proof is
+	// carried by caller provided CoTA cell dep and witness type fields
+	JoyIDSubKey JoyIDAuthMode = 0x02
+)
+
+// JoyID sizes
+const (
+	// JoyIDLockArgsSize lock args, 0x0001 then blake160 of public key
+	JoyIDLockArgsSize = 2 + Blake160Size
+	// WebAuthnAuthenticatorDataSize authenticator data without extensions,
+	// rp id hash, flags and sign counter
+	WebAuthnAuthenticatorDataSize = 32 + 1 + 4
+
+	// joyIDLockFixedSize auth mode, public key and signature, also the
+	// zero lock placeholder of signing message, since WebAuthn client data
+	// is unknown before signing
+	joyIDLockFixedSize = 1 + R1PubkeySize + R1SignatureSize
+)
+
+// joyIDArgsPrefix secp256r1 key type of JoyID lock args
+var joyIDArgsPrefix = []byte{0x00, 0x01}
+
+// JoyIDLockArgs calculate JoyID lock args of secp256r1 public key, key
+// is hashed from 64 bytes raw form
+func JoyIDLockArgs(pubkey []byte) (Bytes, error) {
+	pub, err := ParseR1Pubkey(pubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	args := append([]byte{}, joyIDArgsPrefix...)
+	return Bytes(append(args, Blake160(R1PubkeyBytes(pub))...)), nil
+}
+
+// JoyIDMessage signing message of JoyID lock for input group, sighash
+// all with zero lock of auth mode, public key and signature size
+func (t *Transaction) JoyIDMessage(group []int) (Hash, error) {
+	return t.sighashAllMessage(group, make(Bytes, joyIDLockFixedSize))
+}
+
+// WebAuthnChallenge WebAuthn challenge of signing message, JoyID signs the
+// lower case hex of message, client data carries it base64url encoded
+func WebAuthnChallenge(msg Hash) []byte {
+	return []byte(hex.EncodeToString(msg[:]))
+}
+
+// WebAuthnAssertion authenticator response of navigator.credentials.get
+type WebAuthnAssertion struct {
+	AuthenticatorData Bytes `json:"authenticator_data"`
+	ClientDataJSON    Bytes `json:"client_data_json"`
+	// Signature DER ecdsa signature as returned by authenticator
+	Signature Bytes `json:"signature"`
+}
+
+// JoyIDWitnessLock JoyID WebAuthn witness lock
+/*
+ *     mode(1) | pubkey(64) | signature(64) | authenticator_data(37) | client_data_json
+ */
+type JoyIDWitnessLock struct {
+	Mode              JoyIDAuthMode
+	Pubkey            Bytes
+	Signature         Bytes
+	AuthenticatorData Bytes
+	ClientDataJSON    Bytes
+}
+
+// NewJoyIDWitnessLock new witness lock from authenticator assertion
+func NewJoyIDWitnessLock(mode JoyIDAuthMode, pubkey []byte, a *WebAuthnAssertion) (*JoyIDWitnessLock, error) {
+	pub, err := ParseR1Pubkey(pubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := R1SignatureFromDER(a.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JoyIDWitnessLock{
+		Mode:              mode,
+		Pubkey:            R1PubkeyBytes(pub),
+		Signature:         sig,
+		AuthenticatorData: a.AuthenticatorData.Clone(),
+		ClientDataJSON:    a.ClientDataJSON.Clone(),
+	}, nil
+}
+
+// Serialize joyid witness lock
+func (l *JoyIDWitnessLock) Serialize() ([]byte, error) {
+	if l.Mode != JoyIDMainKey && l.Mode != JoyIDSubKey {
+		return nil, fmt.Errorf("invalid joyid auth mode 0x%02x", byte(l.Mode))
+	}
+
+	if len(l.Pubkey) != R1PubkeySize {
+		return nil, fmt.Errorf("invalid joyid pubkey, should be %d bytes", R1PubkeySize)
+	}
+
+	if len(l.Signature) != R1SignatureSize {
+		return nil, fmt.Errorf("invalid joyid signature, should be %d bytes", R1SignatureSize)
+	}
+
+	if len(l.AuthenticatorData) != WebAuthnAuthenticatorDataSize {
+		return nil, fmt.Errorf("invalid authenticator data, should be %d bytes", WebAuthnAuthenticatorDataSize)
+	}
+
+	b := new(bytes.Buffer)
+
+	b.WriteByte(byte(l.Mode))
+	b.Write(l.Pubkey)
+	b.Write(l.Signature)
+	b.Write(l.AuthenticatorData)
+	b.Write(l.ClientDataJSON)
+
+	return b.Bytes(), nil
+}
+
+// Deserialize joyid witness lock
+func (l *JoyIDWitnessLock) Deserialize(data []byte) error {
+	if len(data) < joyIDLockFixedSize+WebAuthnAuthenticatorDataSize {
+		return fmt.Errorf("invalid joyid witness lock, too short")
+	}
+
+	mode := JoyIDAuthMode(data[0])
+	if mode != JoyIDMainKey && mode != JoyIDSubKey {
+		return fmt.Errorf("invalid joyid auth mode 0x%02x", data[0])
+	}
+
+	data = data[1:]
+	l.Mode = mode
+	l.Pubkey = Bytes(data[:R1PubkeySize]).Clone()
+
+	data = data[R1PubkeySize:]
+	l.Signature = Bytes(data[:R1SignatureSize]).Clone()
+
+	data = data[R1SignatureSize:]
+	l.AuthenticatorData = Bytes(data[:WebAuthnAuthenticatorDataSize]).Clone()
+	l.ClientDataJSON = Bytes(data[WebAuthnAuthenticatorDataSize:]).Clone()
+
+	return nil
+}
+
+// LockArgs JoyID lock args of witness public key
+func (l *JoyIDWitnessLock) LockArgs() (Bytes, error) {
+	return JoyIDLockArgs(l.Pubkey)
+}
+
+// Verify check witness lock signs message, client data must be a
+// webauthn.get response to the message challenge
+/*
+ * WebAuthn signs sha256(authenticator_data || sha256(client_data_json)).
+ */
+func (l *JoyIDWitnessLock) Verify(msg Hash) error {
+	var clientData struct {
+		Type      string `json:"type"`
+		Challenge string `json:"challenge"`
+	}
+
+	err := json.Unmarshal(l.ClientDataJSON, &clientData)
+	if err != nil {
+		return fmt.Errorf("invalid client data json, %s", err)
+	}
+
+	if clientData.Type != "webauthn.get" {
+		return fmt.Errorf("invalid client data type %s", clientData.Type)
+	}
+
+	challenge, err := base64.RawURLEncoding.DecodeString(clientData.Challenge)
+	if err != nil {
+		return fmt.Errorf("invalid client data challenge, %s", err)
+	}
+
+	if !ConstantTimeEqual(challenge, WebAuthnChallenge(msg)) {
+		return fmt.Errorf("mismatch client data challenge")
+	}
+
+	clientHash := sha256.Sum256(l.ClientDataJSON)
+	digest := sha256.Sum256(append(append([]byte{}, l.AuthenticatorData...), clientHash[:]...))
+
+	if !VerifyR1Signature(l.Pubkey, digest[:], l.Signature) {
+		return fmt.Errorf("invalid joyid signature")
+	}
+
+	return nil
+}
+
+// WebAuthnAuthenticator passkey held by browser or device, JoyID app
+// bridge usually
+type WebAuthnAuthenticator interface {
+	// GetPublicKey secp256r1 public key of credential
+	GetPublicKey() ([]byte, error)
+	// GetAssertion sign challenge with credential
+	GetAssertion(challenge []byte) (*WebAuthnAssertion, error)
+}
+
+// JoyIDSigner signer of JoyID locked input groups
+type JoyIDSigner struct {
+	auth WebAuthnAuthenticator
+	mode JoyIDAuthMode
+}
+
+// NewJoyIDSigner new JoyID signer unlocking with given auth mode
+func NewJoyIDSigner(auth WebAuthnAuthenticator, mode JoyIDAuthMode) (*JoyIDSigner, error) {
+	if mode != JoyIDMainKey && mode != JoyIDSubKey {
+		return nil, fmt.Errorf("invalid joyid auth mode 0x%02x", byte(mode))
+	}
+
+	return &JoyIDSigner{auth: auth, mode: mode}, nil
+}
+
+// SignTransaction sign input group with authenticator and fill lock of
+// the first witness in group
+func (s *JoyIDSigner) SignTransaction(tx *Transaction, group []int) error {
+	msg, err := tx.JoyIDMessage(group)
+	if err != nil {
+		return err
+	}
+
+	pubkey, err := s.auth.GetPublicKey()
+	if err != nil {
+		return err
+	}
+
+	a, err := s.auth.GetAssertion(WebAuthnChallenge(msg))
+	if err != nil {
+		return err
+	}
+
+	l, err := NewJoyIDWitnessLock(s.mode, pubkey, a)
+	if err != nil {
+		return err
+	}
+
+	// Authenticator is out of process, make sure it signed what we asked
+	err = l.Verify(msg)
+	if err != nil {
+		return err
+	}
+
+	lock, err := l.Serialize()
+	if err != nil {
+		return err
+	}
+
+	return tx.SetWitnessLock(group, lock)
+}
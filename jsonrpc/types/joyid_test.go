@@ -0,0 +1,144 @@
+package types
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+type testAuthenticator struct {
+	key *ecdsa.PrivateKey
+	// tamper sign another challenge than requested
+	tamper bool
+}
+
+func (a *testAuthenticator) GetPublicKey() ([]byte, error) {
+	return R1PubkeyBytes(&a.key.PublicKey), nil
+}
+
+func (a *testAuthenticator) GetAssertion(challenge []byte) (*WebAuthnAssertion, error) {
+	if a.tamper {
+		challenge = WebAuthnChallenge(Hash{})
+	}
+
+	authData := make(Bytes, WebAuthnAuthenticatorDataSize)
+	authData[32] = 0x05
+
+	clientData := Bytes(fmt.Sprintf(`{"type":"webauthn.get","challenge":"%s","origin":"https://app.joy.id"}`,
+		base64.RawURLEncoding.EncodeToString(challenge)))
+
+	clientHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientHash[:]...))
+
+	r, s, err := ecdsa.Sign(rand.Reader, a.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebAuthnAssertion{AuthenticatorData: authData, ClientDataJSON: clientData, Signature: der}, nil
+}
+
+func TestJoyIDSigner(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Errorf("fail to generate key: %s\n", err)
+		return
+	}
+
+	auth := &testAuthenticator{key: key}
+
+	s, err := NewJoyIDSigner(auth, JoyIDMainKey)
+	if err != nil {
+		t.Errorf("fail to create signer: %s\n", err)
+		return
+	}
+
+	tx := &Transaction{
+		Inputs:      []CellInput{{PreviousOutput: OutPoint{TxHash: Hash{1}}}},
+		Outputs:     []CellOutput{{Capacity: 61 * ShannonsPerCKB, Lock: Script{HashType: Type, Args: make(Bytes, 20)}}},
+		OutputsData: []Bytes{{}},
+		Witnesses:   []Bytes{{}},
+	}
+
+	msg, err := tx.JoyIDMessage([]int{0})
+	if err != nil {
+		t.Errorf("fail to compute joyid message: %s\n", err)
+		return
+	}
+
+	err = s.SignTransaction(tx, []int{0})
+	if err != nil {
+		t.Errorf("fail to sign transaction: %s\n", err)
+		return
+	}
+
+	w, err := tx.WitnessArgsAt(0)
+	if err != nil {
+		t.Errorf("fail to deserialize witness: %s\n", err)
+		return
+	}
+
+	var l JoyIDWitnessLock
+	err = l.Deserialize(*w.Lock)
+	if err != nil {
+		t.Errorf("fail to deserialize joyid witness lock: %s\n", err)
+		return
+	}
+
+	if l.Mode != JoyIDMainKey {
+		t.Errorf("mismatch result, expect %v, got %v", JoyIDMainKey, l.Mode)
+		return
+	}
+
+	err = l.Verify(msg)
+	if err != nil {
+		t.Errorf("fail to verify joyid witness lock: %s\n", err)
+		return
+	}
+
+	args, err := l.LockArgs()
+	if err != nil {
+		t.Errorf("fail to compute lock args: %s\n", err)
+		return
+	}
+
+	expect, _ := JoyIDLockArgs(R1PubkeyBytes(&key.PublicKey))
+	if len(args) != JoyIDLockArgsSize || args.String() != expect.String() || args[0] != 0x00 || args[1] != 0x01 {
+		t.Errorf("mismatch result, expect %v, got %v", expect, args)
+		return
+	}
+
+	// Signing message does not depend on the lock being signed
+	again, err := tx.JoyIDMessage([]int{0})
+	if err != nil || again != msg {
+		t.Errorf("mismatch result, expect %v, got %v", msg, again)
+		return
+	}
+
+	if l.Verify(Hash{1}) == nil {
+		t.Errorf("expect error on other message")
+		return
+	}
+
+	auth.tamper = true
+	if s.SignTransaction(tx, []int{0}) == nil {
+		t.Errorf("expect error on assertion of other challenge")
+		return
+	}
+
+	if _, err = NewJoyIDSigner(auth, JoyIDAuthMode(0x03)); err == nil {
+		t.Errorf("expect error on unknown auth mode")
+		return
+	}
+}
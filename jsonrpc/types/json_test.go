@@ -0,0 +1,128 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// Recorded get_block response from ckb node
+const recordedBlock = `{
+  "header": {
+    "compact_target": "0x1e083126",
+    "dao": "0xb5a3e047474401001bc476b9ee573000c0c387962a38000000febffacf030000",
+    "epoch": "0x7080018000001",
+    "extra_hash": "0x0000000000000000000000000000000000000000000000000000000000000000",
+    "hash": "0xa5f5c85987a15de25661e5a214f2c1449cd803f071acc7999820f25246471f40",
+    "nonce": "0x0",
+    "number": "0x400",
+    "parent_hash": "0xae003585fa15309b30b31aed3dcf385e9472c3c3e93746a6c4540629a6a1ed2d",
+    "proposals_hash": "0x0000000000000000000000000000000000000000000000000000000000000000",
+    "timestamp": "0x5cd2b117",
+    "transactions_root": "0xc47d5b78b3c4c4c853e2a32810818940d0ee403423bea9ec7b8e566d9595206c",
+    "version": "0x0"
+  },
+  "proposals": [],
+  "transactions": [
+    {
+      "cell_deps": [],
+      "hash": "0x365698b50ca0da75dca2c87f9e7b563811d3b5813736b8cc62cc3b106faceb17",
+      "header_deps": [],
+      "inputs": [
+        {
+          "previous_output": {
+            "index": "0xffffffff",
+            "tx_hash": "0x0000000000000000000000000000000000000000000000000000000000000000"
+          },
+          "since": "0x400"
+        }
+      ],
+      "outputs": [
+        {
+          "capacity": "0x18e64b61cf",
+          "lock": {
+            "code_hash": "0x28e83a1277d48add8e72fadaa9248559e1b632bab2bd60b27955ebc4c03800a5",
+            "hash_type": "data",
+            "args": "0x"
+          },
+          "type": null
+        }
+      ],
+      "outputs_data": [
+        "0x"
+      ],
+      "version": "0x0",
+      "witnesses": [
+        "0x450000000c000000410000003500000010000000300000003100000028e83a1277d48add8e72fadaa9248559e1b632bab2bd60b27955ebc4c03800a5000000000000"
+      ]
+    }
+  ],
+  "uncles": []
+}`
+
+// strictRoundTrip decode json rejecting unknown fields, then check that
+// encoding it again gives the same json
+func strictRoundTrip(t *testing.T, raw string, v interface{}) {
+	dec := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(v)
+	if err != nil {
+		t.Errorf("fail to strictly unmarshal json: %s\n", err)
+		return
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		t.Errorf("fail to marshal: %s\n", err)
+		return
+	}
+
+	var expect, got interface{}
+	json.Unmarshal([]byte(raw), &expect)
+	json.Unmarshal(encoded, &got)
+
+	if !reflect.DeepEqual(expect, got) {
+		t.Errorf("mismatch round trip, expect %s, got %s", raw, encoded)
+		return
+	}
+}
+
+func TestBlockViewJSON(t *testing.T) {
+	var b BlockView
+	strictRoundTrip(t, recordedBlock, &b)
+
+	// All fields must be decoded to get the node's hash back
+	h, err := b.Transactions[0].ComputeHash()
+	if err != nil {
+		t.Errorf("fail to compute hash: %s\n", err)
+		return
+	}
+
+	if h != b.Transactions[0].Hash {
+		t.Errorf("mismatch transaction hash, expect %v, got %v", b.Transactions[0].Hash, h)
+		return
+	}
+}
+
+func TestHeaderHash(t *testing.T) {
+	var b BlockView
+
+	err := json.Unmarshal([]byte(recordedBlock), &b)
+	if err != nil {
+		t.Errorf("fail to unmarshal block json: %s\n", err)
+		return
+	}
+
+	h, err := b.Header.ComputeHash()
+	if err != nil {
+		t.Errorf("fail to compute hash: %s\n", err)
+		return
+	}
+
+	if h != b.Header.Hash {
+		t.Errorf("mismatch header hash, expect %v, got %v", b.Header.Hash, h)
+		return
+	}
+}
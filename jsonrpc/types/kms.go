@@ -0,0 +1,143 @@
+package types
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+)
+
+// oidSecp256k1 secp256k1 named curve object identifier
+var oidSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+
+// DERSigner key held by cloud kms, AWS KMS Sign and GCP KMS
+// AsymmetricSign both return ASN.1 DER ecdsa signatures without recovery id
+type DERSigner interface {
+	SignDER(digest Hash) ([]byte, error)
+}
+
+// KMSSigner RemoteSigner adapter for cloud kms secp256k1 keys
+type KMSSigner struct {
+	der    DERSigner
+	pubkey []byte
+}
+
+// NewKMSSigner new kms signer, pubkey is compressed, uncompressed or DER
+// SubjectPublicKeyInfo as returned by kms GetPublicKey
+func NewKMSSigner(der DERSigner, pubkey []byte) (*KMSSigner, error) {
+	if len(pubkey) > 0 && pubkey[0] == 0x30 {
+		var err error
+		pubkey, err = ParsePKIXPubkey(pubkey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	compressed, err := CompressPubkey(pubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KMSSigner{der: der, pubkey: compressed}, nil
+}
+
+// GetPublicKey compressed public key
+func (s *KMSSigner) GetPublicKey() ([]byte, error) {
+	return append([]byte{}, s.pubkey...), nil
+}
+
+// SignDigest sign digest with kms and convert to recoverable signature
+func (s *KMSSigner) SignDigest(digest Hash) ([]byte, error) {
+	der, err := s.der.SignDER(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return RecoverableSignatureFromDER(digest, der, s.pubkey)
+}
+
+// SignTransaction sign input group with sighash all
+func (s *KMSSigner) SignTransaction(tx *Transaction, group []int) error {
+	return SignSighashAll(s, tx, group)
+}
+
+// ParsePKIXPubkey parse DER SubjectPublicKeyInfo of secp256k1 key into
+// uncompressed public key, x509 package does not know the curve
+func ParsePKIXPubkey(der []byte) ([]byte, error) {
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+
+	rest, err := asn1.Unmarshal(der, &spki)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key info, %s", err)
+	}
+
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("invalid public key info, trailing data")
+	}
+
+	var curve asn1.ObjectIdentifier
+	_, err = asn1.Unmarshal(spki.Algorithm.Parameters.FullBytes, &curve)
+	if err != nil || !curve.Equal(oidSecp256k1) {
+		return nil, fmt.Errorf("invalid public key info, not a secp256k1 key")
+	}
+
+	return spki.PublicKey.RightAlign(), nil
+}
+
+// RecoverableSignatureFromDER convert DER ecdsa signature into 65 bytes
+// recoverable signature
+/*
+ * There are three steps:
+ *
+ *     Parse r and s from DER.
+ *     Normalize s to lower half of curve order, ckb rejects high s.
+ *     Find recovery id by recovering pubkey and comparing to signer.
+ */
+func RecoverableSignatureFromDER(digest Hash, der []byte, pubkey []byte) ([]byte, error) {
+	var rs struct {
+		R, S *big.Int
+	}
+
+	rest, err := asn1.Unmarshal(der, &rs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid der signature, %s", err)
+	}
+
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("invalid der signature, trailing data")
+	}
+
+	n := secp256k1.S256().Params().N
+	if rs.R.Sign() <= 0 || rs.S.Sign() <= 0 || rs.R.Cmp(n) >= 0 || rs.S.Cmp(n) >= 0 {
+		return nil, fmt.Errorf("invalid der signature, r or s out of range")
+	}
+
+	if rs.S.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		rs.S = new(big.Int).Sub(n, rs.S)
+	}
+
+	compressed, err := CompressPubkey(pubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	sig := make([]byte, SignatureSize)
+	copy(sig[32-len(rs.R.Bytes()):32], rs.R.Bytes())
+	copy(sig[64-len(rs.S.Bytes()):64], rs.S.Bytes())
+
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+
+		recovered, err := RecoverPubkey(digest, sig)
+		if err == nil && ConstantTimeEqual(recovered, compressed) {
+			return sig, nil
+		}
+	}
+
+	return nil, fmt.Errorf("invalid der signature, not signed by given public key")
+}
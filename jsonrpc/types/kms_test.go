@@ -0,0 +1,85 @@
+package types
+
+import (
+	"bytes"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+)
+
+// fakeDERSigner sign with local key and return high s DER signature, the
+// worst case kms may return
+type fakeDERSigner struct {
+	key *KeySigner
+}
+
+func (f *fakeDERSigner) SignDER(digest Hash) ([]byte, error) {
+	sig, err := f.key.SignDigest(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	n := secp256k1.S256().Params().N
+	s := new(big.Int).Sub(n, new(big.Int).SetBytes(sig[32:64]))
+
+	return asn1.Marshal(struct{ R, S *big.Int }{new(big.Int).SetBytes(sig[:32]), s})
+}
+
+func TestKMSSigner(t *testing.T) {
+	seckey := make([]byte, 32)
+	seckey[31] = 1
+
+	key, err := NewKeySigner(seckey)
+	if err != nil {
+		t.Errorf("fail to create signer: %s\n", err)
+		return
+	}
+
+	pub, _ := key.GetPublicKey()
+	uncompressed, _ := DecompressPubkey(pub)
+
+	params, _ := asn1.Marshal(oidSecp256k1)
+	spki, err := asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1},
+			Parameters: asn1.RawValue{FullBytes: params},
+		},
+		PublicKey: asn1.BitString{Bytes: uncompressed, BitLength: len(uncompressed) * 8},
+	})
+	if err != nil {
+		t.Errorf("fail to marshal public key info: %s\n", err)
+		return
+	}
+
+	s, err := NewKMSSigner(&fakeDERSigner{key: key}, spki)
+	if err != nil {
+		t.Errorf("fail to create kms signer: %s\n", err)
+		return
+	}
+
+	kmsPub, _ := s.GetPublicKey()
+	if !bytes.Equal(kmsPub, pub) {
+		t.Errorf("mismatch public key, expect %x, got %x", pub, kmsPub)
+		return
+	}
+
+	digest := Hash{1, 2, 3}
+	expect, _ := key.SignDigest(digest)
+
+	sig, err := s.SignDigest(digest)
+	if err != nil {
+		t.Errorf("fail to sign digest: %s\n", err)
+		return
+	}
+
+	if !bytes.Equal(sig, expect) {
+		t.Errorf("mismatch result, expect %x, got %x", expect, sig)
+		return
+	}
+}
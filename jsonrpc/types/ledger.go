@@ -0,0 +1,240 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LedgerTransport exchange of one apdu with device, usb hid or a
+// speculos emulator, response includes the 2 bytes status word
+type LedgerTransport interface {
+	Exchange(apdu []byte) ([]byte, error)
+}
+
+// Ledger ckb app apdu
+/*
+ * Command apdu:
+ *
+ *     cla ins p1 p2 lc data
+ *
+ * Lc is one byte, data at most 255 bytes. Response is data followed by
+ * status word, 0x9000 on success.
+ *
+ * Signing streams its payload: first chunk is bip32 path with p1 0x00,
+ * last chunk has 0x80 set in p1, chunks between have p1 0x01.
+ */
+const (
+	ledgerCLA = 0x80
+
+	LedgerInsGetAppVersion   = 0x00
+	LedgerInsGetPublicKey    = 0x02
+	LedgerInsSignMessageHash = 0x07
+
+	ledgerP1First = 0x00
+	ledgerP1More  = 0x01
+	ledgerP1Last  = 0x80
+
+	ledgerMaxData = 255
+)
+
+// Ledger status words
+const (
+	LedgerSWOK             = 0x9000
+	LedgerSWUserRejected   = 0x6985
+	LedgerSWInsNotSupport  = 0x6d00
+	LedgerSWAppNotOpen     = 0x6e00
+	LedgerSWDeviceLocked   = 0x5515
+	ledgerStatusWordLength = 2
+)
+
+// LedgerPathHardened bip32 hardened index flag
+const LedgerPathHardened = uint32(0x80000000)
+
+// DefaultLedgerPath ckb bip44 path of first address, m/44'/309'/0'/0/0
+var DefaultLedgerPath = []uint32{44 | LedgerPathHardened, 309 | LedgerPathHardened, LedgerPathHardened, 0, 0}
+
+// LedgerError ledger status word other than success
+type LedgerError struct {
+	SW uint16
+}
+
+// Error implement error
+func (e *LedgerError) Error() string {
+	switch e.SW {
+	case LedgerSWUserRejected:
+		return "ledger user rejected"
+	case LedgerSWInsNotSupport, LedgerSWAppNotOpen:
+		return fmt.Sprintf("ledger ckb app not open, status 0x%04x", e.SW)
+	case LedgerSWDeviceLocked:
+		return "ledger device locked"
+	default:
+		return fmt.Sprintf("ledger status 0x%04x", e.SW)
+	}
+}
+
+// LedgerAPDU encode command apdu
+func LedgerAPDU(ins byte, p1 byte, p2 byte, data []byte) ([]byte, error) {
+	if len(data) > ledgerMaxData {
+		return nil, fmt.Errorf("invalid apdu, %d bytes data exceed %d", len(data), ledgerMaxData)
+	}
+
+	apdu := make([]byte, 0, 5+len(data))
+	apdu = append(apdu, ledgerCLA, ins, p1, p2, byte(len(data)))
+	return append(apdu, data...), nil
+}
+
+// ParseLedgerResponse split response into data and status word, error if
+// status is not success
+func ParseLedgerResponse(resp []byte) ([]byte, error) {
+	if len(resp) < ledgerStatusWordLength {
+		return nil, fmt.Errorf("invalid ledger response, %d bytes", len(resp))
+	}
+
+	n := len(resp) - ledgerStatusWordLength
+	sw := binary.BigEndian.Uint16(resp[n:])
+	if sw != LedgerSWOK {
+		return nil, &LedgerError{SW: sw}
+	}
+
+	return resp[:n], nil
+}
+
+// ParseLedgerPath parse bip32 path like m/44'/309'/0'/0/0
+func ParseLedgerPath(s string) ([]uint32, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) < 2 || parts[0] != "m" {
+		return nil, fmt.Errorf("invalid bip32 path %q, should start with m/", s)
+	}
+
+	path := make([]uint32, 0, len(parts)-1)
+	for _, p := range parts[1:] {
+		hardened := strings.HasSuffix(p, "'")
+		n, err := strconv.ParseUint(strings.TrimSuffix(p, "'"), 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bip32 path %q, bad index %q", s, p)
+		}
+
+		i := uint32(n)
+		if hardened {
+			i |= LedgerPathHardened
+		}
+		path = append(path, i)
+	}
+
+	return path, nil
+}
+
+// encodeLedgerPath path as count then big-endian indexes
+func encodeLedgerPath(path []uint32) ([]byte, error) {
+	if len(path) == 0 || len(path) > 10 {
+		return nil, fmt.Errorf("invalid bip32 path, %d levels", len(path))
+	}
+
+	b := make([]byte, 1+4*len(path))
+	b[0] = byte(len(path))
+	for i, n := range path {
+		binary.BigEndian.PutUint32(b[1+4*i:], n)
+	}
+
+	return b, nil
+}
+
+// LedgerSigner RemoteSigner of key held by ledger ckb app
+/*
+ * SignDigest asks device to sign message hash, blind signing, which has
+ * to be allowed in app settings. Signatures are checked to recover to
+ * device public key before use, so a wrong path or device fails here
+ * rather than on chain.
+ */
+type LedgerSigner struct {
+	transport LedgerTransport
+	path      []uint32
+	pubkey    []byte
+}
+
+// NewLedgerSigner new ledger signer of key at bip32 path, DefaultLedgerPath
+// if nil, public key is read from device
+func NewLedgerSigner(t LedgerTransport, path []uint32) (*LedgerSigner, error) {
+	if path == nil {
+		path = DefaultLedgerPath
+	}
+
+	s := &LedgerSigner{transport: t, path: append([]uint32{}, path...)}
+
+	p, err := encodeLedgerPath(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.exchange(LedgerInsGetPublicKey, 0, p)
+	if err != nil {
+		return nil, err
+	}
+
+	// Response is key length then key
+	if len(data) == 0 || int(data[0]) != len(data)-1 {
+		return nil, fmt.Errorf("invalid ledger public key response, %d bytes", len(data))
+	}
+
+	s.pubkey, err = CompressPubkey(data[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// GetPublicKey compressed public key
+func (s *LedgerSigner) GetPublicKey() ([]byte, error) {
+	return append([]byte{}, s.pubkey...), nil
+}
+
+// SignDigest sign digest on device
+func (s *LedgerSigner) SignDigest(digest Hash) ([]byte, error) {
+	p, err := encodeLedgerPath(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.exchange(LedgerInsSignMessageHash, ledgerP1First, p)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := s.exchange(LedgerInsSignMessageHash, ledgerP1Last, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sig) != SignatureSize {
+		return nil, fmt.Errorf("invalid ledger signature, should be %d bytes, got %d", SignatureSize, len(sig))
+	}
+
+	recovered, err := RecoverPubkey(digest, sig)
+	if err != nil || !ConstantTimeEqual(recovered, s.pubkey) {
+		return nil, fmt.Errorf("invalid ledger signature, not signed by key at path")
+	}
+
+	return sig, nil
+}
+
+// SignTransaction sign input group with sighash all
+func (s *LedgerSigner) SignTransaction(tx *Transaction, group []int) error {
+	return SignSighashAll(s, tx, group)
+}
+
+func (s *LedgerSigner) exchange(ins byte, p1 byte, data []byte) ([]byte, error) {
+	apdu, err := LedgerAPDU(ins, p1, 0, data)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.transport.Exchange(apdu)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseLedgerResponse(resp)
+}
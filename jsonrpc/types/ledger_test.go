@@ -0,0 +1,228 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// fakeLedger ckb app speaking apdu, signs with local key
+type fakeLedger struct {
+	key    *KeySigner
+	apdus  [][]byte
+	path   []byte
+	sw     uint16
+	badSig bool
+}
+
+func (f *fakeLedger) Exchange(apdu []byte) ([]byte, error) {
+	f.apdus = append(f.apdus, append([]byte{}, apdu...))
+
+	if len(apdu) < 5 || int(apdu[4]) != len(apdu)-5 || apdu[0] != ledgerCLA {
+		return nil, fmt.Errorf("malformed apdu %x", apdu)
+	}
+
+	if f.sw != 0 {
+		return []byte{byte(f.sw >> 8), byte(f.sw)}, nil
+	}
+
+	ok := []byte{0x90, 0x00}
+	data := apdu[5:]
+	switch apdu[1] {
+	case LedgerInsGetPublicKey:
+		pub, _ := f.key.GetPublicKey()
+		uncompressed, _ := DecompressPubkey(pub)
+		return append(append([]byte{byte(len(uncompressed))}, uncompressed...), ok...), nil
+	case LedgerInsSignMessageHash:
+		if apdu[2] == ledgerP1First {
+			f.path = append([]byte{}, data...)
+			return ok, nil
+		}
+
+		var digest Hash
+		copy(digest[:], data)
+		if f.badSig {
+			digest[0] ^= 0xff
+		}
+
+		sig, _ := f.key.SignDigest(digest)
+		return append(sig, ok...), nil
+	default:
+		return []byte{0x6d, 0x00}, nil
+	}
+}
+
+func TestLedgerAPDU(t *testing.T) {
+	apdu, err := LedgerAPDU(LedgerInsGetPublicKey, 0x01, 0x02, []byte{0xaa, 0xbb})
+	if err != nil {
+		t.Errorf("fail to encode apdu: %s\n", err)
+		return
+	}
+
+	expect := []byte{0x80, 0x02, 0x01, 0x02, 0x02, 0xaa, 0xbb}
+	if !bytes.Equal(apdu, expect) {
+		t.Errorf("mismatch result, expect %x, got %x", expect, apdu)
+		return
+	}
+
+	_, err = LedgerAPDU(LedgerInsGetPublicKey, 0, 0, make([]byte, 256))
+	if err == nil {
+		t.Errorf("expect error on oversized data")
+		return
+	}
+
+	data, err := ParseLedgerResponse([]byte{0x01, 0x90, 0x00})
+	if err != nil || !bytes.Equal(data, []byte{0x01}) {
+		t.Errorf("fail to parse response: %v %x\n", err, data)
+		return
+	}
+
+	_, err = ParseLedgerResponse([]byte{0x69, 0x85})
+	if e, ok := err.(*LedgerError); !ok || e.SW != LedgerSWUserRejected {
+		t.Errorf("mismatch result, expect user rejected, got %v", err)
+		return
+	}
+
+	_, err = ParseLedgerResponse([]byte{0x90})
+	if err == nil {
+		t.Errorf("expect error on short response")
+		return
+	}
+}
+
+func TestParseLedgerPath(t *testing.T) {
+	path, err := ParseLedgerPath("m/44'/309'/0'/0/0")
+	if err != nil {
+		t.Errorf("fail to parse path: %s\n", err)
+		return
+	}
+
+	if len(path) != len(DefaultLedgerPath) {
+		t.Errorf("mismatch result, expect %v, got %v", DefaultLedgerPath, path)
+		return
+	}
+
+	for i := range path {
+		if path[i] != DefaultLedgerPath[i] {
+			t.Errorf("mismatch result, expect %v, got %v", DefaultLedgerPath, path)
+			return
+		}
+	}
+
+	for _, s := range []string{"44'/309'", "m/x", "m/2147483648", "m/1''"} {
+		_, err = ParseLedgerPath(s)
+		if err == nil {
+			t.Errorf("expect error on path %q", s)
+			return
+		}
+	}
+}
+
+func TestLedgerSigner(t *testing.T) {
+	seckey := make([]byte, 32)
+	seckey[31] = 1
+
+	key, err := NewKeySigner(seckey)
+	if err != nil {
+		t.Errorf("fail to create signer: %s\n", err)
+		return
+	}
+
+	dev := &fakeLedger{key: key}
+	s, err := NewLedgerSigner(dev, nil)
+	if err != nil {
+		t.Errorf("fail to create ledger signer: %s\n", err)
+		return
+	}
+
+	pub, _ := s.GetPublicKey()
+	expect, _ := key.GetPublicKey()
+	if !bytes.Equal(pub, expect) {
+		t.Errorf("mismatch result, expect %x, got %x", expect, pub)
+		return
+	}
+
+	// Count then big-endian indexes
+	p := []byte{0x05, 0x80, 0, 0, 44, 0x80, 0, 0x01, 0x35, 0x80, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	if !bytes.Equal(dev.apdus[0], append([]byte{0x80, 0x02, 0x00, 0x00, byte(len(p))}, p...)) {
+		t.Errorf("mismatch get public key apdu, got %x", dev.apdus[0])
+		return
+	}
+
+	digest := Hash{0x01, 0x02}
+	sig, err := s.SignDigest(digest)
+	if err != nil {
+		t.Errorf("fail to sign digest: %s\n", err)
+		return
+	}
+
+	if !VerifySignature(pub, digest, sig) {
+		t.Errorf("mismatch result, signature not verified")
+		return
+	}
+
+	last := dev.apdus[len(dev.apdus)-1]
+	if !bytes.Equal(dev.path, p) || last[2] != ledgerP1Last || !bytes.Equal(last[5:], digest[:]) {
+		t.Errorf("mismatch sign apdus, got %x", dev.apdus)
+		return
+	}
+
+	dev.badSig = true
+	_, err = s.SignDigest(digest)
+	if err == nil {
+		t.Errorf("expect error on signature by other key")
+		return
+	}
+
+	dev.badSig = false
+	dev.sw = LedgerSWUserRejected
+	_, err = s.SignDigest(digest)
+	if e, ok := err.(*LedgerError); !ok || e.SW != LedgerSWUserRejected {
+		t.Errorf("mismatch result, expect user rejected, got %v", err)
+		return
+	}
+
+	_, err = NewLedgerSigner(dev, []uint32{})
+	if err == nil {
+		t.Errorf("expect error on empty path")
+		return
+	}
+}
+
+func TestLedgerSignerTransaction(t *testing.T) {
+	seckey := make([]byte, 32)
+	seckey[31] = 2
+
+	key, _ := NewKeySigner(seckey)
+	s, err := NewLedgerSigner(&fakeLedger{key: key}, DefaultLedgerPath)
+	if err != nil {
+		t.Errorf("fail to create ledger signer: %s\n", err)
+		return
+	}
+
+	tx := &Transaction{
+		Inputs:    []CellInput{{}},
+		Witnesses: []Bytes{{}},
+	}
+
+	err = s.SignTransaction(tx, []int{0})
+	if err != nil {
+		t.Errorf("fail to sign transaction: %s\n", err)
+		return
+	}
+
+	expect := &Transaction{
+		Inputs:    []CellInput{{}},
+		Witnesses: []Bytes{{}},
+	}
+	err = key.SignTransaction(expect, []int{0})
+	if err != nil {
+		t.Errorf("fail to sign transaction locally: %s\n", err)
+		return
+	}
+
+	if !bytes.Equal(tx.Witnesses[0], expect.Witnesses[0]) {
+		t.Errorf("mismatch result, expect %x, got %x", expect.Witnesses[0], tx.Witnesses[0])
+		return
+	}
+}
@@ -0,0 +1,116 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SetScriptsCommand ckb light client set_scripts command
+type SetScriptsCommand string
+
+// Enum values
+const (
+	// SetScriptsAll replace all watched scripts, the default
+	SetScriptsAll SetScriptsCommand = "all"
+	// SetScriptsPartial add or update given scripts, others are kept
+	SetScriptsPartial SetScriptsCommand = "partial"
+	// SetScriptsDelete remove given scripts, block number is ignored
+	SetScriptsDelete SetScriptsCommand = "delete"
+)
+
+// ScriptStatus ckb light client watched script, synced from block number
+type ScriptStatus struct {
+	Script      Script     `json:"script"`
+	ScriptType  ScriptType `json:"script_type"`
+	BlockNumber Uint64     `json:"block_number"`
+}
+
+// SetScriptsParams params of set_scripts
+/*
+ * Sent as positional params, command is left out if nil so older light
+ * clients, which replace all, accept it:
+ *
+ *     [[ScriptStatus], SetScriptsCommand]
+ */
+type SetScriptsParams struct {
+	Scripts []ScriptStatus
+	Command *SetScriptsCommand
+}
+
+// MarshalJSON marshal set scripts params to json array
+func (p SetScriptsParams) MarshalJSON() ([]byte, error) {
+	scripts := p.Scripts
+	if scripts == nil {
+		scripts = []ScriptStatus{}
+	}
+
+	if p.Command == nil {
+		return json.Marshal([]interface{}{scripts})
+	}
+
+	return json.Marshal([]interface{}{scripts, *p.Command})
+}
+
+// UnmarshalJSON unmarshal set scripts params from json array
+func (p *SetScriptsParams) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	if len(raw) != 1 && len(raw) != 2 {
+		return fmt.Errorf("invalid set scripts params, should be 1 or 2 elements array")
+	}
+
+	err = json.Unmarshal(raw[0], &p.Scripts)
+	if err != nil {
+		return err
+	}
+
+	p.Command = nil
+	if len(raw) == 2 {
+		p.Command = new(SetScriptsCommand)
+		return json.Unmarshal(raw[1], p.Command)
+	}
+
+	return nil
+}
+
+// ApplySetScripts watched scripts after set_scripts, as the light client
+// computes them, scripts are matched by script and script type
+func ApplySetScripts(current []ScriptStatus, scripts []ScriptStatus, cmd SetScriptsCommand) ([]ScriptStatus, error) {
+	switch cmd {
+	case SetScriptsAll:
+		return append([]ScriptStatus{}, scripts...), nil
+	case SetScriptsPartial, SetScriptsDelete:
+	default:
+		return nil, fmt.Errorf("invalid set scripts command %s", cmd)
+	}
+
+	result := []ScriptStatus{}
+	for _, c := range current {
+		if indexOfScriptStatus(scripts, &c) == -1 {
+			result = append(result, c)
+		}
+	}
+
+	if cmd == SetScriptsPartial {
+		result = append(result, scripts...)
+	}
+
+	return result, nil
+}
+
+// indexOfScriptStatus index of status matching script and script type, -1
+// if not found
+func indexOfScriptStatus(statuses []ScriptStatus, s *ScriptStatus) int {
+	for i := 0; i < len(statuses); i++ {
+		if statuses[i].ScriptType == s.ScriptType && statuses[i].Script.Equal(&s.Script) {
+			return i
+		}
+	}
+
+	return -1
+}
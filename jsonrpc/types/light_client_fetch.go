@@ -0,0 +1,166 @@
+package types
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// FetchStatus ckb light client fetch_header and fetch_transaction status
+type FetchStatus string
+
+// Enum values
+const (
+	// FetchStatusFetched data is returned
+	FetchStatusFetched FetchStatus = "fetched"
+	// FetchStatusFetching request was sent to peers at first_sent
+	FetchStatusFetching FetchStatus = "fetching"
+	// FetchStatusAdded request was queued at timestamp
+	FetchStatusAdded FetchStatus = "added"
+	// FetchStatusNotFound no peer has it, asking again queues a new request
+	FetchStatusNotFound FetchStatus = "not_found"
+)
+
+// FetchHeaderResponse result of fetch_header
+type FetchHeaderResponse struct {
+	Status    FetchStatus `json:"status"`
+	Data      *HeaderView `json:"data,omitempty"`
+	FirstSent *Uint64     `json:"first_sent,omitempty"`
+	Timestamp *Uint64     `json:"timestamp,omitempty"`
+}
+
+// UnmarshalJSON unmarshal fetch header response, fetched must carry data
+func (r *FetchHeaderResponse) UnmarshalJSON(data []byte) error {
+	type response FetchHeaderResponse
+
+	err := json.Unmarshal(data, (*response)(r))
+	if err != nil {
+		return err
+	}
+
+	return checkFetchResponse(r.Status, r.Data != nil, r.FirstSent, r.Timestamp)
+}
+
+// FetchTransactionResponse result of fetch_transaction
+type FetchTransactionResponse struct {
+	Status    FetchStatus            `json:"status"`
+	Data      *TransactionWithStatus `json:"data,omitempty"`
+	FirstSent *Uint64                `json:"first_sent,omitempty"`
+	Timestamp *Uint64                `json:"timestamp,omitempty"`
+}
+
+// UnmarshalJSON unmarshal fetch transaction response, fetched must carry
+// data
+func (r *FetchTransactionResponse) UnmarshalJSON(data []byte) error {
+	type response FetchTransactionResponse
+
+	err := json.Unmarshal(data, (*response)(r))
+	if err != nil {
+		return err
+	}
+
+	return checkFetchResponse(r.Status, r.Data != nil, r.FirstSent, r.Timestamp)
+}
+
+// FetchHeaderFetcher light client fetch_header
+type FetchHeaderFetcher interface {
+	FetchHeader(ctx context.Context, hash Hash) (*FetchHeaderResponse, error)
+}
+
+// FetchTransactionFetcher light client fetch_transaction
+type FetchTransactionFetcher interface {
+	FetchTransaction(ctx context.Context, hash Hash) (*FetchTransactionResponse, error)
+}
+
+// WaitFetchedHeader call fetch_header every interval until header is
+// fetched, use ctx deadline as timeout
+func WaitFetchedHeader(ctx context.Context, f FetchHeaderFetcher, hash Hash, interval time.Duration) (*HeaderView, error) {
+	var header *HeaderView
+
+	err := pollFetch(ctx, hash, interval, func() (FetchStatus, error) {
+		r, err := f.FetchHeader(ctx, hash)
+		if err != nil {
+			return "", err
+		}
+
+		header = r.Data
+		return r.Status, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+// WaitFetchedTransaction call fetch_transaction every interval until
+// transaction is fetched, use ctx deadline as timeout
+func WaitFetchedTransaction(ctx context.Context, f FetchTransactionFetcher, hash Hash, interval time.Duration) (*TransactionWithStatus, error) {
+	var tx *TransactionWithStatus
+
+	err := pollFetch(ctx, hash, interval, func() (FetchStatus, error) {
+		r, err := f.FetchTransaction(ctx, hash)
+		if err != nil {
+			return "", err
+		}
+
+		tx = r.Data
+		return r.Status, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// pollFetch call fetch until fetched, not found is an error since asking
+// again only starts over
+func pollFetch(ctx context.Context, hash Hash, interval time.Duration, fetch func() (FetchStatus, error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		status, err := fetch()
+		if err != nil {
+			return err
+		}
+
+		switch status {
+		case FetchStatusFetched:
+			return nil
+		case FetchStatusNotFound:
+			return fmt.Errorf("fetch %s not found", hash)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("fetch %s %s, %s", hash, status, ctx.Err())
+		}
+	}
+}
+
+// checkFetchResponse check fields required by status are present
+func checkFetchResponse(status FetchStatus, hasData bool, firstSent *Uint64, timestamp *Uint64) error {
+	switch status {
+	case FetchStatusFetched:
+		if !hasData {
+			return fmt.Errorf("invalid fetch response, fetched without data")
+		}
+	case FetchStatusFetching:
+		if firstSent == nil {
+			return fmt.Errorf("invalid fetch response, fetching without first_sent")
+		}
+	case FetchStatusAdded:
+		if timestamp == nil {
+			return fmt.Errorf("invalid fetch response, added without timestamp")
+		}
+	case FetchStatusNotFound:
+	default:
+		return fmt.Errorf("invalid fetch response, unknown status %s", status)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package types
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type testFetchHeader []string
+
+func (f *testFetchHeader) FetchHeader(ctx context.Context, hash Hash) (*FetchHeaderResponse, error) {
+	payload := (*f)[0]
+	if len(*f) > 1 {
+		*f = (*f)[1:]
+	}
+
+	var r FetchHeaderResponse
+	err := json.Unmarshal([]byte(payload), &r)
+	return &r, err
+}
+
+func TestUnmarshalFetchResponse(t *testing.T) {
+	var r FetchTransactionResponse
+
+	err := json.Unmarshal([]byte(`{"status": "fetching", "first_sent": "0x18d7b7d1bb1"}`), &r)
+	if err != nil {
+		t.Errorf("fail to unmarshal fetch response: %s\n", err)
+		return
+	}
+
+	if r.Status != FetchStatusFetching || r.FirstSent == nil || *r.FirstSent != 0x18d7b7d1bb1 {
+		t.Errorf("mismatch result, got %v", r)
+		return
+	}
+
+	for _, payload := range []string{`{"status": "fetched"}`, `{"status": "added"}`, `{"status": "done"}`} {
+		err = json.Unmarshal([]byte(payload), &r)
+		if err == nil {
+			t.Errorf("expect error on %s", payload)
+			return
+		}
+	}
+}
+
+func TestWaitFetchedHeader(t *testing.T) {
+	f := &testFetchHeader{
+		`{"status": "added", "timestamp": "0x1"}`,
+		`{"status": "fetching", "first_sent": "0x2"}`,
+		`{"status": "fetched", "data": {"hash": "0x0100000000000000000000000000000000000000000000000000000000000000"}}`,
+	}
+
+	h, err := WaitFetchedHeader(context.Background(), f, Hash{0x01}, time.Millisecond)
+	if err != nil {
+		t.Errorf("fail to wait fetched header: %s\n", err)
+		return
+	}
+
+	if h.Hash != (Hash{0x01}) {
+		t.Errorf("mismatch result, expect %v, got %v", Hash{0x01}, h.Hash)
+		return
+	}
+
+	f = &testFetchHeader{`{"status": "not_found"}`}
+	_, err = WaitFetchedHeader(context.Background(), f, Hash{0x01}, time.Millisecond)
+	if err == nil {
+		t.Errorf("expect error on not found")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	f = &testFetchHeader{`{"status": "fetching", "first_sent": "0x2"}`}
+	_, err = WaitFetchedHeader(ctx, f, Hash{0x01}, time.Millisecond)
+	if err == nil {
+		t.Errorf("expect error on timeout")
+		return
+	}
+}
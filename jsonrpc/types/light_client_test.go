@@ -0,0 +1,97 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSetScriptsParams(t *testing.T) {
+	cmd := SetScriptsPartial
+	p := SetScriptsParams{
+		Scripts: []ScriptStatus{{Script: Script{CodeHash: Hash{0x01}, HashType: Type, Args: Bytes{}}, ScriptType: ScriptTypeLock, BlockNumber: 0x10}},
+		Command: &cmd,
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Errorf("fail to marshal set scripts params: %s\n", err)
+		return
+	}
+
+	expect := `[[{"script":{"code_hash":"0x0100000000000000000000000000000000000000000000000000000000000000","hash_type":"type","args":"0x"},"script_type":"lock","block_number":"0x10"}],"partial"]`
+	if string(data) != expect {
+		t.Errorf("mismatch result, expect %v, got %v", expect, string(data))
+		return
+	}
+
+	var q SetScriptsParams
+	err = json.Unmarshal(data, &q)
+	if err != nil {
+		t.Errorf("fail to unmarshal set scripts params: %s\n", err)
+		return
+	}
+
+	if q.Command == nil || *q.Command != SetScriptsPartial || len(q.Scripts) != 1 || q.Scripts[0].BlockNumber != 0x10 {
+		t.Errorf("mismatch result, got %v", q)
+		return
+	}
+
+	data, err = json.Marshal(SetScriptsParams{})
+	if err != nil {
+		t.Errorf("fail to marshal set scripts params: %s\n", err)
+		return
+	}
+
+	if string(data) != `[[]]` {
+		t.Errorf("mismatch result, expect [[]], got %v", string(data))
+		return
+	}
+}
+
+func TestApplySetScripts(t *testing.T) {
+	a := ScriptStatus{Script: Script{CodeHash: Hash{0x01}, HashType: Type, Args: Bytes{0x01}}, ScriptType: ScriptTypeLock, BlockNumber: 1}
+	b := ScriptStatus{Script: Script{CodeHash: Hash{0x01}, HashType: Type, Args: Bytes{0x02}}, ScriptType: ScriptTypeLock, BlockNumber: 2}
+	bType := ScriptStatus{Script: b.Script, ScriptType: ScriptTypeType, BlockNumber: 3}
+
+	updated := b
+	updated.BlockNumber = 20
+
+	result, err := ApplySetScripts([]ScriptStatus{a, b, bType}, []ScriptStatus{updated}, SetScriptsPartial)
+	if err != nil {
+		t.Errorf("fail to apply partial: %s\n", err)
+		return
+	}
+
+	if len(result) != 3 || result[2].BlockNumber != 20 || result[1].ScriptType != ScriptTypeType {
+		t.Errorf("mismatch result, got %v", result)
+		return
+	}
+
+	result, err = ApplySetScripts(result, []ScriptStatus{a}, SetScriptsDelete)
+	if err != nil {
+		t.Errorf("fail to apply delete: %s\n", err)
+		return
+	}
+
+	if len(result) != 2 || indexOfScriptStatus(result, &a) != -1 {
+		t.Errorf("mismatch result, got %v", result)
+		return
+	}
+
+	result, err = ApplySetScripts(result, []ScriptStatus{a}, SetScriptsAll)
+	if err != nil {
+		t.Errorf("fail to apply all: %s\n", err)
+		return
+	}
+
+	if len(result) != 1 || result[0].BlockNumber != 1 {
+		t.Errorf("mismatch result, got %v", result)
+		return
+	}
+
+	_, err = ApplySetScripts(result, nil, SetScriptsCommand("replace"))
+	if err == nil {
+		t.Errorf("expect error on unknown command")
+		return
+	}
+}
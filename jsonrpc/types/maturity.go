@@ -0,0 +1,116 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// CellbaseMaturity epochs before a cellbase output can be spent
+const CellbaseMaturity = 4
+
+// ChainPoint position on chain, either the block that will include the
+// transaction or the block that committed an input cell
+type ChainPoint struct {
+	Number Uint64
+	Epoch  EpochNumberWithFraction
+	// MedianTime median timestamp of previous 37 blocks, in milliseconds
+	MedianTime Uint64
+}
+
+// InputCellInfo chain point of committed input cell
+type InputCellInfo struct {
+	ChainPoint
+	Cellbase bool
+}
+
+// InputMaturity evaluation result of input since and cellbase maturity
+/*
+ * Targets are what tip must reach before the input can be spent, block
+ * number, epoch or timestamp in milliseconds depending on metric. Epoch
+ * targets are rational since relative epochs add fractions.
+ */
+type InputMaturity struct {
+	Mature bool
+
+	HasSince     bool
+	SinceMetric  SinceMetric
+	SinceTarget  *big.Rat
+	SinceMatured bool
+
+	CellbaseTarget  *big.Rat
+	CellbaseMatured bool
+}
+
+// EvaluateMaturity evaluate since locks and cellbase maturity of
+// transaction inputs against tip, cells are in input order
+func (t *Transaction) EvaluateMaturity(tip ChainPoint, cells []InputCellInfo) ([]InputMaturity, error) {
+	if len(cells) != len(t.Inputs) {
+		return nil, fmt.Errorf("invalid input cells, %d for %d inputs", len(cells), len(t.Inputs))
+	}
+
+	ms := make([]InputMaturity, len(cells))
+	for i := 0; i < len(cells); i++ {
+		m, err := EvaluateInputMaturity(t.Inputs[i].Since, tip, cells[i])
+		if err != nil {
+			return nil, fmt.Errorf("input %d %s", i, err)
+		}
+
+		ms[i] = *m
+	}
+
+	return ms, nil
+}
+
+// EvaluateInputMaturity evaluate since lock and cellbase maturity of one
+// input against tip
+func EvaluateInputMaturity(since Uint64, tip ChainPoint, cell InputCellInfo) (*InputMaturity, error) {
+	m := &InputMaturity{SinceMatured: true, CellbaseMatured: true}
+
+	if cell.Cellbase {
+		m.CellbaseTarget = cell.Epoch.Rat()
+		m.CellbaseTarget.Add(m.CellbaseTarget, big.NewRat(CellbaseMaturity, 1))
+		m.CellbaseMatured = tip.Epoch.Rat().Cmp(m.CellbaseTarget) >= 0
+	}
+
+	if since != 0 {
+		s, err := DecodeSince(since)
+		if err != nil {
+			return nil, err
+		}
+
+		m.HasSince = true
+		m.SinceMetric = s.Metric
+
+		var current, target, base *big.Rat
+		switch s.Metric {
+		case SinceBlockNumber:
+			current = ratFromUint64(uint64(tip.Number))
+			target = ratFromUint64(s.Value)
+			base = ratFromUint64(uint64(cell.Number))
+		case SinceEpoch:
+			current = tip.Epoch.Rat()
+			target = NewEpochNumberWithFraction(Uint64(s.Value)).Rat()
+			base = cell.Epoch.Rat()
+		case SinceTimestamp:
+			// Since timestamp is in seconds
+			current = ratFromUint64(uint64(tip.MedianTime))
+			target = new(big.Rat).Mul(ratFromUint64(s.Value), big.NewRat(1000, 1))
+			base = ratFromUint64(uint64(cell.MedianTime))
+		}
+
+		if s.Relative {
+			target.Add(target, base)
+		}
+
+		m.SinceTarget = target
+		m.SinceMatured = current.Cmp(target) >= 0
+	}
+
+	m.Mature = m.SinceMatured && m.CellbaseMatured
+
+	return m, nil
+}
+
+func ratFromUint64(n uint64) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).SetUint64(n))
+}
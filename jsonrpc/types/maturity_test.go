@@ -0,0 +1,74 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestEvaluateMaturity(t *testing.T) {
+	epoch := func(number, index, length uint64) EpochNumberWithFraction {
+		return EpochNumberWithFraction{Number: number, Index: index, Length: length}
+	}
+
+	cell := InputCellInfo{
+		ChainPoint: ChainPoint{Number: 1000, Epoch: epoch(10, 500, 1000), MedianTime: 1600000000000},
+		Cellbase:   true,
+	}
+
+	tip := ChainPoint{Number: 5000, Epoch: epoch(14, 400, 1000), MedianTime: 1600100000000}
+
+	tx := &Transaction{
+		Inputs: []CellInput{
+			{Since: 0},
+			// Relative 4 epochs, like dao withdraw lock
+			{Since: Since{Relative: true, Metric: SinceEpoch, Value: uint64(epoch(4, 0, 1).Uint64())}.Encode()},
+			// Relative 2 whole epochs, zero length
+			{Since: 0xa000000000000002},
+			// Absolute timestamp in seconds
+			{Since: Since{Metric: SinceTimestamp, Value: 1600050000}.Encode()},
+		},
+	}
+
+	plain := cell
+	plain.Cellbase = false
+
+	ms, err := tx.EvaluateMaturity(tip, []InputCellInfo{cell, plain, plain, plain})
+	if err != nil {
+		t.Errorf("fail to evaluate maturity: %s\n", err)
+		return
+	}
+
+	// Cellbase matures at 14(500/1000), tip is 14(400/1000)
+	if ms[0].Mature || ms[0].CellbaseTarget.Cmp(big.NewRat(29, 2)) != 0 {
+		t.Errorf("mismatch cellbase maturity, got %+v", ms[0])
+		return
+	}
+
+	if ms[1].Mature || !ms[1].HasSince || ms[1].SinceTarget.Cmp(big.NewRat(29, 2)) != 0 {
+		t.Errorf("mismatch relative epoch maturity, got %+v", ms[1])
+		return
+	}
+
+	// Cell epoch 10(500/1000) plus 2
+	if !ms[2].Mature || ms[2].SinceTarget.Cmp(big.NewRat(25, 2)) != 0 {
+		t.Errorf("mismatch relative whole epochs maturity, got %+v", ms[2])
+		return
+	}
+
+	if !ms[3].Mature || ms[3].SinceTarget.Cmp(big.NewRat(1600050000000, 1)) != 0 {
+		t.Errorf("mismatch absolute timestamp maturity, got %+v", ms[3])
+		return
+	}
+
+	tip.Epoch = epoch(14, 1, 2)
+	ms, err = tx.EvaluateMaturity(tip, []InputCellInfo{cell, plain, plain, plain})
+	if err != nil {
+		t.Errorf("fail to evaluate maturity: %s\n", err)
+		return
+	}
+
+	if !ms[0].Mature || !ms[1].Mature {
+		t.Errorf("expect matured inputs, got %+v", ms)
+		return
+	}
+}
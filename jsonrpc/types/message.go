@@ -0,0 +1,47 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+)
+
+// MessageMagic prefix of signed off-chain message, same as neuron wallet
+const MessageMagic = "Nervos Message:"
+
+// MessageDigest digest of off-chain message to be signed
+/*
+ *     blake2b-256("Nervos Message:" || message)
+ */
+func MessageDigest(message []byte) Hash {
+	h := NewBlake2b()
+	h.Write([]byte(MessageMagic))
+	h.Write(message)
+
+	var digest Hash
+	copy(digest[:], h.Sum(nil))
+
+	return digest
+}
+
+// SignMessage sign off-chain message with secp256k1 private key, returns
+// 65 bytes recoverable signature
+func SignMessage(seckey []byte, message []byte) ([]byte, error) {
+	if len(seckey) != 32 {
+		return nil, fmt.Errorf("invalid private key, should be 32 bytes")
+	}
+
+	digest := MessageDigest(message)
+	return secp256k1.Sign(digest[:], seckey)
+}
+
+// VerifyMessage verify off-chain message signature against public key
+func VerifyMessage(pubkey []byte, message []byte, sig []byte) bool {
+	return VerifySignature(pubkey, MessageDigest(message), sig)
+}
+
+// RecoverMessageSigner recover default secp256k1 lock args of message
+// signer, compare it to the claimed address for login flows
+func RecoverMessageSigner(message []byte, sig []byte) (Bytes, error) {
+	return RecoverLockArgs(MessageDigest(message), sig)
+}
@@ -0,0 +1,40 @@
+package types
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSignMessage(t *testing.T) {
+	seckey := make([]byte, 32)
+	seckey[31] = 1
+	message := []byte("login to example.com, nonce 42")
+
+	sig, err := SignMessage(seckey, message)
+	if err != nil {
+		t.Errorf("fail to sign message: %s\n", err)
+		return
+	}
+
+	args, err := RecoverMessageSigner(message, sig)
+	if err != nil {
+		t.Errorf("fail to recover signer: %s\n", err)
+		return
+	}
+
+	pub, err := RecoverPubkey(MessageDigest(message), sig)
+	if err != nil {
+		t.Errorf("fail to recover pubkey: %s\n", err)
+		return
+	}
+
+	if !bytes.Equal(args, Blake160(pub)) || !VerifyMessage(pub, message, sig) {
+		t.Errorf("mismatch signer, got %v", args)
+		return
+	}
+
+	if VerifyMessage(pub, []byte("other message"), sig) {
+		t.Errorf("expect invalid signature on other message")
+		return
+	}
+}
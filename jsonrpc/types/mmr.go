@@ -0,0 +1,620 @@
+package types
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+// HeaderDigestSize molecule HeaderDigest struct size
+const HeaderDigestSize = hashSize + 32 + 6*uint64Size + 2*uint32Size
+
+// HeaderDigest ckb chain root mmr node, digest of headers in
+// [start_number, end_number]
+/*
+ * Leaf children hash is the header hash, parent children hash is
+ * blake2b-256 of both molecule serialized children. Difficulty adds up,
+ * start fields come from the left child and end fields from the right.
+ */
+type HeaderDigest struct {
+	ChildrenHash       Hash    `json:"children_hash"`
+	TotalDifficulty    Uint256 `json:"total_difficulty"`
+	StartNumber        Uint64  `json:"start_number"`
+	EndNumber          Uint64  `json:"end_number"`
+	StartEpoch         Uint64  `json:"start_epoch"`
+	EndEpoch           Uint64  `json:"end_epoch"`
+	StartTimestamp     Uint64  `json:"start_timestamp"`
+	EndTimestamp       Uint64  `json:"end_timestamp"`
+	StartCompactTarget Uint32  `json:"start_compact_target"`
+	EndCompactTarget   Uint32  `json:"end_compact_target"`
+}
+
+// NewHeaderDigest mmr leaf of header
+func NewHeaderDigest(h *HeaderView) HeaderDigest {
+	return HeaderDigest{
+		ChildrenHash:       h.Hash,
+		TotalDifficulty:    CompactToDifficulty(uint32(h.CompactTarget)),
+		StartNumber:        h.Number,
+		EndNumber:          h.Number,
+		StartEpoch:         h.Epoch,
+		EndEpoch:           h.Epoch,
+		StartTimestamp:     h.Timestamp,
+		EndTimestamp:       h.Timestamp,
+		StartCompactTarget: h.CompactTarget,
+		EndCompactTarget:   h.CompactTarget,
+	}
+}
+
+// Serialize header digest
+func (d *HeaderDigest) Serialize() ([]byte, error) {
+	fields := make([][]byte, 0, 10)
+
+	for _, f := range []MolSerializer{
+		&d.ChildrenHash,
+		&d.TotalDifficulty,
+		&d.StartNumber,
+		&d.EndNumber,
+		&d.StartEpoch,
+		&d.EndEpoch,
+		&d.StartTimestamp,
+		&d.EndTimestamp,
+		&d.StartCompactTarget,
+		&d.EndCompactTarget,
+	} {
+		b, err := f.Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, b)
+	}
+
+	return SerializeStruct(fields), nil
+}
+
+// Deserialize header digest
+func (d *HeaderDigest) Deserialize(data []byte) error {
+	sizes := []int{
+		hashSize, 32,
+		uint64Size, uint64Size, uint64Size, uint64Size, uint64Size, uint64Size,
+		uint32Size, uint32Size,
+	}
+
+	fields, err := DeserializeStruct(data, sizes)
+	if err != nil {
+		return err
+	}
+
+	for i, f := range []MolDeserializer{
+		&d.ChildrenHash,
+		&d.TotalDifficulty,
+		&d.StartNumber,
+		&d.EndNumber,
+		&d.StartEpoch,
+		&d.EndEpoch,
+		&d.StartTimestamp,
+		&d.EndTimestamp,
+		&d.StartCompactTarget,
+		&d.EndCompactTarget,
+	} {
+		err = f.Deserialize(fields[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ComputeHash calculate digest hash, chain root hash if digest is mmr root
+func (d *HeaderDigest) ComputeHash() (Hash, error) {
+	var h Hash
+
+	b, err := d.Serialize()
+	if err != nil {
+		return h, err
+	}
+
+	copy(h[:], Blake2b256(b))
+	return h, nil
+}
+
+// MergeHeaderDigest parent of adjacent digests, rhs must follow lhs
+func MergeHeaderDigest(lhs *HeaderDigest, rhs *HeaderDigest) (HeaderDigest, error) {
+	var d HeaderDigest
+
+	if lhs.EndNumber+1 != rhs.StartNumber {
+		return d, fmt.Errorf("invalid header digest, %d does not follow %d", rhs.StartNumber, lhs.EndNumber)
+	}
+
+	l, err := lhs.Serialize()
+	if err != nil {
+		return d, err
+	}
+
+	r, err := rhs.Serialize()
+	if err != nil {
+		return d, err
+	}
+
+	copy(d.ChildrenHash[:], Blake2b256(append(l, r...)))
+
+	d.TotalDifficulty, err = lhs.TotalDifficulty.Add(rhs.TotalDifficulty)
+	if err != nil {
+		return d, err
+	}
+
+	d.StartNumber, d.EndNumber = lhs.StartNumber, rhs.EndNumber
+	d.StartEpoch, d.EndEpoch = lhs.StartEpoch, rhs.EndEpoch
+	d.StartTimestamp, d.EndTimestamp = lhs.StartTimestamp, rhs.EndTimestamp
+	d.StartCompactTarget, d.EndCompactTarget = lhs.StartCompactTarget, rhs.EndCompactTarget
+
+	return d, nil
+}
+
+// ChainRoot chain root hash committed by block extension, mmr root hash
+// of all headers before the block
+func ChainRoot(extension Bytes) (Hash, error) {
+	var h Hash
+
+	if len(extension) < hashSize {
+		return h, fmt.Errorf("invalid extension, no chain root")
+	}
+
+	copy(h[:], extension[:hashSize])
+	return h, nil
+}
+
+// MMR in memory ckb merkle mountain range of header digests
+/*
+ * Nodes are stored in post order, leaf index i is at LeafIndexToPos(i).
+ * Peaks are bagged from right to left, each one merged as left child of
+ * the bag, so the root spans all headers in order.
+ */
+type MMR struct {
+	nodes []HeaderDigest
+}
+
+// NewMMR new empty mmr
+func NewMMR() *MMR {
+	return &MMR{nodes: []HeaderDigest{}}
+}
+
+// Size mmr size, number of nodes
+func (m *MMR) Size() uint64 {
+	return uint64(len(m.nodes))
+}
+
+// Push append leaf, returns its position
+func (m *MMR) Push(leaf HeaderDigest) (uint64, error) {
+	pos := m.Size()
+	m.nodes = append(m.nodes, leaf)
+
+	height := uint32(0)
+	for mmrPosHeight(m.Size()) > height {
+		right := m.Size() - 1
+		left := right - siblingOffset(height)
+
+		parent, err := MergeHeaderDigest(&m.nodes[left], &m.nodes[right])
+		if err != nil {
+			m.nodes = m.nodes[:pos]
+			return 0, err
+		}
+
+		m.nodes = append(m.nodes, parent)
+		height++
+	}
+
+	return pos, nil
+}
+
+// Root mmr root digest
+func (m *MMR) Root() (HeaderDigest, error) {
+	if m.Size() == 0 {
+		return HeaderDigest{}, fmt.Errorf("empty mmr has no root")
+	}
+
+	peaks := []HeaderDigest{}
+	for _, p := range mmrPeaks(m.Size()) {
+		peaks = append(peaks, m.nodes[p])
+	}
+
+	return bagMMRPeaks(peaks)
+}
+
+// Proof merkle proof of leaves at positions
+func (m *MMR) Proof(positions []uint64) (*MMRProof, error) {
+	pos := sortedPositions(positions)
+	if len(pos) == 0 {
+		return nil, fmt.Errorf("invalid mmr proof, no leaf")
+	}
+
+	items := []HeaderDigest{}
+	if m.Size() == 1 && pos[0] == 0 {
+		return &MMRProof{MMRSize: 1, Items: items}, nil
+	}
+
+	track := 0
+	for _, peak := range mmrPeaks(m.Size()) {
+		n := 0
+		for n < len(pos) && pos[n] <= peak {
+			n++
+		}
+
+		if n == 0 {
+			track++
+		} else {
+			track = 0
+		}
+
+		var err error
+		items, err = m.peakProof(items, pos[:n], peak)
+		if err != nil {
+			return nil, err
+		}
+
+		pos = pos[n:]
+	}
+
+	if len(pos) != 0 {
+		return nil, fmt.Errorf("invalid mmr proof, position %d out of mmr", pos[0])
+	}
+
+	// Peaks right of the last proven one are bagged into one item
+	if track > 1 {
+		rhs, err := bagMMRPeaks(items[len(items)-track:])
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items[:len(items)-track], rhs)
+	}
+
+	return &MMRProof{MMRSize: m.Size(), Items: items}, nil
+}
+
+// peakProof append proof items of positions under peak
+func (m *MMR) peakProof(items []HeaderDigest, pos []uint64, peak uint64) ([]HeaderDigest, error) {
+	if len(pos) == 1 && pos[0] == peak {
+		return items, nil
+	}
+
+	if len(pos) == 0 {
+		return append(items, m.nodes[peak]), nil
+	}
+
+	queue := make([]mmrNode, 0, len(pos))
+	for _, p := range pos {
+		if mmrPosHeight(p) != 0 {
+			return nil, fmt.Errorf("invalid mmr proof, position %d is not a leaf", p)
+		}
+
+		queue = append(queue, mmrNode{pos: p})
+	}
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		if n.pos == peak {
+			break
+		}
+
+		sib, parent := mmrSibling(n.pos, n.height)
+		if len(queue) > 0 && queue[0].pos == sib {
+			queue = queue[1:]
+		} else {
+			items = append(items, m.nodes[sib])
+		}
+
+		if parent < peak {
+			queue = append(queue, mmrNode{pos: parent, height: n.height + 1})
+		}
+	}
+
+	return items, nil
+}
+
+// MMRLeaf leaf proven by mmr proof
+type MMRLeaf struct {
+	Pos    uint64
+	Digest HeaderDigest
+}
+
+// MMRProof ckb merkle mountain range proof
+type MMRProof struct {
+	MMRSize uint64
+	Items   []HeaderDigest
+}
+
+// CalculateRoot root digest of mmr holding leaves, as proven by proof
+func (p *MMRProof) CalculateRoot(leaves []MMRLeaf) (HeaderDigest, error) {
+	if len(leaves) == 0 {
+		return HeaderDigest{}, fmt.Errorf("invalid mmr proof, no leaf")
+	}
+
+	if p.MMRSize == 1 && len(leaves) == 1 && leaves[0].Pos == 0 {
+		return leaves[0].Digest, nil
+	}
+
+	nodes := sortedLeaves(leaves)
+	items := p.Items
+
+	peaks := []HeaderDigest{}
+	for _, peak := range mmrPeaks(p.MMRSize) {
+		n := 0
+		for n < len(nodes) && nodes[n].pos <= peak {
+			n++
+		}
+
+		// Peaks right of the last leaf may come bagged as one item
+		if n == 0 && len(items) == 0 {
+			break
+		}
+
+		var root HeaderDigest
+		switch {
+		case n == 1 && nodes[0].pos == peak:
+			root = nodes[0].digest
+		case n == 0:
+			root = items[0]
+			items = items[1:]
+		default:
+			var err error
+			root, items, err = peakRoot(nodes[:n], peak, items)
+			if err != nil {
+				return HeaderDigest{}, err
+			}
+		}
+
+		nodes = nodes[n:]
+		peaks = append(peaks, root)
+	}
+
+	if len(nodes) != 0 {
+		return HeaderDigest{}, fmt.Errorf("invalid mmr proof, leaf %d out of mmr", nodes[0].pos)
+	}
+
+	// Bagged right hand side peaks
+	if len(items) > 0 {
+		peaks = append(peaks, items[0])
+		items = items[1:]
+	}
+
+	if len(items) != 0 {
+		return HeaderDigest{}, fmt.Errorf("invalid mmr proof, %d items left", len(items))
+	}
+
+	return bagMMRPeaks(peaks)
+}
+
+// Verify verify leaves are in mmr of root
+func (p *MMRProof) Verify(root *HeaderDigest, leaves []MMRLeaf) error {
+	r, err := p.CalculateRoot(leaves)
+	if err != nil {
+		return err
+	}
+
+	if r != *root {
+		return fmt.Errorf("mismatch mmr root, expect %s, got %s", root.ChildrenHash, r.ChildrenHash)
+	}
+
+	return nil
+}
+
+// VerifyChainRoot verify leaves are among headers committed by chain root
+// of block extension
+func (p *MMRProof) VerifyChainRoot(extension Bytes, leaves []MMRLeaf) error {
+	expect, err := ChainRoot(extension)
+	if err != nil {
+		return err
+	}
+
+	r, err := p.CalculateRoot(leaves)
+	if err != nil {
+		return err
+	}
+
+	h, err := r.ComputeHash()
+	if err != nil {
+		return err
+	}
+
+	if h != expect {
+		return fmt.Errorf("mismatch chain root, expect %s, got %s", expect, h)
+	}
+
+	return nil
+}
+
+// LeafIndexToPos mmr position of leaf index
+func LeafIndexToPos(index uint64) uint64 {
+	return LeafIndexToMMRSize(index) - uint64(bits.TrailingZeros64(index+1)) - 1
+}
+
+// LeafIndexToMMRSize mmr size holding leaves up to index
+func LeafIndexToMMRSize(index uint64) uint64 {
+	leaves := index + 1
+	return 2*leaves - uint64(bits.OnesCount64(leaves))
+}
+
+// mmrNode node being merged up to peak
+type mmrNode struct {
+	pos    uint64
+	height uint32
+	digest HeaderDigest
+}
+
+// peakRoot merge leaves under peak up to peak with proof items, returns
+// items left
+func peakRoot(nodes []mmrNode, peak uint64, items []HeaderDigest) (HeaderDigest, []HeaderDigest, error) {
+	queue := append([]mmrNode{}, nodes...)
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		if n.pos == peak {
+			if len(queue) != 0 {
+				return HeaderDigest{}, nil, fmt.Errorf("invalid mmr proof, nodes left under peak %d", peak)
+			}
+
+			return n.digest, items, nil
+		}
+
+		sib, parent := mmrSibling(n.pos, n.height)
+
+		var sibling HeaderDigest
+		if len(queue) > 0 && queue[0].pos == sib {
+			sibling = queue[0].digest
+			queue = queue[1:]
+		} else {
+			if len(items) == 0 {
+				return HeaderDigest{}, nil, fmt.Errorf("invalid mmr proof, not enough items")
+			}
+
+			sibling = items[0]
+			items = items[1:]
+		}
+
+		var d HeaderDigest
+		var err error
+		if sib < n.pos {
+			d, err = MergeHeaderDigest(&sibling, &n.digest)
+		} else {
+			d, err = MergeHeaderDigest(&n.digest, &sibling)
+		}
+		if err != nil {
+			return HeaderDigest{}, nil, err
+		}
+
+		if parent > peak {
+			return HeaderDigest{}, nil, fmt.Errorf("invalid mmr proof, node %d above peak %d", parent, peak)
+		}
+
+		queue = append(queue, mmrNode{pos: parent, height: n.height + 1, digest: d})
+	}
+
+	return HeaderDigest{}, nil, fmt.Errorf("invalid mmr proof, peak %d not reached", peak)
+}
+
+// bagMMRPeaks bag peaks from right to left
+func bagMMRPeaks(peaks []HeaderDigest) (HeaderDigest, error) {
+	if len(peaks) == 0 {
+		return HeaderDigest{}, fmt.Errorf("invalid mmr proof, no peak")
+	}
+
+	bag := peaks[len(peaks)-1]
+	for i := len(peaks) - 2; i >= 0; i-- {
+		var err error
+		bag, err = MergeHeaderDigest(&peaks[i], &bag)
+		if err != nil {
+			return HeaderDigest{}, err
+		}
+	}
+
+	return bag, nil
+}
+
+// mmrSibling sibling and parent positions of node at height
+func mmrSibling(pos uint64, height uint32) (uint64, uint64) {
+	if mmrPosHeight(pos+1) > height {
+		// Right sibling, parent follows it
+		return pos - siblingOffset(height), pos + 1
+	}
+
+	return pos + siblingOffset(height), pos + parentOffset(height)
+}
+
+// mmrPeaks peak positions of mmr size, left to right
+func mmrPeaks(size uint64) []uint64 {
+	if size == 0 {
+		return nil
+	}
+
+	height, pos := uint32(0), uint64(0)
+	for h := uint32(1); peakPosOfHeight(h) < size; h++ {
+		height, pos = h, peakPosOfHeight(h)
+	}
+
+	peaks := []uint64{pos}
+	for height > 0 {
+		// Move to right sibling, then down to left child until inside
+		pos += siblingOffset(height)
+		for pos > size-1 && height > 0 {
+			height--
+			pos -= parentOffset(height)
+		}
+
+		if pos > size-1 {
+			break
+		}
+
+		peaks = append(peaks, pos)
+	}
+
+	return peaks
+}
+
+// mmrPosHeight height of node at position
+func mmrPosHeight(pos uint64) uint32 {
+	pos++
+	for !allOnes(pos) {
+		pos -= 1<<(63-uint(bits.LeadingZeros64(pos))) - 1
+	}
+
+	return uint32(63 - bits.LeadingZeros64(pos))
+}
+
+// allOnes report whether n is 2^k-1, k > 0
+func allOnes(n uint64) bool {
+	return n != 0 && n&(n+1) == 0
+}
+
+// peakPosOfHeight position of the first peak of height
+func peakPosOfHeight(height uint32) uint64 {
+	return 1<<(height+1) - 2
+}
+
+// siblingOffset distance to sibling at height
+func siblingOffset(height uint32) uint64 {
+	return 2<<height - 1
+}
+
+// parentOffset distance from left child at height to parent
+func parentOffset(height uint32) uint64 {
+	return 2 << height
+}
+
+// sortedPositions sorted unique positions
+func sortedPositions(positions []uint64) []uint64 {
+	pos := append([]uint64{}, positions...)
+	sort.Slice(pos, func(i, j int) bool { return pos[i] < pos[j] })
+
+	unique := make([]uint64, 0, len(pos))
+	for i, p := range pos {
+		if i == 0 || p != pos[i-1] {
+			unique = append(unique, p)
+		}
+	}
+
+	return unique
+}
+
+// sortedLeaves leaves as nodes sorted by unique position
+func sortedLeaves(leaves []MMRLeaf) []mmrNode {
+	nodes := make([]mmrNode, 0, len(leaves))
+	for _, l := range leaves {
+		nodes = append(nodes, mmrNode{pos: l.Pos, digest: l.Digest})
+	}
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].pos < nodes[j].pos })
+
+	unique := make([]mmrNode, 0, len(nodes))
+	for i, n := range nodes {
+		if i == 0 || n.pos != nodes[i-1].pos {
+			unique = append(unique, n)
+		}
+	}
+
+	return unique
+}
@@ -0,0 +1,201 @@
+package types
+
+import (
+	"testing"
+)
+
+func testMMRLeaf(number uint64) HeaderDigest {
+	h := &HeaderView{Hash: Hash{byte(number), 0x4d}}
+	h.Number = Uint64(number)
+	h.CompactTarget = 0x1e083126
+	h.Timestamp = Uint64(1000 + number)
+
+	return NewHeaderDigest(h)
+}
+
+func TestMMRPeaks(t *testing.T) {
+	peaks := mmrPeaks(LeafIndexToMMRSize(6))
+	expect := []uint64{6, 9, 10}
+
+	if len(peaks) != len(expect) {
+		t.Errorf("mismatch result, expect %v, got %v", expect, peaks)
+		return
+	}
+
+	for i := range expect {
+		if peaks[i] != expect[i] {
+			t.Errorf("mismatch result, expect %v, got %v", expect, peaks)
+			return
+		}
+	}
+
+	if LeafIndexToPos(4) != 7 || LeafIndexToPos(7) != 11 {
+		t.Errorf("mismatch result, expect 7 and 11, got %d and %d", LeafIndexToPos(4), LeafIndexToPos(7))
+		return
+	}
+}
+
+func TestMMRRoot(t *testing.T) {
+	m := NewMMR()
+	leaves := []HeaderDigest{testMMRLeaf(0), testMMRLeaf(1), testMMRLeaf(2)}
+
+	for _, l := range leaves {
+		_, err := m.Push(l)
+		if err != nil {
+			t.Errorf("fail to push leaf: %s\n", err)
+			return
+		}
+	}
+
+	left, err := MergeHeaderDigest(&leaves[0], &leaves[1])
+	if err != nil {
+		t.Errorf("fail to merge digest: %s\n", err)
+		return
+	}
+
+	expect, err := MergeHeaderDigest(&left, &leaves[2])
+	if err != nil {
+		t.Errorf("fail to merge digest: %s\n", err)
+		return
+	}
+
+	root, err := m.Root()
+	if err != nil {
+		t.Errorf("fail to get root: %s\n", err)
+		return
+	}
+
+	if root != expect {
+		t.Errorf("mismatch result, expect %v, got %v", expect, root)
+		return
+	}
+
+	if root.StartNumber != 0 || root.EndNumber != 2 || root.EndTimestamp != 1002 {
+		t.Errorf("mismatch result, expect range [0, 2], got [%d, %d]", root.StartNumber, root.EndNumber)
+		return
+	}
+
+	_, err = m.Push(testMMRLeaf(5))
+	if err == nil {
+		t.Errorf("expect error on non adjacent leaf")
+		return
+	}
+
+	if m.Size() != 4 {
+		t.Errorf("mismatch result, expect size 4, got %d", m.Size())
+		return
+	}
+}
+
+func TestMMRProof(t *testing.T) {
+	for n := uint64(1); n <= 12; n++ {
+		m := NewMMR()
+		for i := uint64(0); i < n; i++ {
+			_, err := m.Push(testMMRLeaf(i))
+			if err != nil {
+				t.Errorf("fail to push leaf: %s\n", err)
+				return
+			}
+		}
+
+		root, err := m.Root()
+		if err != nil {
+			t.Errorf("fail to get root: %s\n", err)
+			return
+		}
+
+		// Every pair of leaves, and single leaves when equal
+		for i := uint64(0); i < n; i++ {
+			for j := i; j < n; j++ {
+				leaves := []MMRLeaf{
+					{Pos: LeafIndexToPos(j), Digest: testMMRLeaf(j)},
+					{Pos: LeafIndexToPos(i), Digest: testMMRLeaf(i)},
+				}
+
+				p, err := m.Proof([]uint64{leaves[0].Pos, leaves[1].Pos})
+				if err != nil {
+					t.Errorf("fail to generate proof of %d %d in %d: %s\n", i, j, n, err)
+					return
+				}
+
+				err = p.Verify(&root, leaves)
+				if err != nil {
+					t.Errorf("fail to verify proof of %d %d in %d: %s\n", i, j, n, err)
+					return
+				}
+
+				leaves[0].Digest.ChildrenHash[0] ^= 0xff
+				if p.Verify(&root, leaves) == nil {
+					t.Errorf("expect error on tampered leaf %d in %d", j, n)
+					return
+				}
+			}
+		}
+	}
+}
+
+func TestVerifyChainRoot(t *testing.T) {
+	m := NewMMR()
+	for i := uint64(0); i < 5; i++ {
+		_, err := m.Push(testMMRLeaf(i))
+		if err != nil {
+			t.Errorf("fail to push leaf: %s\n", err)
+			return
+		}
+	}
+
+	root, err := m.Root()
+	if err != nil {
+		t.Errorf("fail to get root: %s\n", err)
+		return
+	}
+
+	h, err := root.ComputeHash()
+	if err != nil {
+		t.Errorf("fail to hash root: %s\n", err)
+		return
+	}
+
+	p, err := m.Proof([]uint64{LeafIndexToPos(3)})
+	if err != nil {
+		t.Errorf("fail to generate proof: %s\n", err)
+		return
+	}
+
+	leaves := []MMRLeaf{{Pos: LeafIndexToPos(3), Digest: testMMRLeaf(3)}}
+
+	err = p.VerifyChainRoot(Bytes(append(h[:], 0x01)), leaves)
+	if err != nil {
+		t.Errorf("fail to verify chain root: %s\n", err)
+		return
+	}
+
+	err = p.VerifyChainRoot(Bytes(make([]byte, 32)), leaves)
+	if err == nil {
+		t.Errorf("expect error on mismatch chain root")
+		return
+	}
+
+	b, err := root.Serialize()
+	if err != nil {
+		t.Errorf("fail to serialize digest: %s\n", err)
+		return
+	}
+
+	if len(b) != HeaderDigestSize {
+		t.Errorf("mismatch result, expect %d bytes, got %d", HeaderDigestSize, len(b))
+		return
+	}
+
+	var d HeaderDigest
+	err = d.Deserialize(b)
+	if err != nil {
+		t.Errorf("fail to deserialize digest: %s\n", err)
+		return
+	}
+
+	if d != root {
+		t.Errorf("mismatch result, expect %v, got %v", root, d)
+		return
+	}
+}
@@ -0,0 +1,208 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// MultisigConfig secp256k1 blake160 multisig lock config
+type MultisigConfig struct {
+	// RequireFirstN first n signers must all sign
+	RequireFirstN int
+	// Threshold signatures required
+	Threshold    int
+	PubkeyHashes []Bytes
+}
+
+// NewMultisigConfig new multisig config, validate limits of system script
+func NewMultisigConfig(requireFirstN, threshold int, pubkeyHashes []Bytes) (*MultisigConfig, error) {
+	c := &MultisigConfig{
+		RequireFirstN: requireFirstN,
+		Threshold:     threshold,
+		PubkeyHashes:  pubkeyHashes,
+	}
+
+	return c, c.Validate()
+}
+
+// Validate check multisig config
+func (c *MultisigConfig) Validate() error {
+	n := len(c.PubkeyHashes)
+
+	if n == 0 || n > 255 {
+		return fmt.Errorf("invalid multisig config, should have 1 to 255 pubkey hashes")
+	}
+
+	if c.Threshold <= 0 || c.Threshold > n {
+		return fmt.Errorf("invalid multisig config, threshold %d out of 1 to %d", c.Threshold, n)
+	}
+
+	if c.RequireFirstN < 0 || c.RequireFirstN > c.Threshold {
+		return fmt.Errorf("invalid multisig config, require first n %d exceeds threshold %d", c.RequireFirstN, c.Threshold)
+	}
+
+	seen := make(map[string]bool)
+	for _, h := range c.PubkeyHashes {
+		err := ValidateLockArgs(h)
+		if err != nil {
+			return fmt.Errorf("invalid multisig config, %s", err)
+		}
+
+		if seen[string(h)] {
+			return fmt.Errorf("invalid multisig config, duplicated pubkey hash %s", h)
+		}
+		seen[string(h)] = true
+	}
+
+	return nil
+}
+
+// MultisigScript serialized config, the prefix of witness lock
+/*
+ *     0 | require_first_n | threshold | pubkeys count | pubkey hashes
+ */
+func (c *MultisigConfig) MultisigScript() Bytes {
+	b := new(bytes.Buffer)
+
+	b.Write([]byte{0, byte(c.RequireFirstN), byte(c.Threshold), byte(len(c.PubkeyHashes))})
+	for _, h := range c.PubkeyHashes {
+		b.Write(h)
+	}
+
+	return Bytes(b.Bytes())
+}
+
+// LockArgs multisig lock args, blake160 of multisig script
+func (c *MultisigConfig) LockArgs() Bytes {
+	return Bytes(Blake160(c.MultisigScript()))
+}
+
+// LockScript multisig lock script
+func (c *MultisigConfig) LockScript() Script {
+	return Script{
+		CodeHash: SecpMultisigCodeHash,
+		HashType: Type,
+		Args:     c.LockArgs(),
+	}
+}
+
+// MultisigSession collect multisig signatures arriving in any order and
+// assemble the witness lock once enough are in
+type MultisigSession struct {
+	tx      *Transaction
+	group   []int
+	config  *MultisigConfig
+	message Hash
+	// sigs signatures keyed by signer index in config
+	sigs map[int][]byte
+}
+
+// NewMultisigSession new signing session for input group locked by
+// config, transaction must not change until finalized
+func NewMultisigSession(tx *Transaction, group []int, config *MultisigConfig) (*MultisigSession, error) {
+	err := config.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	placeholder := append(config.MultisigScript(), make(Bytes, SignatureSize*config.Threshold)...)
+
+	msg, err := tx.sighashAllMessage(group, placeholder)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MultisigSession{
+		tx:      tx,
+		group:   group,
+		config:  config,
+		message: msg,
+		sigs:    make(map[int][]byte),
+	}, nil
+}
+
+// Message signing message every signer signs
+func (s *MultisigSession) Message() Hash {
+	return s.message
+}
+
+// AddSignature add signature, signer is recovered from it, returns
+// signer index in config
+func (s *MultisigSession) AddSignature(sig []byte) (int, error) {
+	args, err := RecoverLockArgs(s.message, sig)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, h := range s.config.PubkeyHashes {
+		if ConstantTimeEqual(h, args) {
+			s.sigs[i] = append([]byte{}, sig...)
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid signature, signer %s not in multisig config", args)
+}
+
+// Missing signer indexes still required, empty when complete
+/*
+ * Required first n signers are listed if they have not signed. If more
+ * signatures are needed beyond them, all remaining signers are listed
+ * since any of them can fill the rest.
+ */
+func (s *MultisigSession) Missing() []int {
+	var missing []int
+
+	for i := 0; i < s.config.RequireFirstN; i++ {
+		if s.sigs[i] == nil {
+			missing = append(missing, i)
+		}
+	}
+
+	others := 0
+	for i := range s.sigs {
+		if i >= s.config.RequireFirstN {
+			others++
+		}
+	}
+
+	if s.config.RequireFirstN+others >= s.config.Threshold {
+		return missing
+	}
+
+	for i := s.config.RequireFirstN; i < len(s.config.PubkeyHashes); i++ {
+		if s.sigs[i] == nil {
+			missing = append(missing, i)
+		}
+	}
+
+	return missing
+}
+
+// Complete report whether enough signatures are collected
+func (s *MultisigSession) Complete() bool {
+	return len(s.Missing()) == 0
+}
+
+// Finalize write multisig lock into first witness of group
+/*
+ * Lock is multisig script followed by exactly threshold signatures, in
+ * signer order, required first n signers included.
+ */
+func (s *MultisigSession) Finalize() error {
+	if !s.Complete() {
+		return fmt.Errorf("multisig incomplete, %d of %d signatures", len(s.sigs), s.config.Threshold)
+	}
+
+	lock := s.config.MultisigScript()
+
+	count := 0
+	for i := 0; i < len(s.config.PubkeyHashes) && count < s.config.Threshold; i++ {
+		if s.sigs[i] != nil {
+			lock = append(lock, s.sigs[i]...)
+			count++
+		}
+	}
+
+	return s.tx.SetWitnessLock(s.group, lock)
+}
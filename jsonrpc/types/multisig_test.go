@@ -0,0 +1,103 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestMultisigSession(t *testing.T) {
+	var signers []*KeySigner
+	var hashes []Bytes
+
+	for i := 1; i <= 3; i++ {
+		seckey := make([]byte, 32)
+		seckey[31] = byte(i)
+
+		s, _ := NewKeySigner(seckey)
+		pub, _ := s.GetPublicKey()
+		args, _ := PubkeyToLockArgs(pub)
+
+		signers = append(signers, s)
+		hashes = append(hashes, args)
+	}
+
+	config, err := NewMultisigConfig(1, 2, hashes)
+	if err != nil {
+		t.Errorf("fail to create multisig config: %s\n", err)
+		return
+	}
+
+	tx := &Transaction{
+		Inputs:      []CellInput{{PreviousOutput: OutPoint{TxHash: Hash{1}}}},
+		Outputs:     []CellOutput{{Capacity: 61 * ShannonsPerCKB, Lock: config.LockScript()}},
+		OutputsData: []Bytes{{}},
+		Witnesses:   []Bytes{{}},
+	}
+
+	session, err := NewMultisigSession(tx, []int{0}, config)
+	if err != nil {
+		t.Errorf("fail to create multisig session: %s\n", err)
+		return
+	}
+
+	for _, i := range []int{2, 1} {
+		sig, _ := signers[i].SignDigest(session.Message())
+
+		idx, err := session.AddSignature(sig)
+		if err != nil || idx != i {
+			t.Errorf("mismatch signer index, expect %v, got %v %v", i, idx, err)
+			return
+		}
+	}
+
+	// Threshold reached but first signer is required
+	if session.Complete() || len(session.Missing()) != 1 || session.Missing()[0] != 0 {
+		t.Errorf("mismatch missing signers, got %v", session.Missing())
+		return
+	}
+
+	err = session.Finalize()
+	if err == nil {
+		t.Errorf("expect error on incomplete session")
+		return
+	}
+
+	sig, _ := signers[0].SignDigest(session.Message())
+	_, err = session.AddSignature(sig)
+	if err != nil {
+		t.Errorf("fail to add signature: %s\n", err)
+		return
+	}
+
+	err = session.Finalize()
+	if err != nil {
+		t.Errorf("fail to finalize: %s\n", err)
+		return
+	}
+
+	var w WitnessArgs
+	err = w.Deserialize(tx.Witnesses[0])
+	if err != nil {
+		t.Errorf("fail to deserialize witness: %s\n", err)
+		return
+	}
+
+	expectLen := 4 + 3*Blake160Size + 2*SignatureSize
+	if w.Lock == nil || len(*w.Lock) != expectLen {
+		t.Errorf("mismatch witness lock length, expect %v, got %v", expectLen, w.Lock)
+		return
+	}
+
+	// Signatures of signer 0 and 1, in signer order
+	lock := *w.Lock
+	first, _ := RecoverLockArgs(session.Message(), lock[4+3*Blake160Size:4+3*Blake160Size+SignatureSize])
+	if !first.Equal(hashes[0]) {
+		t.Errorf("mismatch first signature signer, expect %v, got %v", hashes[0], first)
+		return
+	}
+
+	_, err = NewMultisigConfig(3, 2, hashes)
+	if err == nil {
+		t.Errorf("expect error on require first n above threshold")
+		return
+	}
+}
@@ -0,0 +1,123 @@
+package types
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"testing"
+)
+
+// openRPCSchemas go types of openrpc component schemas, by schema name
+var openRPCSchemas = map[string]string{
+	"Script":                            "Script",
+	"OutPoint":                          "OutPoint",
+	"CellInput":                         "CellInput",
+	"CellOutput":                        "CellOutput",
+	"CellDep":                           "CellDep",
+	"TransactionView":                   "TransactionView",
+	"HeaderView":                        "HeaderView",
+	"UncleBlockView":                    "UncleBlockView",
+	"BlockView":                         "BlockView",
+	"EpochView":                         "EpochView",
+	"IndexerTip":                        "IndexerTip",
+	"IndexerCell":                       "IndexerCell",
+	"IndexerPagination_for_IndexerCell": "IndexerCells",
+}
+
+// TestOpenRPCSchemas check json fields of rpc types against the checked in
+// ckb openrpc document, so drift shows up when the document is updated
+func TestOpenRPCSchemas(t *testing.T) {
+	raw, err := ioutil.ReadFile("../testdata/openrpc.json")
+	if err != nil {
+		t.Errorf("fail to read openrpc document: %s\n", err)
+		return
+	}
+
+	var doc struct {
+		Components struct {
+			Schemas map[string]struct {
+				Type       string                     `json:"type"`
+				Required   []string                   `json:"required"`
+				Properties map[string]json.RawMessage `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+
+	err = json.Unmarshal(raw, &doc)
+	if err != nil {
+		t.Errorf("fail to parse openrpc document: %s\n", err)
+		return
+	}
+
+	generated, err := GenerateJSONSchema()
+	if err != nil {
+		t.Errorf("fail to generate json schema: %s\n", err)
+		return
+	}
+
+	var defs struct {
+		Definitions map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"definitions"`
+	}
+
+	err = json.Unmarshal(generated, &defs)
+	if err != nil {
+		t.Errorf("fail to parse json schema: %s\n", err)
+		return
+	}
+
+	for name, s := range doc.Components.Schemas {
+		if s.Type != "object" {
+			continue
+		}
+
+		goName, ok := openRPCSchemas[name]
+		if !ok {
+			t.Errorf("openrpc schema %s has no go type", name)
+			continue
+		}
+
+		def, ok := defs.Definitions[goName]
+		if !ok {
+			t.Errorf("go type %s of openrpc schema %s is not an rpc schema type", goName, name)
+			continue
+		}
+
+		expect, got := keys(s.Properties), keys(def.Properties)
+		if !equalStrings(expect, got) {
+			t.Errorf("mismatch %s fields, openrpc %v, go %s %v", name, expect, goName, got)
+			continue
+		}
+
+		for _, r := range s.Required {
+			if _, ok := def.Properties[r]; !ok {
+				t.Errorf("openrpc %s requires %s, missing in go %s", name, r, goName)
+			}
+		}
+	}
+}
+
+func keys(m map[string]json.RawMessage) []string {
+	ks := make([]string, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+
+	return ks
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,37 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Equal report whether two outpoints point to the same cell
+func (o OutPoint) Equal(other OutPoint) bool {
+	return o == other
+}
+
+// String outpoint in "hash:index" form, index in decimal
+func (o OutPoint) String() string {
+	return fmt.Sprintf("%s:%d", o.TxHash, uint32(o.Index))
+}
+
+// ParseOutPoint parse outpoint from "hash:index" form, index in decimal
+func ParseOutPoint(s string) (OutPoint, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return OutPoint{}, fmt.Errorf("invalid outpoint, should be hash:index")
+	}
+
+	h, err := ParseHash(parts[0])
+	if err != nil {
+		return OutPoint{}, err
+	}
+
+	i, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return OutPoint{}, err
+	}
+
+	return OutPoint{TxHash: h, Index: Uint32(i)}, nil
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOutPointString(t *testing.T) {
+	outpoint := `{
+		"tx_hash": "0xe49352ee4984694d88eb3c1493a33d69d61c786dc5b0a32c4b3978d4fad64379",
+		"index":  "0x6"
+	}`
+
+	expect := "0xe49352ee4984694d88eb3c1493a33d69d61c786dc5b0a32c4b3978d4fad64379:6"
+
+	var o OutPoint
+
+	err := json.Unmarshal([]byte(outpoint), &o)
+	if err != nil {
+		t.Errorf("fail to unmarshal test outpoint json: %s\n", err)
+		return
+	}
+
+	if o.String() != expect {
+		t.Errorf("mismatch result, expect %v, got %v", expect, o.String())
+		return
+	}
+
+	parsed, err := ParseOutPoint(expect)
+	if err != nil {
+		t.Errorf("fail to parse outpoint: %s\n", err)
+		return
+	}
+
+	if !parsed.Equal(o) {
+		t.Errorf("mismatch parsed outpoint, expect %v, got %v", o, parsed)
+		return
+	}
+
+	set := map[OutPoint]bool{o: true}
+	if !set[parsed] {
+		t.Errorf("parsed outpoint should hit map key")
+		return
+	}
+
+	_, err = ParseOutPoint("0xe49352ee4984694d88eb3c1493a33d69d61c786dc5b0a32c4b3978d4fad64379")
+	if err == nil {
+		t.Errorf("expect error on missing index")
+		return
+	}
+}
@@ -0,0 +1,161 @@
+package types
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+)
+
+// R1LockSize witness lock of r1 based locks, public key then signature
+/*
+ *     pubkey(64) | r(32) | s(32)
+ *
+ * Signing message is sighash all with lock filled by 128 zero bytes.
+ */
+const R1LockSize = R1PubkeySize + R1SignatureSize
+
+// P256Signer secp256r1 signer, key held by HSM or kms through DERSigner,
+// enterprise HSMs often only store P-256 keys
+type P256Signer struct {
+	der    DERSigner
+	pubkey []byte
+}
+
+// NewP256Signer new P-256 signer, pubkey is raw, uncompressed, compressed
+// or DER SubjectPublicKeyInfo as exported by HSM
+func NewP256Signer(der DERSigner, pubkey []byte) (*P256Signer, error) {
+	if len(pubkey) > 0 && pubkey[0] == 0x30 {
+		key, err := x509.ParsePKIXPublicKey(pubkey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key info, %s", err)
+		}
+
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok || pub.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("invalid public key info, not a secp256r1 key")
+		}
+
+		pubkey = R1PubkeyBytes(pub)
+	}
+
+	pub, err := ParseR1Pubkey(pubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &P256Signer{der: der, pubkey: R1PubkeyBytes(pub)}, nil
+}
+
+// NewP256KeySigner in memory P-256 signer from 32 bytes private key, for
+// tests and dev chains
+func NewP256KeySigner(seckey []byte) (*P256Signer, error) {
+	if len(seckey) != 32 {
+		return nil, fmt.Errorf("invalid private key, should be 32 bytes")
+	}
+
+	curve := elliptic.P256()
+
+	d := new(big.Int).SetBytes(seckey)
+	if d.Sign() == 0 || d.Cmp(curve.Params().N) >= 0 {
+		return nil, fmt.Errorf("invalid private key, out of range")
+	}
+
+	key := &ecdsa.PrivateKey{D: d}
+	key.Curve = curve
+	key.X, key.Y = curve.ScalarBaseMult(seckey)
+
+	return NewP256Signer(&p256Key{key: key}, R1PubkeyBytes(&key.PublicKey))
+}
+
+// GetPublicKey 64 bytes raw public key
+func (s *P256Signer) GetPublicKey() ([]byte, error) {
+	return append([]byte{}, s.pubkey...), nil
+}
+
+// SignDigest sign digest, returns 64 bytes r || s with low s
+func (s *P256Signer) SignDigest(digest Hash) ([]byte, error) {
+	der, err := s.der.SignDER(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := R1SignatureFromDER(der)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err = NormalizeR1Signature(sig)
+	if err != nil {
+		return nil, err
+	}
+
+	if !VerifyR1Signature(s.pubkey, digest[:], sig) {
+		return nil, fmt.Errorf("invalid der signature, not signed by given public key")
+	}
+
+	return sig, nil
+}
+
+// SignTransaction sign input group and fill lock of the first witness in
+// group with public key and signature
+func (s *P256Signer) SignTransaction(tx *Transaction, group []int) error {
+	msg, err := tx.R1Message(group)
+	if err != nil {
+		return err
+	}
+
+	sig, err := s.SignDigest(msg)
+	if err != nil {
+		return err
+	}
+
+	return tx.SetWitnessLock(group, append(append(Bytes{}, s.pubkey...), sig...))
+}
+
+// R1Message signing message of r1 based lock for input group
+func (t *Transaction) R1Message(group []int) (Hash, error) {
+	return t.sighashAllMessage(group, make(Bytes, R1LockSize))
+}
+
+// VerifyR1Lock verify r1 witness lock signs message, high s is rejected
+func VerifyR1Lock(msg Hash, lock []byte) error {
+	if len(lock) != R1LockSize {
+		return fmt.Errorf("invalid r1 lock, should be %d bytes", R1LockSize)
+	}
+
+	sig := lock[R1PubkeySize:]
+
+	normalized, err := NormalizeR1Signature(sig)
+	if err != nil {
+		return err
+	}
+
+	if !ConstantTimeEqual(normalized, sig) {
+		return fmt.Errorf("invalid r1 signature, high s")
+	}
+
+	if !VerifyR1Signature(lock[:R1PubkeySize], msg[:], sig) {
+		return fmt.Errorf("invalid r1 signature")
+	}
+
+	return nil
+}
+
+// p256Key in memory DERSigner
+type p256Key struct {
+	key *ecdsa.PrivateKey
+}
+
+// SignDER sign digest, returns DER signature
+func (k *p256Key) SignDER(digest Hash) ([]byte, error) {
+	r, s, err := ecdsa.Sign(rand.Reader, k.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(struct{ R, S *big.Int }{r, s})
+}
@@ -0,0 +1,142 @@
+package types
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+)
+
+// highSKey DERSigner always returning high s signatures, like some HSMs
+type highSKey struct {
+	key *ecdsa.PrivateKey
+}
+
+func (k *highSKey) SignDER(digest Hash) ([]byte, error) {
+	r, s, err := ecdsa.Sign(rand.Reader, k.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	n := elliptic.P256().Params().N
+	if s.Cmp(new(big.Int).Rsh(n, 1)) <= 0 {
+		s = new(big.Int).Sub(n, s)
+	}
+
+	return asn1.Marshal(struct{ R, S *big.Int }{r, s})
+}
+
+func TestP256Signer(t *testing.T) {
+	seckey := make([]byte, 32)
+	seckey[31] = 1
+
+	s, err := NewP256KeySigner(seckey)
+	if err != nil {
+		t.Errorf("fail to create signer: %s\n", err)
+		return
+	}
+
+	tx := &Transaction{
+		Inputs:      []CellInput{{PreviousOutput: OutPoint{TxHash: Hash{1}}}, {PreviousOutput: OutPoint{TxHash: Hash{2}}}},
+		Outputs:     []CellOutput{{Capacity: 61 * ShannonsPerCKB, Lock: Script{HashType: Type, Args: make(Bytes, 20)}}},
+		OutputsData: []Bytes{{}},
+		Witnesses:   []Bytes{{}, {}},
+	}
+
+	msg, err := tx.R1Message([]int{0, 1})
+	if err != nil {
+		t.Errorf("fail to compute r1 message: %s\n", err)
+		return
+	}
+
+	err = s.SignTransaction(tx, []int{0, 1})
+	if err != nil {
+		t.Errorf("fail to sign transaction: %s\n", err)
+		return
+	}
+
+	w, err := tx.WitnessArgsAt(0)
+	if err != nil {
+		t.Errorf("fail to deserialize witness: %s\n", err)
+		return
+	}
+
+	err = VerifyR1Lock(msg, *w.Lock)
+	if err != nil {
+		t.Errorf("fail to verify r1 lock: %s\n", err)
+		return
+	}
+
+	pub, _ := s.GetPublicKey()
+	if !bytes.Equal((*w.Lock)[:R1PubkeySize], pub) {
+		t.Errorf("mismatch result, expect %x, got %x", pub, (*w.Lock)[:R1PubkeySize])
+		return
+	}
+
+	if _, err = NewP256KeySigner(make([]byte, 32)); err == nil {
+		t.Errorf("expect error on zero private key")
+		return
+	}
+}
+
+func TestP256SignerLowS(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Errorf("fail to generate key: %s\n", err)
+		return
+	}
+
+	spki, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Errorf("fail to marshal public key info: %s\n", err)
+		return
+	}
+
+	s, err := NewP256Signer(&highSKey{key: key}, spki)
+	if err != nil {
+		t.Errorf("fail to create signer: %s\n", err)
+		return
+	}
+
+	digest := Hash{1, 2, 3}
+
+	sig, err := s.SignDigest(digest)
+	if err != nil {
+		t.Errorf("fail to sign digest: %s\n", err)
+		return
+	}
+
+	half := new(big.Int).Rsh(elliptic.P256().Params().N, 1)
+	if new(big.Int).SetBytes(sig[32:]).Cmp(half) > 0 {
+		t.Errorf("expect low s signature, got %x", sig)
+		return
+	}
+
+	lock := append(R1PubkeyBytes(&key.PublicKey), sig...)
+	err = VerifyR1Lock(digest, lock)
+	if err != nil {
+		t.Errorf("fail to verify r1 lock: %s\n", err)
+		return
+	}
+
+	// Flip back to high s, still a valid ecdsa signature but rejected
+	high := new(big.Int).Sub(elliptic.P256().Params().N, new(big.Int).SetBytes(sig[32:]))
+	for i := 32; i < R1SignatureSize; i++ {
+		lock[R1PubkeySize+i] = 0
+	}
+	copyPadded(lock[R1PubkeySize+32:], high.Bytes())
+
+	if !VerifyR1Signature(lock[:R1PubkeySize], digest[:], lock[R1PubkeySize:]) {
+		t.Errorf("expect high s signature to verify")
+		return
+	}
+
+	if VerifyR1Lock(digest, lock) == nil {
+		t.Errorf("expect error on high s signature")
+		return
+	}
+}
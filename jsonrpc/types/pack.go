@@ -0,0 +1,135 @@
+package types
+
+import (
+	"fmt"
+)
+
+// Pack serialize transaction with witnesses into molecule Transaction,
+// unlike Serialize which only covers RawTransaction
+func (t *Transaction) Pack() ([]byte, error) {
+	raw, err := t.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	ws := make([][]byte, len(t.Witnesses))
+	for i := 0; i < len(t.Witnesses); i++ {
+		w, err := t.Witnesses[i].Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		ws[i] = w
+	}
+
+	return SerializeTable([][]byte{raw, SerializeDynVec(ws)}), nil
+}
+
+// Unpack deserialize transaction with witnesses from molecule Transaction
+func (t *Transaction) Unpack(data []byte) error {
+	return t.unpack(data, decoder{})
+}
+
+func (t *Transaction) unpack(data []byte, d decoder) error {
+	fields, err := DeserializeTable(d.trimOffsets(data), 2)
+	if err != nil {
+		return err
+	}
+
+	err = t.deserialize(fields[0], d)
+	if err != nil {
+		return err
+	}
+
+	t.Witnesses, err = deserializeBytesVec(fields[1], d)
+	return err
+}
+
+// ReplaceWitnesses replace witnesses of packed molecule Transaction,
+// raw transaction bytes are copied as they are, not re-serialized
+/*
+ * Transaction is a table of raw transaction and witnesses, so replacing
+ * witnesses only rewrites the table header and witnesses vector, cheap in
+ * signing and fee adjustment loops that rewrite witnesses many times.
+ */
+func ReplaceWitnesses(packed []byte, witnesses []Bytes) ([]byte, error) {
+	fields, err := DeserializeTable(packed, 2)
+	if err != nil {
+		return nil, fmt.Errorf("invalid packed transaction, %s", err)
+	}
+
+	ws := make([][]byte, len(witnesses))
+	for i := 0; i < len(witnesses); i++ {
+		ws[i], err = witnesses[i].Serialize()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return SerializeTable([][]byte{fields[0], SerializeDynVec(ws)}), nil
+}
+
+// ReplaceWitness replace witness i of packed molecule Transaction, other
+// witnesses and raw transaction are copied as they are
+func ReplaceWitness(packed []byte, i int, witness Bytes) ([]byte, error) {
+	fields, err := DeserializeTable(packed, 2)
+	if err != nil {
+		return nil, fmt.Errorf("invalid packed transaction, %s", err)
+	}
+
+	ws, err := DeserializeDynVec(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid packed transaction witnesses, %s", err)
+	}
+
+	if i < 0 || i >= len(ws) {
+		return nil, fmt.Errorf("invalid witness index %d, transaction has %d witnesses", i, len(ws))
+	}
+
+	ws[i], err = witness.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return SerializeTable([][]byte{fields[0], SerializeDynVec(ws)}), nil
+}
+
+// ComputeHash calculate transaction hash, blake2b-256 of RawTransaction
+func (t *Transaction) ComputeHash() (Hash, error) {
+	var h Hash
+
+	raw, err := t.Serialize()
+	if err != nil {
+		return h, err
+	}
+
+	copy(h[:], Blake2b256(raw))
+	return h, nil
+}
+
+// ComputeHash calculate script hash, blake2b-256 of molecule Script, the
+// lock hash or type hash referenced by other scripts
+func (s *Script) ComputeHash() (Hash, error) {
+	var h Hash
+
+	b, err := s.Serialize()
+	if err != nil {
+		return h, err
+	}
+
+	copy(h[:], Blake2b256(b))
+	return h, nil
+}
+
+// ComputeHash calculate header hash, blake2b-256 of molecule Header
+func (h *Header) ComputeHash() (Hash, error) {
+	var hh Hash
+
+	b, err := h.Serialize()
+	if err != nil {
+		return hh, err
+	}
+
+	copy(hh[:], Blake2b256(b))
+	return hh, nil
+}
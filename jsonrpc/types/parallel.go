@@ -0,0 +1,80 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// DecodeBlocks decode molecule blocks concurrently, result keeps input
+// order, workers <= 0 means one per cpu
+func DecodeBlocks(raw [][]byte, workers int) ([]Block, error) {
+	blocks := make([]Block, len(raw))
+
+	err := parallelDecode(len(raw), workers, func(i int) error {
+		return blocks[i].Deserialize(raw[i])
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+// DecodeBlockViews decode json blocks from batched get_block responses
+// concurrently, result keeps input order, workers <= 0 means one per cpu
+func DecodeBlockViews(raw []json.RawMessage, workers int) ([]BlockView, error) {
+	blocks := make([]BlockView, len(raw))
+
+	err := parallelDecode(len(raw), workers, func(i int) error {
+		return json.Unmarshal(raw[i], &blocks[i])
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+// parallelDecode run decode on indexes 0 to n-1 with a worker pool, the
+// error of lowest index is returned so result does not depend on timing
+func parallelDecode(n int, workers int, decode func(i int) error) error {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	if workers > n {
+		workers = n
+	}
+
+	errs := make([]error, n)
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				errs[i] = decode(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("block %d, %s", i, err)
+		}
+	}
+
+	return nil
+}
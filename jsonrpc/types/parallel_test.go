@@ -0,0 +1,61 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecodeBlocks(t *testing.T) {
+	var view BlockView
+	err := json.Unmarshal([]byte(recordedBlock), &view)
+	if err != nil {
+		t.Errorf("fail to unmarshal block: %s\n", err)
+		return
+	}
+
+	var raw [][]byte
+	var rawJSON []json.RawMessage
+	for i := 0; i < 20; i++ {
+		b := Block{Header: view.Header.Header, Proposals: view.Proposals}
+		for _, tx := range view.Transactions {
+			b.Transactions = append(b.Transactions, tx.Transaction)
+		}
+		b.Header.Number = Uint64(i)
+
+		data, err := b.Serialize()
+		if err != nil {
+			t.Errorf("fail to serialize: %s\n", err)
+			return
+		}
+
+		raw = append(raw, data)
+		rawJSON = append(rawJSON, json.RawMessage(recordedBlock))
+	}
+
+	blocks, err := DecodeBlocks(raw, 4)
+	if err != nil {
+		t.Errorf("fail to decode blocks: %s\n", err)
+		return
+	}
+
+	for i := 0; i < len(blocks); i++ {
+		if blocks[i].Header.Number != Uint64(i) {
+			t.Errorf("mismatch block order, expect %v, got %v", i, blocks[i].Header.Number)
+			return
+		}
+	}
+
+	views, err := DecodeBlockViews(rawJSON, 0)
+	if err != nil || len(views) != 20 || views[19].Header.Hash != view.Header.Hash {
+		t.Errorf("fail to decode block views: %v", err)
+		return
+	}
+
+	raw[3] = raw[3][:10]
+	raw[7] = raw[7][:10]
+	_, err = DecodeBlocks(raw, 4)
+	if err == nil || err.Error()[:7] != "block 3" {
+		t.Errorf("expect error on block 3, got %v", err)
+		return
+	}
+}
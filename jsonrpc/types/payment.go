@@ -0,0 +1,168 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+)
+
+// PaymentURIScheme scheme of ckb payment uri
+const PaymentURIScheme = "ckb"
+
+// Payment uri query params
+const (
+	paymentAmount     = "amount"
+	paymentUdt        = "udt"
+	paymentUdtAmount  = "udt_amount"
+	paymentLabel      = "label"
+	paymentMessage    = "message"
+	paymentRequired   = "req-"
+	paymentUdtArgsLen = hashSize
+)
+
+// PaymentRequest payment uri, what point-of-sale and invoices show as qr
+// code
+/*
+ *     ckb:<address>?amount=<ckb>&udt=<sudt args>&udt_amount=<amount>&label=<label>&message=<message>
+ *
+ * Address is full format, any format DecodeAddress accepts is parsed.
+ * Amount is CKB with up to 8 fraction digits and no group separators, like
+ * "1234.5678". Udt is sudt type script args, the owner lock hash, and
+ * udt_amount is token amount in base units as decimal integer. Params are
+ * optional and unknown ones ignored, except unknown "req-" params, which a
+ * wallet must understand, reject the uri.
+ */
+type PaymentRequest struct {
+	Address Address
+	// Amount shannons, nil if not set
+	Amount *Uint64
+	// Udt sudt type script args, nil if not set
+	Udt *Hash
+	// UdtAmount token amount, nil if not set
+	UdtAmount *Uint128
+	Label     string
+	Message   string
+}
+
+// ParsePaymentURI parse ckb payment uri
+func ParsePaymentURI(s string) (*PaymentRequest, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payment uri, %s", err)
+	}
+
+	if !strings.EqualFold(u.Scheme, PaymentURIScheme) || u.Opaque == "" {
+		return nil, fmt.Errorf("invalid payment uri, should be %s:<address>", PaymentURIScheme)
+	}
+
+	addr, err := ParseAddress(u.Opaque)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payment uri, %s", err)
+	}
+
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payment uri, %s", err)
+	}
+
+	r := &PaymentRequest{Address: addr}
+	for k, v := range query {
+		if len(v) != 1 {
+			return nil, fmt.Errorf("invalid payment uri, duplicate param %s", k)
+		}
+
+		err = r.setParam(k, v[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid payment uri, %s", err)
+		}
+	}
+
+	if r.UdtAmount != nil && r.Udt == nil {
+		return nil, fmt.Errorf("invalid payment uri, %s without %s", paymentUdtAmount, paymentUdt)
+	}
+
+	return r, nil
+}
+
+// setParam set field of query param
+func (r *PaymentRequest) setParam(k string, v string) error {
+	switch k {
+	case paymentAmount:
+		if strings.ContainsAny(v, ", ") {
+			return fmt.Errorf("invalid amount %q, should be plain CKB", v)
+		}
+
+		c, err := ParseCapacity(v)
+		if err != nil {
+			return err
+		}
+		r.Amount = &c
+	case paymentUdt:
+		b, err := ParseBytes(v)
+		if err != nil {
+			return err
+		}
+
+		if len(b) != paymentUdtArgsLen {
+			return fmt.Errorf("invalid udt, args should be %d bytes", paymentUdtArgsLen)
+		}
+
+		var h Hash
+		copy(h[:], b)
+		r.Udt = &h
+	case paymentUdtAmount:
+		n, ok := new(big.Int).SetString(v, 10)
+		if !ok || !isDigits(v) {
+			return fmt.Errorf("invalid udt amount %q, should be decimal integer", v)
+		}
+
+		a, err := Uint128FromBig(n)
+		if err != nil {
+			return err
+		}
+		r.UdtAmount = &a
+	case paymentLabel:
+		r.Label = v
+	case paymentMessage:
+		r.Message = v
+	default:
+		if strings.HasPrefix(k, paymentRequired) {
+			return fmt.Errorf("unsupported required param %s", k)
+		}
+	}
+
+	return nil
+}
+
+// String payment uri, address in full format, params in sorted order
+func (r *PaymentRequest) String() string {
+	query := url.Values{}
+	if r.Amount != nil {
+		query.Set(paymentAmount, formatCKB(*r.Amount))
+	}
+
+	if r.Udt != nil {
+		query.Set(paymentUdt, r.Udt.String())
+	}
+
+	if r.UdtAmount != nil {
+		query.Set(paymentUdtAmount, r.UdtAmount.Big().String())
+	}
+
+	if r.Label != "" {
+		query.Set(paymentLabel, r.Label)
+	}
+
+	if r.Message != "" {
+		query.Set(paymentMessage, r.Message)
+	}
+
+	s := PaymentURIScheme + ":" + r.Address.String()
+	if len(query) == 0 {
+		return s
+	}
+
+	// Spaces as %20, wallets do not all decode '+'
+	return s + "?" + strings.Replace(query.Encode(), "+", "%20", -1)
+}
@@ -0,0 +1,71 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestPaymentURI(t *testing.T) {
+	args, _ := ParseBytes("0xb39bbc0b3673c7d36450bc14cfcdad2d559c6c64")
+	full := "ckb1qzda0cr08m85hc8jlnfp3zer7xulejywt49kt2rr0vthywaa50xwsqdnnw7qkdnnclfkg59uzn8umtfd2kwxceqxwquc4"
+
+	amount := Uint64(123456780000)
+	udt := SecpSighashAllCodeHash
+	udtAmount := Uint128{Hi: 1, Lo: 0}
+	r := &PaymentRequest{
+		Address:   Address{Network: Mainnet, Script: Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: args}},
+		Amount:    &amount,
+		Udt:       &udt,
+		UdtAmount: &udtAmount,
+		Label:     "coffee shop",
+		Message:   "order #42",
+	}
+
+	s := r.String()
+	expect := "ckb:" + full + "?amount=1234.5678&label=coffee%20shop&message=order%20%2342&udt=" + udt.String() + "&udt_amount=18446744073709551616"
+	if s != expect {
+		t.Errorf("mismatch result, expect %v, got %v", expect, s)
+		return
+	}
+
+	parsed, err := ParsePaymentURI(s)
+	if err != nil {
+		t.Errorf("fail to parse payment uri: %s\n", err)
+		return
+	}
+
+	if !parsed.Address.Script.Equal(&r.Address.Script) || *parsed.Amount != amount || *parsed.Udt != udt ||
+		*parsed.UdtAmount != udtAmount || parsed.Label != r.Label || parsed.Message != r.Message {
+		t.Errorf("mismatch result, expect %v, got %v", r, parsed)
+		return
+	}
+
+	// Plain address, unknown optional param ignored
+	parsed, err = ParsePaymentURI("ckb:" + full + "?foo=bar")
+	if err != nil {
+		t.Errorf("fail to parse payment uri: %s\n", err)
+		return
+	}
+
+	if parsed.Amount != nil || parsed.Udt != nil || parsed.Label != "" {
+		t.Errorf("expect no params, got %v", parsed)
+		return
+	}
+
+	for _, s := range []string{
+		"bitcoin:" + full,
+		"ckb:",
+		"ckb:" + full + "?amount=1,234",
+		"ckb:" + full + "?amount=0.000000001",
+		"ckb:" + full + "?amount=1&amount=2",
+		"ckb:" + full + "?udt=0x00",
+		"ckb:" + full + "?udt_amount=1",
+		"ckb:" + full + "?udt=" + udt.String() + "&udt_amount=-1",
+		"ckb:" + full + "?req-expiry=100",
+	} {
+		_, err = ParsePaymentURI(s)
+		if err == nil {
+			t.Errorf("expect error on %v", s)
+			return
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// PoolTransactionRejectType ckb tx pool reject reason type
+type PoolTransactionRejectType string
+
+// Reject reason types
+const (
+	RejectLowFeeRate                    PoolTransactionRejectType = "LowFeeRate"
+	RejectExceededMaximumAncestorsCount PoolTransactionRejectType = "ExceededMaximumAncestorsCount"
+	RejectExceededTransactionSizeLimit  PoolTransactionRejectType = "ExceededTransactionSizeLimit"
+	RejectFull                          PoolTransactionRejectType = "Full"
+	RejectDuplicated                    PoolTransactionRejectType = "Duplicated"
+	RejectMalformed                     PoolTransactionRejectType = "Malformed"
+	RejectDeclaredWrongCycles           PoolTransactionRejectType = "DeclaredWrongCycles"
+	RejectResolve                       PoolTransactionRejectType = "Resolve"
+	RejectVerification                  PoolTransactionRejectType = "Verification"
+	RejectExpiry                        PoolTransactionRejectType = "Expiry"
+	RejectRBFRejected                   PoolTransactionRejectType = "RBFRejected"
+	RejectInvalidated                   PoolTransactionRejectType = "Invalidated"
+)
+
+// PoolTransactionEntry ckb tx pool entry, payload of new_transaction and
+// proposed_transaction topics
+type PoolTransactionEntry struct {
+	Transaction TransactionView `json:"transaction"`
+	Cycles      Uint64          `json:"cycles"`
+	Size        Uint64          `json:"size"`
+	Fee         Uint64          `json:"fee"`
+	Timestamp   Uint64          `json:"timestamp"`
+}
+
+// RawTxPool result of get_raw_tx_pool, not verbose
+type RawTxPool struct {
+	Pending  []Hash `json:"pending"`
+	Proposed []Hash `json:"proposed"`
+}
+
+// TxPoolInfo result of tx_pool_info
+type TxPoolInfo struct {
+	TipHash          Hash   `json:"tip_hash"`
+	TipNumber        Uint64 `json:"tip_number"`
+	Pending          Uint64 `json:"pending"`
+	Proposed         Uint64 `json:"proposed"`
+	Orphan           Uint64 `json:"orphan"`
+	TotalTxSize      Uint64 `json:"total_tx_size"`
+	TotalTxCycles    Uint64 `json:"total_tx_cycles"`
+	MinFeeRate       Uint64 `json:"min_fee_rate"`
+	LastTxsUpdatedAt Uint64 `json:"last_txs_updated_at"`
+	TxSizeLimit      Uint64 `json:"tx_size_limit"`
+	MaxTxPoolSize    Uint64 `json:"max_tx_pool_size"`
+	// MinRbfRate absent before node supports replace by fee
+	MinRbfRate      *Uint64 `json:"min_rbf_rate,omitempty"`
+	VerifyQueueSize *Uint64 `json:"verify_queue_size,omitempty"`
+}
+
+// SupportsRBF report whether node accepts replace by fee, node disables it
+// by setting min_rbf_rate no higher than min_fee_rate
+func (i *TxPoolInfo) SupportsRBF() bool {
+	return i.MinRbfRate != nil && *i.MinRbfRate > i.MinFeeRate
+}
+
+// PoolTransactionReject ckb tx pool reject reason
+type PoolTransactionReject struct {
+	Type        PoolTransactionRejectType `json:"type"`
+	Description string                    `json:"description"`
+}
+
+// RejectedTransaction payload of rejected_transaction topic
+/*
+ * The node sends it as a two elements array:
+ *
+ *     [PoolTransactionEntry, PoolTransactionReject]
+ */
+type RejectedTransaction struct {
+	Entry  PoolTransactionEntry
+	Reason PoolTransactionReject
+}
+
+// UnmarshalJSON unmarshal rejected transaction from json array
+func (r *RejectedTransaction) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	if len(raw) != 2 {
+		return fmt.Errorf("invalid rejected transaction, should be 2 elements array")
+	}
+
+	err = json.Unmarshal(raw[0], &r.Entry)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw[1], &r.Reason)
+}
+
+// MarshalJSON marshal rejected transaction to json array
+func (r RejectedTransaction) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{r.Entry, r.Reason})
+}
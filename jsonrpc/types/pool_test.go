@@ -0,0 +1,60 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUnmarshalRejectedTransaction(t *testing.T) {
+	payload := `[
+		{
+			"transaction": {
+				"cell_deps": [],
+				"hash": "0xa0ef4eb5f4ceeb08a4c8524d84c5da95dce2f608e0ca2ec8091191b0f330c6e3",
+				"header_deps": [],
+				"inputs": [],
+				"outputs": [],
+				"outputs_data": [],
+				"version": "0x0",
+				"witnesses": []
+			},
+			"cycles": "0x219",
+			"size": "0x112",
+			"fee": "0x16923f7dcf",
+			"timestamp": "0x17c983e6e44"
+		},
+		{
+			"type": "Resolve",
+			"description": "Resolve failed Dead(OutPoint(0x0000000000000000000000000000000000000000000000000000000000000000ffffffff))"
+		}
+	]`
+
+	var r RejectedTransaction
+
+	err := json.Unmarshal([]byte(payload), &r)
+	if err != nil {
+		t.Errorf("fail to unmarshal rejected transaction json: %s\n", err)
+		return
+	}
+
+	if r.Entry.Transaction.Hash.String() != "0xa0ef4eb5f4ceeb08a4c8524d84c5da95dce2f608e0ca2ec8091191b0f330c6e3" {
+		t.Errorf("mismatch transaction hash, got %v", r.Entry.Transaction.Hash)
+		return
+	}
+
+	if r.Entry.Fee != 0x16923f7dcf {
+		t.Errorf("mismatch fee, got %v", r.Entry.Fee)
+		return
+	}
+
+	if r.Reason.Type != RejectResolve {
+		t.Errorf("mismatch reject type, expect %v, got %v", RejectResolve, r.Reason.Type)
+		return
+	}
+
+	err = json.Unmarshal([]byte(`[{"cycles": "0x0"}]`), &r)
+	if err == nil {
+		t.Errorf("expect error on single element payload")
+		return
+	}
+}
@@ -0,0 +1,174 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+)
+
+// PrivateKeySize secp256k1 private key size
+const PrivateKeySize = 32
+
+// PrivateKey secp256k1 private key held in a buffer that Destroy wipes, so
+// long running hot wallets can bound how long key material stays in memory
+/*
+ * Only the buffer owned by PrivateKey is wiped. Bytes the key was created
+ * from belong to caller, and the secp256k1 library may keep transient
+ * copies on its own stack while signing. Using a destroyed key is an
+ * error.
+ */
+type PrivateKey struct {
+	mu  sync.RWMutex
+	key []byte
+}
+
+// NewPrivateKey new private key from 32 bytes, copied, caller should wipe
+// seckey once done with it
+func NewPrivateKey(seckey []byte) (*PrivateKey, error) {
+	if len(seckey) != PrivateKeySize {
+		return nil, fmt.Errorf("invalid private key, should be %d bytes", PrivateKeySize)
+	}
+
+	d := new(big.Int).SetBytes(seckey)
+	if d.Sign() == 0 || d.Cmp(secp256k1.S256().Params().N) >= 0 {
+		return nil, fmt.Errorf("invalid private key, out of range")
+	}
+
+	return &PrivateKey{key: append(make([]byte, 0, PrivateKeySize), seckey...)}, nil
+}
+
+// generateKeyAttempts candidates read before giving up, a uniform reader
+// falls out of range with probability about 2^-128 per candidate
+const generateKeyAttempts = 16
+
+// GenerateKey generate private key from rand, usually crypto/rand.Reader
+/*
+ * Candidates of 32 bytes are read until one is in [1, n), n the curve
+ * order, so keys are uniform and never rejected by signing. Candidates are
+ * wiped after use. Pass DeterministicRand only in tests.
+ */
+func GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	b := make([]byte, PrivateKeySize)
+	defer wipeBytes(b)
+
+	for i := 0; i < generateKeyAttempts; i++ {
+		_, err := io.ReadFull(rand, b)
+		if err != nil {
+			return nil, fmt.Errorf("fail to read random bytes, %s", err)
+		}
+
+		k, err := NewPrivateKey(b)
+		if err == nil {
+			return k, nil
+		}
+	}
+
+	return nil, fmt.Errorf("fail to generate private key, random source out of range %d times", generateKeyAttempts)
+}
+
+// deterministicRand blake2b-256 of seed and counter, block by block
+type deterministicRand struct {
+	seed    []byte
+	counter uint64
+	buf     []byte
+}
+
+// DeterministicRand reader of bytes derived from seed, same seed same
+// keys, for reproducible tests only, never for real keys
+func DeterministicRand(seed []byte) io.Reader {
+	return &deterministicRand{seed: append([]byte{}, seed...)}
+}
+
+// Read fill p from blocks of blake2b-256(seed || counter in little-endian)
+func (r *deterministicRand) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.buf) == 0 {
+			var c [uint64Size]byte
+			binary.LittleEndian.PutUint64(c[:], r.counter)
+			r.counter++
+
+			r.buf = Blake2b256(append(append([]byte{}, r.seed...), c[:]...))
+		}
+
+		m := copy(p[n:], r.buf)
+		r.buf = r.buf[m:]
+		n += m
+	}
+
+	return n, nil
+}
+
+// LockArgs default secp256k1 lock args of key
+func (k *PrivateKey) LockArgs() (Bytes, error) {
+	pub, err := k.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return PubkeyToLockArgs(pub)
+}
+
+// Address address of default secp256k1 lock of key
+func (k *PrivateKey) Address(network Network) (Address, error) {
+	pub, err := k.PublicKey()
+	if err != nil {
+		return Address{}, err
+	}
+
+	return PubkeyToAddress(network, pub)
+}
+
+// Destroy wipe key material, safe to call more than once
+func (k *PrivateKey) Destroy() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	wipeBytes(k.key)
+	k.key = nil
+}
+
+// Destroyed report whether key was destroyed
+func (k *PrivateKey) Destroyed() bool {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.key == nil
+}
+
+// PublicKey compressed public key
+func (k *PrivateKey) PublicKey() ([]byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if k.key == nil {
+		return nil, fmt.Errorf("private key destroyed")
+	}
+
+	x, y := secp256k1.S256().ScalarBaseMult(k.key)
+
+	return secp256k1.CompressPubkey(x, y), nil
+}
+
+// Sign sign digest, returns 65 bytes recoverable signature
+func (k *PrivateKey) Sign(digest Hash) ([]byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if k.key == nil {
+		return nil, fmt.Errorf("private key destroyed")
+	}
+
+	return secp256k1.Sign(digest[:], k.key)
+}
+
+// wipeBytes overwrite b with zeros
+func wipeBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,136 @@
+package types
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPrivateKeyDestroy(t *testing.T) {
+	seckey := make([]byte, PrivateKeySize)
+	seckey[31] = 1
+
+	key, err := NewPrivateKey(seckey)
+	if err != nil {
+		t.Errorf("fail to create private key: %s\n", err)
+		return
+	}
+
+	// Key owns its copy
+	seckey[31] = 2
+
+	s := NewPrivateKeySigner(key)
+	pub, err := s.GetPublicKey()
+	if err != nil {
+		t.Errorf("fail to get public key: %s\n", err)
+		return
+	}
+
+	// Generator point
+	expect := "0x0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+	if Bytes(pub).String() != expect {
+		t.Errorf("mismatch result, expect %v, got %v", expect, Bytes(pub))
+		return
+	}
+
+	digest := Hash{1}
+	sig, err := s.SignDigest(digest)
+	if err != nil {
+		t.Errorf("fail to sign digest: %s\n", err)
+		return
+	}
+
+	recovered, err := RecoverPubkey(digest, sig)
+	if err != nil || !Bytes(recovered).Equal(pub) {
+		t.Errorf("mismatch recovered pubkey, got %v %v", Bytes(recovered), err)
+		return
+	}
+
+	buf := key.key
+	s.Destroy()
+	s.Destroy()
+
+	if !key.Destroyed() || !Bytes(buf).Equal(make(Bytes, PrivateKeySize)) {
+		t.Errorf("expect key material wiped, got %v", Bytes(buf))
+		return
+	}
+
+	_, err = s.SignDigest(digest)
+	if err == nil {
+		t.Errorf("expect error on destroyed key")
+		return
+	}
+
+	_, err = s.GetPublicKey()
+	if err == nil {
+		t.Errorf("expect error on destroyed key")
+		return
+	}
+}
+
+func TestNewPrivateKey(t *testing.T) {
+	// Zero and curve order are out of range
+	order, _ := ParseBytes("0xfffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+	for _, b := range [][]byte{make([]byte, PrivateKeySize), order, make([]byte, 31)} {
+		_, err := NewPrivateKey(b)
+		if err == nil {
+			t.Errorf("expect error on private key %v", Bytes(b))
+			return
+		}
+	}
+}
+
+func TestGenerateKey(t *testing.T) {
+	k1, err := GenerateKey(DeterministicRand([]byte("seed")))
+	if err != nil {
+		t.Errorf("fail to generate key: %s\n", err)
+		return
+	}
+
+	k2, _ := GenerateKey(DeterministicRand([]byte("seed")))
+	k3, _ := GenerateKey(DeterministicRand([]byte("other seed")))
+
+	args1, err := k1.LockArgs()
+	if err != nil {
+		t.Errorf("fail to derive lock args: %s\n", err)
+		return
+	}
+
+	args2, _ := k2.LockArgs()
+	args3, _ := k3.LockArgs()
+	if !args1.Equal(args2) || args1.Equal(args3) {
+		t.Errorf("mismatch deterministic keys, got %v %v %v", args1, args2, args3)
+		return
+	}
+
+	addr, err := k1.Address(Testnet)
+	if err != nil {
+		t.Errorf("fail to derive address: %s\n", err)
+		return
+	}
+
+	if addr.Network != Testnet || !addr.Script.Args.Equal(args1) || addr.Script.CodeHash != SecpSighashAllCodeHash {
+		t.Errorf("mismatch address, got %v", addr.Script)
+		return
+	}
+
+	// Source stuck above curve order is rejected
+	_, err = GenerateKey(bytes.NewReader(bytes.Repeat([]byte{0xff}, PrivateKeySize*generateKeyAttempts)))
+	if err == nil {
+		t.Errorf("expect error on out of range source")
+		return
+	}
+
+	// Short source
+	_, err = GenerateKey(bytes.NewReader(make([]byte, 8)))
+	if err == nil {
+		t.Errorf("expect error on short source")
+		return
+	}
+
+	k1.Destroy()
+	_, err = k1.Address(Mainnet)
+	if err == nil {
+		t.Errorf("expect error on destroyed key")
+		return
+	}
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// ProposalShortID ckb proposal short id, first 10 bytes of transaction
+// hash, '0x' prefix hex string in json
+type ProposalShortID [10]byte
+
+// ProposalShortIDFromTransactionHash proposal short id of transaction
+func ProposalShortIDFromTransactionHash(txHash Hash) ProposalShortID {
+	var p ProposalShortID
+	copy(p[:], txHash[:len(p)])
+
+	return p
+}
+
+// ParseProposalShortID parse proposal short id from '0x' prefix hex string
+func ParseProposalShortID(s string) (ProposalShortID, error) {
+	var p ProposalShortID
+
+	err := check0xPrefix(s)
+	if err != nil {
+		return p, err
+	}
+
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return p, err
+	}
+
+	if len(b) != len(p) {
+		return p, fmt.Errorf("invalid proposal short id, should be 10 bytes")
+	}
+
+	copy(p[:], b)
+	return p, nil
+}
+
+// String '0x' prefix hex string
+func (p ProposalShortID) String() string {
+	return "0x" + hex.EncodeToString(p[:])
+}
+
+// MarshalJSON marshal proposal short id to '0x' prefix hex string
+func (p ProposalShortID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON unmarshal proposal short id from '0x' prefix hex string
+func (p *ProposalShortID) UnmarshalJSON(data []byte) error {
+	var s string
+
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+
+	pp, err := ParseProposalShortID(s)
+	if err != nil {
+		return err
+	}
+
+	*p = pp
+	return nil
+}
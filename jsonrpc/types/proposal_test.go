@@ -0,0 +1,29 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestProposalShortID(t *testing.T) {
+	txHash, _ := ParseHash("0x365698b50ca0da75dca2c87f9e7b563811d3b5813736b8cc62cc3b106faceb17")
+
+	p := ProposalShortIDFromTransactionHash(txHash)
+	if p.String() != "0x365698b50ca0da75dca2" {
+		t.Errorf("mismatch result, expect %v, got %v", "0x365698b50ca0da75dca2", p)
+		return
+	}
+
+	var ps []ProposalShortID
+	err := json.Unmarshal([]byte(`["0x365698b50ca0da75dca2"]`), &ps)
+	if err != nil || len(ps) != 1 || ps[0] != p {
+		t.Errorf("mismatch unmarshal result, got %v %v", ps, err)
+		return
+	}
+
+	_, err = ParseProposalShortID("0x365698b50ca0da75dc")
+	if err == nil {
+		t.Errorf("expect error on 9 bytes short id")
+		return
+	}
+}
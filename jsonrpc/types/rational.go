@@ -0,0 +1,236 @@
+package types
+
+import (
+	"fmt"
+)
+
+// RationalU256 ckb rational number over uint256, kept in lowest terms
+/*
+ * Same semantics as ckb util rational RationalU256, used by consensus for
+ * epoch fractions and difficulty, so results match the node exactly:
+ *
+ *     Add and Mul reduce by gcd before multiplying.
+ *     IntoU256 floors.
+ *     SaturatingSub stops at zero.
+ *
+ * Unlike ckb, which panics, overflow and division by zero are errors.
+ * Zero value has zero denominator and is invalid, use NewRationalU256.
+ */
+type RationalU256 struct {
+	numer Uint256
+	denom Uint256
+}
+
+// NewRationalU256 new rational numer / denom in lowest terms
+func NewRationalU256(numer Uint256, denom Uint256) (RationalU256, error) {
+	if denom.IsZero() {
+		return RationalU256{}, fmt.Errorf("rational u256 denominator is zero")
+	}
+
+	g := gcdUint256(numer, denom)
+	n, _, _ := numer.DivMod(g)
+	d, _, _ := denom.DivMod(g)
+
+	return RationalU256{numer: n, denom: d}, nil
+}
+
+// RationalU256FromU256 rational of integer
+func RationalU256FromU256(u Uint256) RationalU256 {
+	return RationalU256{numer: u, denom: NewUint256(1)}
+}
+
+// Numer numerator
+func (r RationalU256) Numer() Uint256 {
+	return r.numer
+}
+
+// Denom denominator
+func (r RationalU256) Denom() Uint256 {
+	return r.denom
+}
+
+// IsZero report whether rational is zero
+func (r RationalU256) IsZero() bool {
+	return r.numer.IsZero()
+}
+
+// Cmp compare rationals, return -1 if r < o, 0 if r == o, 1 if r > o
+func (r RationalU256) Cmp(o RationalU256) (int, error) {
+	if r.denom == o.denom {
+		return r.numer.Cmp(o.numer), nil
+	}
+
+	lhs, err := r.numer.Mul(o.denom)
+	if err != nil {
+		return 0, err
+	}
+
+	rhs, err := o.numer.Mul(r.denom)
+	if err != nil {
+		return 0, err
+	}
+
+	return lhs.Cmp(rhs), nil
+}
+
+// Add return r + o
+func (r RationalU256) Add(o RationalU256) (RationalU256, error) {
+	if r.denom == o.denom {
+		n, err := r.numer.Add(o.numer)
+		if err != nil {
+			return RationalU256{}, err
+		}
+
+		return NewRationalU256(n, r.denom)
+	}
+
+	lhs, rhs, lcm, err := r.commonDenom(o)
+	if err != nil {
+		return RationalU256{}, err
+	}
+
+	n, err := lhs.Add(rhs)
+	if err != nil {
+		return RationalU256{}, err
+	}
+
+	return NewRationalU256(n, lcm)
+}
+
+// Sub return r - o, error if o > r
+func (r RationalU256) Sub(o RationalU256) (RationalU256, error) {
+	if r.denom == o.denom {
+		n, err := r.numer.Sub(o.numer)
+		if err != nil {
+			return RationalU256{}, err
+		}
+
+		return NewRationalU256(n, r.denom)
+	}
+
+	lhs, rhs, lcm, err := r.commonDenom(o)
+	if err != nil {
+		return RationalU256{}, err
+	}
+
+	n, err := lhs.Sub(rhs)
+	if err != nil {
+		return RationalU256{}, err
+	}
+
+	return NewRationalU256(n, lcm)
+}
+
+// SaturatingSub return r - o, zero if o > r
+func (r RationalU256) SaturatingSub(o RationalU256) (RationalU256, error) {
+	c, err := r.Cmp(o)
+	if err != nil {
+		return RationalU256{}, err
+	}
+
+	if c <= 0 {
+		return RationalU256FromU256(Uint256{}), nil
+	}
+
+	return r.Sub(o)
+}
+
+// Mul return r * o
+func (r RationalU256) Mul(o RationalU256) (RationalU256, error) {
+	gad := gcdUint256(r.numer, o.denom)
+	gbc := gcdUint256(r.denom, o.numer)
+
+	a, _, _ := r.numer.DivMod(gad)
+	b, _, _ := r.denom.DivMod(gbc)
+	c, _, _ := o.numer.DivMod(gbc)
+	d, _, _ := o.denom.DivMod(gad)
+
+	n, err := a.Mul(c)
+	if err != nil {
+		return RationalU256{}, err
+	}
+
+	dd, err := b.Mul(d)
+	if err != nil {
+		return RationalU256{}, err
+	}
+
+	return RationalU256{numer: n, denom: dd}, nil
+}
+
+// Div return r / o, error if o is zero
+func (r RationalU256) Div(o RationalU256) (RationalU256, error) {
+	if o.IsZero() {
+		return RationalU256{}, fmt.Errorf("rational u256 division by zero")
+	}
+
+	return r.Mul(RationalU256{numer: o.denom, denom: o.numer})
+}
+
+// AddU256 return r + u
+func (r RationalU256) AddU256(u Uint256) (RationalU256, error) {
+	return r.Add(RationalU256FromU256(u))
+}
+
+// SubU256 return r - u, error if u > r
+func (r RationalU256) SubU256(u Uint256) (RationalU256, error) {
+	return r.Sub(RationalU256FromU256(u))
+}
+
+// MulU256 return r * u
+func (r RationalU256) MulU256(u Uint256) (RationalU256, error) {
+	return r.Mul(RationalU256FromU256(u))
+}
+
+// DivU256 return r / u, error if u is zero
+func (r RationalU256) DivU256(u Uint256) (RationalU256, error) {
+	return r.Div(RationalU256FromU256(u))
+}
+
+// IntoU256 integer part, numer / denom rounded down
+func (r RationalU256) IntoU256() (Uint256, error) {
+	q, _, err := r.numer.DivMod(r.denom)
+	return q, err
+}
+
+// String rational in "numer/denom" form, both '0x' prefix hex
+func (r RationalU256) String() string {
+	return r.numer.String() + "/" + r.denom.String()
+}
+
+// commonDenom numerators over least common denominator
+func (r RationalU256) commonDenom(o RationalU256) (Uint256, Uint256, Uint256, error) {
+	g := gcdUint256(r.denom, o.denom)
+	od, _, _ := o.denom.DivMod(g)
+
+	lcm, err := r.denom.Mul(od)
+	if err != nil {
+		return Uint256{}, Uint256{}, Uint256{}, err
+	}
+
+	lm, _, _ := lcm.DivMod(r.denom)
+	rm, _, _ := lcm.DivMod(o.denom)
+
+	lhs, err := r.numer.Mul(lm)
+	if err != nil {
+		return Uint256{}, Uint256{}, Uint256{}, err
+	}
+
+	rhs, err := o.numer.Mul(rm)
+	if err != nil {
+		return Uint256{}, Uint256{}, Uint256{}, err
+	}
+
+	return lhs, rhs, lcm, nil
+}
+
+// gcdUint256 greatest common divisor, gcd(0, n) is n, never zero for
+// non zero denominator
+func gcdUint256(a Uint256, b Uint256) Uint256 {
+	for !b.IsZero() {
+		_, r, _ := a.DivMod(b)
+		a, b = b, r
+	}
+
+	return a
+}
@@ -0,0 +1,116 @@
+package types
+
+import (
+	"testing"
+)
+
+func testRational(t *testing.T, n uint64, d uint64) RationalU256 {
+	r, err := NewRationalU256(NewUint256(n), NewUint256(d))
+	if err != nil {
+		t.Fatalf("fail to create rational: %s\n", err)
+	}
+
+	return r
+}
+
+func TestRationalU256(t *testing.T) {
+	half := testRational(t, 2, 4)
+	if half.Numer() != NewUint256(1) || half.Denom() != NewUint256(2) {
+		t.Errorf("mismatch result, expect 1/2, got %s", half)
+		return
+	}
+
+	third := testRational(t, 1, 3)
+
+	sum, err := half.Add(third)
+	if err != nil {
+		t.Errorf("fail to add: %s\n", err)
+		return
+	}
+
+	if sum != testRational(t, 5, 6) {
+		t.Errorf("mismatch result, expect 5/6, got %s", sum)
+		return
+	}
+
+	diff, err := half.Sub(third)
+	if err != nil {
+		t.Errorf("fail to sub: %s\n", err)
+		return
+	}
+
+	if diff != testRational(t, 1, 6) {
+		t.Errorf("mismatch result, expect 1/6, got %s", diff)
+		return
+	}
+
+	_, err = third.Sub(half)
+	if err == nil {
+		t.Errorf("expect error on sub underflow")
+		return
+	}
+
+	zero, err := third.SaturatingSub(half)
+	if err != nil || !zero.IsZero() {
+		t.Errorf("mismatch result, expect zero, got %s, %v", zero, err)
+		return
+	}
+
+	prod, err := testRational(t, 3, 4).Mul(testRational(t, 2, 9))
+	if err != nil {
+		t.Errorf("fail to mul: %s\n", err)
+		return
+	}
+
+	if prod != testRational(t, 1, 6) {
+		t.Errorf("mismatch result, expect 1/6, got %s", prod)
+		return
+	}
+
+	q, err := testRational(t, 7, 2).DivU256(NewUint256(2))
+	if err != nil {
+		t.Errorf("fail to div: %s\n", err)
+		return
+	}
+
+	u, err := q.IntoU256()
+	if err != nil || u != NewUint256(1) {
+		t.Errorf("mismatch result, expect 7/4 floor 1, got %s", u)
+		return
+	}
+
+	c, err := third.Cmp(half)
+	if err != nil || c != -1 {
+		t.Errorf("mismatch result, expect -1, got %d", c)
+		return
+	}
+
+	_, err = NewRationalU256(NewUint256(1), Uint256{})
+	if err == nil {
+		t.Errorf("expect error on zero denominator")
+		return
+	}
+
+	_, err = half.Div(RationalU256FromU256(Uint256{}))
+	if err == nil {
+		t.Errorf("expect error on division by zero")
+		return
+	}
+
+	_, err = RationalU256FromU256(MaxUint256()).AddU256(NewUint256(1))
+	if err == nil {
+		t.Errorf("expect error on overflow")
+		return
+	}
+
+	e, err := NewEpochNumberWithFraction(0x40001000005).RationalU256()
+	if err != nil {
+		t.Errorf("fail to convert epoch: %s\n", err)
+		return
+	}
+
+	if e != testRational(t, 21, 4) {
+		t.Errorf("mismatch result, expect 21/4, got %s", e)
+		return
+	}
+}
@@ -0,0 +1,79 @@
+package types
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// ReplaceByFee rebuild transaction paying more fee so node replaces the
+// original in tx pool, returned transaction needs signing again
+/*
+ * Replacement spends every original input, so inputs are a superset, and
+ * keeps outputs except the last change cell of given address, which is
+ * rebuilt. On top of fee rate, node requires
+ *
+ *     fee >= original fee + min_rbf_rate * size / 1000
+ *
+ * Plain cells of change address are added if change can not pay it.
+ */
+func (b *Builder) ReplaceByFee(original *Skeleton, change Address, feeRate Uint64, pool *TxPoolInfo) (*Transaction, error) {
+	if !pool.SupportsRBF() {
+		return nil, fmt.Errorf("node does not support replace by fee")
+	}
+
+	inputs, err := original.InputsCapacity()
+	if err != nil {
+		return nil, err
+	}
+
+	outputs, err := original.OutputsCapacity()
+	if err != nil {
+		return nil, err
+	}
+
+	if inputs < outputs {
+		return nil, fmt.Errorf("invalid original transaction, outputs exceed inputs")
+	}
+	originalFee := inputs - outputs
+
+	s := &Skeleton{Tx: original.Tx.Clone(), Cells: append([]LiveCell{}, original.Cells...)}
+
+	for i := len(s.Tx.Outputs) - 1; i >= 0; i-- {
+		o := &s.Tx.Outputs[i]
+		if o.Type == nil && len(s.Tx.OutputsData[i]) == 0 && o.Lock.Equal(&change.Script) {
+			s.Tx.Outputs = append(s.Tx.Outputs[:i], s.Tx.Outputs[i+1:]...)
+			s.Tx.OutputsData = append(s.Tx.OutputsData[:i], s.Tx.OutputsData[i+1:]...)
+			break
+		}
+	}
+
+	minRbfRate := *pool.MinRbfRate
+
+	err = b.balance(s, change, func(size uint64) (Uint64, error) {
+		byRate, err := CalculateFee(size, feeRate)
+		if err != nil {
+			return 0, err
+		}
+
+		byRbf, err := CalculateFee(size, minRbfRate)
+		if err != nil {
+			return 0, err
+		}
+
+		required, carry := bits.Add64(uint64(originalFee), uint64(byRbf), 0)
+		if carry != 0 {
+			return 0, fmt.Errorf("fee overflow")
+		}
+
+		if uint64(byRate) > required {
+			return byRate, nil
+		}
+
+		return Uint64(required), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Tx, nil
+}
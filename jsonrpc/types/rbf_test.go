@@ -0,0 +1,81 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestReplaceByFee(t *testing.T) {
+	from := Address{Network: Testnet, Script: Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: make(Bytes, 20)}}
+	to := Address{Network: Testnet, Script: Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: append(Bytes{0x01}, make(Bytes, 19)...)}}
+
+	c := testCollector{
+		from.Script.Args.String(): {
+			testPlainCell(1, from.Script, 200),
+			testPlainCell(2, from.Script, 100),
+		},
+	}
+
+	b := NewBuilder(c, testRegistry())
+
+	original := NewSkeleton()
+	original.AddOutput(CellOutput{Capacity: 100 * ShannonsPerCKB, Lock: to.Script}, Bytes{})
+
+	err := b.Balance(original, from, DefaultMinFeeRate)
+	if err != nil {
+		t.Errorf("fail to balance original: %s\n", err)
+		return
+	}
+
+	var pool TxPoolInfo
+	err = json.Unmarshal([]byte(`{"tip_hash":"0x0000000000000000000000000000000000000000000000000000000000000000","tip_number":"0x1","pending":"0x1","proposed":"0x0","orphan":"0x0","total_tx_size":"0x1","total_tx_cycles":"0x1","min_fee_rate":"0x3e8","min_rbf_rate":"0x5dc","last_txs_updated_at":"0x0","tx_size_limit":"0x7d000","max_tx_pool_size":"0xaba9500","verify_queue_size":"0x0"}`), &pool)
+	if err != nil {
+		t.Errorf("fail to unmarshal tx pool info: %s\n", err)
+		return
+	}
+
+	if !pool.SupportsRBF() {
+		t.Errorf("expect node to support rbf")
+		return
+	}
+
+	originalFee, _ := original.Tx.Fee(DefaultMinFeeRate)
+
+	tx, err := b.ReplaceByFee(original, from, DefaultMinFeeRate, &pool)
+	if err != nil {
+		t.Errorf("fail to replace by fee: %s\n", err)
+		return
+	}
+
+	if len(tx.Inputs) != 1 || len(tx.Outputs) != 2 || !tx.Outputs[0].Equal(&original.Tx.Outputs[0]) {
+		t.Errorf("mismatch replacement, got %v %v", tx.Inputs, tx.Outputs)
+		return
+	}
+
+	// Same size, so fee is original fee plus min rbf rate of size
+	size, _ := tx.SizeInBlock()
+	byRbf, _ := CalculateFee(size, 1500)
+	fee := 200*ShannonsPerCKB - tx.Outputs[0].Capacity - tx.Outputs[1].Capacity
+	if fee != originalFee+byRbf {
+		t.Errorf("mismatch result, expect %v, got %v", originalFee+byRbf, fee)
+		return
+	}
+
+	// Original change can not pay very high fee rate, another cell is added
+	tx, err = b.ReplaceByFee(original, from, 100*ShannonsPerCKB, &pool)
+	if err != nil {
+		t.Errorf("fail to replace by high fee: %s\n", err)
+		return
+	}
+
+	if len(tx.Inputs) != 2 || tx.Inputs[0] != original.Tx.Inputs[0] {
+		t.Errorf("mismatch replacement inputs, got %v", tx.Inputs)
+		return
+	}
+
+	pool.MinRbfRate = nil
+	if _, err = b.ReplaceByFee(original, from, DefaultMinFeeRate, &pool); err == nil {
+		t.Errorf("expect error on node without rbf")
+		return
+	}
+}
@@ -0,0 +1,250 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// JSONSchemaDraft json schema draft used by generated documents
+const JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// rpcSchemaTypes rpc request and response types exported as schema
+// definitions
+var rpcSchemaTypes = []interface{}{
+	Script{},
+	OutPoint{},
+	CellInput{},
+	CellOutput{},
+	CellDep{},
+	Transaction{},
+	TransactionView{},
+	Header{},
+	HeaderView{},
+	UncleBlock{},
+	UncleBlockView{},
+	Block{},
+	BlockView{},
+	EpochView{},
+	ChainInfo{},
+	AlertMessage{},
+	SearchKey{},
+	IndexerTip{},
+	IndexerCells{},
+	PoolTransactionEntry{},
+	TxPoolInfo{},
+	RawTxPool{},
+	TransactionWithStatus{},
+	ScriptStatus{},
+	FetchHeaderResponse{},
+	FetchTransactionResponse{},
+	HeaderDigest{},
+	PoolTransactionReject{},
+	RejectedTransaction{},
+	DaoWithdrawingCalculationKind{},
+}
+
+func hexSchema(pattern string, description string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "string",
+		"pattern":     pattern,
+		"description": description,
+	}
+}
+
+func enumSchema(values ...string) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "string",
+		"enum": values,
+	}
+}
+
+// Schemas of types with custom json form
+var (
+	hexUintPattern = "^0x[0-9a-fA-F]+$"
+
+	scalarSchemas = map[reflect.Type]map[string]interface{}{
+		reflect.TypeOf(Hash{}):            hexSchema("^0x[0-9a-fA-F]{64}$", "32 bytes hex"),
+		reflect.TypeOf(ProposalShortID{}): hexSchema("^0x[0-9a-fA-F]{20}$", "10 bytes hex"),
+		reflect.TypeOf(Bytes{}):           hexSchema("^0x([0-9a-fA-F]{2})*$", "bytes hex"),
+		reflect.TypeOf(Uint32(0)):         hexSchema(hexUintPattern, "uint32 hex number"),
+		reflect.TypeOf(Uint64(0)):         hexSchema(hexUintPattern, "uint64 hex number"),
+		reflect.TypeOf(Uint128{}):         hexSchema(hexUintPattern, "uint128 hex number"),
+		reflect.TypeOf(Uint256{}):         hexSchema(hexUintPattern, "uint256 hex number"),
+
+		reflect.TypeOf(ScriptHashType("")):            enumSchema(string(Data), string(Type), string(Data1), string(Data2)),
+		reflect.TypeOf(DepType("")):                   enumSchema(string(Code), string(DepGroup)),
+		reflect.TypeOf(ScriptType("")):                enumSchema(string(ScriptTypeLock), string(ScriptTypeType)),
+		reflect.TypeOf(SearchMode("")):                enumSchema(string(Prefix), string(Exact), string(Partial)),
+		reflect.TypeOf(SearchOrder("")):               enumSchema(string(Asc), string(Desc)),
+		reflect.TypeOf(SetScriptsCommand("")):         enumSchema(string(SetScriptsAll), string(SetScriptsPartial), string(SetScriptsDelete)),
+		reflect.TypeOf(FetchStatus("")):               enumSchema(string(FetchStatusFetched), string(FetchStatusFetching), string(FetchStatusAdded), string(FetchStatusNotFound)),
+		reflect.TypeOf(CellStatus("")):                enumSchema(string(CellStatusLive), string(CellStatusDead), string(CellStatusUnknown)),
+		reflect.TypeOf(TxStatusType("")):              enumSchema(string(TxStatusPending), string(TxStatusProposed), string(TxStatusCommitted), string(TxStatusUnknown), string(TxStatusRejected)),
+		reflect.TypeOf(PoolTransactionRejectType("")): enumSchema(string(RejectLowFeeRate), string(RejectExceededMaximumAncestorsCount), string(RejectExceededTransactionSizeLimit), string(RejectFull), string(RejectDuplicated), string(RejectMalformed), string(RejectDeclaredWrongCycles), string(RejectResolve), string(RejectVerification), string(RejectExpiry), string(RejectRBFRejected), string(RejectInvalidated)),
+	}
+)
+
+// GenerateJSONSchema json schema document with a definition for every rpc
+// request and response type, named by go type name
+func GenerateJSONSchema() ([]byte, error) {
+	g := &schemaGenerator{defs: map[string]interface{}{}}
+
+	for _, v := range rpcSchemaTypes {
+		g.schemaOf(reflect.TypeOf(v))
+	}
+
+	doc := map[string]interface{}{
+		"$schema":     JSONSchemaDraft,
+		"title":       "ckb rpc types",
+		"definitions": g.defs,
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+type schemaGenerator struct {
+	defs map[string]interface{}
+}
+
+func ref(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/definitions/" + name}
+}
+
+// schemaOf schema of type, named structs are added to definitions and
+// referenced
+func (g *schemaGenerator) schemaOf(t reflect.Type) map[string]interface{} {
+	if s, ok := scalarSchemas[t]; ok {
+		return s
+	}
+
+	switch t {
+	case reflect.TypeOf(RejectedTransaction{}):
+		// Two elements array, see RejectedTransaction
+		g.define(t, func() map[string]interface{} {
+			return map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					g.schemaOf(reflect.TypeOf(PoolTransactionEntry{})),
+					g.schemaOf(reflect.TypeOf(PoolTransactionReject{})),
+				},
+				"minItems": 2,
+				"maxItems": 2,
+			}
+		})
+		return ref(t.Name())
+	case reflect.TypeOf(DaoWithdrawingCalculationKind{}):
+		g.define(t, func() map[string]interface{} {
+			tagged := func(typ string, value reflect.Type) map[string]interface{} {
+				return map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"type":  enumSchema(typ),
+						"value": g.schemaOf(value),
+					},
+					"required":             []string{"type", "value"},
+					"additionalProperties": false,
+				}
+			}
+
+			return map[string]interface{}{
+				"oneOf": []interface{}{
+					tagged("withdrawing_header_hash", reflect.TypeOf(Hash{})),
+					tagged("withdrawing_out_point", reflect.TypeOf(OutPoint{})),
+				},
+			}
+		})
+		return ref(t.Name())
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return map[string]interface{}{
+			"oneOf": []interface{}{g.schemaOf(t.Elem()), map[string]interface{}{"type": "null"}},
+		}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": g.schemaOf(t.Elem())}
+	case reflect.Array:
+		return map[string]interface{}{
+			"type":     "array",
+			"items":    g.schemaOf(t.Elem()),
+			"minItems": t.Len(),
+			"maxItems": t.Len(),
+		}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Struct:
+		g.define(t, func() map[string]interface{} {
+			return g.structSchema(t)
+		})
+		return ref(t.Name())
+	}
+
+	panic(fmt.Sprintf("no json schema for %s", t))
+}
+
+// define add definition once, placeholder breaks recursion
+func (g *schemaGenerator) define(t reflect.Type, build func() map[string]interface{}) {
+	if _, ok := g.defs[t.Name()]; ok {
+		return
+	}
+
+	g.defs[t.Name()] = map[string]interface{}{}
+	g.defs[t.Name()] = build()
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]interface{} {
+	props := map[string]interface{}{}
+	required := []string{}
+
+	g.addFields(t, props, &required)
+
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           props,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// addFields add json fields of struct, embedded structs are flattened
+func (g *schemaGenerator) addFields(t reflect.Type, props map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		if f.Anonymous && tag == "" && f.Type.Kind() == reflect.Struct {
+			g.addFields(f.Type, props, required)
+			continue
+		}
+
+		name := f.Name
+		parts := strings.Split(tag, ",")
+		if parts[0] != "" {
+			name = parts[0]
+		}
+
+		omitEmpty := false
+		for _, p := range parts[1:] {
+			omitEmpty = omitEmpty || p == "omitempty"
+		}
+
+		props[name] = g.schemaOf(f.Type)
+		if !omitEmpty {
+			*required = append(*required, name)
+		}
+	}
+}
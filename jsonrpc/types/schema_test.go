@@ -0,0 +1,131 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+// validateSchema minimal validator for the keywords generated schemas use
+func validateSchema(defs map[string]interface{}, s map[string]interface{}, v interface{}) error {
+	if r, ok := s["$ref"].(string); ok {
+		return validateSchema(defs, defs[r[len("#/definitions/"):]].(map[string]interface{}), v)
+	}
+
+	if alts, ok := s["oneOf"].([]interface{}); ok {
+		for _, alt := range alts {
+			if validateSchema(defs, alt.(map[string]interface{}), v) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("no alternative matches %v", v)
+	}
+
+	switch s["type"] {
+	case "null":
+		if v != nil {
+			return fmt.Errorf("expect null, got %v", v)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("expect boolean, got %v", v)
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("expect string, got %v", v)
+		}
+		if p, ok := s["pattern"].(string); ok && !regexp.MustCompile(p).MatchString(str) {
+			return fmt.Errorf("%q mismatch pattern %s", str, p)
+		}
+		if enum, ok := s["enum"].([]interface{}); ok {
+			found := false
+			for _, e := range enum {
+				found = found || e == str
+			}
+			if !found {
+				return fmt.Errorf("%q not in enum %v", str, enum)
+			}
+		}
+	case "array":
+		items, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("expect array, got %v", v)
+		}
+		for i, item := range items {
+			is, ok := s["items"].(map[string]interface{})
+			if !ok {
+				is = s["items"].([]interface{})[i].(map[string]interface{})
+			}
+			if err := validateSchema(defs, is, item); err != nil {
+				return err
+			}
+		}
+	case "object":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("expect object, got %v", v)
+		}
+		props := s["properties"].(map[string]interface{})
+		for k, fv := range obj {
+			ps, ok := props[k]
+			if !ok {
+				return fmt.Errorf("unexpected property %s", k)
+			}
+			if err := validateSchema(defs, ps.(map[string]interface{}), fv); err != nil {
+				return fmt.Errorf("%s: %s", k, err)
+			}
+		}
+		for _, r := range s["required"].([]interface{}) {
+			if _, ok := obj[r.(string)]; !ok {
+				return fmt.Errorf("missing property %s", r)
+			}
+		}
+	}
+
+	return nil
+}
+
+func TestGenerateJSONSchema(t *testing.T) {
+	raw, err := GenerateJSONSchema()
+	if err != nil {
+		t.Errorf("fail to generate schema: %s\n", err)
+		return
+	}
+
+	var doc map[string]interface{}
+	err = json.Unmarshal(raw, &doc)
+	if err != nil {
+		t.Errorf("fail to unmarshal schema: %s\n", err)
+		return
+	}
+
+	defs := doc["definitions"].(map[string]interface{})
+	for _, name := range []string{"BlockView", "SearchKey", "RejectedTransaction", "DaoWithdrawingCalculationKind"} {
+		if _, ok := defs[name]; !ok {
+			t.Errorf("mismatch result, expect definition %s", name)
+			return
+		}
+	}
+
+	var block interface{}
+	err = json.Unmarshal([]byte(recordedBlock), &block)
+	if err != nil {
+		t.Errorf("fail to unmarshal block json: %s\n", err)
+		return
+	}
+
+	err = validateSchema(defs, defs["BlockView"].(map[string]interface{}), block)
+	if err != nil {
+		t.Errorf("recorded block should match schema: %s\n", err)
+		return
+	}
+
+	block.(map[string]interface{})["header"].(map[string]interface{})["number"] = "400"
+	err = validateSchema(defs, defs["BlockView"].(map[string]interface{}), block)
+	if err == nil {
+		t.Errorf("should reject number without 0x prefix")
+		return
+	}
+}
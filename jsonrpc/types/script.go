@@ -0,0 +1,41 @@
+package types
+
+import "fmt"
+
+// ChainVersion ckb consensus version, each hard fork allows new script
+// hash types
+type ChainVersion int
+
+// Chain versions
+const (
+	CKB2019 ChainVersion = iota
+	CKB2021
+	CKB2023
+)
+
+// ValidateFor validate script hash type is allowed by chain version
+/*
+ * Allowed hash types:
+ *
+ *     CKB2019: data, type
+ *     CKB2021: data, type, data1
+ *     CKB2023: data, type, data1, data2
+ */
+func (t ScriptHashType) ValidateFor(v ChainVersion) error {
+	switch t {
+	case Data, Type:
+		return nil
+	case Data1:
+		if v >= CKB2021 {
+			return nil
+		}
+	case Data2:
+		if v >= CKB2023 {
+			return nil
+		}
+	default:
+		return fmt.Errorf("invalid script hash type")
+	}
+
+	return fmt.Errorf("script hash type %s is not allowed before hard fork", t)
+}
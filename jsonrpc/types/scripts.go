@@ -0,0 +1,87 @@
+package types
+
+import (
+	"fmt"
+)
+
+// System script type hashes, deployed in genesis block so they are the
+// same on mainnet, testnet and dev chains
+var (
+	SecpSighashAllCodeHash = mustParseHash("0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8")
+	SecpMultisigCodeHash   = mustParseHash("0x5c5069eb0857efc65e1bca0c07df34c31663b3622fd3876c876320fc9634e2a8")
+	DaoCodeHash            = mustParseHash("0x82d76d1b75fe2fd9a27dfbaa65a039221a380d76c926f378d3f81cf3e7e13f2e")
+)
+
+// systemScriptNames known system script names, keyed by type hash
+var systemScriptNames = map[Hash]string{
+	SecpSighashAllCodeHash: "secp256k1_blake160_sighash_all",
+	SecpMultisigCodeHash:   "secp256k1_blake160_multisig_all",
+	DaoCodeHash:            "dao",
+}
+
+// SystemScriptName name of known system script, empty if unknown
+func SystemScriptName(s *Script) string {
+	if s == nil || s.HashType != Type {
+		return ""
+	}
+
+	return systemScriptNames[s.CodeHash]
+}
+
+// SystemScript deployed script, how to reference it in scripts and where
+// to load its code from
+type SystemScript struct {
+	Name     string         `json:"name" yaml:"name" toml:"name"`
+	CodeHash Hash           `json:"code_hash" yaml:"code_hash" toml:"code_hash"`
+	HashType ScriptHashType `json:"hash_type" yaml:"hash_type" toml:"hash_type"`
+	CellDep  CellDep        `json:"cell_dep" yaml:"cell_dep" toml:"cell_dep"`
+}
+
+// ScriptRegistry system scripts deployed on a network, usually loaded
+// from service config
+type ScriptRegistry struct {
+	Network Network        `json:"network" yaml:"network" toml:"network"`
+	Scripts []SystemScript `json:"scripts" yaml:"scripts" toml:"scripts"`
+}
+
+// Lookup system script by name
+func (r *ScriptRegistry) Lookup(name string) (*SystemScript, error) {
+	for i := 0; i < len(r.Scripts); i++ {
+		if r.Scripts[i].Name == name {
+			return &r.Scripts[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown system script %s on %s", name, r.Network)
+}
+
+// Script build script of named system script with args
+func (r *ScriptRegistry) Script(name string, args Bytes) (*Script, error) {
+	s, err := r.Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Script{CodeHash: s.CodeHash, HashType: s.HashType, Args: args}, nil
+}
+
+// Find system script referenced by script, nil if script is not one of
+// registry
+func (r *ScriptRegistry) Find(s *Script) *SystemScript {
+	for i := 0; i < len(r.Scripts); i++ {
+		if r.Scripts[i].CodeHash == s.CodeHash && r.Scripts[i].HashType == s.HashType {
+			return &r.Scripts[i]
+		}
+	}
+
+	return nil
+}
+
+func mustParseHash(s string) Hash {
+	h, err := ParseHash(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return h
+}
@@ -0,0 +1,96 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestScriptRegistryYAML(t *testing.T) {
+	config := `
+network: ckt
+scripts:
+  - name: secp256k1_blake160_sighash_all
+    code_hash: "0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8"
+    hash_type: type
+    cell_dep:
+      out_point:
+        tx_hash: "0xf8de3bb47d055cdf460d93a2a6e1b05f7432f9777c8c474abf4eec1d4aee5d37"
+        index: 0
+      dep_type: dep_group
+`
+
+	var r ScriptRegistry
+
+	err := yaml.Unmarshal([]byte(config), &r)
+	if err != nil {
+		t.Errorf("fail to unmarshal test registry yaml: %s\n", err)
+		return
+	}
+
+	if r.Network != Testnet || len(r.Scripts) != 1 {
+		t.Errorf("mismatch registry, got %v", r)
+		return
+	}
+
+	s, err := r.Script("secp256k1_blake160_sighash_all", Bytes{0x01, 0x02})
+	if err != nil {
+		t.Errorf("fail to build script: %s\n", err)
+		return
+	}
+
+	if s.CodeHash != SecpSighashAllCodeHash || !reflect.DeepEqual(s.Args, Bytes{0x01, 0x02}) {
+		t.Errorf("mismatch script, got %v", s)
+		return
+	}
+
+	dep := r.Scripts[0].CellDep
+	if dep.DepType != DepGroup || dep.OutPoint.String() != "0xf8de3bb47d055cdf460d93a2a6e1b05f7432f9777c8c474abf4eec1d4aee5d37:0" {
+		t.Errorf("mismatch cell dep, got %v", dep)
+		return
+	}
+
+	out, err := yaml.Marshal(s)
+	if err != nil {
+		t.Errorf("fail to marshal script yaml: %s\n", err)
+		return
+	}
+
+	var parsed Script
+
+	err = yaml.Unmarshal(out, &parsed)
+	if err != nil {
+		t.Errorf("fail to unmarshal script yaml: %s\n", err)
+		return
+	}
+
+	if !parsed.Equal(s) {
+		t.Errorf("mismatch result, expect %v, got %v", s, parsed)
+		return
+	}
+
+	_, err = r.Lookup("dao")
+	if err == nil {
+		t.Errorf("expect error on unknown script")
+		return
+	}
+}
+
+func TestConfigTags(t *testing.T) {
+	for _, v := range []interface{}{Script{}, OutPoint{}, CellDep{}, SystemScript{}, ScriptRegistry{}} {
+		typ := reflect.TypeOf(v)
+
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			j := f.Tag.Get("json")
+
+			for _, k := range []string{"yaml", "toml"} {
+				if f.Tag.Get(k) != j {
+					t.Errorf("mismatch %s.%s %s tag, expect %v, got %v", typ.Name(), f.Name, k, j, f.Tag.Get(k))
+					return
+				}
+			}
+		}
+	}
+}
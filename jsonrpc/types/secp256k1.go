@@ -0,0 +1,82 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+)
+
+// SignatureSize secp256k1 recoverable signature size, r, s and recovery id
+const SignatureSize = 65
+
+// RecoverPubkey recover compressed public key from message digest and
+// recoverable signature
+func RecoverPubkey(digest Hash, sig []byte) ([]byte, error) {
+	if len(sig) != SignatureSize {
+		return nil, fmt.Errorf("invalid signature, should be 65 bytes")
+	}
+
+	pub, err := secp256k1.RecoverPubkey(digest[:], sig)
+	if err != nil {
+		return nil, err
+	}
+
+	return CompressPubkey(pub)
+}
+
+// CompressPubkey convert public key to 33 bytes compressed form, accept
+// both compressed and 65 bytes uncompressed form
+func CompressPubkey(pubkey []byte) ([]byte, error) {
+	switch {
+	case len(pubkey) == 33 && (pubkey[0] == 0x02 || pubkey[0] == 0x03):
+		x, _ := secp256k1.DecompressPubkey(pubkey)
+		if x == nil {
+			return nil, fmt.Errorf("invalid pubkey, not on secp256k1 curve")
+		}
+
+		return append([]byte{}, pubkey...), nil
+	case len(pubkey) == 65 && pubkey[0] == 0x04:
+		x := new(big.Int).SetBytes(pubkey[1:33])
+		y := new(big.Int).SetBytes(pubkey[33:])
+		if !secp256k1.S256().IsOnCurve(x, y) {
+			return nil, fmt.Errorf("invalid pubkey, not on secp256k1 curve")
+		}
+
+		return secp256k1.CompressPubkey(x, y), nil
+	}
+
+	return nil, fmt.Errorf("invalid pubkey, should be 33 bytes compressed or 65 bytes uncompressed")
+}
+
+// DecompressPubkey convert public key to 65 bytes uncompressed form,
+// 0x04 || x || y, accept both compressed and uncompressed form
+func DecompressPubkey(pubkey []byte) ([]byte, error) {
+	compressed, err := CompressPubkey(pubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	x, y := secp256k1.DecompressPubkey(compressed)
+	return secp256k1.S256().Marshal(x, y), nil
+}
+
+// RecoverLockArgs recover default secp256k1 lock args of signer
+func RecoverLockArgs(digest Hash, sig []byte) (Bytes, error) {
+	pub, err := RecoverPubkey(digest, sig)
+	if err != nil {
+		return nil, err
+	}
+
+	return PubkeyToLockArgs(pub)
+}
+
+// VerifySignature verify signature of message digest against compressed
+// or uncompressed public key, recovery id is ignored if present
+func VerifySignature(pubkey []byte, digest Hash, sig []byte) bool {
+	if len(sig) != SignatureSize && len(sig) != SignatureSize-1 {
+		return false
+	}
+
+	return secp256k1.VerifySignature(pubkey, digest[:], sig[:64])
+}
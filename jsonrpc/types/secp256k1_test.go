@@ -0,0 +1,88 @@
+package types
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+)
+
+func TestRecoverPubkey(t *testing.T) {
+	// Private key 1, public key is the generator point
+	seckey := make([]byte, 32)
+	seckey[31] = 1
+	expectHex := "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+
+	digest := Hash{1, 2, 3}
+	sig, err := secp256k1.Sign(digest[:], seckey)
+	if err != nil {
+		t.Errorf("fail to sign: %s\n", err)
+		return
+	}
+
+	pub, err := RecoverPubkey(digest, sig)
+	if err != nil {
+		t.Errorf("fail to recover pubkey: %s\n", err)
+		return
+	}
+
+	if hex.EncodeToString(pub) != expectHex {
+		t.Errorf("mismatch result, expect %v, got %x", expectHex, pub)
+		return
+	}
+
+	args, err := RecoverLockArgs(digest, sig)
+	if err != nil || !bytes.Equal(args, Blake160(pub)) {
+		t.Errorf("mismatch lock args, got %v", args)
+		return
+	}
+
+	if !VerifySignature(pub, digest, sig) {
+		t.Errorf("expect valid signature")
+		return
+	}
+
+	digest[0] = 0xff
+	if VerifySignature(pub, digest, sig) {
+		t.Errorf("expect invalid signature on other digest")
+		return
+	}
+}
+
+func TestCompressPubkey(t *testing.T) {
+	compressed, _ := hex.DecodeString("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	uncompressedHex := "0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798" +
+		"483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"
+
+	uncompressed, err := DecompressPubkey(compressed)
+	if err != nil {
+		t.Errorf("fail to decompress pubkey: %s\n", err)
+		return
+	}
+
+	if hex.EncodeToString(uncompressed) != uncompressedHex {
+		t.Errorf("mismatch result, expect %v, got %x", uncompressedHex, uncompressed)
+		return
+	}
+
+	c, err := CompressPubkey(uncompressed)
+	if err != nil || !bytes.Equal(c, compressed) {
+		t.Errorf("mismatch result, expect %x, got %x", compressed, c)
+		return
+	}
+
+	a1, _ := PubkeyToLockArgs(compressed)
+	a2, _ := PubkeyToLockArgs(uncompressed)
+	if !bytes.Equal(a1, a2) {
+		t.Errorf("mismatch lock args, expect %v, got %v", a1, a2)
+		return
+	}
+
+	uncompressed[64] ^= 1
+	_, err = CompressPubkey(uncompressed)
+	if err == nil {
+		t.Errorf("expect error on point not on curve")
+		return
+	}
+}
@@ -0,0 +1,163 @@
+package types
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+)
+
+// Secp256r1 sizes, public key is x || y without 0x04 prefix, signature is
+// r || s, both as used by r1 based locks
+const (
+	R1PubkeySize    = 64
+	R1SignatureSize = 64
+)
+
+// ParseR1Pubkey parse secp256r1 public key, accept 64 bytes raw, 65
+// bytes uncompressed and 33 bytes compressed form
+func ParseR1Pubkey(pubkey []byte) (*ecdsa.PublicKey, error) {
+	curve := elliptic.P256()
+
+	var x, y *big.Int
+
+	switch {
+	case len(pubkey) == R1PubkeySize:
+		x = new(big.Int).SetBytes(pubkey[:32])
+		y = new(big.Int).SetBytes(pubkey[32:])
+	case len(pubkey) == 65 && pubkey[0] == 0x04:
+		x = new(big.Int).SetBytes(pubkey[1:33])
+		y = new(big.Int).SetBytes(pubkey[33:])
+	case len(pubkey) == 33 && (pubkey[0] == 0x02 || pubkey[0] == 0x03):
+		x = new(big.Int).SetBytes(pubkey[1:])
+		y = decompressR1(x, pubkey[0] == 0x03)
+		if y == nil {
+			return nil, fmt.Errorf("invalid pubkey, not on secp256r1 curve")
+		}
+	default:
+		return nil, fmt.Errorf("invalid pubkey, should be 64 bytes raw, 65 bytes uncompressed or 33 bytes compressed")
+	}
+
+	if !curve.IsOnCurve(x, y) {
+		return nil, fmt.Errorf("invalid pubkey, not on secp256r1 curve")
+	}
+
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+// decompressR1 solve y from x, y^2 = x^3 - 3x + b, nil if x is not on curve
+/*
+ * P-256 prime is 3 mod 4, so square root is (y^2)^((p + 1) / 4).
+ */
+func decompressR1(x *big.Int, odd bool) *big.Int {
+	params := elliptic.P256().Params()
+	p := params.P
+
+	if x.Cmp(p) >= 0 {
+		return nil
+	}
+
+	y2 := new(big.Int).Exp(x, big.NewInt(3), p)
+	y2.Sub(y2, new(big.Int).Mul(x, big.NewInt(3)))
+	y2.Add(y2, params.B)
+	y2.Mod(y2, p)
+
+	e := new(big.Int).Add(p, big.NewInt(1))
+	e.Rsh(e, 2)
+
+	y := new(big.Int).Exp(y2, e, p)
+	if new(big.Int).Exp(y, big.NewInt(2), p).Cmp(y2) != 0 {
+		return nil
+	}
+
+	if (y.Bit(0) == 1) != odd {
+		y.Sub(p, y)
+	}
+
+	return y
+}
+
+// R1PubkeyBytes 64 bytes raw form of secp256r1 public key
+func R1PubkeyBytes(pub *ecdsa.PublicKey) []byte {
+	b := make([]byte, R1PubkeySize)
+	copyPadded(b[:32], pub.X.Bytes())
+	copyPadded(b[32:], pub.Y.Bytes())
+
+	return b
+}
+
+// R1SignatureFromDER convert DER ecdsa signature, as returned by WebAuthn
+// authenticators and most HSMs, into 64 bytes r || s
+func R1SignatureFromDER(der []byte) ([]byte, error) {
+	var rs struct {
+		R, S *big.Int
+	}
+
+	rest, err := asn1.Unmarshal(der, &rs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid der signature, %s", err)
+	}
+
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("invalid der signature, trailing data")
+	}
+
+	n := elliptic.P256().Params().N
+	if rs.R.Sign() <= 0 || rs.S.Sign() <= 0 || rs.R.Cmp(n) >= 0 || rs.S.Cmp(n) >= 0 {
+		return nil, fmt.Errorf("invalid der signature, r or s out of range")
+	}
+
+	sig := make([]byte, R1SignatureSize)
+	copyPadded(sig[:32], rs.R.Bytes())
+	copyPadded(sig[32:], rs.S.Bytes())
+
+	return sig, nil
+}
+
+// VerifyR1Signature verify 64 bytes r || s signature of digest against
+// secp256r1 public key in any form ParseR1Pubkey accepts
+func VerifyR1Signature(pubkey []byte, digest []byte, sig []byte) bool {
+	if len(sig) != R1SignatureSize {
+		return false
+	}
+
+	pub, err := ParseR1Pubkey(pubkey)
+	if err != nil {
+		return false
+	}
+
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+
+	return ecdsa.Verify(pub, digest, r, s)
+}
+
+// NormalizeR1Signature normalize s of 64 bytes r || s signature to lower
+// half of curve order, both s and n - s verify, r1 locks only accept the
+// lower one so signatures are not malleable
+func NormalizeR1Signature(sig []byte) ([]byte, error) {
+	if len(sig) != R1SignatureSize {
+		return nil, fmt.Errorf("invalid signature, should be %d bytes", R1SignatureSize)
+	}
+
+	n := elliptic.P256().Params().N
+	s := new(big.Int).SetBytes(sig[32:])
+
+	normalized := append([]byte{}, sig...)
+	if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		s.Sub(n, s)
+
+		for i := 32; i < R1SignatureSize; i++ {
+			normalized[i] = 0
+		}
+		copyPadded(normalized[32:], s.Bytes())
+	}
+
+	return normalized, nil
+}
+
+// copyPadded copy big endian integer bytes right aligned into dst
+func copyPadded(dst []byte, src []byte) {
+	copy(dst[len(dst)-len(src):], src)
+}
@@ -0,0 +1,75 @@
+package types
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+)
+
+func TestR1Signature(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Errorf("fail to generate key: %s\n", err)
+		return
+	}
+
+	raw := R1PubkeyBytes(&key.PublicKey)
+	uncompressed := elliptic.Marshal(elliptic.P256(), key.X, key.Y)
+
+	compressed := make([]byte, 33)
+	compressed[0] = 0x02 + byte(key.Y.Bit(0))
+	copyPadded(compressed[1:], key.X.Bytes())
+
+	for _, pubkey := range [][]byte{raw, uncompressed, compressed} {
+		pub, err := ParseR1Pubkey(pubkey)
+		if err != nil {
+			t.Errorf("fail to parse pubkey: %s\n", err)
+			return
+		}
+
+		if !bytes.Equal(R1PubkeyBytes(pub), raw) {
+			t.Errorf("mismatch result, expect %x, got %x", raw, R1PubkeyBytes(pub))
+			return
+		}
+	}
+
+	digest := sha256.Sum256([]byte("ckb"))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Errorf("fail to sign digest: %s\n", err)
+		return
+	}
+
+	der, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	if err != nil {
+		t.Errorf("fail to marshal der signature: %s\n", err)
+		return
+	}
+
+	sig, err := R1SignatureFromDER(der)
+	if err != nil {
+		t.Errorf("fail to convert der signature: %s\n", err)
+		return
+	}
+
+	if !VerifyR1Signature(compressed, digest[:], sig) {
+		t.Errorf("fail to verify signature")
+		return
+	}
+
+	sig[0] ^= 1
+	if VerifyR1Signature(raw, digest[:], sig) {
+		t.Errorf("expect tampered signature to fail")
+		return
+	}
+
+	if _, err = ParseR1Pubkey(append([]byte{0x04}, make([]byte, 64)...)); err == nil {
+		t.Errorf("expect error on point not on curve")
+		return
+	}
+}
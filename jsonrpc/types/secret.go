@@ -0,0 +1,18 @@
+package types
+
+import (
+	"crypto/subtle"
+)
+
+// ConstantTimeEqual report whether a and b are the same, in time that
+// depends only on their lengths, for digests, keys, signatures and
+// challenges an attacker may probe byte by byte
+func ConstantTimeEqual(a []byte, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// ConstantTimeEqual report whether two hashes are the same, in constant
+// time
+func (h Hash) ConstantTimeEqual(other Hash) bool {
+	return ConstantTimeEqual(h[:], other[:])
+}
@@ -0,0 +1,23 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestConstantTimeEqual(t *testing.T) {
+	if !ConstantTimeEqual([]byte{1, 2, 3}, []byte{1, 2, 3}) || !ConstantTimeEqual(nil, []byte{}) {
+		t.Errorf("expect equal bytes")
+		return
+	}
+
+	if ConstantTimeEqual([]byte{1, 2, 3}, []byte{1, 2, 4}) || ConstantTimeEqual([]byte{1, 2}, []byte{1, 2, 3}) {
+		t.Errorf("expect different bytes")
+		return
+	}
+
+	h := SecpSighashAllCodeHash
+	if !h.ConstantTimeEqual(SecpSighashAllCodeHash) || h.ConstantTimeEqual(DaoCodeHash) {
+		t.Errorf("mismatch hash comparison")
+		return
+	}
+}
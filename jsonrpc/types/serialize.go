@@ -126,6 +126,12 @@ func SerializeDynVec(items [][]byte) []byte {
  */
 func SerializeTable(fields [][]byte) []byte {
 	size := u32Size
+
+	// Empty table, just return size's bytes
+	if len(fields) == 0 {
+		return serializeUint32(size)
+	}
+
 	offsets := make([]uint32, len(fields))
 
 	// Calculate first offset then loop for rest items offsets
@@ -161,3 +167,19 @@ func SerializeOption(o MolSerializer) ([]byte, error) {
 
 	return o.Serialize()
 }
+
+// SerializeUnion serialize union
+/*
+ * There are two steps of serializing a union:
+ *
+ *     Serialize the item id as a 32 bit unsigned integer in little-endian.
+ *     Serialize the inner item.
+ */
+func SerializeUnion(id uint32, item []byte) []byte {
+	b := new(bytes.Buffer)
+
+	b.Write(serializeUint32(id))
+	b.Write(item)
+
+	return b.Bytes()
+}
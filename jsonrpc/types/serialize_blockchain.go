@@ -3,7 +3,6 @@ package types
 import (
 	"bytes"
 	"encoding/binary"
-	"encoding/hex"
 	"fmt"
 	"strconv"
 	"strings"
@@ -18,38 +17,34 @@ func check0xPrefix(s string) error {
 
 // Serialize hash
 func (h *Hash) Serialize() ([]byte, error) {
-	inner := string(*h)
-
-	err := check0xPrefix(inner)
-	if err != nil {
-		return nil, err
-	}
-
-	b, err := hex.DecodeString(inner[2:])
-	if err != nil {
-		return nil, err
-	}
-
-	if len(b) != 32 {
-		return nil, fmt.Errorf("invalid hash, should be 32 bytes")
-	}
+	b := make([]byte, len(h))
+	copy(b, h[:])
 
 	return b, nil
 }
 
 // Serialize script hash type
 func (t *ScriptHashType) Serialize() ([]byte, error) {
-	inner := string(*t)
-
-	if strings.Compare(inner, string(Data)) != 0 && strings.Compare(inner, string(Type)) != 0 {
-		return nil, fmt.Errorf("invalid script hash type")
-	}
-
-	if strings.Compare(inner, string(Data)) == 0 {
+	switch *t {
+	case Data:
 		return []byte{00}, nil
+	case Type:
+		return []byte{01}, nil
+	case Data1:
+		return []byte{02}, nil
+	case Data2:
+		return []byte{04}, nil
+	}
+
+	// Unknown hash type kept by AllowUnknownHashTypes, like "0x03"
+	if len(*t) == 4 && strings.HasPrefix(string(*t), "0x") {
+		b, err := strconv.ParseUint(string(*t)[2:], 16, 8)
+		if err == nil {
+			return []byte{byte(b)}, nil
+		}
 	}
 
-	return []byte{01}, nil
+	return nil, fmt.Errorf("invalid script hash type")
 }
 
 // Serialize dep type
@@ -69,21 +64,9 @@ func (t *DepType) Serialize() ([]byte, error) {
 
 // Serialize bytes
 func (b *Bytes) Serialize() ([]byte, error) {
-	inner := string(*b)
-
-	err := check0xPrefix(inner)
-	if err != nil {
-		return nil, err
-	}
-
-	decoded, err := hex.DecodeString(inner[2:])
-	if err != nil {
-		return nil, err
-	}
-
-	bytes := make([][]byte, len(decoded))
-	for i := 0; i < len(decoded); i++ {
-		bytes[i] = []byte{decoded[i]}
+	bytes := make([][]byte, len(*b))
+	for i := 0; i < len(*b); i++ {
+		bytes[i] = []byte{(*b)[i]}
 	}
 
 	return SerializeFixVec(bytes), nil
@@ -91,47 +74,13 @@ func (b *Bytes) Serialize() ([]byte, error) {
 
 // Serialize uint32
 func (u *Uint32) Serialize() ([]byte, error) {
-	inner := string(*u)
-
-	err := check0xPrefix(inner)
-	if err != nil {
-		return nil, err
-	}
-
-	uu := inner[2:]
-	if len(inner)%2 != 0 {
-		uu = "0" + uu
-	}
-
-	n, err := strconv.ParseUint(uu, 16, 32)
-	if err != nil {
-		return nil, err
-	}
-
-	return serializeUint32(uint32(n)), nil
+	return serializeUint32(uint32(*u)), nil
 }
 
 // Serialize uint64
 func (u *Uint64) Serialize() ([]byte, error) {
-	inner := string(*u)
-
-	err := check0xPrefix(inner)
-	if err != nil {
-		return nil, err
-	}
-
-	uu := inner[2:]
-	if len(inner)%2 != 0 {
-		uu = "0" + uu
-	}
-
-	n, err := strconv.ParseUint(uu, 16, 64)
-	if err != nil {
-		return nil, err
-	}
-
 	b := make([]byte, 8)
-	binary.LittleEndian.PutUint64(b, n)
+	binary.LittleEndian.PutUint64(b, uint64(*u))
 
 	return b, nil
 }
@@ -157,7 +106,7 @@ func (s *Script) Serialize() ([]byte, error) {
 		return nil, err
 	}
 
-	return SerializeTable([][]byte{h, t, a}), nil
+	return SerializeTable(append([][]byte{h, t, a}, s.UnknownFields...)), nil
 }
 
 // Serialize outpoint
@@ -212,7 +161,7 @@ func (o *CellOutput) Serialize() ([]byte, error) {
 		return nil, err
 	}
 
-	return SerializeTable([][]byte{c, l, t}), nil
+	return SerializeTable(append([][]byte{c, l, t}, o.UnknownFields...)), nil
 }
 
 // Serialize cell dep
@@ -248,16 +197,7 @@ func (t *Transaction) Serialize() ([]byte, error) {
 	}
 	cdsBytes := SerializeFixVec(cds)
 
-	hds := make([][]byte, len(t.HeaderDeps))
-	for i := 0; i < len(t.HeaderDeps); i++ {
-		hd, err := t.HeaderDeps[i].Serialize()
-		if err != nil {
-			return nil, err
-		}
-
-		hds[i] = hd
-	}
-	hdsBytes := SerializeFixVec(hds)
+	hdsBytes := SerializeByte32Vec(t.HeaderDeps)
 
 	ips := make([][]byte, len(t.Inputs))
 	for i := 0; i < len(t.Inputs); i++ {
@@ -293,5 +233,102 @@ func (t *Transaction) Serialize() ([]byte, error) {
 	odsBytes := SerializeDynVec(ods)
 
 	fields := [][]byte{v, cdsBytes, hdsBytes, ipsBytes, opsBytes, odsBytes}
-	return SerializeTable(fields), nil
+	return SerializeTable(append(fields, t.UnknownFields...)), nil
+}
+
+// Serialize header, molecule Header struct with RawHeader and nonce
+func (h *Header) Serialize() ([]byte, error) {
+	fields := make([][]byte, 0, 11)
+
+	for _, f := range []MolSerializer{
+		&h.Version,
+		&h.CompactTarget,
+		&h.Timestamp,
+		&h.Number,
+		&h.Epoch,
+		&h.ParentHash,
+		&h.TransactionsRoot,
+		&h.ProposalsHash,
+		&h.ExtraHash,
+		&h.Dao,
+		&h.Nonce,
+	} {
+		b, err := f.Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, b)
+	}
+
+	return SerializeStruct(fields), nil
+}
+
+// Serialize proposal short id
+func (p *ProposalShortID) Serialize() ([]byte, error) {
+	b := make([]byte, len(p))
+	copy(b, p[:])
+
+	return b, nil
+}
+
+// Serialize uncle block
+func (u *UncleBlock) Serialize() ([]byte, error) {
+	h, err := u.Header.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	p := SerializeProposalShortIDVec(u.Proposals)
+
+	return SerializeTable(append([][]byte{h, p}, u.UnknownFields...)), nil
+}
+
+// Serialize block, as BlockV1 if block has extension
+func (b *Block) Serialize() ([]byte, error) {
+	h, err := b.Header.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	us := make([][]byte, len(b.Uncles))
+	for i := 0; i < len(b.Uncles); i++ {
+		u, err := b.Uncles[i].Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		us[i] = u
+	}
+
+	txs := make([][]byte, len(b.Transactions))
+	for i := 0; i < len(b.Transactions); i++ {
+		tx, err := b.Transactions[i].Pack()
+		if err != nil {
+			return nil, err
+		}
+
+		txs[i] = tx
+	}
+
+	p := SerializeProposalShortIDVec(b.Proposals)
+
+	fields := [][]byte{h, SerializeDynVec(us), SerializeDynVec(txs), p}
+	if b.Extension != nil || len(b.UnknownFields) != 0 {
+		// Unknown fields follow extension, so an empty one is written if
+		// there is none
+		e := Bytes{}
+		if b.Extension != nil {
+			e = *b.Extension
+		}
+
+		eb, err := e.Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, eb)
+	}
+
+	return SerializeTable(append(fields, b.UnknownFields...)), nil
 }
@@ -256,3 +256,86 @@ func TestSerializeTransaction(t *testing.T) {
 		return
 	}
 }
+
+func TestSerializeScriptHashType(t *testing.T) {
+	cases := []struct {
+		hashType ScriptHashType
+		expect   byte
+		version  ChainVersion
+	}{
+		{Data, 0, CKB2019},
+		{Type, 1, CKB2019},
+		{Data1, 2, CKB2021},
+		{Data2, 4, CKB2023},
+	}
+
+	for _, c := range cases {
+		got, err := c.hashType.Serialize()
+		if err != nil {
+			t.Errorf("fail to serialize %s: %s\n", c.hashType, err)
+			return
+		}
+
+		if got[0] != c.expect {
+			t.Errorf("mismatch result for %s, expect %v, got %v", c.hashType, c.expect, got[0])
+			return
+		}
+
+		var back ScriptHashType
+
+		err = back.Deserialize(got)
+		if err != nil || back != c.hashType {
+			t.Errorf("fail to deserialize %s: %v", c.hashType, err)
+			return
+		}
+
+		if c.hashType.ValidateFor(c.version) != nil {
+			t.Errorf("expect %s allowed in version %v", c.hashType, c.version)
+			return
+		}
+
+		if c.version > CKB2019 && c.hashType.ValidateFor(c.version-1) == nil {
+			t.Errorf("expect %s not allowed before version %v", c.hashType, c.version)
+			return
+		}
+	}
+
+	invalid := ScriptHashType("data3")
+	_, err := invalid.Serialize()
+	if err == nil {
+		t.Errorf("expect error on unknown hash type")
+		return
+	}
+}
+
+func TestSerializeHeaderNonce(t *testing.T) {
+	var h Header
+
+	err := json.Unmarshal([]byte(`{"nonce": "0x8f2a6bdc5d9e0c7b1e4f3a2d6c8b9e01"}`), &h)
+	if err != nil {
+		t.Errorf("fail to unmarshal test header json: %s\n", err)
+		return
+	}
+
+	got, err := h.Serialize()
+	if err != nil {
+		t.Errorf("fail to serialize: %s\n", err)
+		return
+	}
+
+	expectHex := "019e8b6c2d3a4f1e7b0c9e5ddc6b2a8f"
+	gotHex := hex.EncodeToString(got[len(got)-16:])
+
+	if len(got) != 208 || gotHex != expectHex {
+		t.Errorf("mismatch result, expect %v in 208 bytes, got %v in %v bytes", expectHex, gotHex, len(got))
+		return
+	}
+
+	var back Header
+
+	err = back.Deserialize(got)
+	if err != nil || back != h {
+		t.Errorf("fail to deserialize header: %v", err)
+		return
+	}
+}
@@ -0,0 +1,178 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// RemoteSigner signer keeping private key out of process, hardware
+// wallet or kms, so builders never hold raw keys
+type RemoteSigner interface {
+	// GetPublicKey compressed secp256k1 public key
+	GetPublicKey() ([]byte, error)
+	// SignDigest sign 32 bytes digest, returns 65 bytes recoverable
+	// signature
+	SignDigest(digest Hash) ([]byte, error)
+	// SignTransaction sign input group, inputs indexes sharing the same
+	// lock, and fill lock of the first witness in group
+	SignTransaction(tx *Transaction, group []int) error
+}
+
+// SighashAllMessage signing message of secp256k1 sighash all lock for
+// input group
+/*
+ * blake2b-256 of:
+ *
+ *     Transaction hash.
+ *     First witness in group, with lock filled by 65 zero bytes.
+ *     Other witnesses in group.
+ *     Witnesses without matching input.
+ *
+ * Every witness is prefixed with its length as a 64 bit unsigned integer
+ * in little-endian.
+ */
+func (t *Transaction) SighashAllMessage(group []int) (Hash, error) {
+	return t.sighashAllMessage(group, make(Bytes, SignatureSize))
+}
+
+// sighashAllMessage signing message with given lock placeholder, multisig
+// lock placeholder is longer than single signature
+func (t *Transaction) sighashAllMessage(group []int, placeholder Bytes) (Hash, error) {
+	var msg Hash
+
+	if len(group) == 0 {
+		return msg, fmt.Errorf("invalid input group, empty")
+	}
+
+	for _, i := range group {
+		if i < 0 || i >= len(t.Inputs) || i >= len(t.Witnesses) {
+			return msg, fmt.Errorf("invalid input group, no witness for input %d", i)
+		}
+	}
+
+	txHash, err := t.ComputeHash()
+	if err != nil {
+		return msg, err
+	}
+
+	var first WitnessArgs
+	if len(t.Witnesses[group[0]]) != 0 {
+		err = first.Deserialize(t.Witnesses[group[0]])
+		if err != nil {
+			return msg, fmt.Errorf("invalid witness %d, %s", group[0], err)
+		}
+	}
+
+	first.Lock = &placeholder
+
+	w, err := first.Serialize()
+	if err != nil {
+		return msg, err
+	}
+
+	h := NewBlake2b()
+	h.Write(txHash[:])
+
+	writeWitness := func(w []byte) {
+		size := make([]byte, uint64Size)
+		binary.LittleEndian.PutUint64(size, uint64(len(w)))
+		h.Write(size)
+		h.Write(w)
+	}
+
+	writeWitness(w)
+	for _, i := range group[1:] {
+		writeWitness(t.Witnesses[i])
+	}
+
+	for i := len(t.Inputs); i < len(t.Witnesses); i++ {
+		writeWitness(t.Witnesses[i])
+	}
+
+	copy(msg[:], h.Sum(nil))
+	return msg, nil
+}
+
+// SignSighashAll sign input group with secp256k1 sighash all and fill
+// lock of the first witness in group, missing witnesses are padded with
+// empty ones first, like a transaction decoded from rpc json may need
+func SignSighashAll(s RemoteSigner, tx *Transaction, group []int) error {
+	tx.expandWitnesses(len(tx.Inputs))
+
+	msg, err := tx.SighashAllMessage(group)
+	if err != nil {
+		return err
+	}
+
+	sig, err := s.SignDigest(msg)
+	if err != nil {
+		return err
+	}
+
+	return tx.SetWitnessLock(group, sig)
+}
+
+// setWitnessLock set lock of witness args at given witness index, other
+// fields are kept
+func (t *Transaction) setWitnessLock(i int, lock Bytes) error {
+	var w WitnessArgs
+	if len(t.Witnesses[i]) != 0 {
+		err := w.Deserialize(t.Witnesses[i])
+		if err != nil {
+			return fmt.Errorf("invalid witness %d, %s", i, err)
+		}
+	}
+
+	w.Lock = &lock
+
+	b, err := w.Serialize()
+	if err != nil {
+		return err
+	}
+
+	t.Witnesses[i] = b
+	return nil
+}
+
+// KeySigner in memory private key signer, for tests, dev chains and hot
+// wallets that destroy key when done
+type KeySigner struct {
+	key *PrivateKey
+}
+
+// NewKeySigner new signer from 32 bytes secp256k1 private key, key is
+// copied
+func NewKeySigner(seckey []byte) (*KeySigner, error) {
+	key, err := NewPrivateKey(seckey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeySigner{key: key}, nil
+}
+
+// NewPrivateKeySigner new signer using private key, destroying key
+// disables signer
+func NewPrivateKeySigner(key *PrivateKey) *KeySigner {
+	return &KeySigner{key: key}
+}
+
+// GetPublicKey compressed public key
+func (s *KeySigner) GetPublicKey() ([]byte, error) {
+	return s.key.PublicKey()
+}
+
+// SignDigest sign digest
+func (s *KeySigner) SignDigest(digest Hash) ([]byte, error) {
+	return s.key.Sign(digest)
+}
+
+// Destroy wipe private key of signer
+func (s *KeySigner) Destroy() {
+	s.key.Destroy()
+}
+
+// SignTransaction sign input group with sighash all
+func (s *KeySigner) SignTransaction(tx *Transaction, group []int) error {
+	return SignSighashAll(s, tx, group)
+}
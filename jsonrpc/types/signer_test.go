@@ -0,0 +1,104 @@
+package types
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestKeySigner(t *testing.T) {
+	seckey := make([]byte, 32)
+	seckey[31] = 1
+
+	s, err := NewKeySigner(seckey)
+	if err != nil {
+		t.Errorf("fail to create signer: %s\n", err)
+		return
+	}
+
+	pub, err := s.GetPublicKey()
+	if err != nil {
+		t.Errorf("fail to get public key: %s\n", err)
+		return
+	}
+
+	tx := &Transaction{
+		Inputs:      []CellInput{{PreviousOutput: OutPoint{TxHash: Hash{1}}}, {PreviousOutput: OutPoint{TxHash: Hash{2}}}},
+		Outputs:     []CellOutput{{Capacity: 61 * ShannonsPerCKB, Lock: Script{HashType: Type, Args: make(Bytes, 20)}}},
+		OutputsData: []Bytes{{}},
+		Witnesses:   []Bytes{{}, {}},
+	}
+
+	msg, err := tx.SighashAllMessage([]int{0, 1})
+	if err != nil {
+		t.Errorf("fail to compute sighash all message: %s\n", err)
+		return
+	}
+
+	var rs RemoteSigner = s
+	err = rs.SignTransaction(tx, []int{0, 1})
+	if err != nil {
+		t.Errorf("fail to sign transaction: %s\n", err)
+		return
+	}
+
+	var w WitnessArgs
+	err = w.Deserialize(tx.Witnesses[0])
+	if err != nil {
+		t.Errorf("fail to deserialize witness: %s\n", err)
+		return
+	}
+
+	if w.Lock == nil || w.InputType != nil || !VerifySignature(pub, msg, *w.Lock) {
+		t.Errorf("mismatch witness lock, got %v", w.Lock)
+		return
+	}
+
+	// Signing message does not depend on the lock being signed
+	again, err := tx.SighashAllMessage([]int{0, 1})
+	if err != nil || !bytes.Equal(again[:], msg[:]) {
+		t.Errorf("mismatch result, expect %v, got %v", msg, again)
+		return
+	}
+
+	_, err = tx.SighashAllMessage([]int{2})
+	if err == nil {
+		t.Errorf("expect error on input without witness")
+		return
+	}
+}
+
+func TestSignSighashAllNoWitnesses(t *testing.T) {
+	seckey := make([]byte, 32)
+	seckey[31] = 1
+
+	s, _ := NewKeySigner(seckey)
+	tx := &Transaction{
+		Inputs:  []CellInput{{PreviousOutput: OutPoint{TxHash: Hash{1}}}, {PreviousOutput: OutPoint{TxHash: Hash{2}}}},
+		Outputs: []CellOutput{{Capacity: 100, Lock: Script{HashType: Type}}},
+	}
+
+	err := SignSighashAll(s, tx, []int{0, 1})
+	if err != nil {
+		t.Errorf("fail to sign transaction without witnesses: %s\n", err)
+		return
+	}
+
+	if len(tx.Witnesses) != 2 || len(tx.Witnesses[1]) != 0 {
+		t.Errorf("mismatch result, expect 2 witnesses with second empty, got %x", tx.Witnesses)
+		return
+	}
+
+	var w WitnessArgs
+	err = w.Deserialize(tx.Witnesses[0])
+	if err != nil || w.Lock == nil || len(*w.Lock) != SignatureSize {
+		t.Errorf("mismatch result, expect signature in first witness lock, got %v %v", w.Lock, err)
+		return
+	}
+
+	msg, _ := tx.SighashAllMessage([]int{0, 1})
+	pub, _ := s.GetPublicKey()
+	if !VerifySignature(pub, msg, *w.Lock) {
+		t.Errorf("mismatch result, signature not verified")
+		return
+	}
+}
@@ -0,0 +1,79 @@
+package types
+
+import (
+	"fmt"
+)
+
+// SinceMetric since value metric
+type SinceMetric uint8
+
+// Since metrics
+const (
+	SinceBlockNumber SinceMetric = 0
+	SinceEpoch       SinceMetric = 1
+	SinceTimestamp   SinceMetric = 2
+)
+
+// Since flags
+const (
+	sinceRelativeFlag = uint64(1) << 63
+	sinceMetricShift  = 61
+	sinceMetricMask   = uint64(3) << sinceMetricShift
+	sinceReservedMask = uint64(0x1f) << 56
+	sinceValueMask    = uint64(1)<<56 - 1
+)
+
+// Since decoded cell input since
+/*
+ * Layout of the uint64:
+ *
+ *     bit 63:     relative flag
+ *     bit 61-62:  metric, block number, epoch or timestamp
+ *     bit 56-60:  reserved, must be zero
+ *     bit 0-55:   value
+ *
+ * Zero since means no lock.
+ */
+type Since struct {
+	Relative bool
+	Metric   SinceMetric
+	Value    uint64
+}
+
+// DecodeSince decode cell input since, error on malformed since
+func DecodeSince(v Uint64) (Since, error) {
+	u := uint64(v)
+
+	if u&sinceReservedMask != 0 {
+		return Since{}, fmt.Errorf("invalid since %s, reserved bits are set", v)
+	}
+
+	s := Since{
+		Relative: u&sinceRelativeFlag != 0,
+		Metric:   SinceMetric((u & sinceMetricMask) >> sinceMetricShift),
+		Value:    u & sinceValueMask,
+	}
+
+	switch s.Metric {
+	case SinceBlockNumber, SinceTimestamp:
+	case SinceEpoch:
+		e := NewEpochNumberWithFraction(Uint64(s.Value))
+		if s.Relative && !e.IsWellFormedIncrement() || !s.Relative && !e.IsWellFormed() {
+			return Since{}, fmt.Errorf("invalid since %s, malformed epoch", v)
+		}
+	default:
+		return Since{}, fmt.Errorf("invalid since %s, unknown metric", v)
+	}
+
+	return s, nil
+}
+
+// Encode encode since into uint64
+func (s Since) Encode() Uint64 {
+	u := uint64(s.Metric)<<sinceMetricShift | s.Value&sinceValueMask
+	if s.Relative {
+		u |= sinceRelativeFlag
+	}
+
+	return Uint64(u)
+}
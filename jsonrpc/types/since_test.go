@@ -0,0 +1,56 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestDecodeSince(t *testing.T) {
+	// Relative 1 epoch, the dao withdraw lock
+	v := Uint64(0xa000010000000001)
+
+	s, err := DecodeSince(v)
+	if err != nil {
+		t.Errorf("fail to decode since: %s\n", err)
+		return
+	}
+
+	if !s.Relative || s.Metric != SinceEpoch || s.Value != 0x10000000001 {
+		t.Errorf("mismatch since, got %+v", s)
+		return
+	}
+
+	e := NewEpochNumberWithFraction(Uint64(s.Value))
+	if e.Number != 1 || e.Index != 0 || e.Length != 1 || e.Uint64() != Uint64(s.Value) {
+		t.Errorf("mismatch epoch, got %v", e)
+		return
+	}
+
+	if s.Encode() != v {
+		t.Errorf("mismatch result, expect %v, got %v", v, s.Encode())
+		return
+	}
+
+	// Relative 2 whole epochs, zero length is an increment
+	s, err = DecodeSince(0xa000000000000002)
+	if err != nil {
+		t.Errorf("fail to decode relative whole epochs since: %s\n", err)
+		return
+	}
+
+	if !s.Relative || s.Metric != SinceEpoch || s.Value != 2 {
+		t.Errorf("mismatch since, got %+v", s)
+		return
+	}
+
+	for _, bad := range []Uint64{
+		0x0100000000000000, // reserved bit
+		0x6000000000000000, // unknown metric
+		0x2000000000000001, // epoch without length
+	} {
+		_, err = DecodeSince(bad)
+		if err == nil {
+			t.Errorf("expect error on since %v", bad)
+			return
+		}
+	}
+}
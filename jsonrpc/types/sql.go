@@ -0,0 +1,121 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Database values are molecule bytes, for bytea or blob columns. Scan
+// also accepts text columns, in the same hex, "hash:index" or json form
+// used elsewhere. Use pointers for nullable columns, NULL is rejected.
+
+// sqlText text form of scanned value, empty if it is raw bytes
+func sqlText(src interface{}, raw int) (string, []byte, error) {
+	switch v := src.(type) {
+	case string:
+		return strings.TrimSpace(v), nil, nil
+	case []byte:
+		// Text columns are scanned as bytes by some drivers
+		if len(v) != raw && (strings.HasPrefix(string(v), "0x") || strings.HasPrefix(string(v), "{")) {
+			return strings.TrimSpace(string(v)), nil, nil
+		}
+
+		return "", v, nil
+	case nil:
+		return "", nil, fmt.Errorf("invalid sql value, NULL")
+	}
+
+	return "", nil, fmt.Errorf("invalid sql value, unsupported type %T", src)
+}
+
+// Value hash as 32 bytes
+func (h Hash) Value() (driver.Value, error) {
+	return h[:], nil
+}
+
+// Scan hash from 32 bytes or '0x' prefix hex text
+func (h *Hash) Scan(src interface{}) error {
+	text, raw, err := sqlText(src, hashSize)
+	if err != nil {
+		return err
+	}
+
+	if raw != nil {
+		return h.Deserialize(raw)
+	}
+
+	hh, err := ParseHash(text)
+	if err != nil {
+		return err
+	}
+
+	*h = hh
+	return nil
+}
+
+// Value outpoint as molecule bytes
+func (o OutPoint) Value() (driver.Value, error) {
+	return o.Serialize()
+}
+
+// Scan outpoint from molecule bytes or "hash:index" text
+func (o *OutPoint) Scan(src interface{}) error {
+	text, raw, err := sqlText(src, outPointSize)
+	if err != nil {
+		return err
+	}
+
+	if raw != nil {
+		return o.Deserialize(raw)
+	}
+
+	oo, err := ParseOutPoint(text)
+	if err != nil {
+		return err
+	}
+
+	*o = oo
+	return nil
+}
+
+// Value script as molecule bytes
+func (s Script) Value() (driver.Value, error) {
+	return s.Serialize()
+}
+
+// Scan script from molecule bytes, '0x' prefix molecule hex or json text
+func (s *Script) Scan(src interface{}) error {
+	// Molecule first, a large script may start with "0x" bytes
+	if b, ok := src.([]byte); ok && s.Deserialize(b) == nil {
+		return nil
+	}
+
+	text, raw, err := sqlText(src, -1)
+	if err != nil {
+		return err
+	}
+
+	if raw != nil {
+		return s.Deserialize(raw)
+	}
+
+	if strings.HasPrefix(text, "{") {
+		var ss Script
+		err = json.Unmarshal([]byte(text), &ss)
+		if err != nil {
+			return err
+		}
+
+		*s = ss
+		return nil
+	}
+
+	b, err := ParseBytes(text)
+	if err != nil {
+		return err
+	}
+
+	return s.Deserialize(b)
+}
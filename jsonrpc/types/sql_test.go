@@ -0,0 +1,82 @@
+package types
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+)
+
+var (
+	_ driver.Valuer = Hash{}
+	_ sql.Scanner   = (*Hash)(nil)
+	_ driver.Valuer = OutPoint{}
+	_ sql.Scanner   = (*OutPoint)(nil)
+	_ driver.Valuer = Script{}
+	_ sql.Scanner   = (*Script)(nil)
+)
+
+func TestSQLHash(t *testing.T) {
+	h := Hash{1, 2, 3}
+
+	v, err := h.Value()
+	if err != nil {
+		t.Errorf("fail to get value: %s\n", err)
+		return
+	}
+
+	for _, src := range []interface{}{v, h.String(), []byte(h.String())} {
+		var got Hash
+		err = got.Scan(src)
+		if err != nil || got != h {
+			t.Errorf("mismatch result, expect %v, got %v %v", h, got, err)
+			return
+		}
+	}
+
+	var got Hash
+	for _, src := range []interface{}{nil, 1, []byte{1}, "0x01"} {
+		if got.Scan(src) == nil {
+			t.Errorf("should reject %v", src)
+			return
+		}
+	}
+}
+
+func TestSQLOutPoint(t *testing.T) {
+	o := OutPoint{TxHash: Hash{1}, Index: 7}
+
+	v, err := o.Value()
+	if err != nil {
+		t.Errorf("fail to get value: %s\n", err)
+		return
+	}
+
+	for _, src := range []interface{}{v, o.String()} {
+		var got OutPoint
+		err = got.Scan(src)
+		if err != nil || got != o {
+			t.Errorf("mismatch result, expect %v, got %v %v", o, got, err)
+			return
+		}
+	}
+}
+
+func TestSQLScript(t *testing.T) {
+	s := Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: Bytes{1, 2}}
+
+	v, err := s.Value()
+	if err != nil {
+		t.Errorf("fail to get value: %s\n", err)
+		return
+	}
+
+	json := `{"code_hash": "` + s.CodeHash.String() + `", "hash_type": "type", "args": "0x0102"}`
+	for _, src := range []interface{}{v, Bytes(v.([]byte)).String(), json, []byte(json)} {
+		var got Script
+		err = got.Scan(src)
+		if err != nil || !got.Equal(&s) {
+			t.Errorf("mismatch result, expect %v, got %v %v", s, got, err)
+			return
+		}
+	}
+}
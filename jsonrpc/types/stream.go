@@ -0,0 +1,87 @@
+package types
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrBlockNotFound block fetcher found no block, like get_block_by_number
+// past tip returning null
+var ErrBlockNotFound = errors.New("block not found")
+
+// BlockFetcher fetch block by number, usually backed by
+// get_block_by_number rpc
+type BlockFetcher interface {
+	GetBlockByNumber(ctx context.Context, number Uint64) (*BlockView, error)
+}
+
+// StreamedBlock block delivered by BlockStream, Err is set on the last
+// item if streaming stopped on error
+type StreamedBlock struct {
+	Number Uint64
+	Block  *BlockView
+	Err    error
+}
+
+// BlockStream fetch blocks from given number with bounded concurrency
+// and deliver them in order
+/*
+ * Up to concurrency blocks are fetched ahead. Stream stops on the first
+ * fetch error, which is delivered as the last item, or when ctx is done.
+ * Fetcher returning nil block, as rpc does past tip, is delivered as
+ * ErrBlockNotFound. Channel is closed when stream stops.
+ */
+func BlockStream(ctx context.Context, f BlockFetcher, fromNumber Uint64, concurrency int) <-chan StreamedBlock {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	out := make(chan StreamedBlock)
+
+	go func() {
+		defer close(out)
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var pending []chan StreamedBlock
+		next := fromNumber
+
+		for {
+			for len(pending) < concurrency {
+				ch := make(chan StreamedBlock, 1)
+				pending = append(pending, ch)
+
+				go func(number Uint64) {
+					b, err := f.GetBlockByNumber(ctx, number)
+					if err == nil && b == nil {
+						err = ErrBlockNotFound
+					}
+					ch <- StreamedBlock{Number: number, Block: b, Err: err}
+				}(next)
+
+				next++
+			}
+
+			var item StreamedBlock
+			select {
+			case item = <-pending[0]:
+			case <-ctx.Done():
+				return
+			}
+			pending = pending[1:]
+
+			select {
+			case out <- item:
+			case <-ctx.Done():
+				return
+			}
+
+			if item.Err != nil {
+				return
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,89 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+type fakeBlockFetcher struct {
+	tip Uint64
+	// null past tip return nil block without error, like rpc
+	null bool
+}
+
+func (f *fakeBlockFetcher) GetBlockByNumber(ctx context.Context, number Uint64) (*BlockView, error) {
+	time.Sleep(time.Duration(rand.Intn(1000)) * time.Microsecond)
+
+	if number > f.tip && f.null {
+		return nil, nil
+	}
+
+	if number > f.tip {
+		return nil, fmt.Errorf("block %d not found", number)
+	}
+
+	b := new(BlockView)
+	b.Header.Header.Number = number
+
+	return b, nil
+}
+
+func TestBlockStream(t *testing.T) {
+	s := BlockStream(context.Background(), &fakeBlockFetcher{tip: 50}, 10, 8)
+
+	expect := Uint64(10)
+	for item := range s {
+		if item.Err != nil {
+			if item.Number != 51 {
+				t.Errorf("mismatch error item, expect %v, got %v", 51, item.Number)
+			}
+			break
+		}
+
+		if item.Block.Header.Header.Number != expect {
+			t.Errorf("mismatch block order, expect %v, got %v", expect, item.Block.Header.Header.Number)
+			return
+		}
+		expect++
+	}
+
+	if expect != 51 {
+		t.Errorf("mismatch streamed blocks, expect up to %v, got %v", 50, expect-1)
+		return
+	}
+
+	s = BlockStream(context.Background(), &fakeBlockFetcher{tip: 20, null: true}, 10, 4)
+
+	expect = Uint64(10)
+	for item := range s {
+		if item.Err != nil {
+			if item.Err != ErrBlockNotFound || item.Number != 21 {
+				t.Errorf("mismatch error item, expect %v at %v, got %v at %v", ErrBlockNotFound, 21, item.Err, item.Number)
+				return
+			}
+			break
+		}
+
+		if item.Block == nil || item.Block.Header.Header.Number != expect {
+			t.Errorf("mismatch block order, expect %v, got %v", expect, item.Block)
+			return
+		}
+		expect++
+	}
+
+	if expect != 21 {
+		t.Errorf("mismatch streamed blocks, expect up to %v, got %v", 20, expect-1)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s = BlockStream(ctx, &fakeBlockFetcher{tip: 1000}, 0, 4)
+	<-s
+	cancel()
+
+	for range s {
+	}
+}
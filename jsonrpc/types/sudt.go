@@ -0,0 +1,139 @@
+package types
+
+import (
+	"fmt"
+)
+
+// SudtAmountSize sudt amount, uint128 in little-endian leading cell data
+const SudtAmountSize = 16
+
+// SudtTypeScript sudt type script of tokens issued by owner lock, args are
+// owner lock hash
+func (b *Builder) SudtTypeScript(owner *Script) (*Script, error) {
+	sudt, err := b.Registry.Lookup("sudt")
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := owner.ComputeHash()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Script{CodeHash: sudt.CodeHash, HashType: sudt.HashType, Args: Bytes(h[:])}, nil
+}
+
+// ParseSudtAmount decode sudt amount from cell data, data may carry more
+// bytes after amount
+func ParseSudtAmount(data []byte) (Uint128, error) {
+	var u Uint128
+
+	if len(data) < SudtAmountSize {
+		return u, fmt.Errorf("invalid sudt data, should be at least %d bytes", SudtAmountSize)
+	}
+
+	err := u.Deserialize(data[:SudtAmountSize])
+	return u, err
+}
+
+// BuildSudtIssue build transaction issuing amount of owner tokens to
+// recipient, fee and cell capacity are paid by owner
+/*
+ * Issuance runs sudt in owner mode, a cell of owner lock is spent so
+ * outputs amount may exceed inputs amount.
+ *
+ * Anyone can pay recipient holding a cell of the token has that cell
+ * topped up, capacity unchanged, instead of getting a new cell.
+ */
+func (b *Builder) BuildSudtIssue(owner Address, to Address, amount Uint128, feeRate Uint64) (*Transaction, error) {
+	if owner.Network != to.Network {
+		return nil, fmt.Errorf("mismatch network, owner %s to %s", owner.Network, to.Network)
+	}
+
+	sudt, err := b.Registry.Lookup("sudt")
+	if err != nil {
+		return nil, err
+	}
+
+	typ, err := b.SudtTypeScript(&owner.Script)
+	if err != nil {
+		return nil, err
+	}
+
+	s := NewSkeleton()
+	s.AddCellDep(sudt.CellDep)
+
+	topped, err := b.topUpSudt(s, to, typ, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if !topped {
+		data, err := amount.Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		o := CellOutput{Lock: *to.Script.Clone(), Type: typ}
+		o.Capacity, err = o.OccupiedCapacity(len(data))
+		if err != nil {
+			return nil, err
+		}
+
+		s.AddOutput(o, data)
+	}
+
+	err = b.Balance(s, owner, feeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range s.Cells {
+		if c.Output.Lock.Equal(&owner.Script) {
+			return s.Tx, nil
+		}
+	}
+
+	return nil, fmt.Errorf("invalid issuance, no owner cell spent")
+}
+
+// topUpSudt spend anyone can pay cell of token and recreate it with amount
+// added, false if recipient is not anyone can pay or has no such cell
+func (b *Builder) topUpSudt(s *Skeleton, to Address, typ *Script, amount Uint128) (bool, error) {
+	if !b.isAnyoneCanPay(&to.Script) {
+		return false, nil
+	}
+
+	cells, err := b.Collector.LiveCells(&to.Script)
+	if err != nil {
+		return false, err
+	}
+
+	for _, c := range cells {
+		if c.Output.Type == nil || !c.Output.Type.Equal(typ) {
+			continue
+		}
+
+		held, err := ParseSudtAmount(c.Data)
+		if err != nil {
+			return false, err
+		}
+
+		total, err := held.Add(amount)
+		if err != nil {
+			return false, err
+		}
+
+		data, err := total.Serialize()
+		if err != nil {
+			return false, err
+		}
+
+		s.AddInput(c, 0)
+		s.AddOutput(*c.Output.Clone(), Bytes(append(data, c.Data[SudtAmountSize:]...)))
+
+		return true, nil
+	}
+
+	return false, nil
+}
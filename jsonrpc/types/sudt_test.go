@@ -0,0 +1,102 @@
+package types
+
+import (
+	"testing"
+)
+
+func testSudtRegistry() *ScriptRegistry {
+	r := testRegistry()
+	r.Scripts = append(r.Scripts,
+		SystemScript{
+			Name:     "sudt",
+			CodeHash: Hash{0x5d},
+			HashType: Type,
+			CellDep:  CellDep{OutPoint: OutPoint{TxHash: Hash{0xe1}}, DepType: Code},
+		},
+		SystemScript{
+			Name:     "anyone_can_pay",
+			CodeHash: Hash{0xac},
+			HashType: Type,
+			CellDep:  CellDep{OutPoint: OutPoint{TxHash: Hash{0xe2}}, DepType: DepGroup},
+		},
+	)
+
+	return r
+}
+
+func TestBuildSudtIssue(t *testing.T) {
+	owner := Address{Network: Testnet, Script: Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: make(Bytes, 20)}}
+	to := Address{Network: Testnet, Script: Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: append(Bytes{0x01}, make(Bytes, 19)...)}}
+	acp := Address{Network: Testnet, Script: Script{CodeHash: Hash{0xac}, HashType: Type, Args: Bytes{0x02}}}
+
+	c := testCollector{
+		owner.Script.Args.String(): {testPlainCell(1, owner.Script, 1000)},
+	}
+
+	b := NewBuilder(c, testSudtRegistry())
+
+	typ, err := b.SudtTypeScript(&owner.Script)
+	if err != nil {
+		t.Errorf("fail to build sudt type script: %s\n", err)
+		return
+	}
+
+	lockHash, _ := owner.Script.ComputeHash()
+	if typ.CodeHash != (Hash{0x5d}) || typ.Args.String() != Bytes(lockHash[:]).String() {
+		t.Errorf("mismatch sudt type script, got %v", typ)
+		return
+	}
+
+	tx, err := b.BuildSudtIssue(owner, to, NewUint128(1000000), DefaultMinFeeRate)
+	if err != nil {
+		t.Errorf("fail to build sudt issue: %s\n", err)
+		return
+	}
+
+	amount, err := ParseSudtAmount(tx.OutputsData[0])
+	if err != nil || amount.Cmp(NewUint128(1000000)) != 0 {
+		t.Errorf("mismatch result, expect %v, got %v", NewUint128(1000000), amount)
+		return
+	}
+
+	// 142 CKB occupied by sighash all locked sudt cell
+	if !tx.Outputs[0].Type.Equal(typ) || tx.Outputs[0].Capacity != 142*ShannonsPerCKB {
+		t.Errorf("mismatch sudt cell, got %v", tx.Outputs[0])
+		return
+	}
+
+	if len(tx.Inputs) != 1 || len(tx.CellDeps) != 2 {
+		t.Errorf("mismatch inputs or cell deps, got %v %v", tx.Inputs, tx.CellDeps)
+		return
+	}
+
+	held := make(Bytes, SudtAmountSize+1)
+	held[0] = 10
+	held[SudtAmountSize] = 0xff
+	c[acp.Script.Args.String()] = []LiveCell{
+		testPlainCell(2, acp.Script, 500),
+		{OutPoint: OutPoint{TxHash: Hash{3}}, Output: CellOutput{Capacity: 200 * ShannonsPerCKB, Lock: acp.Script, Type: typ}, Data: held},
+	}
+
+	tx, err = b.BuildSudtIssue(owner, acp, NewUint128(5), DefaultMinFeeRate)
+	if err != nil {
+		t.Errorf("fail to build sudt issue to anyone can pay: %s\n", err)
+		return
+	}
+
+	if len(tx.Inputs) != 2 || tx.Inputs[0].PreviousOutput.TxHash != (Hash{3}) || tx.Outputs[0].Capacity != 200*ShannonsPerCKB {
+		t.Errorf("mismatch topped up cell, got %v %v", tx.Inputs, tx.Outputs)
+		return
+	}
+
+	if tx.OutputsData[0].String() != "0x0f000000000000000000000000000000ff" {
+		t.Errorf("mismatch result, expect %v, got %v", "0x0f000000000000000000000000000000ff", tx.OutputsData[0])
+		return
+	}
+
+	// Anyone can pay input is unlocked without signature
+	if len(tx.Witnesses[0]) != 0 || len(tx.Witnesses[1]) == 0 || len(tx.CellDeps) != 3 {
+		t.Errorf("mismatch witnesses or cell deps, got %v %v", tx.Witnesses, tx.CellDeps)
+		return
+	}
+}
@@ -0,0 +1,157 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// DefaultBlockInterval average mainnet block interval, epoch duration
+// target over typical epoch length
+const DefaultBlockInterval = 8 * time.Second
+
+// TimeEstimator estimate block numbers, epochs and wall clock times from
+// tip, assuming blocks keep coming at average interval
+/*
+ * Epochs are converted to blocks at tip epoch length, so estimates far
+ * from tip drift as epoch length adjusts. Good enough for display, like
+ * "unlocks in ~3 days", never for consensus.
+ */
+type TimeEstimator struct {
+	// TipNumber tip block number
+	TipNumber Uint64
+	// TipEpoch tip epoch with fraction, the tip header epoch
+	TipEpoch EpochNumberWithFraction
+	// TipTime tip header timestamp
+	TipTime time.Time
+	// BlockInterval average block interval
+	BlockInterval time.Duration
+}
+
+// NewTimeEstimator new estimator from tip header
+func NewTimeEstimator(tip *Header, interval time.Duration) *TimeEstimator {
+	return &TimeEstimator{
+		TipNumber:     tip.Number,
+		TipEpoch:      NewEpochNumberWithFraction(tip.Epoch),
+		TipTime:       unixMilli(uint64(tip.Timestamp)),
+		BlockInterval: interval,
+	}
+}
+
+// BlockTime estimated time of block number, past blocks too
+func (e *TimeEstimator) BlockTime(number Uint64) time.Time {
+	return e.TipTime.Add(time.Duration(blocksBetween(e.TipNumber, number)) * e.BlockInterval)
+}
+
+// BlockAt estimated block number at time, tip for times before genesis
+// would be reached
+func (e *TimeEstimator) BlockAt(t time.Time) Uint64 {
+	blocks := int64(t.Sub(e.TipTime) / e.BlockInterval)
+	if blocks < 0 && uint64(-blocks) > uint64(e.TipNumber) {
+		return 0
+	}
+
+	return Uint64(int64(e.TipNumber) + blocks)
+}
+
+// EpochBlockNumber estimated block number of epoch with fraction
+func (e *TimeEstimator) EpochBlockNumber(epoch EpochNumberWithFraction) (Uint64, error) {
+	if e.TipEpoch.Length == 0 {
+		return 0, fmt.Errorf("invalid tip epoch %s, zero length", e.TipEpoch)
+	}
+
+	if !epoch.IsWellFormed() {
+		return 0, fmt.Errorf("invalid epoch %s", epoch)
+	}
+
+	// Distance in epochs times tip epoch length, rounded down
+	d := new(big.Rat).Sub(epoch.Rat(), e.TipEpoch.Rat())
+	d.Mul(d, new(big.Rat).SetInt64(int64(e.TipEpoch.Length)))
+	blocks := new(big.Int).Quo(d.Num(), d.Denom()).Int64()
+
+	if blocks < 0 && uint64(-blocks) > uint64(e.TipNumber) {
+		return 0, nil
+	}
+
+	return Uint64(int64(e.TipNumber) + blocks), nil
+}
+
+// EpochTime estimated time of epoch with fraction
+func (e *TimeEstimator) EpochTime(epoch EpochNumberWithFraction) (time.Time, error) {
+	n, err := e.EpochBlockNumber(epoch)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return e.BlockTime(n), nil
+}
+
+// EpochAt estimated epoch with fraction at block number
+func (e *TimeEstimator) EpochAt(number Uint64) (EpochNumberWithFraction, error) {
+	l := int64(e.TipEpoch.Length)
+	if l == 0 {
+		return EpochNumberWithFraction{}, fmt.Errorf("invalid tip epoch %s, zero length", e.TipEpoch)
+	}
+
+	// Blocks since start of tip epoch
+	pos := int64(e.TipEpoch.Index) + blocksBetween(e.TipNumber, number)
+
+	epochs := pos / l
+	index := pos % l
+	if index < 0 {
+		epochs--
+		index += l
+	}
+
+	number64 := int64(e.TipEpoch.Number) + epochs
+	if number64 < 0 {
+		return EpochNumberWithFraction{Length: uint64(l)}, nil
+	}
+
+	return EpochNumberWithFraction{Number: uint64(number64), Index: uint64(index), Length: uint64(l)}, nil
+}
+
+// HumanizeDuration approximate duration in the largest fitting unit, like
+// "~3 days", negative durations are treated as positive
+func HumanizeDuration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+
+	for _, u := range []struct {
+		d    time.Duration
+		name string
+	}{
+		{24 * time.Hour, "day"},
+		{time.Hour, "hour"},
+		{time.Minute, "minute"},
+	} {
+		if d < u.d {
+			continue
+		}
+
+		// Round to nearest unit
+		n := int64((d + u.d/2) / u.d)
+		if n == 1 {
+			return "~1 " + u.name
+		}
+
+		return fmt.Sprintf("~%d %ss", n, u.name)
+	}
+
+	return "less than a minute"
+}
+
+// blocksBetween signed block distance from a to b
+func blocksBetween(a Uint64, b Uint64) int64 {
+	if b >= a {
+		return int64(b - a)
+	}
+
+	return -int64(a - b)
+}
+
+// unixMilli time of unix milliseconds, go 1.13 has no time.UnixMilli
+func unixMilli(ms uint64) time.Time {
+	return time.Unix(int64(ms/1000), int64(ms%1000)*int64(time.Millisecond))
+}
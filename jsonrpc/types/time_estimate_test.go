@@ -0,0 +1,96 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeEstimator(t *testing.T) {
+	tip := &Header{
+		Number:    10000,
+		Epoch:     EpochNumberWithFraction{Number: 10, Index: 100, Length: 1000}.Uint64(),
+		Timestamp: 1700000000000,
+	}
+	e := NewTimeEstimator(tip, DefaultBlockInterval)
+
+	if got := e.BlockTime(10450); !got.Equal(e.TipTime.Add(time.Hour)) {
+		t.Errorf("mismatch result, expect %v, got %v", e.TipTime.Add(time.Hour), got)
+		return
+	}
+
+	if got := e.BlockTime(9550); !got.Equal(e.TipTime.Add(-time.Hour)) {
+		t.Errorf("mismatch result, expect %v, got %v", e.TipTime.Add(-time.Hour), got)
+		return
+	}
+
+	if got := e.BlockAt(e.TipTime.Add(time.Hour)); got != 10450 {
+		t.Errorf("mismatch result, expect 10450, got %d", got)
+		return
+	}
+
+	if got := e.BlockAt(e.TipTime.Add(-24 * time.Hour)); got != 0 {
+		t.Errorf("mismatch result, expect 0, got %d", got)
+		return
+	}
+
+	n, err := e.EpochBlockNumber(EpochNumberWithFraction{Number: 12, Index: 1, Length: 2})
+	if err != nil {
+		t.Errorf("fail to estimate epoch block number: %s\n", err)
+		return
+	}
+
+	if n != 10000+1900+500 {
+		t.Errorf("mismatch result, expect 12400, got %d", n)
+		return
+	}
+
+	epoch, err := e.EpochAt(12400)
+	if err != nil {
+		t.Errorf("fail to estimate epoch: %s\n", err)
+		return
+	}
+
+	if epoch != (EpochNumberWithFraction{Number: 12, Index: 500, Length: 1000}) {
+		t.Errorf("mismatch result, expect 12(500/1000), got %s", epoch)
+		return
+	}
+
+	epoch, err = e.EpochAt(9000)
+	if err != nil {
+		t.Errorf("fail to estimate epoch: %s\n", err)
+		return
+	}
+
+	if epoch != (EpochNumberWithFraction{Number: 9, Index: 100, Length: 1000}) {
+		t.Errorf("mismatch result, expect 9(100/1000), got %s", epoch)
+		return
+	}
+
+	at, err := e.EpochTime(EpochNumberWithFraction{Number: 16, Index: 100, Length: 1000})
+	if err != nil {
+		t.Errorf("fail to estimate epoch time: %s\n", err)
+		return
+	}
+
+	if s := HumanizeDuration(at.Sub(e.TipTime)); s != "~13 hours" {
+		t.Errorf("mismatch result, expect ~13 hours, got %s", s)
+		return
+	}
+}
+
+func TestHumanizeDuration(t *testing.T) {
+	for _, tt := range []struct {
+		d      time.Duration
+		expect string
+	}{
+		{70 * time.Hour, "~3 days"},
+		{90 * time.Minute, "~2 hours"},
+		{-5 * time.Minute, "~5 minutes"},
+		{30 * time.Second, "less than a minute"},
+	} {
+		if s := HumanizeDuration(tt.d); s != tt.expect {
+			t.Errorf("mismatch result, expect %s, got %s", tt.expect, s)
+			return
+		}
+	}
+}
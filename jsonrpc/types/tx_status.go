@@ -0,0 +1,39 @@
+package types
+
+// TxStatusType ckb transaction status
+type TxStatusType string
+
+// Transaction statuses
+const (
+	TxStatusPending   TxStatusType = "pending"
+	TxStatusProposed  TxStatusType = "proposed"
+	TxStatusCommitted TxStatusType = "committed"
+	TxStatusUnknown   TxStatusType = "unknown"
+	TxStatusRejected  TxStatusType = "rejected"
+
+	// TxStatusEvicted local status of tracked transaction node forgot
+	// without rejecting, node itself reports unknown
+	TxStatusEvicted TxStatusType = "evicted"
+)
+
+// TxStatus ckb transaction status, block is set once committed
+type TxStatus struct {
+	Status      TxStatusType `json:"status"`
+	BlockHash   *Hash        `json:"block_hash"`
+	BlockNumber *Uint64      `json:"block_number,omitempty"`
+	// Reason set if rejected
+	Reason *string `json:"reason,omitempty"`
+}
+
+// TransactionWithStatus result of get_transaction
+type TransactionWithStatus struct {
+	Transaction     *TransactionView `json:"transaction"`
+	Cycles          *Uint64          `json:"cycles,omitempty"`
+	TimeAddedToPool *Uint64          `json:"time_added_to_pool,omitempty"`
+	TxStatus        TxStatus         `json:"tx_status"`
+}
+
+// IsFinal report whether status no longer changes without a reorg
+func (t TxStatusType) IsFinal() bool {
+	return t == TxStatusCommitted || t == TxStatusRejected || t == TxStatusEvicted
+}
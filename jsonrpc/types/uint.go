@@ -0,0 +1,81 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Uint32 ckb uint32, '0x' prefix hex number in json
+type Uint32 uint32
+
+// Uint64 ckb uint64, '0x' prefix hex number in json
+type Uint64 uint64
+
+// parseHexUint parse '0x' prefix hex number with given bit size
+func parseHexUint(s string, bitSize int) (uint64, error) {
+	err := check0xPrefix(s)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(s) == 2 {
+		return 0, fmt.Errorf("invalid number, empty hex digits")
+	}
+
+	return strconv.ParseUint(s[2:], 16, bitSize)
+}
+
+// unmarshalHexUint unmarshal '0x' prefix hex number json string
+func unmarshalHexUint(data []byte, bitSize int) (uint64, error) {
+	var s string
+
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return 0, err
+	}
+
+	return parseHexUint(s, bitSize)
+}
+
+// String '0x' prefix hex number
+func (u Uint32) String() string {
+	return fmt.Sprintf("0x%x", uint32(u))
+}
+
+// MarshalJSON marshal uint32 to '0x' prefix hex number
+func (u Uint32) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.String())
+}
+
+// UnmarshalJSON unmarshal uint32 from '0x' prefix hex number
+func (u *Uint32) UnmarshalJSON(data []byte) error {
+	n, err := unmarshalHexUint(data, 32)
+	if err != nil {
+		return err
+	}
+
+	*u = Uint32(n)
+	return nil
+}
+
+// String '0x' prefix hex number
+func (u Uint64) String() string {
+	return fmt.Sprintf("0x%x", uint64(u))
+}
+
+// MarshalJSON marshal uint64 to '0x' prefix hex number
+func (u Uint64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.String())
+}
+
+// UnmarshalJSON unmarshal uint64 from '0x' prefix hex number
+func (u *Uint64) UnmarshalJSON(data []byte) error {
+	n, err := unmarshalHexUint(data, 64)
+	if err != nil {
+		return err
+	}
+
+	*u = Uint64(n)
+	return nil
+}
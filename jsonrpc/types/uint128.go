@@ -0,0 +1,210 @@
+package types
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"strconv"
+)
+
+// Uint128 ckb uint128, two uint64 limbs, '0x' prefix hex number in json
+type Uint128 struct {
+	Hi uint64
+	Lo uint64
+}
+
+// NewUint128 new uint128 from uint64
+func NewUint128(n uint64) Uint128 {
+	return Uint128{Lo: n}
+}
+
+// ParseUint128 parse uint128 from '0x' prefix hex number
+func ParseUint128(s string) (Uint128, error) {
+	err := check0xPrefix(s)
+	if err != nil {
+		return Uint128{}, err
+	}
+
+	uu := s[2:]
+	if len(uu) == 0 || len(uu) > 32 {
+		return Uint128{}, fmt.Errorf("invalid uint128, should be 1 to 32 hex digits")
+	}
+
+	var u Uint128
+
+	if len(uu) > 16 {
+		u.Hi, err = strconv.ParseUint(uu[:len(uu)-16], 16, 64)
+		if err != nil {
+			return Uint128{}, err
+		}
+
+		uu = uu[len(uu)-16:]
+	}
+
+	u.Lo, err = strconv.ParseUint(uu, 16, 64)
+	if err != nil {
+		return Uint128{}, err
+	}
+
+	return u, nil
+}
+
+// Uint128FromBig new uint128 from big int
+func Uint128FromBig(b *big.Int) (Uint128, error) {
+	if b.Sign() < 0 || b.BitLen() > 128 {
+		return Uint128{}, fmt.Errorf("invalid uint128, out of range")
+	}
+
+	lo := new(big.Int).And(b, new(big.Int).SetUint64(^uint64(0)))
+	hi := new(big.Int).Rsh(b, 64)
+
+	return Uint128{Hi: hi.Uint64(), Lo: lo.Uint64()}, nil
+}
+
+// Big convert uint128 to big int
+func (u Uint128) Big() *big.Int {
+	b := new(big.Int).SetUint64(u.Hi)
+	b.Lsh(b, 64)
+
+	return b.Or(b, new(big.Int).SetUint64(u.Lo))
+}
+
+// IsZero report whether uint128 is zero
+func (u Uint128) IsZero() bool {
+	return u.Hi == 0 && u.Lo == 0
+}
+
+// Cmp compare uint128, return -1 if u < v, 0 if u == v, 1 if u > v
+func (u Uint128) Cmp(v Uint128) int {
+	switch {
+	case u.Hi < v.Hi:
+		return -1
+	case u.Hi > v.Hi:
+		return 1
+	case u.Lo < v.Lo:
+		return -1
+	case u.Lo > v.Lo:
+		return 1
+	}
+
+	return 0
+}
+
+// Add return u + v, error on overflow
+func (u Uint128) Add(v Uint128) (Uint128, error) {
+	lo, carry := bits.Add64(u.Lo, v.Lo, 0)
+	hi, carry := bits.Add64(u.Hi, v.Hi, carry)
+	if carry != 0 {
+		return Uint128{}, fmt.Errorf("uint128 add overflow")
+	}
+
+	return Uint128{Hi: hi, Lo: lo}, nil
+}
+
+// Sub return u - v, error on underflow
+func (u Uint128) Sub(v Uint128) (Uint128, error) {
+	lo, borrow := bits.Sub64(u.Lo, v.Lo, 0)
+	hi, borrow := bits.Sub64(u.Hi, v.Hi, borrow)
+	if borrow != 0 {
+		return Uint128{}, fmt.Errorf("uint128 sub underflow")
+	}
+
+	return Uint128{Hi: hi, Lo: lo}, nil
+}
+
+// Mul return u * v, error on overflow
+func (u Uint128) Mul(v Uint128) (Uint128, error) {
+	if u.Hi != 0 && v.Hi != 0 {
+		return Uint128{}, fmt.Errorf("uint128 mul overflow")
+	}
+
+	hi, lo := bits.Mul64(u.Lo, v.Lo)
+
+	c1, x := bits.Mul64(u.Hi, v.Lo)
+	c2, y := bits.Mul64(u.Lo, v.Hi)
+	if c1 != 0 || c2 != 0 {
+		return Uint128{}, fmt.Errorf("uint128 mul overflow")
+	}
+
+	hi, carry := bits.Add64(hi, x, 0)
+	if carry != 0 {
+		return Uint128{}, fmt.Errorf("uint128 mul overflow")
+	}
+
+	hi, carry = bits.Add64(hi, y, 0)
+	if carry != 0 {
+		return Uint128{}, fmt.Errorf("uint128 mul overflow")
+	}
+
+	return Uint128{Hi: hi, Lo: lo}, nil
+}
+
+// DivMod return u / v and u % v, error on division by zero
+func (u Uint128) DivMod(v Uint128) (Uint128, Uint128, error) {
+	if v.IsZero() {
+		return Uint128{}, Uint128{}, fmt.Errorf("uint128 division by zero")
+	}
+
+	q, r := new(big.Int).QuoRem(u.Big(), v.Big(), new(big.Int))
+
+	// Both are in range since u is uint128 and v is not zero
+	qq, _ := Uint128FromBig(q)
+	rr, _ := Uint128FromBig(r)
+
+	return qq, rr, nil
+}
+
+// String '0x' prefix hex number
+func (u Uint128) String() string {
+	if u.Hi == 0 {
+		return fmt.Sprintf("0x%x", u.Lo)
+	}
+
+	return fmt.Sprintf("0x%x%016x", u.Hi, u.Lo)
+}
+
+// MarshalJSON marshal uint128 to '0x' prefix hex number
+func (u Uint128) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.String())
+}
+
+// UnmarshalJSON unmarshal uint128 from '0x' prefix hex number
+func (u *Uint128) UnmarshalJSON(data []byte) error {
+	var s string
+
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+
+	uu, err := ParseUint128(s)
+	if err != nil {
+		return err
+	}
+
+	*u = uu
+	return nil
+}
+
+// Serialize uint128, 16 bytes little-endian
+func (u *Uint128) Serialize() ([]byte, error) {
+	b := make([]byte, 16)
+	binary.LittleEndian.PutUint64(b[:8], u.Lo)
+	binary.LittleEndian.PutUint64(b[8:], u.Hi)
+
+	return b, nil
+}
+
+// Deserialize uint128, 16 bytes little-endian
+func (u *Uint128) Deserialize(data []byte) error {
+	if len(data) != 16 {
+		return fmt.Errorf("invalid uint128, should be 16 bytes")
+	}
+
+	u.Lo = binary.LittleEndian.Uint64(data[:8])
+	u.Hi = binary.LittleEndian.Uint64(data[8:])
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestUint128JSON(t *testing.T) {
+	raw := `"0x1a2b3c4d5e6f708192a3b4c5d6e7f8"`
+
+	var u Uint128
+
+	err := json.Unmarshal([]byte(raw), &u)
+	if err != nil {
+		t.Errorf("fail to unmarshal uint128 json: %s\n", err)
+		return
+	}
+
+	if u.Hi != 0x1a2b3c4d5e6f70 || u.Lo != 0x8192a3b4c5d6e7f8 {
+		t.Errorf("mismatch limbs, got %x %x", u.Hi, u.Lo)
+		return
+	}
+
+	got, err := json.Marshal(u)
+	if err != nil {
+		t.Errorf("fail to marshal uint128: %s\n", err)
+		return
+	}
+
+	if string(got) != raw {
+		t.Errorf("mismatch result, expect %v, got %v", raw, string(got))
+		return
+	}
+
+	err = json.Unmarshal([]byte(`"0x100000000000000000000000000000000"`), &u)
+	if err == nil {
+		t.Errorf("expect error on 129 bits number")
+		return
+	}
+}
+
+func TestSerializeUint128(t *testing.T) {
+	u, err := ParseUint128("0x1a2b3c4d5e6f708192a3b4c5d6e7f8")
+	if err != nil {
+		t.Errorf("fail to parse uint128: %s\n", err)
+		return
+	}
+
+	expectHex := "f8e7d6c5b4a39281706f5e4d3c2b1a00"
+
+	got, err := u.Serialize()
+	if err != nil {
+		t.Errorf("fail to serialize: %s\n", err)
+		return
+	}
+
+	gotHex := hex.EncodeToString(got)
+
+	if gotHex != expectHex {
+		t.Errorf("mismatch result, expect %v, got %v", expectHex, gotHex)
+		return
+	}
+}
+
+func TestUint128Arithmetic(t *testing.T) {
+	max := Uint128{Hi: ^uint64(0), Lo: ^uint64(0)}
+	one := NewUint128(1)
+
+	_, err := max.Add(one)
+	if err == nil {
+		t.Errorf("expect add overflow")
+		return
+	}
+
+	_, err = NewUint128(0).Sub(one)
+	if err == nil {
+		t.Errorf("expect sub underflow")
+		return
+	}
+
+	a := Uint128{Hi: 0x1, Lo: 0xffffffffffffffff}
+	b := NewUint128(0x100000000)
+
+	got, err := a.Mul(b)
+	if err != nil {
+		t.Errorf("fail to mul: %s\n", err)
+		return
+	}
+
+	expect := new(big.Int).Mul(a.Big(), b.Big())
+	if got.Big().Cmp(expect) != 0 {
+		t.Errorf("mismatch result, expect %v, got %v", expect, got.Big())
+		return
+	}
+
+	_, err = max.Mul(NewUint128(2))
+	if err == nil {
+		t.Errorf("expect mul overflow")
+		return
+	}
+
+	q, r, err := got.DivMod(b)
+	if err != nil {
+		t.Errorf("fail to div: %s\n", err)
+		return
+	}
+
+	if q.Cmp(a) != 0 || !r.IsZero() {
+		t.Errorf("mismatch result, expect %v, got %v rem %v", a, q, r)
+		return
+	}
+
+	back, err := Uint128FromBig(max.Big())
+	if err != nil || back != max {
+		t.Errorf("fail to round trip through big int: %v", err)
+		return
+	}
+}
@@ -0,0 +1,282 @@
+package types
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"strconv"
+)
+
+// Uint256 ckb uint256, four uint64 limbs least significant first,
+// '0x' prefix hex number in json
+type Uint256 [4]uint64
+
+// NewUint256 new uint256 from uint64
+func NewUint256(n uint64) Uint256 {
+	return Uint256{n, 0, 0, 0}
+}
+
+// MaxUint256 2^256 - 1
+func MaxUint256() Uint256 {
+	return Uint256{^uint64(0), ^uint64(0), ^uint64(0), ^uint64(0)}
+}
+
+// ParseUint256 parse uint256 from '0x' prefix hex number
+func ParseUint256(s string) (Uint256, error) {
+	err := check0xPrefix(s)
+	if err != nil {
+		return Uint256{}, err
+	}
+
+	uu := s[2:]
+	if len(uu) == 0 || len(uu) > 64 {
+		return Uint256{}, fmt.Errorf("invalid uint256, should be 1 to 64 hex digits")
+	}
+
+	var u Uint256
+
+	for i := 0; len(uu) > 0; i++ {
+		start := len(uu) - 16
+		if start < 0 {
+			start = 0
+		}
+
+		u[i], err = strconv.ParseUint(uu[start:], 16, 64)
+		if err != nil {
+			return Uint256{}, err
+		}
+
+		uu = uu[:start]
+	}
+
+	return u, nil
+}
+
+// Uint256FromBig new uint256 from big int
+func Uint256FromBig(b *big.Int) (Uint256, error) {
+	if b.Sign() < 0 || b.BitLen() > 256 {
+		return Uint256{}, fmt.Errorf("invalid uint256, out of range")
+	}
+
+	var buf [32]byte
+	bb := b.Bytes()
+	copy(buf[32-len(bb):], bb)
+
+	var u Uint256
+	for i := 0; i < 4; i++ {
+		u[i] = binary.BigEndian.Uint64(buf[24-8*i : 32-8*i])
+	}
+
+	return u, nil
+}
+
+// Big convert uint256 to big int
+func (u Uint256) Big() *big.Int {
+	var buf [32]byte
+	for i := 0; i < 4; i++ {
+		binary.BigEndian.PutUint64(buf[24-8*i:32-8*i], u[i])
+	}
+
+	return new(big.Int).SetBytes(buf[:])
+}
+
+// IsZero report whether uint256 is zero
+func (u Uint256) IsZero() bool {
+	return u == Uint256{}
+}
+
+// Cmp compare uint256, return -1 if u < v, 0 if u == v, 1 if u > v
+func (u Uint256) Cmp(v Uint256) int {
+	for i := 3; i >= 0; i-- {
+		switch {
+		case u[i] < v[i]:
+			return -1
+		case u[i] > v[i]:
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// BitLen minimum bits to represent uint256, zero for zero
+func (u Uint256) BitLen() int {
+	for i := 3; i >= 0; i-- {
+		if u[i] != 0 {
+			return 64*i + bits.Len64(u[i])
+		}
+	}
+
+	return 0
+}
+
+// Add return u + v, error on overflow
+func (u Uint256) Add(v Uint256) (Uint256, error) {
+	var r Uint256
+	var carry uint64
+
+	for i := 0; i < 4; i++ {
+		r[i], carry = bits.Add64(u[i], v[i], carry)
+	}
+
+	if carry != 0 {
+		return Uint256{}, fmt.Errorf("uint256 add overflow")
+	}
+
+	return r, nil
+}
+
+// Sub return u - v, error on underflow
+func (u Uint256) Sub(v Uint256) (Uint256, error) {
+	var r Uint256
+	var borrow uint64
+
+	for i := 0; i < 4; i++ {
+		r[i], borrow = bits.Sub64(u[i], v[i], borrow)
+	}
+
+	if borrow != 0 {
+		return Uint256{}, fmt.Errorf("uint256 sub underflow")
+	}
+
+	return r, nil
+}
+
+// Mul return u * v, error on overflow
+func (u Uint256) Mul(v Uint256) (Uint256, error) {
+	// Schoolbook multiplication into 8 limbs, then check upper half
+	var r [8]uint64
+
+	for i := 0; i < 4; i++ {
+		var carry uint64
+
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(u[i], v[j])
+
+			var c uint64
+			lo, c = bits.Add64(lo, r[i+j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+
+			r[i+j] = lo
+			carry = hi
+		}
+
+		r[i+4] = carry
+	}
+
+	if r[4]|r[5]|r[6]|r[7] != 0 {
+		return Uint256{}, fmt.Errorf("uint256 mul overflow")
+	}
+
+	return Uint256{r[0], r[1], r[2], r[3]}, nil
+}
+
+// DivMod return u / v and u % v, error on division by zero
+func (u Uint256) DivMod(v Uint256) (Uint256, Uint256, error) {
+	if v.IsZero() {
+		return Uint256{}, Uint256{}, fmt.Errorf("uint256 division by zero")
+	}
+
+	q, r := new(big.Int).QuoRem(u.Big(), v.Big(), new(big.Int))
+
+	// Both are in range since u is uint256 and v is not zero
+	qq, _ := Uint256FromBig(q)
+	rr, _ := Uint256FromBig(r)
+
+	return qq, rr, nil
+}
+
+// Lsh return u << n, bits shifted out are dropped
+func (u Uint256) Lsh(n uint) Uint256 {
+	var r Uint256
+
+	limbs, shift := int(n/64), n%64
+	for i := 3; i >= limbs; i-- {
+		r[i] = u[i-limbs] << shift
+		if shift != 0 && i-limbs > 0 {
+			r[i] |= u[i-limbs-1] >> (64 - shift)
+		}
+	}
+
+	return r
+}
+
+// Rsh return u >> n
+func (u Uint256) Rsh(n uint) Uint256 {
+	var r Uint256
+
+	limbs, shift := int(n/64), n%64
+	for i := 0; i+limbs < 4; i++ {
+		r[i] = u[i+limbs] >> shift
+		if shift != 0 && i+limbs < 3 {
+			r[i] |= u[i+limbs+1] << (64 - shift)
+		}
+	}
+
+	return r
+}
+
+// String '0x' prefix hex number
+func (u Uint256) String() string {
+	i := 3
+	for i > 0 && u[i] == 0 {
+		i--
+	}
+
+	s := fmt.Sprintf("0x%x", u[i])
+	for i--; i >= 0; i-- {
+		s += fmt.Sprintf("%016x", u[i])
+	}
+
+	return s
+}
+
+// MarshalJSON marshal uint256 to '0x' prefix hex number
+func (u Uint256) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.String())
+}
+
+// UnmarshalJSON unmarshal uint256 from '0x' prefix hex number
+func (u *Uint256) UnmarshalJSON(data []byte) error {
+	var s string
+
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+
+	uu, err := ParseUint256(s)
+	if err != nil {
+		return err
+	}
+
+	*u = uu
+	return nil
+}
+
+// Serialize uint256, 32 bytes little-endian
+func (u *Uint256) Serialize() ([]byte, error) {
+	b := make([]byte, 32)
+	for i := 0; i < 4; i++ {
+		binary.LittleEndian.PutUint64(b[8*i:8*i+8], u[i])
+	}
+
+	return b, nil
+}
+
+// Deserialize uint256, 32 bytes little-endian
+func (u *Uint256) Deserialize(data []byte) error {
+	if len(data) != 32 {
+		return fmt.Errorf("invalid uint256, should be 32 bytes")
+	}
+
+	for i := 0; i < 4; i++ {
+		u[i] = binary.LittleEndian.Uint64(data[8*i : 8*i+8])
+	}
+
+	return nil
+}
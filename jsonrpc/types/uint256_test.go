@@ -0,0 +1,117 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestUint256JSON(t *testing.T) {
+	raw := `"0x1000000000000000000000000000000000000000000000000000000000abcdef"`
+
+	var u Uint256
+
+	err := json.Unmarshal([]byte(raw), &u)
+	if err != nil {
+		t.Errorf("fail to unmarshal uint256 json: %s\n", err)
+		return
+	}
+
+	if u[3] != 0x1000000000000000 || u[0] != 0xabcdef {
+		t.Errorf("mismatch limbs, got %v", [4]uint64(u))
+		return
+	}
+
+	got, err := json.Marshal(u)
+	if err != nil {
+		t.Errorf("fail to marshal uint256: %s\n", err)
+		return
+	}
+
+	if string(got) != raw {
+		t.Errorf("mismatch result, expect %v, got %v", raw, string(got))
+		return
+	}
+
+	back, err := Uint256FromBig(u.Big())
+	if err != nil || back != u {
+		t.Errorf("fail to round trip through big int: %v", err)
+		return
+	}
+}
+
+func TestUint256Arithmetic(t *testing.T) {
+	a, _ := ParseUint256("0xffffffffffffffffffffffffffffffff")
+	b, _ := ParseUint256("0x123456789abcdef0123456789abcdef")
+
+	got, err := a.Mul(b)
+	if err != nil {
+		t.Errorf("fail to mul: %s\n", err)
+		return
+	}
+
+	expect := new(big.Int).Mul(a.Big(), b.Big())
+	if got.Big().Cmp(expect) != 0 {
+		t.Errorf("mismatch result, expect %x, got %v", expect, got)
+		return
+	}
+
+	_, err = MaxUint256().Mul(NewUint256(2))
+	if err == nil {
+		t.Errorf("expect mul overflow")
+		return
+	}
+
+	_, err = MaxUint256().Add(NewUint256(1))
+	if err == nil {
+		t.Errorf("expect add overflow")
+		return
+	}
+
+	if b.Lsh(70).Rsh(70) != b {
+		t.Errorf("mismatch shift round trip")
+		return
+	}
+
+	expect = new(big.Int).Rsh(got.Big(), 131)
+	if got.Rsh(131).Big().Cmp(expect) != 0 {
+		t.Errorf("mismatch rsh, expect %x, got %v", expect, got.Rsh(131))
+		return
+	}
+}
+
+func TestCompactTarget(t *testing.T) {
+	cases := []struct {
+		compact    uint32
+		difficulty uint64
+	}{
+		{0x20010000, 0x100},
+		{0x20800000, 0x2},
+		{0x1e083126, 0x1f4003},
+	}
+
+	for _, c := range cases {
+		got := CompactToDifficulty(c.compact)
+		if got != NewUint256(c.difficulty) {
+			t.Errorf("mismatch difficulty for %x, expect %x, got %v", c.compact, c.difficulty, got)
+			return
+		}
+
+		target, overflow := CompactToTarget(c.compact)
+		if overflow {
+			t.Errorf("unexpected overflow for %x", c.compact)
+			return
+		}
+
+		if TargetToCompact(target) != c.compact {
+			t.Errorf("mismatch compact, expect %x, got %x", c.compact, TargetToCompact(target))
+			return
+		}
+	}
+
+	_, overflow := CompactToTarget(0x21010000)
+	if !overflow {
+		t.Errorf("expect overflow")
+		return
+	}
+}
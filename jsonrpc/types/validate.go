@@ -0,0 +1,78 @@
+package types
+
+import (
+	"fmt"
+)
+
+// MaxTransactionSize default tx pool limit of serialized transaction size,
+// witnesses included
+const MaxTransactionSize = 512000
+
+// Validate check transaction structure without chain state
+/*
+ * Same checks the node does before resolving inputs:
+ *
+ *     Version is 0.
+ *     Inputs and outputs are not empty.
+ *     Outputs data matches outputs.
+ *     No duplicated cell deps, header deps or inputs.
+ *     Every since is well formed.
+ *     Serialized size within MaxTransactionSize.
+ */
+func (t *Transaction) Validate() error {
+	if t.Version != 0 {
+		return fmt.Errorf("invalid transaction, unsupported version %d", t.Version)
+	}
+
+	if len(t.Inputs) == 0 {
+		return fmt.Errorf("invalid transaction, empty inputs")
+	}
+
+	if len(t.Outputs) == 0 {
+		return fmt.Errorf("invalid transaction, empty outputs")
+	}
+
+	if len(t.OutputsData) != len(t.Outputs) {
+		return fmt.Errorf("invalid transaction, %d outputs data for %d outputs", len(t.OutputsData), len(t.Outputs))
+	}
+
+	cellDeps := make(map[CellDep]bool)
+	for _, d := range t.CellDeps {
+		if cellDeps[d] {
+			return fmt.Errorf("invalid transaction, duplicated cell dep %s", d.OutPoint)
+		}
+		cellDeps[d] = true
+	}
+
+	headerDeps := make(map[Hash]bool)
+	for _, h := range t.HeaderDeps {
+		if headerDeps[h] {
+			return fmt.Errorf("invalid transaction, duplicated header dep %s", h)
+		}
+		headerDeps[h] = true
+	}
+
+	inputs := make(map[OutPoint]bool)
+	for i, in := range t.Inputs {
+		if inputs[in.PreviousOutput] {
+			return fmt.Errorf("invalid transaction, duplicated input %s", in.PreviousOutput)
+		}
+		inputs[in.PreviousOutput] = true
+
+		_, err := DecodeSince(in.Since)
+		if err != nil {
+			return fmt.Errorf("invalid transaction, input %d %s", i, err)
+		}
+	}
+
+	b, err := t.Pack()
+	if err != nil {
+		return err
+	}
+
+	if len(b) > MaxTransactionSize {
+		return fmt.Errorf("invalid transaction, size %d exceeds %d", len(b), MaxTransactionSize)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestValidateTransaction(t *testing.T) {
+	lock := Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: make(Bytes, 20)}
+	in := CellInput{PreviousOutput: OutPoint{TxHash: Hash{1}}}
+
+	tx := &Transaction{
+		Inputs:      []CellInput{in},
+		Outputs:     []CellOutput{{Capacity: 61 * ShannonsPerCKB, Lock: lock}},
+		OutputsData: []Bytes{{}},
+		Witnesses:   []Bytes{},
+	}
+
+	err := tx.Validate()
+	if err != nil {
+		t.Errorf("fail to validate transaction: %s\n", err)
+		return
+	}
+
+	// Relative 2 whole epochs since
+	tx.Inputs[0].Since = 0xa000000000000002
+	err = tx.Validate()
+	if err != nil {
+		t.Errorf("fail to validate transaction with relative epoch since: %s\n", err)
+		return
+	}
+	tx.Inputs[0].Since = 0
+
+	tx.Inputs = append(tx.Inputs, in)
+	err = tx.Validate()
+	if err == nil {
+		t.Errorf("expect error on duplicated inputs")
+		return
+	}
+
+	tx.Inputs = []CellInput{in}
+	tx.OutputsData = nil
+	err = tx.Validate()
+	if err == nil {
+		t.Errorf("expect error on outputs data mismatch")
+		return
+	}
+
+	tx.OutputsData = []Bytes{make(Bytes, MaxTransactionSize)}
+	err = tx.Validate()
+	if err == nil {
+		t.Errorf("expect error on oversized transaction")
+		return
+	}
+}
@@ -0,0 +1,47 @@
+package types
+
+// SerializeByte32Vec serialize hashes into molecule Byte32Vec
+func SerializeByte32Vec(hs []Hash) []byte {
+	items := make([][]byte, len(hs))
+	for i := 0; i < len(hs); i++ {
+		items[i] = hs[i][:]
+	}
+
+	return SerializeFixVec(items)
+}
+
+// DeserializeByte32Vec deserialize molecule Byte32Vec into hashes
+func DeserializeByte32Vec(data []byte) ([]Hash, error) {
+	return deserializeByte32Vec(data, decoder{})
+}
+
+func deserializeByte32Vec(data []byte, d decoder) ([]Hash, error) {
+	items, err := d.fixvec(data, hashSize)
+	if err != nil {
+		return nil, err
+	}
+
+	hs := d.arena.allocHashes(len(items))
+	for i := 0; i < len(items); i++ {
+		copy(hs[i][:], items[i])
+	}
+
+	return hs, nil
+}
+
+// SerializeProposalShortIDVec serialize proposal short ids into molecule
+// ProposalShortIdVec
+func SerializeProposalShortIDVec(ps []ProposalShortID) []byte {
+	items := make([][]byte, len(ps))
+	for i := 0; i < len(ps); i++ {
+		items[i] = ps[i][:]
+	}
+
+	return SerializeFixVec(items)
+}
+
+// DeserializeProposalShortIDVec deserialize molecule ProposalShortIdVec
+// into proposal short ids
+func DeserializeProposalShortIDVec(data []byte) ([]ProposalShortID, error) {
+	return deserializeProposals(data, decoder{})
+}
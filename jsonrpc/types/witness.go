@@ -0,0 +1,181 @@
+package types
+
+import (
+	"fmt"
+)
+
+// WitnessArgs ckb witness args, the witness layout used by lock and type
+// scripts, absent fields are molecule none
+type WitnessArgs struct {
+	Lock       *Bytes `json:"lock"`
+	InputType  *Bytes `json:"input_type"`
+	OutputType *Bytes `json:"output_type"`
+	// UnknownFields trailing table fields unknown to this version
+	UnknownFields [][]byte `json:"-"`
+}
+
+// Serialize witness args
+func (w *WitnessArgs) Serialize() ([]byte, error) {
+	fields := make([][]byte, 3)
+
+	for i, f := range []*Bytes{w.Lock, w.InputType, w.OutputType} {
+		fields[i] = []byte{}
+		if f == nil {
+			continue
+		}
+
+		b, err := f.Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		fields[i] = b
+	}
+
+	return SerializeTable(append(fields, w.UnknownFields...)), nil
+}
+
+// Deserialize witness args
+func (w *WitnessArgs) Deserialize(data []byte) error {
+	return w.deserialize(data, decoder{})
+}
+
+func (w *WitnessArgs) deserialize(data []byte, d decoder) error {
+	fields, extra, err := d.table(data, 3)
+	if err != nil {
+		return err
+	}
+	w.UnknownFields = unknownFields(extra, d)
+
+	for i, f := range []**Bytes{&w.Lock, &w.InputType, &w.OutputType} {
+		*f = nil
+		if len(fields[i]) == 0 {
+			continue
+		}
+
+		b := new(Bytes)
+		err = b.deserialize(fields[i], d)
+		if err != nil {
+			return err
+		}
+
+		*f = b
+	}
+
+	return nil
+}
+
+// WitnessArgsAt decode witness args at witness index, empty witness is
+// witness args with all fields none
+func (t *Transaction) WitnessArgsAt(i int) (*WitnessArgs, error) {
+	if i < 0 || i >= len(t.Witnesses) {
+		return nil, fmt.Errorf("invalid witness index %d, %d witnesses", i, len(t.Witnesses))
+	}
+
+	w := new(WitnessArgs)
+	if len(t.Witnesses[i]) == 0 {
+		return w, nil
+	}
+
+	err := w.Deserialize(t.Witnesses[i])
+	if err != nil {
+		return nil, fmt.Errorf("invalid witness %d, %s", i, err)
+	}
+
+	return w, nil
+}
+
+// SetWitnessArgsAt serialize witness args into witness index, witnesses
+// are expanded if needed
+func (t *Transaction) SetWitnessArgsAt(i int, w *WitnessArgs) error {
+	if i < 0 {
+		return fmt.Errorf("invalid witness index %d", i)
+	}
+
+	b, err := w.Serialize()
+	if err != nil {
+		return err
+	}
+
+	t.expandWitnesses(i + 1)
+	t.Witnesses[i] = b
+	return nil
+}
+
+// ExpandWitnesses pad witnesses with empty ones until every input has a
+// witness
+func (t *Transaction) ExpandWitnesses() {
+	t.expandWitnesses(len(t.Inputs))
+}
+
+func (t *Transaction) expandWitnesses(n int) {
+	for len(t.Witnesses) < n {
+		t.Witnesses = append(t.Witnesses, Bytes{})
+	}
+}
+
+// SetWitnessLock set lock of the first witness in input group, the one
+// checked by lock script, other fields are kept
+func (t *Transaction) SetWitnessLock(group []int, lock Bytes) error {
+	err := t.checkInputGroup(group)
+	if err != nil {
+		return err
+	}
+
+	t.ExpandWitnesses()
+	return t.setWitnessLock(group[0], lock)
+}
+
+// GroupWitnesses witnesses of input group, in group order, witnesses are
+// expanded if needed
+func (t *Transaction) GroupWitnesses(group []int) ([]Bytes, error) {
+	err := t.checkInputGroup(group)
+	if err != nil {
+		return nil, err
+	}
+
+	t.ExpandWitnesses()
+
+	ws := make([]Bytes, len(group))
+	for i, j := range group {
+		ws[i] = t.Witnesses[j]
+	}
+
+	return ws, nil
+}
+
+func (t *Transaction) checkInputGroup(group []int) error {
+	if len(group) == 0 {
+		return fmt.Errorf("invalid input group, empty")
+	}
+
+	for _, i := range group {
+		if i < 0 || i >= len(t.Inputs) {
+			return fmt.Errorf("invalid input group, no input %d", i)
+		}
+	}
+
+	return nil
+}
+
+// GroupInputsByLock group input indexes sharing the same lock, locks are
+// the resolved locks of inputs, groups are ordered by first input
+func GroupInputsByLock(locks []Script) [][]int {
+	groups := [][]int{}
+	firsts := []int{}
+	for i := range locks {
+		g := 0
+		for g < len(firsts) && !locks[firsts[g]].Equal(&locks[i]) {
+			g++
+		}
+
+		if g == len(firsts) {
+			firsts = append(firsts, i)
+			groups = append(groups, []int{})
+		}
+
+		groups[g] = append(groups[g], i)
+	}
+
+	return groups
+}
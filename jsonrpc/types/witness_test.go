@@ -0,0 +1,81 @@
+package types
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestWitnessArgsAt(t *testing.T) {
+	tx := &Transaction{
+		Inputs:    []CellInput{{}, {}, {}},
+		Witnesses: []Bytes{{}},
+	}
+
+	typ := Bytes{1, 2, 3}
+	err := tx.SetWitnessArgsAt(1, &WitnessArgs{InputType: &typ})
+	if err != nil {
+		t.Errorf("fail to set witness args: %s\n", err)
+		return
+	}
+
+	err = tx.SetWitnessLock([]int{1, 2}, Bytes{4})
+	if err != nil {
+		t.Errorf("fail to set witness lock: %s\n", err)
+		return
+	}
+
+	if len(tx.Witnesses) != 3 || len(tx.Witnesses[2]) != 0 {
+		t.Errorf("mismatch result, expect 3 witnesses, got %v", tx.Witnesses)
+		return
+	}
+
+	w, err := tx.WitnessArgsAt(1)
+	if err != nil {
+		t.Errorf("fail to get witness args: %s\n", err)
+		return
+	}
+
+	if w.Lock == nil || !bytes.Equal(*w.Lock, Bytes{4}) || w.InputType == nil || !bytes.Equal(*w.InputType, typ) || w.OutputType != nil {
+		t.Errorf("mismatch result, got %v", w)
+		return
+	}
+
+	w, err = tx.WitnessArgsAt(0)
+	if err != nil || w.Lock != nil {
+		t.Errorf("mismatch result, expect empty witness args, got %v %v", w, err)
+		return
+	}
+
+	ws, err := tx.GroupWitnesses([]int{2, 1})
+	if err != nil || len(ws) != 2 || !bytes.Equal(ws[1], tx.Witnesses[1]) {
+		t.Errorf("mismatch result, got %v %v", ws, err)
+		return
+	}
+
+	for _, group := range [][]int{{}, {3}, {-1}} {
+		err = tx.SetWitnessLock(group, Bytes{4})
+		if err == nil {
+			t.Errorf("should reject input group %v", group)
+			return
+		}
+	}
+
+	_, err = tx.WitnessArgsAt(3)
+	if err == nil {
+		t.Errorf("should reject witness index out of range")
+		return
+	}
+}
+
+func TestGroupInputsByLock(t *testing.T) {
+	a := Script{CodeHash: Hash{1}, HashType: Type, Args: Bytes{1}}
+	b := Script{CodeHash: Hash{1}, HashType: Type, Args: Bytes{2}}
+
+	groups := GroupInputsByLock([]Script{a, b, a, b, b})
+	expect := [][]int{{0, 2}, {1, 3, 4}}
+	if !reflect.DeepEqual(groups, expect) {
+		t.Errorf("mismatch result, expect %v, got %v", expect, groups)
+		return
+	}
+}
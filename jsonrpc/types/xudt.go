@@ -0,0 +1,411 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// xUDT args flags, lower 29 bits select where extension scripts are
+const (
+	XudtExtensionNone       uint32 = 0
+	XudtExtensionInArgs     uint32 = 1
+	XudtExtensionHashInArgs uint32 = 2
+
+	// XudtOwnerModeInputType owner script may be type of an input
+	XudtOwnerModeInputType uint32 = 1 << 31
+	// XudtOwnerModeOutputType owner script may be type of an output
+	XudtOwnerModeOutputType uint32 = 1 << 30
+	// XudtOwnerModeSkipLock owner lock of inputs does not enable owner mode
+	XudtOwnerModeSkipLock uint32 = 1 << 29
+
+	xudtExtensionMask uint32 = 1<<29 - 1
+)
+
+// XudtArgs xUDT type script args
+/*
+ *     owner_lock_hash(32) | flags(4) | extension
+ *
+ * Extension is ScriptVec in args mode, blake160 of ScriptVec in hash mode
+ * with scripts given by witness, and absent otherwise. Flags are optional
+ * when zero.
+ */
+type XudtArgs struct {
+	OwnerLockHash Hash
+	Flags         uint32
+	Extensions    []Script
+}
+
+// Serialize xudt args
+func (a *XudtArgs) Serialize() ([]byte, error) {
+	b := new(bytes.Buffer)
+	b.Write(a.OwnerLockHash[:])
+
+	mode := a.Flags & xudtExtensionMask
+	if a.Flags == 0 && len(a.Extensions) == 0 {
+		return b.Bytes(), nil
+	}
+	b.Write(serializeUint32(a.Flags))
+
+	switch mode {
+	case XudtExtensionNone:
+		if len(a.Extensions) != 0 {
+			return nil, fmt.Errorf("invalid xudt args, extensions without extension flag")
+		}
+	case XudtExtensionInArgs, XudtExtensionHashInArgs:
+		vec, err := serializeScriptVec(a.Extensions)
+		if err != nil {
+			return nil, err
+		}
+
+		if mode == XudtExtensionHashInArgs {
+			vec = Blake160(vec)
+		}
+		b.Write(vec)
+	default:
+		return nil, fmt.Errorf("invalid xudt args, unknown extension flag %d", mode)
+	}
+
+	return b.Bytes(), nil
+}
+
+// Deserialize xudt args, extensions are left nil in hash mode
+func (a *XudtArgs) Deserialize(data []byte) error {
+	if len(data) < hashSize {
+		return fmt.Errorf("invalid xudt args, should be at least 32 bytes")
+	}
+
+	copy(a.OwnerLockHash[:], data)
+	a.Flags = 0
+	a.Extensions = nil
+
+	data = data[hashSize:]
+	if len(data) == 0 {
+		return nil
+	}
+
+	flags, err := deserializeUint32(data)
+	if err != nil {
+		return err
+	}
+	a.Flags = flags
+	data = data[uint32Size:]
+
+	switch flags & xudtExtensionMask {
+	case XudtExtensionNone:
+		if len(data) != 0 {
+			return fmt.Errorf("invalid xudt args, trailing data without extension flag")
+		}
+	case XudtExtensionInArgs:
+		a.Extensions, err = deserializeScriptVec(data)
+		return err
+	case XudtExtensionHashInArgs:
+		if len(data) != Blake160Size {
+			return fmt.Errorf("invalid xudt args, extension hash should be %d bytes", Blake160Size)
+		}
+	default:
+		return fmt.Errorf("invalid xudt args, unknown extension flag %d", flags&xudtExtensionMask)
+	}
+
+	return nil
+}
+
+// XudtWitnessInput xUDT witness, in input type of the first input of the
+// xUDT group, or output type of the first output if group has no input
+/*
+ *     table XudtWitnessInput {
+ *         owner_script:       ScriptOpt,
+ *         owner_signature:    BytesOpt,
+ *         raw_extension_data: ScriptVecOpt,
+ *         extension_data:     BytesVec,
+ *     }
+ */
+type XudtWitnessInput struct {
+	OwnerScript    *Script
+	OwnerSignature *Bytes
+	// RawExtensionData extension scripts of hash mode, nil is none
+	RawExtensionData []Script
+	// ExtensionData one item per extension script
+	ExtensionData []Bytes
+}
+
+// Serialize xudt witness input
+func (w *XudtWitnessInput) Serialize() ([]byte, error) {
+	owner, err := SerializeOption(w.OwnerScript)
+	if err != nil {
+		return nil, err
+	}
+
+	sig := []byte{}
+	if w.OwnerSignature != nil {
+		sig, err = w.OwnerSignature.Serialize()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	raw := []byte{}
+	if w.RawExtensionData != nil {
+		raw, err = serializeScriptVec(w.RawExtensionData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data := make([][]byte, len(w.ExtensionData))
+	for i := 0; i < len(w.ExtensionData); i++ {
+		data[i], err = w.ExtensionData[i].Serialize()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return SerializeTable([][]byte{owner, sig, raw, SerializeDynVec(data)}), nil
+}
+
+// Deserialize xudt witness input
+func (w *XudtWitnessInput) Deserialize(data []byte) error {
+	fields, err := DeserializeTable(data, 4)
+	if err != nil {
+		return err
+	}
+
+	w.OwnerScript = nil
+	if len(fields[0]) != 0 {
+		w.OwnerScript = new(Script)
+		err = w.OwnerScript.Deserialize(fields[0])
+		if err != nil {
+			return err
+		}
+	}
+
+	w.OwnerSignature = nil
+	if len(fields[1]) != 0 {
+		w.OwnerSignature = new(Bytes)
+		err = w.OwnerSignature.Deserialize(fields[1])
+		if err != nil {
+			return err
+		}
+	}
+
+	w.RawExtensionData = nil
+	if len(fields[2]) != 0 {
+		w.RawExtensionData, err = deserializeScriptVec(fields[2])
+		if err != nil {
+			return err
+		}
+	}
+
+	w.ExtensionData, err = deserializeBytesVec(fields[3], decoder{})
+	return err
+}
+
+// XudtTypeScript xUDT type script with args
+func (b *Builder) XudtTypeScript(args *XudtArgs) (*Script, error) {
+	xudt, err := b.Registry.Lookup("xudt")
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := args.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Script{CodeHash: xudt.CodeHash, HashType: xudt.HashType, Args: a}, nil
+}
+
+// BuildXudtMint build transaction minting amount of xUDT to recipient in
+// owner mode by lock, the owner address lock hash must be args owner lock
+// hash
+/*
+ * Witness, if any, goes to output type of the first witness, the mint
+ * group has no xUDT input.
+ */
+func (b *Builder) BuildXudtMint(owner Address, to Address, args *XudtArgs, amount Uint128, witness *XudtWitnessInput, feeRate Uint64) (*Transaction, error) {
+	if owner.Network != to.Network {
+		return nil, fmt.Errorf("mismatch network, owner %s to %s", owner.Network, to.Network)
+	}
+
+	lockHash, err := owner.Script.ComputeHash()
+	if err != nil {
+		return nil, err
+	}
+
+	if lockHash != args.OwnerLockHash {
+		return nil, fmt.Errorf("invalid xudt owner, lock hash %s mismatch args owner %s", lockHash, args.OwnerLockHash)
+	}
+
+	if args.Flags&XudtOwnerModeSkipLock != 0 {
+		return nil, fmt.Errorf("invalid xudt owner, owner mode by lock is disabled")
+	}
+
+	xudt, err := b.Registry.Lookup("xudt")
+	if err != nil {
+		return nil, err
+	}
+
+	typ, err := b.XudtTypeScript(args)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := amount.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	o := CellOutput{Lock: *to.Script.Clone(), Type: typ}
+	o.Capacity, err = o.OccupiedCapacity(len(data))
+	if err != nil {
+		return nil, err
+	}
+
+	s := NewSkeleton()
+	s.AddCellDep(xudt.CellDep)
+	s.AddOutput(o, data)
+
+	if witness != nil {
+		w, err := witness.Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		wb := Bytes(w)
+		err = s.Tx.SetWitnessArgsAt(0, &WitnessArgs{OutputType: &wb})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = b.Balance(s, owner, feeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Tx, nil
+}
+
+// BuildXudtBurn build transaction burning amount of xUDT held by address,
+// cells of the token are spent until amount is covered, the rest of tokens
+// goes back in one cell and freed capacity goes to change
+/*
+ * Witness, if any, goes to input type of the first xUDT input.
+ */
+func (b *Builder) BuildXudtBurn(holder Address, typ *Script, amount Uint128, witness *XudtWitnessInput, feeRate Uint64) (*Transaction, error) {
+	xudt, err := b.Registry.Lookup("xudt")
+	if err != nil {
+		return nil, err
+	}
+
+	if typ.CodeHash != xudt.CodeHash || typ.HashType != xudt.HashType {
+		return nil, fmt.Errorf("invalid xudt type script %s", typ.CodeHash)
+	}
+
+	cells, err := b.Collector.LiveCells(&holder.Script)
+	if err != nil {
+		return nil, err
+	}
+
+	s := NewSkeleton()
+	s.AddCellDep(xudt.CellDep)
+
+	var held Uint128
+	for _, c := range cells {
+		if held.Cmp(amount) >= 0 {
+			break
+		}
+
+		if c.Output.Type == nil || !c.Output.Type.Equal(typ) {
+			continue
+		}
+
+		a, err := ParseSudtAmount(c.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		held, err = held.Add(a)
+		if err != nil {
+			return nil, err
+		}
+
+		s.AddInput(c, 0)
+	}
+
+	if held.Cmp(amount) < 0 {
+		return nil, fmt.Errorf("insufficient xudt, hold %s, burn %s", held, amount)
+	}
+
+	left, err := held.Sub(amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if !left.IsZero() {
+		data, err := left.Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		o := CellOutput{Lock: *holder.Script.Clone(), Type: typ.Clone()}
+		o.Capacity, err = o.OccupiedCapacity(len(data))
+		if err != nil {
+			return nil, err
+		}
+
+		s.AddOutput(o, data)
+	}
+
+	if witness != nil {
+		w, err := witness.Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		wb := Bytes(w)
+		err = s.Tx.SetWitnessArgsAt(0, &WitnessArgs{InputType: &wb})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = b.Balance(s, holder, feeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Tx, nil
+}
+
+// serializeScriptVec serialize dynvec of scripts
+func serializeScriptVec(scripts []Script) ([]byte, error) {
+	items := make([][]byte, len(scripts))
+	for i := 0; i < len(scripts); i++ {
+		s, err := scripts[i].Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		items[i] = s
+	}
+
+	return SerializeDynVec(items), nil
+}
+
+// deserializeScriptVec deserialize dynvec of scripts
+func deserializeScriptVec(data []byte) ([]Script, error) {
+	items, err := DeserializeDynVec(data)
+	if err != nil {
+		return nil, err
+	}
+
+	scripts := make([]Script, len(items))
+	for i := 0; i < len(items); i++ {
+		err = scripts[i].Deserialize(items[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return scripts, nil
+}
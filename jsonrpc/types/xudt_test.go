@@ -0,0 +1,149 @@
+package types
+
+import (
+	"testing"
+)
+
+func testXudtRegistry() *ScriptRegistry {
+	r := testRegistry()
+	r.Scripts = append(r.Scripts, SystemScript{
+		Name:     "xudt",
+		CodeHash: Hash{0x50},
+		HashType: Data1,
+		CellDep:  CellDep{OutPoint: OutPoint{TxHash: Hash{0xe3}}, DepType: Code},
+	})
+
+	return r
+}
+
+func TestXudtArgs(t *testing.T) {
+	ext := Script{CodeHash: Hash{0xee}, HashType: Data1, Args: Bytes{0x01}}
+
+	for _, a := range []XudtArgs{
+		{OwnerLockHash: Hash{1}},
+		{OwnerLockHash: Hash{1}, Flags: XudtOwnerModeInputType},
+		{OwnerLockHash: Hash{1}, Flags: XudtExtensionInArgs, Extensions: []Script{ext}},
+	} {
+		b, err := a.Serialize()
+		if err != nil {
+			t.Errorf("fail to serialize xudt args: %s\n", err)
+			return
+		}
+
+		var parsed XudtArgs
+		err = parsed.Deserialize(b)
+		if err != nil {
+			t.Errorf("fail to deserialize xudt args: %s\n", err)
+			return
+		}
+
+		if parsed.OwnerLockHash != a.OwnerLockHash || parsed.Flags != a.Flags || len(parsed.Extensions) != len(a.Extensions) {
+			t.Errorf("mismatch result, expect %v, got %v", a, parsed)
+			return
+		}
+	}
+
+	hashed := XudtArgs{OwnerLockHash: Hash{1}, Flags: XudtExtensionHashInArgs, Extensions: []Script{ext}}
+	b, err := hashed.Serialize()
+	if err != nil || len(b) != hashSize+uint32Size+Blake160Size {
+		t.Errorf("mismatch hash mode args, got %x %v", b, err)
+		return
+	}
+
+	bad := XudtArgs{OwnerLockHash: Hash{1}, Extensions: []Script{ext}}
+	if _, err = bad.Serialize(); err == nil {
+		t.Errorf("expect error on extensions without flag")
+		return
+	}
+
+	w := XudtWitnessInput{RawExtensionData: []Script{ext}, ExtensionData: []Bytes{{0x02}}}
+	wb, err := w.Serialize()
+	if err != nil {
+		t.Errorf("fail to serialize xudt witness: %s\n", err)
+		return
+	}
+
+	var parsed XudtWitnessInput
+	err = parsed.Deserialize(wb)
+	if err != nil {
+		t.Errorf("fail to deserialize xudt witness: %s\n", err)
+		return
+	}
+
+	if parsed.OwnerScript != nil || parsed.OwnerSignature != nil || len(parsed.RawExtensionData) != 1 || parsed.ExtensionData[0].String() != "0x02" {
+		t.Errorf("mismatch result, expect %v, got %v", w, parsed)
+		return
+	}
+}
+
+func TestBuildXudtMintBurn(t *testing.T) {
+	owner := Address{Network: Testnet, Script: Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: make(Bytes, 20)}}
+	holder := Address{Network: Testnet, Script: Script{CodeHash: SecpSighashAllCodeHash, HashType: Type, Args: append(Bytes{0x01}, make(Bytes, 19)...)}}
+
+	c := testCollector{
+		owner.Script.Args.String(): {testPlainCell(1, owner.Script, 1000)},
+	}
+
+	b := NewBuilder(c, testXudtRegistry())
+
+	lockHash, _ := owner.Script.ComputeHash()
+	args := &XudtArgs{OwnerLockHash: lockHash}
+
+	tx, err := b.BuildXudtMint(owner, holder, args, NewUint128(300), &XudtWitnessInput{}, DefaultMinFeeRate)
+	if err != nil {
+		t.Errorf("fail to build xudt mint: %s\n", err)
+		return
+	}
+
+	typ := tx.Outputs[0].Type
+	if typ == nil || typ.CodeHash != (Hash{0x50}) || typ.Args.String() != Bytes(lockHash[:]).String() {
+		t.Errorf("mismatch xudt type script, got %v", typ)
+		return
+	}
+
+	w, err := tx.WitnessArgsAt(0)
+	if err != nil || w.OutputType == nil || w.Lock == nil || tx.Inputs[0].PreviousOutput.TxHash != (Hash{1}) {
+		t.Errorf("mismatch mint witness, got %v %v", w, err)
+		return
+	}
+
+	_, err = b.BuildXudtMint(holder, holder, args, NewUint128(300), nil, DefaultMinFeeRate)
+	if err == nil {
+		t.Errorf("expect error on mint by non owner")
+		return
+	}
+
+	c[holder.Script.Args.String()] = []LiveCell{
+		{OutPoint: OutPoint{TxHash: Hash{2}}, Output: tx.Outputs[0], Data: tx.OutputsData[0]},
+		{OutPoint: OutPoint{TxHash: Hash{3}}, Output: tx.Outputs[0], Data: tx.OutputsData[0]},
+	}
+
+	burn, err := b.BuildXudtBurn(holder, typ, NewUint128(400), nil, DefaultMinFeeRate)
+	if err != nil {
+		t.Errorf("fail to build xudt burn: %s\n", err)
+		return
+	}
+
+	if len(burn.Inputs) != 2 || len(burn.Outputs) != 2 {
+		t.Errorf("mismatch burn transaction, got %v %v", burn.Inputs, burn.Outputs)
+		return
+	}
+
+	left, err := ParseSudtAmount(burn.OutputsData[0])
+	if err != nil || left.Cmp(NewUint128(200)) != 0 {
+		t.Errorf("mismatch result, expect %v, got %v", NewUint128(200), left)
+		return
+	}
+
+	// Capacity freed by burned cell is change
+	if burn.Outputs[1].Type != nil || burn.Outputs[1].Capacity >= 142*ShannonsPerCKB || burn.Outputs[1].Capacity < 141*ShannonsPerCKB {
+		t.Errorf("mismatch change, got %v", burn.Outputs[1])
+		return
+	}
+
+	_, err = b.BuildXudtBurn(holder, typ, NewUint128(601), nil, DefaultMinFeeRate)
+	if err == nil {
+		t.Errorf("expect error on insufficient xudt")
+		return
+	}
+}
@@ -0,0 +1,74 @@
+//go:build awskms
+// +build awskms
+
+package kms
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// AWSClient subset of aws kms client used by AWSKey, *kms.Client
+// implements it
+type AWSClient interface {
+	Sign(ctx context.Context, in *kms.SignInput, opts ...func(*kms.Options)) (*kms.SignOutput, error)
+	GetPublicKey(ctx context.Context, in *kms.GetPublicKeyInput, opts ...func(*kms.Options)) (*kms.GetPublicKeyOutput, error)
+}
+
+// AWSKey aws kms ECC_SECG_P256K1 key as types.DERSigner
+/*
+ * Digest is sent as is with message type DIGEST, so kms signs the ckb
+ * blake2b digest, not a sha256 of it. ECDSA_SHA_256 only names the
+ * algorithm kms expects for this key spec.
+ */
+type AWSKey struct {
+	Client AWSClient
+	// KeyID key id, arn or alias
+	KeyID string
+	// Timeout of each kms call, none if zero
+	Timeout time.Duration
+}
+
+// NewAWSSigner new RemoteSigner of aws kms key, public key is read from kms
+func NewAWSSigner(ctx context.Context, client AWSClient, keyID string) (*types.KMSSigner, error) {
+	k := &AWSKey{Client: client, KeyID: keyID}
+
+	out, err := client.GetPublicKey(ctx, &kms.GetPublicKeyInput{KeyId: aws.String(keyID)})
+	if err != nil {
+		return nil, err
+	}
+
+	if out.KeySpec != kmstypes.KeySpecEccSecgP256k1 {
+		return nil, fmt.Errorf("invalid aws kms key %s, spec %s is not %s", keyID, out.KeySpec, kmstypes.KeySpecEccSecgP256k1)
+	}
+
+	return types.NewKMSSigner(k, out.PublicKey)
+}
+
+// SignDER implement types.DERSigner
+func (k *AWSKey) SignDER(digest types.Hash) ([]byte, error) {
+	ctx := context.Background()
+	if k.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, k.Timeout)
+		defer cancel()
+	}
+
+	out, err := k.Client.Sign(ctx, &kms.SignInput{
+		KeyId:            aws.String(k.KeyID),
+		Message:          digest[:],
+		MessageType:      kmstypes.MessageTypeDigest,
+		SigningAlgorithm: kmstypes.SigningAlgorithmSpecEcdsaSha256,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Signature, nil
+}
@@ -0,0 +1,65 @@
+//go:build awskms
+// +build awskms
+
+package kms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+type testAWSClient struct {
+	key  *testKey
+	spec kmstypes.KeySpec
+}
+
+func (c *testAWSClient) Sign(ctx context.Context, in *kms.SignInput, opts ...func(*kms.Options)) (*kms.SignOutput, error) {
+	if in.MessageType != kmstypes.MessageTypeDigest || aws.ToString(in.KeyId) != "alias/ckb" {
+		return nil, &kmstypes.InvalidKeyUsageException{}
+	}
+
+	sig, err := c.key.signDER(in.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kms.SignOutput{Signature: sig}, nil
+}
+
+func (c *testAWSClient) GetPublicKey(ctx context.Context, in *kms.GetPublicKeyInput, opts ...func(*kms.Options)) (*kms.GetPublicKeyOutput, error) {
+	return &kms.GetPublicKeyOutput{KeySpec: c.spec, PublicKey: c.key.spki()}, nil
+}
+
+func TestAWSSigner(t *testing.T) {
+	key := newTestKey()
+
+	s, err := NewAWSSigner(context.Background(), &testAWSClient{key: key, spec: kmstypes.KeySpecEccSecgP256k1}, "alias/ckb")
+	if err != nil {
+		t.Errorf("fail to create aws signer: %s\n", err)
+		return
+	}
+
+	digest := types.Hash{0x01}
+	sig, err := s.SignDigest(digest)
+	if err != nil {
+		t.Errorf("fail to sign digest: %s\n", err)
+		return
+	}
+
+	pub, _ := s.GetPublicKey()
+	if !types.VerifySignature(pub, digest, sig) {
+		t.Errorf("mismatch result, signature not verified")
+		return
+	}
+
+	_, err = NewAWSSigner(context.Background(), &testAWSClient{key: key, spec: kmstypes.KeySpecEccNistP256}, "alias/ckb")
+	if err == nil {
+		t.Errorf("expect error on p256 key")
+		return
+	}
+}
@@ -0,0 +1,11 @@
+// Package kms provides types.DERSigner adapters for cloud kms secp256k1
+// keys, wrapped by types.NewKMSSigner into a RemoteSigner.
+//
+// Each adapter is behind a build tag to keep cloud SDKs out of default
+// builds:
+//
+//	go build -tags awskms ./...
+//	go build -tags gcpkms ./...
+//
+// Without tags this package is empty.
+package kms
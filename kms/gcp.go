@@ -0,0 +1,83 @@
+//go:build gcpkms
+// +build gcpkms
+
+package kms
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/kms/apiv1/kmspb"
+	"github.com/googleapis/gax-go/v2"
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// GCPClient subset of gcp kms client used by GCPKey,
+// *kms.KeyManagementClient implements it
+type GCPClient interface {
+	AsymmetricSign(ctx context.Context, req *kmspb.AsymmetricSignRequest, opts ...gax.CallOption) (*kmspb.AsymmetricSignResponse, error)
+	GetPublicKey(ctx context.Context, req *kmspb.GetPublicKeyRequest, opts ...gax.CallOption) (*kmspb.PublicKey, error)
+}
+
+// GCPKey gcp kms EC_SIGN_SECP256K1_SHA256 key version as types.DERSigner
+/*
+ * Digest goes in the sha256 field as is, so kms signs the ckb blake2b
+ * digest. Name is the full key version resource:
+ *
+ *     projects/p/locations/l/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1
+ */
+type GCPKey struct {
+	Client GCPClient
+	// Name key version resource name
+	Name string
+	// Timeout of each kms call, none if zero
+	Timeout time.Duration
+}
+
+// NewGCPSigner new RemoteSigner of gcp kms key version, public key is read
+// from kms
+func NewGCPSigner(ctx context.Context, client GCPClient, name string) (*types.KMSSigner, error) {
+	k := &GCPKey{Client: client, Name: name}
+
+	pub, err := client.GetPublicKey(ctx, &kmspb.GetPublicKeyRequest{Name: name})
+	if err != nil {
+		return nil, err
+	}
+
+	if pub.Algorithm != kmspb.CryptoKeyVersion_EC_SIGN_SECP256K1_SHA256 {
+		return nil, fmt.Errorf("invalid gcp kms key %s, algorithm %s is not secp256k1", name, pub.Algorithm)
+	}
+
+	block, _ := pem.Decode([]byte(pub.Pem))
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("invalid gcp kms key %s, bad public key pem", name)
+	}
+
+	return types.NewKMSSigner(k, block.Bytes)
+}
+
+// SignDER implement types.DERSigner
+func (k *GCPKey) SignDER(digest types.Hash) ([]byte, error) {
+	ctx := context.Background()
+	if k.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, k.Timeout)
+		defer cancel()
+	}
+
+	resp, err := k.Client.AsymmetricSign(ctx, &kmspb.AsymmetricSignRequest{
+		Name:   k.Name,
+		Digest: &kmspb.Digest{Digest: &kmspb.Digest_Sha256{Sha256: digest[:]}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Name != "" && resp.Name != k.Name {
+		return nil, fmt.Errorf("invalid gcp kms response, signed by %s", resp.Name)
+	}
+
+	return resp.Signature, nil
+}
@@ -0,0 +1,69 @@
+//go:build gcpkms
+// +build gcpkms
+
+package kms
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"testing"
+
+	"cloud.google.com/go/kms/apiv1/kmspb"
+	"github.com/googleapis/gax-go/v2"
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+const testGCPName = "projects/p/locations/l/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1"
+
+type testGCPClient struct {
+	key       *testKey
+	algorithm kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm
+}
+
+func (c *testGCPClient) AsymmetricSign(ctx context.Context, req *kmspb.AsymmetricSignRequest, opts ...gax.CallOption) (*kmspb.AsymmetricSignResponse, error) {
+	if req.Name != testGCPName || req.Digest.GetSha256() == nil {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	sig, err := c.key.signDER(req.Digest.GetSha256())
+	if err != nil {
+		return nil, err
+	}
+
+	return &kmspb.AsymmetricSignResponse{Name: req.Name, Signature: sig}, nil
+}
+
+func (c *testGCPClient) GetPublicKey(ctx context.Context, req *kmspb.GetPublicKeyRequest, opts ...gax.CallOption) (*kmspb.PublicKey, error) {
+	p := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: c.key.spki()})
+	return &kmspb.PublicKey{Algorithm: c.algorithm, Pem: string(p), Name: req.Name}, nil
+}
+
+func TestGCPSigner(t *testing.T) {
+	key := newTestKey()
+
+	s, err := NewGCPSigner(context.Background(), &testGCPClient{key: key, algorithm: kmspb.CryptoKeyVersion_EC_SIGN_SECP256K1_SHA256}, testGCPName)
+	if err != nil {
+		t.Errorf("fail to create gcp signer: %s\n", err)
+		return
+	}
+
+	digest := types.Hash{0x01}
+	sig, err := s.SignDigest(digest)
+	if err != nil {
+		t.Errorf("fail to sign digest: %s\n", err)
+		return
+	}
+
+	pub, _ := s.GetPublicKey()
+	if !types.VerifySignature(pub, digest, sig) {
+		t.Errorf("mismatch result, signature not verified")
+		return
+	}
+
+	_, err = NewGCPSigner(context.Background(), &testGCPClient{key: key, algorithm: kmspb.CryptoKeyVersion_EC_SIGN_P256_SHA256}, testGCPName)
+	if err == nil {
+		t.Errorf("expect error on p256 key")
+		return
+	}
+}
@@ -0,0 +1,58 @@
+//go:build awskms || gcpkms
+// +build awskms gcpkms
+
+package kms
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// testKey local key standing in for kms key
+type testKey struct {
+	key *types.KeySigner
+}
+
+func newTestKey() *testKey {
+	seckey := make([]byte, 32)
+	seckey[31] = 1
+
+	key, _ := types.NewKeySigner(seckey)
+	return &testKey{key: key}
+}
+
+// spki DER SubjectPublicKeyInfo as kms GetPublicKey returns
+func (k *testKey) spki() []byte {
+	pub, _ := k.key.GetPublicKey()
+	uncompressed, _ := types.DecompressPubkey(pub)
+
+	params, _ := asn1.Marshal(asn1.ObjectIdentifier{1, 3, 132, 0, 10})
+	der, _ := asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1},
+			Parameters: asn1.RawValue{FullBytes: params},
+		},
+		PublicKey: asn1.BitString{Bytes: uncompressed, BitLength: len(uncompressed) * 8},
+	})
+
+	return der
+}
+
+// signDER DER ecdsa signature without recovery id as kms Sign returns
+func (k *testKey) signDER(digest []byte) ([]byte, error) {
+	var h types.Hash
+	copy(h[:], digest)
+
+	sig, err := k.key.SignDigest(h)
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(struct{ R, S *big.Int }{new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64])})
+}
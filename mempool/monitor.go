@@ -0,0 +1,270 @@
+// Package mempool monitors a node tx pool, merging new_transaction,
+// proposed_transaction and rejected_transaction subscriptions with
+// get_raw_tx_pool polling into one stream of typed events.
+//
+// Subscriptions are fast but lossy across reconnects, polling catches up
+// on what they missed and is the only way to see transactions leaving the
+// pool, by being committed or evicted.
+package mempool
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// ErrNoFetcher Poll on monitor created without fetcher
+var ErrNoFetcher = errors.New("mempool monitor has no fetcher to poll")
+
+// ErrStaleSnapshot Poll snapshot discarded, pool changed by subscription
+// or another Poll while it was fetched, poll again
+var ErrStaleSnapshot = errors.New("tx pool changed while polling, snapshot discarded")
+
+// Fetcher tx pool source, usually backed by get_raw_tx_pool and
+// get_transaction rpc
+type Fetcher interface {
+	GetRawTxPool(ctx context.Context) (*types.RawTxPool, error)
+	// GetTransaction returns nil transaction if node does not know it
+	GetTransaction(ctx context.Context, hash types.Hash) (*types.TransactionView, error)
+}
+
+// EventType mempool event type
+type EventType int
+
+// Mempool event types
+const (
+	// Added transaction entered pool
+	Added EventType = iota
+	// Proposed transaction was proposed, still in pool
+	Proposed
+	// Removed transaction left pool, committed or evicted
+	Removed
+	// Rejected transaction was rejected by pool
+	Rejected
+)
+
+// String event type name
+func (t EventType) String() string {
+	switch t {
+	case Added:
+		return "added"
+	case Proposed:
+		return "proposed"
+	case Removed:
+		return "removed"
+	case Rejected:
+		return "rejected"
+	default:
+		return "unknown"
+	}
+}
+
+// Event mempool event
+type Event struct {
+	Type        EventType
+	Hash        types.Hash
+	Transaction *types.Transaction
+	// Entry pool entry, set if event comes from subscription
+	Entry *types.PoolTransactionEntry
+	// Reason set on Rejected
+	Reason *types.PoolTransactionReject
+}
+
+// Monitor track pool transactions and emit events on change
+/*
+ * Every transaction gets Added once before other events, whether it is
+ * first seen by subscription or polling. Callback is called outside of
+ * monitor lock, in the order events happen.
+ */
+type Monitor struct {
+	f       Fetcher
+	onEvent func(Event)
+
+	mu   sync.Mutex
+	pool map[types.Hash]*poolTx
+	// seq bumped on every pool change, Poll snapshots older than it are
+	// stale
+	seq uint64
+}
+
+// poolTx transaction known in pool
+type poolTx struct {
+	tx       *types.Transaction
+	proposed bool
+}
+
+// New new monitor, fetcher is used by Poll and may be nil if events only
+// come from subscriptions
+func New(f Fetcher, onEvent func(Event)) *Monitor {
+	return &Monitor{f: f, onEvent: onEvent, pool: make(map[types.Hash]*poolTx)}
+}
+
+// Len number of transactions known in pool
+func (m *Monitor) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.pool)
+}
+
+// OnNewTransaction handle new_transaction subscription payload
+func (m *Monitor) OnNewTransaction(e *types.PoolTransactionEntry) {
+	m.mu.Lock()
+	events := m.add(e.Transaction.Hash, &e.Transaction.Transaction, e)
+	m.mu.Unlock()
+
+	m.emit(events)
+}
+
+// OnProposedTransaction handle proposed_transaction subscription payload
+func (m *Monitor) OnProposedTransaction(e *types.PoolTransactionEntry) {
+	m.mu.Lock()
+	events := m.add(e.Transaction.Hash, &e.Transaction.Transaction, e)
+	events = append(events, m.propose(e.Transaction.Hash, e)...)
+	m.mu.Unlock()
+
+	m.emit(events)
+}
+
+// OnRejectedTransaction handle rejected_transaction subscription payload,
+// transaction never seen before gets Added first
+func (m *Monitor) OnRejectedTransaction(r *types.RejectedTransaction) {
+	hash := r.Entry.Transaction.Hash
+
+	m.mu.Lock()
+	events := m.add(hash, &r.Entry.Transaction.Transaction, &r.Entry)
+	delete(m.pool, hash)
+	m.seq++
+	m.mu.Unlock()
+
+	reason := r.Reason
+	m.emit(append(events, Event{
+		Type:        Rejected,
+		Hash:        hash,
+		Transaction: &r.Entry.Transaction.Transaction,
+		Entry:       &r.Entry,
+		Reason:      &reason,
+	}))
+}
+
+// Poll sync with get_raw_tx_pool, transactions not seen yet are fetched
+// and the ones gone from pool are removed
+/*
+ * Snapshot is fetched outside of lock. If a subscription payload or
+ * another Poll changes pool meanwhile, snapshot may predate it and would
+ * remove or add transactions wrongly, so it is discarded with
+ * ErrStaleSnapshot instead.
+ */
+func (m *Monitor) Poll(ctx context.Context) error {
+	if m.f == nil {
+		return ErrNoFetcher
+	}
+
+	m.mu.Lock()
+	seq := m.seq
+	m.mu.Unlock()
+
+	raw, err := m.f.GetRawTxPool(ctx)
+	if err != nil {
+		return err
+	}
+
+	fetched := make(map[types.Hash]*types.Transaction)
+	for _, h := range append(append([]types.Hash{}, raw.Pending...), raw.Proposed...) {
+		if m.known(h) {
+			continue
+		}
+
+		tx, err := m.f.GetTransaction(ctx, h)
+		if err != nil {
+			return err
+		}
+
+		// Left pool while polling
+		if tx != nil {
+			fetched[h] = &tx.Transaction
+		}
+	}
+
+	m.mu.Lock()
+
+	if m.seq != seq {
+		m.mu.Unlock()
+		return ErrStaleSnapshot
+	}
+
+	var events []Event
+	inPool := make(map[types.Hash]bool)
+	for _, h := range raw.Pending {
+		inPool[h] = true
+		if tx, ok := fetched[h]; ok {
+			events = append(events, m.add(h, tx, nil)...)
+		}
+	}
+
+	for _, h := range raw.Proposed {
+		inPool[h] = true
+		if tx, ok := fetched[h]; ok {
+			events = append(events, m.add(h, tx, nil)...)
+		}
+		events = append(events, m.propose(h, nil)...)
+	}
+
+	for h, p := range m.pool {
+		if !inPool[h] {
+			delete(m.pool, h)
+			m.seq++
+			events = append(events, Event{Type: Removed, Hash: h, Transaction: p.tx})
+		}
+	}
+
+	m.mu.Unlock()
+
+	m.emit(events)
+	return nil
+}
+
+// known report whether transaction is known in pool
+func (m *Monitor) known(hash types.Hash) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.pool[hash]
+	return ok
+}
+
+// add add transaction if not known, caller holds lock
+func (m *Monitor) add(hash types.Hash, tx *types.Transaction, e *types.PoolTransactionEntry) []Event {
+	if _, ok := m.pool[hash]; ok {
+		return nil
+	}
+
+	m.pool[hash] = &poolTx{tx: tx}
+	m.seq++
+	return []Event{{Type: Added, Hash: hash, Transaction: tx, Entry: e}}
+}
+
+// propose mark known transaction proposed, caller holds lock
+func (m *Monitor) propose(hash types.Hash, e *types.PoolTransactionEntry) []Event {
+	p, ok := m.pool[hash]
+	if !ok || p.proposed {
+		return nil
+	}
+
+	p.proposed = true
+	m.seq++
+	return []Event{{Type: Proposed, Hash: hash, Transaction: p.tx, Entry: e}}
+}
+
+// emit call callback with events in order
+func (m *Monitor) emit(events []Event) {
+	if m.onEvent == nil {
+		return
+	}
+
+	for _, e := range events {
+		m.onEvent(e)
+	}
+}
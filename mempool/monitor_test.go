@@ -0,0 +1,157 @@
+package mempool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+type testFetcher struct {
+	raw types.RawTxPool
+	txs map[types.Hash]*types.TransactionView
+	// during called while fetching pool, to race subscriptions
+	during func()
+}
+
+func (f *testFetcher) GetRawTxPool(ctx context.Context) (*types.RawTxPool, error) {
+	raw := f.raw
+	if f.during != nil {
+		f.during()
+	}
+
+	return &raw, nil
+}
+
+func (f *testFetcher) GetTransaction(ctx context.Context, hash types.Hash) (*types.TransactionView, error) {
+	return f.txs[hash], nil
+}
+
+func testTx(hash byte) *types.TransactionView {
+	return &types.TransactionView{Transaction: types.Transaction{Version: types.Uint32(hash)}, Hash: types.Hash{hash}}
+}
+
+func TestMonitor(t *testing.T) {
+	a, b, c := testTx(0x0a), testTx(0x0b), testTx(0x0c)
+	f := &testFetcher{
+		raw: types.RawTxPool{Pending: []types.Hash{a.Hash, b.Hash}},
+		txs: map[types.Hash]*types.TransactionView{a.Hash: a, b.Hash: b},
+	}
+
+	var events []Event
+	m := New(f, func(e Event) {
+		events = append(events, e)
+	})
+
+	m.OnNewTransaction(&types.PoolTransactionEntry{Transaction: *a})
+
+	err := m.Poll(context.Background())
+	if err != nil {
+		t.Errorf("fail to poll: %s\n", err)
+		return
+	}
+
+	if len(events) != 2 || events[0].Entry == nil || events[1].Hash != b.Hash || events[1].Type != Added {
+		t.Errorf("mismatch result, expect a and b added, got %v", events)
+		return
+	}
+
+	if events[1].Transaction.Version != 0x0b {
+		t.Errorf("mismatch result, expect decoded b, got %v", events[1].Transaction)
+		return
+	}
+
+	events = nil
+	m.OnRejectedTransaction(&types.RejectedTransaction{
+		Entry:  types.PoolTransactionEntry{Transaction: *c},
+		Reason: types.PoolTransactionReject{Type: types.RejectResolve, Description: "dead"},
+	})
+
+	f.raw = types.RawTxPool{Pending: []types.Hash{}, Proposed: []types.Hash{b.Hash}}
+	err = m.Poll(context.Background())
+	if err != nil {
+		t.Errorf("fail to poll: %s\n", err)
+		return
+	}
+
+	if len(events) != 4 {
+		t.Errorf("mismatch result, expect 4 events, got %v", events)
+		return
+	}
+
+	// c was never seen, so it is added before rejected
+	if events[0].Type != Added || events[0].Hash != c.Hash || events[0].Entry == nil {
+		t.Errorf("mismatch result, expect c added, got %v", events[0])
+		return
+	}
+
+	if events[1].Type != Rejected || events[1].Hash != c.Hash || events[1].Reason.Type != types.RejectResolve {
+		t.Errorf("mismatch result, expect c rejected, got %v", events[1])
+		return
+	}
+
+	if events[2].Type != Proposed || events[2].Hash != b.Hash {
+		t.Errorf("mismatch result, expect b proposed, got %v", events[2])
+		return
+	}
+
+	if events[3].Type != Removed || events[3].Hash != a.Hash || events[3].Transaction.Version != 0x0a {
+		t.Errorf("mismatch result, expect a removed, got %v", events[3])
+		return
+	}
+
+	if m.Len() != 1 {
+		t.Errorf("mismatch result, expect 1 transaction in pool, got %d", m.Len())
+		return
+	}
+}
+
+func TestMonitorStaleSnapshot(t *testing.T) {
+	a := testTx(0x0a)
+	f := &testFetcher{raw: types.RawTxPool{Pending: []types.Hash{}}}
+
+	var events []Event
+	m := New(f, func(e Event) {
+		events = append(events, e)
+	})
+
+	// a arrives after empty snapshot is taken, applying snapshot would
+	// remove it
+	f.during = func() {
+		f.during = nil
+		m.OnNewTransaction(&types.PoolTransactionEntry{Transaction: *a})
+	}
+
+	err := m.Poll(context.Background())
+	if err != ErrStaleSnapshot {
+		t.Errorf("mismatch result, expect %v, got %v", ErrStaleSnapshot, err)
+		return
+	}
+
+	if m.Len() != 1 || len(events) != 1 || events[0].Type != Added {
+		t.Errorf("mismatch result, expect a kept in pool, got %v", events)
+		return
+	}
+
+	f.raw = types.RawTxPool{Pending: []types.Hash{a.Hash}}
+	err = m.Poll(context.Background())
+	if err != nil {
+		t.Errorf("fail to poll: %s\n", err)
+		return
+	}
+
+	if m.Len() != 1 || len(events) != 1 {
+		t.Errorf("mismatch result, expect no new event, got %v", events)
+		return
+	}
+}
+
+func TestMonitorNoFetcher(t *testing.T) {
+	m := New(nil, nil)
+
+	err := m.Poll(context.Background())
+	if err != ErrNoFetcher {
+		t.Errorf("mismatch result, expect %v, got %v", ErrNoFetcher, err)
+		return
+	}
+}
@@ -0,0 +1,7 @@
+// Package mol provides generic molecule vectors, FixVec and DynVec, that
+// serialize items straight into one buffer and deserialize them straight
+// from input, with no [][]byte in between.
+//
+// Generics need go 1.18, older toolchains build this package empty.
+// Package types keeps its [][]byte helpers so that it builds on go 1.13.
+package mol
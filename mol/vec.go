@@ -0,0 +1,171 @@
+//go:build go1.18
+// +build go1.18
+
+package mol
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// u32Size molecule header item size
+const u32Size = 4
+
+// MolSerializer molecule item T, serialized and deserialized through its
+// pointer, like *types.Script
+type MolSerializer[T any] interface {
+	*T
+	Serialize() ([]byte, error)
+	Deserialize(data []byte) error
+}
+
+// MolFixed molecule fixed size item T, like *types.Hash or
+// *types.OutPoint, every value serializes to the same size
+type MolFixed[T any] interface {
+	MolSerializer[T]
+}
+
+// FixVec molecule fixvec of fixed size items
+/*
+ *     Item count as a 32 bit unsigned integer in little-endian.
+ *     Items.
+ */
+type FixVec[T any, P MolFixed[T]] []T
+
+// Serialize fixvec, error if items differ in size
+func (v FixVec[T, P]) Serialize() ([]byte, error) {
+	b := make([]byte, u32Size, u32Size+len(v)*32)
+	binary.LittleEndian.PutUint32(b, uint32(len(v)))
+
+	size := -1
+	for i := range v {
+		item, err := P(&v[i]).Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		if size >= 0 && len(item) != size {
+			return nil, fmt.Errorf("invalid fixvec item %d, %d bytes, others %d", i, len(item), size)
+		}
+		size = len(item)
+
+		b = append(b, item...)
+	}
+
+	return b, nil
+}
+
+// Deserialize fixvec, item size is data size over count, checked by item
+// Deserialize
+func (v *FixVec[T, P]) Deserialize(data []byte) error {
+	if len(data) < u32Size {
+		return fmt.Errorf("invalid fixvec, should be at least %d bytes", u32Size)
+	}
+
+	n := uint64(binary.LittleEndian.Uint32(data))
+	body := data[u32Size:]
+	if n == 0 {
+		if len(body) != 0 {
+			return fmt.Errorf("invalid fixvec, %d bytes after zero items", len(body))
+		}
+
+		*v = FixVec[T, P]{}
+		return nil
+	}
+
+	if uint64(len(body))%n != 0 {
+		return fmt.Errorf("invalid fixvec, %d bytes for %d items", len(body), n)
+	}
+	size := uint64(len(body)) / n
+
+	items := make(FixVec[T, P], n)
+	for i := uint64(0); i < n; i++ {
+		err := P(&items[i]).Deserialize(body[i*size : (i+1)*size])
+		if err != nil {
+			return fmt.Errorf("invalid fixvec item %d, %s", i, err)
+		}
+	}
+
+	*v = items
+	return nil
+}
+
+// DynVec molecule dynvec of items of any size
+/*
+ *     Full size in bytes as a 32 bit unsigned integer in little-endian.
+ *     Offsets of items as 32 bit unsigned integer in little-endian.
+ *     Items.
+ */
+type DynVec[T any, P MolSerializer[T]] []T
+
+// Serialize dynvec, items are appended after header and offsets filled in
+// as they go
+func (v DynVec[T, P]) Serialize() ([]byte, error) {
+	header := u32Size * (len(v) + 1)
+	if len(v) == 0 {
+		header = u32Size
+	}
+
+	b := make([]byte, header)
+	for i := range v {
+		binary.LittleEndian.PutUint32(b[u32Size*(i+1):], uint32(len(b)))
+
+		item, err := P(&v[i]).Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		b = append(b, item...)
+	}
+	binary.LittleEndian.PutUint32(b, uint32(len(b)))
+
+	return b, nil
+}
+
+// Deserialize dynvec, offsets must be canonical
+func (v *DynVec[T, P]) Deserialize(data []byte) error {
+	if len(data) < u32Size {
+		return fmt.Errorf("invalid dynvec, should be at least %d bytes", u32Size)
+	}
+
+	size := uint64(binary.LittleEndian.Uint32(data))
+	if size != uint64(len(data)) {
+		return fmt.Errorf("invalid dynvec, header size %d mismatch %d bytes", size, len(data))
+	}
+
+	if size == u32Size {
+		*v = DynVec[T, P]{}
+		return nil
+	}
+
+	if size < 2*u32Size {
+		return fmt.Errorf("invalid dynvec, no first offset")
+	}
+
+	first := uint64(binary.LittleEndian.Uint32(data[u32Size:]))
+	if first%u32Size != 0 || first < 2*u32Size || first > size {
+		return fmt.Errorf("invalid dynvec, bad first offset %d", first)
+	}
+
+	n := first/u32Size - 1
+	items := make(DynVec[T, P], n)
+	for i := uint64(0); i < n; i++ {
+		start := uint64(binary.LittleEndian.Uint32(data[u32Size*(i+1):]))
+		end := size
+		if i+1 < n {
+			end = uint64(binary.LittleEndian.Uint32(data[u32Size*(i+2):]))
+		}
+
+		if start > end || end > size {
+			return fmt.Errorf("invalid dynvec, offsets are not in order")
+		}
+
+		err := P(&items[i]).Deserialize(data[start:end])
+		if err != nil {
+			return fmt.Errorf("invalid dynvec item %d, %s", i, err)
+		}
+	}
+
+	*v = items
+	return nil
+}
@@ -0,0 +1,136 @@
+//go:build go1.18
+// +build go1.18
+
+package mol
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+func TestFixVec(t *testing.T) {
+	outPoints := FixVec[types.OutPoint, *types.OutPoint]{
+		{TxHash: types.Hash{0x01}, Index: 0},
+		{TxHash: types.Hash{0x02}, Index: 7},
+	}
+
+	b, err := outPoints.Serialize()
+	if err != nil {
+		t.Errorf("fail to serialize fixvec: %s\n", err)
+		return
+	}
+
+	items := [][]byte{}
+	for i := range outPoints {
+		item, err := outPoints[i].Serialize()
+		if err != nil {
+			t.Errorf("fail to serialize out point: %s\n", err)
+			return
+		}
+		items = append(items, item)
+	}
+	expect := types.SerializeFixVec(items)
+	if !bytes.Equal(b, expect) {
+		t.Errorf("mismatch result, expect %x, got %x", expect, b)
+		return
+	}
+
+	var decoded FixVec[types.OutPoint, *types.OutPoint]
+	err = decoded.Deserialize(b)
+	if err != nil {
+		t.Errorf("fail to deserialize fixvec: %s\n", err)
+		return
+	}
+	if len(decoded) != 2 || decoded[1] != outPoints[1] {
+		t.Errorf("mismatch result, expect %v, got %v", outPoints, decoded)
+		return
+	}
+
+	err = decoded.Deserialize(b[:len(b)-1])
+	if err == nil {
+		t.Errorf("mismatch result, expect error for truncated fixvec")
+		return
+	}
+
+	var empty FixVec[types.Hash, *types.Hash]
+	b, err = empty.Serialize()
+	if err != nil || !bytes.Equal(b, []byte{0, 0, 0, 0}) {
+		t.Errorf("mismatch result, expect 00000000, got %x %v", b, err)
+		return
+	}
+	err = empty.Deserialize(b)
+	if err != nil || len(empty) != 0 {
+		t.Errorf("fail to deserialize empty fixvec: %v\n", err)
+		return
+	}
+}
+
+func TestFixVecMixedSize(t *testing.T) {
+	v := FixVec[types.Bytes, *types.Bytes]{{0x01}, {0x01, 0x02}}
+	_, err := v.Serialize()
+	if err == nil {
+		t.Errorf("mismatch result, expect error for items of different size")
+		return
+	}
+}
+
+func TestDynVec(t *testing.T) {
+	scripts := DynVec[types.Script, *types.Script]{
+		{CodeHash: types.Hash{0x01}, HashType: types.Type, Args: types.Bytes{0x01, 0x02}},
+		{CodeHash: types.Hash{0x02}, HashType: types.Data, Args: types.Bytes{}},
+	}
+
+	b, err := scripts.Serialize()
+	if err != nil {
+		t.Errorf("fail to serialize dynvec: %s\n", err)
+		return
+	}
+
+	items := [][]byte{}
+	for i := range scripts {
+		item, err := scripts[i].Serialize()
+		if err != nil {
+			t.Errorf("fail to serialize script: %s\n", err)
+			return
+		}
+		items = append(items, item)
+	}
+	expect := types.SerializeDynVec(items)
+	if !bytes.Equal(b, expect) {
+		t.Errorf("mismatch result, expect %x, got %x", expect, b)
+		return
+	}
+
+	var decoded DynVec[types.Script, *types.Script]
+	err = decoded.Deserialize(b)
+	if err != nil {
+		t.Errorf("fail to deserialize dynvec: %s\n", err)
+		return
+	}
+	if len(decoded) != 2 || decoded[0].CodeHash != scripts[0].CodeHash || !bytes.Equal(decoded[0].Args, scripts[0].Args) {
+		t.Errorf("mismatch result, expect %v, got %v", scripts, decoded)
+		return
+	}
+
+	bad := append([]byte{}, b...)
+	bad[0]++
+	err = decoded.Deserialize(bad)
+	if err == nil {
+		t.Errorf("mismatch result, expect error for bad dynvec size")
+		return
+	}
+
+	var empty DynVec[types.Bytes, *types.Bytes]
+	b, err = empty.Serialize()
+	if err != nil || !bytes.Equal(b, []byte{0x04, 0, 0, 0}) {
+		t.Errorf("mismatch result, expect 04000000, got %x %v", b, err)
+		return
+	}
+	err = empty.Deserialize(b)
+	if err != nil || len(empty) != 0 {
+		t.Errorf("fail to deserialize empty dynvec: %v\n", err)
+		return
+	}
+}
@@ -0,0 +1,587 @@
+// Package pb encodes ckb core types in protobuf wire format, as described
+// by ckb.proto, so they can pass through grpc services. Messages are wire
+// compatible with code generated from ckb.proto.
+package pb
+
+import (
+	"fmt"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// Hash sizes in bytes
+const (
+	hashSize  = 32
+	nonceSize = 16
+)
+
+func noUnknownFields(kind string, fs [][]byte) error {
+	if len(fs) != 0 {
+		return fmt.Errorf("invalid %s, unknown molecule fields can't be encoded", kind)
+	}
+
+	return nil
+}
+
+// decodeHash decode hash, empty is zero hash as proto3 omits it
+func decodeHash(b []byte) (types.Hash, error) {
+	var h types.Hash
+
+	if len(b) != 0 && len(b) != hashSize {
+		return h, fmt.Errorf("invalid hash, should be 32 bytes, got %d", len(b))
+	}
+
+	copy(h[:], b)
+	return h, nil
+}
+
+func decodeUint32(field int, v uint64) (types.Uint32, error) {
+	if v > 0xffffffff {
+		return 0, fmt.Errorf("invalid field %d, uint32 overflow", field)
+	}
+
+	return types.Uint32(v), nil
+}
+
+// MarshalScript encode script
+func MarshalScript(s *types.Script) ([]byte, error) {
+	err := noUnknownFields("script", s.UnknownFields)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := s.HashType.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	e := new(encoder)
+	e.bytes(1, s.CodeHash[:])
+	e.uint(2, uint64(t[0]))
+	e.bytes(3, s.Args)
+
+	return e.buf, nil
+}
+
+// UnmarshalScript decode script
+func UnmarshalScript(data []byte) (*types.Script, error) {
+	s := &types.Script{HashType: types.Data, Args: types.Bytes{}}
+
+	d := &decoder{data: data}
+	for {
+		field, v, b, ok, err := d.next()
+		if err != nil || !ok {
+			return s, err
+		}
+
+		switch field {
+		case 1:
+			s.CodeHash, err = decodeHash(b)
+		case 2:
+			if v > 0xff {
+				return nil, fmt.Errorf("invalid script hash type %d", v)
+			}
+			err = s.HashType.Deserialize([]byte{byte(v)})
+		case 3:
+			s.Args = append(types.Bytes{}, b...)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// MarshalOutPoint encode outpoint
+func MarshalOutPoint(o *types.OutPoint) []byte {
+	e := new(encoder)
+	e.bytes(1, o.TxHash[:])
+	e.uint(2, uint64(o.Index))
+
+	return e.buf
+}
+
+// UnmarshalOutPoint decode outpoint
+func UnmarshalOutPoint(data []byte) (*types.OutPoint, error) {
+	o := new(types.OutPoint)
+
+	d := &decoder{data: data}
+	for {
+		field, v, b, ok, err := d.next()
+		if err != nil || !ok {
+			return o, err
+		}
+
+		switch field {
+		case 1:
+			o.TxHash, err = decodeHash(b)
+		case 2:
+			o.Index, err = decodeUint32(field, v)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// MarshalCellInput encode cell input
+func MarshalCellInput(i *types.CellInput) []byte {
+	e := new(encoder)
+	e.uint(1, uint64(i.Since))
+	e.message(2, MarshalOutPoint(&i.PreviousOutput))
+
+	return e.buf
+}
+
+// UnmarshalCellInput decode cell input
+func UnmarshalCellInput(data []byte) (*types.CellInput, error) {
+	i := new(types.CellInput)
+
+	d := &decoder{data: data}
+	for {
+		field, v, b, ok, err := d.next()
+		if err != nil || !ok {
+			return i, err
+		}
+
+		switch field {
+		case 1:
+			i.Since = types.Uint64(v)
+		case 2:
+			var o *types.OutPoint
+			o, err = UnmarshalOutPoint(b)
+			if err == nil {
+				i.PreviousOutput = *o
+			}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// MarshalCellOutput encode cell output
+func MarshalCellOutput(o *types.CellOutput) ([]byte, error) {
+	err := noUnknownFields("cell output", o.UnknownFields)
+	if err != nil {
+		return nil, err
+	}
+
+	lock, err := MarshalScript(&o.Lock)
+	if err != nil {
+		return nil, err
+	}
+
+	e := new(encoder)
+	e.uint(1, uint64(o.Capacity))
+	e.message(2, lock)
+
+	if o.Type != nil {
+		t, err := MarshalScript(o.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		e.message(3, t)
+	}
+
+	return e.buf, nil
+}
+
+// UnmarshalCellOutput decode cell output
+func UnmarshalCellOutput(data []byte) (*types.CellOutput, error) {
+	o := &types.CellOutput{Lock: types.Script{HashType: types.Data, Args: types.Bytes{}}}
+
+	d := &decoder{data: data}
+	for {
+		field, v, b, ok, err := d.next()
+		if err != nil || !ok {
+			return o, err
+		}
+
+		switch field {
+		case 1:
+			o.Capacity = types.Uint64(v)
+		case 2:
+			var s *types.Script
+			s, err = UnmarshalScript(b)
+			if err == nil {
+				o.Lock = *s
+			}
+		case 3:
+			o.Type, err = UnmarshalScript(b)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// MarshalCellDep encode cell dep
+func MarshalCellDep(c *types.CellDep) ([]byte, error) {
+	t, err := c.DepType.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	e := new(encoder)
+	e.message(1, MarshalOutPoint(&c.OutPoint))
+	e.uint(2, uint64(t[0]))
+
+	return e.buf, nil
+}
+
+// UnmarshalCellDep decode cell dep
+func UnmarshalCellDep(data []byte) (*types.CellDep, error) {
+	c := &types.CellDep{DepType: types.Code}
+
+	d := &decoder{data: data}
+	for {
+		field, v, b, ok, err := d.next()
+		if err != nil || !ok {
+			return c, err
+		}
+
+		switch field {
+		case 1:
+			var o *types.OutPoint
+			o, err = UnmarshalOutPoint(b)
+			if err == nil {
+				c.OutPoint = *o
+			}
+		case 2:
+			if v > 0xff {
+				return nil, fmt.Errorf("invalid dep type %d", v)
+			}
+			err = c.DepType.Deserialize([]byte{byte(v)})
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// MarshalTransaction encode transaction with witnesses
+func MarshalTransaction(t *types.Transaction) ([]byte, error) {
+	err := noUnknownFields("transaction", t.UnknownFields)
+	if err != nil {
+		return nil, err
+	}
+
+	e := new(encoder)
+	e.uint(1, uint64(t.Version))
+
+	for i := range t.CellDeps {
+		c, err := MarshalCellDep(&t.CellDeps[i])
+		if err != nil {
+			return nil, err
+		}
+
+		e.message(2, c)
+	}
+
+	for i := range t.HeaderDeps {
+		e.bytesAlways(3, t.HeaderDeps[i][:])
+	}
+
+	for i := range t.Inputs {
+		e.message(4, MarshalCellInput(&t.Inputs[i]))
+	}
+
+	for i := range t.Outputs {
+		o, err := MarshalCellOutput(&t.Outputs[i])
+		if err != nil {
+			return nil, err
+		}
+
+		e.message(5, o)
+	}
+
+	for _, od := range t.OutputsData {
+		e.bytesAlways(6, od)
+	}
+
+	for _, w := range t.Witnesses {
+		e.bytesAlways(7, w)
+	}
+
+	return e.buf, nil
+}
+
+// UnmarshalTransaction decode transaction with witnesses
+func UnmarshalTransaction(data []byte) (*types.Transaction, error) {
+	t := &types.Transaction{
+		CellDeps:    []types.CellDep{},
+		HeaderDeps:  []types.Hash{},
+		Inputs:      []types.CellInput{},
+		Outputs:     []types.CellOutput{},
+		OutputsData: []types.Bytes{},
+		Witnesses:   []types.Bytes{},
+	}
+
+	d := &decoder{data: data}
+	for {
+		field, v, b, ok, err := d.next()
+		if err != nil || !ok {
+			return t, err
+		}
+
+		switch field {
+		case 1:
+			t.Version, err = decodeUint32(field, v)
+		case 2:
+			var c *types.CellDep
+			c, err = UnmarshalCellDep(b)
+			if err == nil {
+				t.CellDeps = append(t.CellDeps, *c)
+			}
+		case 3:
+			var h types.Hash
+			h, err = decodeHash(b)
+			t.HeaderDeps = append(t.HeaderDeps, h)
+		case 4:
+			var i *types.CellInput
+			i, err = UnmarshalCellInput(b)
+			if err == nil {
+				t.Inputs = append(t.Inputs, *i)
+			}
+		case 5:
+			var o *types.CellOutput
+			o, err = UnmarshalCellOutput(b)
+			if err == nil {
+				t.Outputs = append(t.Outputs, *o)
+			}
+		case 6:
+			t.OutputsData = append(t.OutputsData, append(types.Bytes{}, b...))
+		case 7:
+			t.Witnesses = append(t.Witnesses, append(types.Bytes{}, b...))
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// MarshalHeader encode header
+func MarshalHeader(h *types.Header) ([]byte, error) {
+	nonce, err := h.Nonce.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	e := new(encoder)
+	e.uint(1, uint64(h.Version))
+	e.uint(2, uint64(h.CompactTarget))
+	e.uint(3, uint64(h.Timestamp))
+	e.uint(4, uint64(h.Number))
+	e.uint(5, uint64(h.Epoch))
+	e.bytes(6, h.ParentHash[:])
+	e.bytes(7, h.TransactionsRoot[:])
+	e.bytes(8, h.ProposalsHash[:])
+	e.bytes(9, h.ExtraHash[:])
+	e.bytes(10, h.Dao[:])
+	e.bytes(11, nonce)
+
+	return e.buf, nil
+}
+
+// UnmarshalHeader decode header
+func UnmarshalHeader(data []byte) (*types.Header, error) {
+	h := new(types.Header)
+
+	d := &decoder{data: data}
+	for {
+		field, v, b, ok, err := d.next()
+		if err != nil || !ok {
+			return h, err
+		}
+
+		switch field {
+		case 1:
+			h.Version, err = decodeUint32(field, v)
+		case 2:
+			h.CompactTarget, err = decodeUint32(field, v)
+		case 3:
+			h.Timestamp = types.Uint64(v)
+		case 4:
+			h.Number = types.Uint64(v)
+		case 5:
+			h.Epoch = types.Uint64(v)
+		case 6:
+			h.ParentHash, err = decodeHash(b)
+		case 7:
+			h.TransactionsRoot, err = decodeHash(b)
+		case 8:
+			h.ProposalsHash, err = decodeHash(b)
+		case 9:
+			h.ExtraHash, err = decodeHash(b)
+		case 10:
+			h.Dao, err = decodeHash(b)
+		case 11:
+			if len(b) != nonceSize {
+				return nil, fmt.Errorf("invalid nonce, should be 16 bytes, got %d", len(b))
+			}
+			err = h.Nonce.Deserialize(b)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func decodeProposal(b []byte) (types.ProposalShortID, error) {
+	var p types.ProposalShortID
+
+	err := p.Deserialize(b)
+	return p, err
+}
+
+// MarshalUncleBlock encode uncle block
+func MarshalUncleBlock(u *types.UncleBlock) ([]byte, error) {
+	err := noUnknownFields("uncle block", u.UnknownFields)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := MarshalHeader(&u.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	e := new(encoder)
+	e.message(1, h)
+	for i := range u.Proposals {
+		e.bytesAlways(2, u.Proposals[i][:])
+	}
+
+	return e.buf, nil
+}
+
+// UnmarshalUncleBlock decode uncle block
+func UnmarshalUncleBlock(data []byte) (*types.UncleBlock, error) {
+	u := &types.UncleBlock{Proposals: []types.ProposalShortID{}}
+
+	d := &decoder{data: data}
+	for {
+		field, _, b, ok, err := d.next()
+		if err != nil || !ok {
+			return u, err
+		}
+
+		switch field {
+		case 1:
+			var h *types.Header
+			h, err = UnmarshalHeader(b)
+			if err == nil {
+				u.Header = *h
+			}
+		case 2:
+			var p types.ProposalShortID
+			p, err = decodeProposal(b)
+			u.Proposals = append(u.Proposals, p)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// MarshalBlock encode block
+func MarshalBlock(b *types.Block) ([]byte, error) {
+	err := noUnknownFields("block", b.UnknownFields)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := MarshalHeader(&b.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	e := new(encoder)
+	e.message(1, h)
+
+	for i := range b.Uncles {
+		u, err := MarshalUncleBlock(&b.Uncles[i])
+		if err != nil {
+			return nil, err
+		}
+
+		e.message(2, u)
+	}
+
+	for i := range b.Transactions {
+		t, err := MarshalTransaction(&b.Transactions[i])
+		if err != nil {
+			return nil, err
+		}
+
+		e.message(3, t)
+	}
+
+	for i := range b.Proposals {
+		e.bytesAlways(4, b.Proposals[i][:])
+	}
+
+	if b.Extension != nil {
+		e.bytesAlways(5, *b.Extension)
+	}
+
+	return e.buf, nil
+}
+
+// UnmarshalBlock decode block
+func UnmarshalBlock(data []byte) (*types.Block, error) {
+	blk := &types.Block{
+		Uncles:       []types.UncleBlock{},
+		Transactions: []types.Transaction{},
+		Proposals:    []types.ProposalShortID{},
+	}
+
+	d := &decoder{data: data}
+	for {
+		field, _, b, ok, err := d.next()
+		if err != nil || !ok {
+			return blk, err
+		}
+
+		switch field {
+		case 1:
+			var h *types.Header
+			h, err = UnmarshalHeader(b)
+			if err == nil {
+				blk.Header = *h
+			}
+		case 2:
+			var u *types.UncleBlock
+			u, err = UnmarshalUncleBlock(b)
+			if err == nil {
+				blk.Uncles = append(blk.Uncles, *u)
+			}
+		case 3:
+			var t *types.Transaction
+			t, err = UnmarshalTransaction(b)
+			if err == nil {
+				blk.Transactions = append(blk.Transactions, *t)
+			}
+		case 4:
+			var p types.ProposalShortID
+			p, err = decodeProposal(b)
+			blk.Proposals = append(blk.Proposals, p)
+		case 5:
+			e := append(types.Bytes{}, b...)
+			blk.Extension = &e
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package pb
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+func TestScript(t *testing.T) {
+	s := &types.Script{CodeHash: types.SecpSighashAllCodeHash, HashType: types.Type, Args: types.Bytes{0xab}}
+
+	got, err := MarshalScript(s)
+	if err != nil {
+		t.Errorf("fail to marshal script: %s\n", err)
+		return
+	}
+
+	// code_hash, hash_type 1 and args, in field order
+	expect := "0a20" + hex.EncodeToString(s.CodeHash[:]) + "1001" + "1a01ab"
+	if hex.EncodeToString(got) != expect {
+		t.Errorf("mismatch result, expect %v, got %x", expect, got)
+		return
+	}
+
+	// Unknown fields are skipped
+	got = append(got, 0x20, 0x01, 0x2a, 0x00)
+	back, err := UnmarshalScript(got)
+	if err != nil {
+		t.Errorf("fail to unmarshal script: %s\n", err)
+		return
+	}
+
+	if !back.Equal(s) {
+		t.Errorf("mismatch result, expect %v, got %v", s, back)
+		return
+	}
+
+	// Zero values are omitted
+	back, err = UnmarshalScript([]byte{})
+	if err != nil || back.HashType != types.Data || back.CodeHash != (types.Hash{}) {
+		t.Errorf("mismatch result, expect zero script, got %v %v", back, err)
+		return
+	}
+
+	_, err = UnmarshalScript([]byte{0x0a, 0x02, 0x01})
+	if err == nil {
+		t.Errorf("should reject truncated field")
+		return
+	}
+}
+
+func TestBlock(t *testing.T) {
+	lock := types.Script{CodeHash: types.SecpSighashAllCodeHash, HashType: types.Type, Args: make(types.Bytes, 20)}
+	ext := types.Bytes{}
+	b := &types.Block{
+		Header: types.Header{Number: 1, Nonce: types.Uint128{Hi: 1, Lo: 2}, Dao: types.Hash{3}},
+		Uncles: []types.UncleBlock{{Proposals: []types.ProposalShortID{{4}}}},
+		Transactions: []types.Transaction{{
+			CellDeps:    []types.CellDep{{OutPoint: types.OutPoint{TxHash: types.Hash{5}}, DepType: types.DepGroup}},
+			HeaderDeps:  []types.Hash{{}},
+			Inputs:      []types.CellInput{{Since: 6, PreviousOutput: types.OutPoint{Index: 7}}},
+			Outputs:     []types.CellOutput{{Capacity: 8, Lock: lock, Type: &lock}},
+			OutputsData: []types.Bytes{{}},
+			Witnesses:   []types.Bytes{{9}, {}},
+		}},
+		Proposals: []types.ProposalShortID{{10}},
+		Extension: &ext,
+	}
+
+	raw, err := MarshalBlock(b)
+	if err != nil {
+		t.Errorf("fail to marshal block: %s\n", err)
+		return
+	}
+
+	back, err := UnmarshalBlock(raw)
+	if err != nil {
+		t.Errorf("fail to unmarshal block: %s\n", err)
+		return
+	}
+
+	expect, err := b.Serialize()
+	if err != nil {
+		t.Errorf("fail to serialize: %s\n", err)
+		return
+	}
+
+	got, err := back.Serialize()
+	if err != nil {
+		t.Errorf("fail to serialize: %s\n", err)
+		return
+	}
+
+	if !bytes.Equal(got, expect) {
+		t.Errorf("mismatch result, expect %x, got %x", expect, got)
+		return
+	}
+}
@@ -0,0 +1,127 @@
+package pb
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// encoder protobuf wire encoder, zero scalars and empty bytes are skipped
+// like proto3 generated code does
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) key(field int, wire int) {
+	e.buf = appendUvarint(e.buf, uint64(field)<<3|uint64(wire))
+}
+
+func (e *encoder) uint(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+
+	e.key(field, wireVarint)
+	e.buf = appendUvarint(e.buf, v)
+}
+
+// bytesAlways write bytes even if empty, for repeated and optional fields
+func (e *encoder) bytesAlways(field int, b []byte) {
+	e.key(field, wireBytes)
+	e.buf = appendUvarint(e.buf, uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+func (e *encoder) bytes(field int, b []byte) {
+	if len(b) == 0 {
+		return
+	}
+
+	e.bytesAlways(field, b)
+}
+
+func (e *encoder) message(field int, m []byte) {
+	e.bytesAlways(field, m)
+}
+
+// decoder protobuf wire decoder, unknown fields are skipped
+type decoder struct {
+	data []byte
+}
+
+// next read next field, its value is varint or length delimited bytes,
+// ok is false at end of data
+func (d *decoder) next() (field int, v uint64, b []byte, ok bool, err error) {
+	if len(d.data) == 0 {
+		return 0, 0, nil, false, nil
+	}
+
+	key, err := d.uvarint()
+	if err != nil {
+		return 0, 0, nil, false, err
+	}
+
+	field = int(key >> 3)
+	if field == 0 {
+		return 0, 0, nil, false, fmt.Errorf("invalid protobuf, field number 0")
+	}
+
+	switch key & 7 {
+	case wireVarint:
+		v, err = d.uvarint()
+	case wireBytes:
+		var n uint64
+		n, err = d.uvarint()
+		if err == nil && n > uint64(len(d.data)) {
+			err = fmt.Errorf("invalid protobuf, field %d length %d exceeds %d bytes", field, n, len(d.data))
+		}
+		if err == nil {
+			b, d.data = d.data[:n], d.data[n:]
+		}
+	case wireFixed64:
+		err = d.skip(8)
+	case wireFixed32:
+		err = d.skip(4)
+	default:
+		err = fmt.Errorf("invalid protobuf, unsupported wire type %d", key&7)
+	}
+
+	if err != nil {
+		return 0, 0, nil, false, err
+	}
+
+	return field, v, b, true, nil
+}
+
+func (d *decoder) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		return 0, fmt.Errorf("invalid protobuf, bad varint")
+	}
+
+	d.data = d.data[n:]
+	return v, nil
+}
+
+func (d *decoder) skip(n int) error {
+	if len(d.data) < n {
+		return fmt.Errorf("invalid protobuf, truncated")
+	}
+
+	d.data = d.data[n:]
+	return nil
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+
+	return append(buf, b[:n]...)
+}
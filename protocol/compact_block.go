@@ -0,0 +1,210 @@
+package protocol
+
+import (
+	"fmt"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// IndexTransaction transaction with its index in block
+type IndexTransaction struct {
+	Index       types.Uint32
+	Transaction types.Transaction
+}
+
+// CompactBlock ckb relay compact block
+/*
+ * Transactions are sent as short ids, except prefilled ones which the
+ * receiver is unlikely to have, like cellbase. It is encoded as
+ * CompactBlockV1 if it has extension.
+ */
+type CompactBlock struct {
+	Header                types.Header
+	ShortIDs              []types.ProposalShortID
+	PrefilledTransactions []IndexTransaction
+	Uncles                []types.Hash
+	Proposals             []types.ProposalShortID
+	Extension             *types.Bytes
+}
+
+// ShortID transaction short id, first 10 bytes of transaction hash
+func ShortID(txHash types.Hash) types.ProposalShortID {
+	return types.ProposalShortIDFromTransactionHash(txHash)
+}
+
+// NewCompactBlock build compact block from block, cellbase is always
+// prefilled, other prefilled transactions are given by index
+func NewCompactBlock(b *types.Block, prefilled []int) (*CompactBlock, error) {
+	if len(b.Transactions) == 0 {
+		return nil, fmt.Errorf("invalid block, no cellbase")
+	}
+
+	// Cellbase
+	isPrefilled := map[int]bool{0: true}
+	for _, i := range prefilled {
+		if i < 0 || i >= len(b.Transactions) {
+			return nil, fmt.Errorf("invalid prefilled index %d", i)
+		}
+
+		isPrefilled[i] = true
+	}
+
+	c := &CompactBlock{
+		Header:                b.Header,
+		ShortIDs:              []types.ProposalShortID{},
+		PrefilledTransactions: []IndexTransaction{},
+		Uncles:                make([]types.Hash, len(b.Uncles)),
+		Proposals:             b.Proposals,
+		Extension:             b.Extension,
+	}
+
+	for i := 0; i < len(b.Transactions); i++ {
+		if isPrefilled[i] {
+			c.PrefilledTransactions = append(c.PrefilledTransactions, IndexTransaction{
+				Index:       types.Uint32(i),
+				Transaction: b.Transactions[i],
+			})
+			continue
+		}
+
+		h, err := b.Transactions[i].ComputeHash()
+		if err != nil {
+			return nil, err
+		}
+
+		c.ShortIDs = append(c.ShortIDs, ShortID(h))
+	}
+
+	for i := 0; i < len(b.Uncles); i++ {
+		h, err := b.Uncles[i].Header.ComputeHash()
+		if err != nil {
+			return nil, err
+		}
+
+		c.Uncles[i] = h
+	}
+
+	return c, nil
+}
+
+// Serialize index transaction
+func (t *IndexTransaction) Serialize() ([]byte, error) {
+	i, err := t.Index.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := t.Transaction.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	return types.SerializeTable([][]byte{i, tx}), nil
+}
+
+// Deserialize index transaction
+func (t *IndexTransaction) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 2)
+	if err != nil {
+		return err
+	}
+
+	err = t.Index.Deserialize(fields[0])
+	if err != nil {
+		return err
+	}
+
+	return t.Transaction.Unpack(fields[1])
+}
+
+// Serialize compact block
+func (c *CompactBlock) Serialize() ([]byte, error) {
+	h, err := c.Header.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	sids := types.SerializeProposalShortIDVec(c.ShortIDs)
+
+	pts := make([][]byte, len(c.PrefilledTransactions))
+	for i := 0; i < len(c.PrefilledTransactions); i++ {
+		pt, err := c.PrefilledTransactions[i].Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		pts[i] = pt
+	}
+
+	us := types.SerializeByte32Vec(c.Uncles)
+
+	ps := types.SerializeProposalShortIDVec(c.Proposals)
+
+	fields := [][]byte{h, sids, types.SerializeDynVec(pts), us, ps}
+	if c.Extension != nil {
+		e, err := c.Extension.Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, e)
+	}
+
+	return types.SerializeTable(fields), nil
+}
+
+// Deserialize compact block, accept both CompactBlock and CompactBlockV1
+func (c *CompactBlock) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 5)
+	if err != nil {
+		fields, err = types.DeserializeTable(data, 6)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = c.Header.Deserialize(fields[0])
+	if err != nil {
+		return err
+	}
+
+	c.ShortIDs, err = types.DeserializeProposalShortIDVec(fields[1])
+	if err != nil {
+		return err
+	}
+
+	pts, err := types.DeserializeDynVec(fields[2])
+	if err != nil {
+		return err
+	}
+	c.PrefilledTransactions = make([]IndexTransaction, len(pts))
+	for i := 0; i < len(pts); i++ {
+		err = c.PrefilledTransactions[i].Deserialize(pts[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	c.Uncles, err = types.DeserializeByte32Vec(fields[3])
+	if err != nil {
+		return err
+	}
+
+	c.Proposals, err = types.DeserializeProposalShortIDVec(fields[4])
+	if err != nil {
+		return err
+	}
+
+	c.Extension = nil
+	if len(fields) == 6 {
+		e := new(types.Bytes)
+		err = e.Deserialize(fields[5])
+		if err != nil {
+			return err
+		}
+
+		c.Extension = e
+	}
+
+	return nil
+}
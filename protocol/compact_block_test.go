@@ -0,0 +1,119 @@
+package protocol
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+const testBlock = `{
+  "header": {
+    "compact_target": "0x1e083126",
+    "dao": "0xb5a3e047474401001bc476b9ee573000c0c387962a38000000febffacf030000",
+    "epoch": "0x7080018000001",
+    "extra_hash": "0x0000000000000000000000000000000000000000000000000000000000000000",
+    "nonce": "0x0",
+    "number": "0x400",
+    "parent_hash": "0xae003585fa15309b30b31aed3dcf385e9472c3c3e93746a6c4540629a6a1ed2d",
+    "proposals_hash": "0x0000000000000000000000000000000000000000000000000000000000000000",
+    "timestamp": "0x5cd2b117",
+    "transactions_root": "0xc47d5b78b3c4c4c853e2a32810818940d0ee403423bea9ec7b8e566d9595206c",
+    "version": "0x0"
+  },
+  "proposals": ["0x365698b50ca0da75dca2"],
+  "transactions": [
+    {
+      "cell_deps": [],
+      "header_deps": [],
+      "inputs": [
+        {
+          "previous_output": {
+            "index": "0xffffffff",
+            "tx_hash": "0x0000000000000000000000000000000000000000000000000000000000000000"
+          },
+          "since": "0x400"
+        }
+      ],
+      "outputs": [
+        {
+          "capacity": "0x18e64b61cf",
+          "lock": {
+            "code_hash": "0x28e83a1277d48add8e72fadaa9248559e1b632bab2bd60b27955ebc4c03800a5",
+            "hash_type": "data",
+            "args": "0x"
+          },
+          "type": null
+        }
+      ],
+      "outputs_data": [
+        "0x"
+      ],
+      "version": "0x0",
+      "witnesses": [
+        "0x450000000c000000410000003500000010000000300000003100000028e83a1277d48add8e72fadaa9248559e1b632bab2bd60b27955ebc4c03800a5000000000000"
+      ]
+    }
+  ],
+  "uncles": []
+}`
+
+func TestCompactBlock(t *testing.T) {
+	var b types.Block
+
+	err := json.Unmarshal([]byte(testBlock), &b)
+	if err != nil {
+		t.Errorf("fail to unmarshal test block json: %s\n", err)
+		return
+	}
+
+	// Add a second transaction so that it is sent as short id
+	tx := b.Transactions[0].Clone()
+	tx.Inputs[0].Since = 0x401
+	b.Transactions = append(b.Transactions, *tx)
+
+	c, err := NewCompactBlock(&b, nil)
+	if err != nil {
+		t.Errorf("fail to build compact block: %s\n", err)
+		return
+	}
+
+	h, _ := tx.ComputeHash()
+	if len(c.ShortIDs) != 1 || c.ShortIDs[0] != ShortID(h) || len(c.PrefilledTransactions) != 1 {
+		t.Errorf("mismatch compact block transactions, got %+v", c)
+		return
+	}
+
+	// Cellbase short id from recorded block hash
+	cellbaseHash, _ := b.Transactions[0].ComputeHash()
+	if ShortID(cellbaseHash).String() != "0x365698b50ca0da75dca2" {
+		t.Errorf("mismatch short id, got %v", ShortID(cellbaseHash))
+		return
+	}
+
+	data, err := c.Serialize()
+	if err != nil {
+		t.Errorf("fail to serialize: %s\n", err)
+		return
+	}
+
+	var got CompactBlock
+
+	err = got.Deserialize(data)
+	if err != nil {
+		t.Errorf("fail to deserialize: %s\n", err)
+		return
+	}
+
+	if got.Header != c.Header || got.ShortIDs[0] != c.ShortIDs[0] || got.Proposals[0] != c.Proposals[0] ||
+		!got.PrefilledTransactions[0].Transaction.Equal(&b.Transactions[0]) {
+		t.Errorf("mismatch compact block, expect %+v, got %+v", c, got)
+		return
+	}
+
+	_, err = NewCompactBlock(&b, []int{2})
+	if err == nil {
+		t.Errorf("expect error on out of range prefilled index")
+		return
+	}
+}
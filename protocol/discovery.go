@@ -0,0 +1,376 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// Discovery payload union item ids
+const (
+	GetNodesID  uint32 = 0
+	NodesID     uint32 = 1
+	GetNodes2ID uint32 = 2
+	Nodes2ID    uint32 = 3
+)
+
+// DiscoveryPayload item of ckb discovery protocol payload union
+type DiscoveryPayload interface {
+	types.MolSerializer
+	types.MolDeserializer
+	DiscoveryItemID() uint32
+}
+
+// GetNodes ckb discovery message, request known nodes
+type GetNodes struct {
+	Version    types.Uint32
+	Count      types.Uint32
+	ListenPort *uint16
+}
+
+// GetNodes2 ckb discovery message, request known nodes with flags
+type GetNodes2 struct {
+	Version       types.Uint32
+	Count         types.Uint32
+	ListenPort    *uint16
+	RequiredFlags types.Uint64
+}
+
+// Node ckb discovery node, addresses are binary multiaddrs
+type Node struct {
+	Addresses []types.Bytes
+}
+
+// Node2 ckb discovery node with flags
+type Node2 struct {
+	Addresses []types.Bytes
+	Flags     types.Uint64
+}
+
+// Nodes ckb discovery message, response to GetNodes or announcement
+type Nodes struct {
+	Announce bool
+	Items    []Node
+}
+
+// Nodes2 ckb discovery message, response to GetNodes2 or announcement
+type Nodes2 struct {
+	Announce bool
+	Items    []Node2
+}
+
+// DiscoveryItemID union item id
+func (m *GetNodes) DiscoveryItemID() uint32 { return GetNodesID }
+
+// DiscoveryItemID union item id
+func (m *Nodes) DiscoveryItemID() uint32 { return NodesID }
+
+// DiscoveryItemID union item id
+func (m *GetNodes2) DiscoveryItemID() uint32 { return GetNodes2ID }
+
+// DiscoveryItemID union item id
+func (m *Nodes2) DiscoveryItemID() uint32 { return Nodes2ID }
+
+// SerializeDiscoveryMessage serialize payload into DiscoveryMessage table
+func SerializeDiscoveryMessage(item DiscoveryPayload) ([]byte, error) {
+	b, err := item.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return types.SerializeTable([][]byte{types.SerializeUnion(item.DiscoveryItemID(), b)}), nil
+}
+
+// DeserializeDiscoveryMessage deserialize DiscoveryMessage table into its
+// payload
+func DeserializeDiscoveryMessage(data []byte) (DiscoveryPayload, error) {
+	fields, err := types.DeserializeTable(data, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	id, inner, err := types.DeserializeUnion(fields[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var item DiscoveryPayload
+	switch id {
+	case GetNodesID:
+		item = new(GetNodes)
+	case NodesID:
+		item = new(Nodes)
+	case GetNodes2ID:
+		item = new(GetNodes2)
+	case Nodes2ID:
+		item = new(Nodes2)
+	default:
+		return nil, fmt.Errorf("unknown discovery payload item id %d", id)
+	}
+
+	err = item.Deserialize(inner)
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func serializePortOpt(p *uint16) []byte {
+	if p == nil {
+		return []byte{}
+	}
+
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, *p)
+
+	return b
+}
+
+func deserializePortOpt(data []byte) (*uint16, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	if len(data) != 2 {
+		return nil, fmt.Errorf("invalid port, should be 2 bytes")
+	}
+
+	p := binary.LittleEndian.Uint16(data)
+	return &p, nil
+}
+
+func serializeBool(b bool) []byte {
+	if b {
+		return []byte{1}
+	}
+
+	return []byte{0}
+}
+
+func deserializeBool(data []byte) (bool, error) {
+	if len(data) != 1 || data[0] > 1 {
+		return false, fmt.Errorf("invalid bool, should be 1 byte of 0 or 1")
+	}
+
+	return data[0] == 1, nil
+}
+
+// Serialize get nodes
+func (m *GetNodes) Serialize() ([]byte, error) {
+	v, err := m.Version.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := m.Count.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return types.SerializeTable([][]byte{v, c, serializePortOpt(m.ListenPort)}), nil
+}
+
+// Deserialize get nodes
+func (m *GetNodes) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 3)
+	if err != nil {
+		return err
+	}
+
+	err = m.Version.Deserialize(fields[0])
+	if err != nil {
+		return err
+	}
+
+	err = m.Count.Deserialize(fields[1])
+	if err != nil {
+		return err
+	}
+
+	m.ListenPort, err = deserializePortOpt(fields[2])
+	return err
+}
+
+// Serialize get nodes2
+func (m *GetNodes2) Serialize() ([]byte, error) {
+	v, err := m.Version.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := m.Count.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := m.RequiredFlags.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return types.SerializeTable([][]byte{v, c, serializePortOpt(m.ListenPort), f}), nil
+}
+
+// Deserialize get nodes2
+func (m *GetNodes2) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 4)
+	if err != nil {
+		return err
+	}
+
+	err = m.Version.Deserialize(fields[0])
+	if err != nil {
+		return err
+	}
+
+	err = m.Count.Deserialize(fields[1])
+	if err != nil {
+		return err
+	}
+
+	m.ListenPort, err = deserializePortOpt(fields[2])
+	if err != nil {
+		return err
+	}
+
+	return m.RequiredFlags.Deserialize(fields[3])
+}
+
+// Serialize node
+func (n *Node) Serialize() ([]byte, error) {
+	as, err := serializeBytesVec(n.Addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.SerializeTable([][]byte{as}), nil
+}
+
+// Deserialize node
+func (n *Node) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 1)
+	if err != nil {
+		return err
+	}
+
+	n.Addresses, err = deserializeBytesVec(fields[0])
+	return err
+}
+
+// Serialize node2
+func (n *Node2) Serialize() ([]byte, error) {
+	as, err := serializeBytesVec(n.Addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := n.Flags.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return types.SerializeTable([][]byte{as, f}), nil
+}
+
+// Deserialize node2
+func (n *Node2) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 2)
+	if err != nil {
+		return err
+	}
+
+	n.Addresses, err = deserializeBytesVec(fields[0])
+	if err != nil {
+		return err
+	}
+
+	return n.Flags.Deserialize(fields[1])
+}
+
+// Serialize nodes
+func (m *Nodes) Serialize() ([]byte, error) {
+	items := make([][]byte, len(m.Items))
+	for i := 0; i < len(m.Items); i++ {
+		n, err := m.Items[i].Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		items[i] = n
+	}
+
+	return types.SerializeTable([][]byte{serializeBool(m.Announce), types.SerializeDynVec(items)}), nil
+}
+
+// Deserialize nodes
+func (m *Nodes) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 2)
+	if err != nil {
+		return err
+	}
+
+	m.Announce, err = deserializeBool(fields[0])
+	if err != nil {
+		return err
+	}
+
+	items, err := types.DeserializeDynVec(fields[1])
+	if err != nil {
+		return err
+	}
+
+	m.Items = make([]Node, len(items))
+	for i := 0; i < len(items); i++ {
+		err = m.Items[i].Deserialize(items[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Serialize nodes2
+func (m *Nodes2) Serialize() ([]byte, error) {
+	items := make([][]byte, len(m.Items))
+	for i := 0; i < len(m.Items); i++ {
+		n, err := m.Items[i].Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		items[i] = n
+	}
+
+	return types.SerializeTable([][]byte{serializeBool(m.Announce), types.SerializeDynVec(items)}), nil
+}
+
+// Deserialize nodes2
+func (m *Nodes2) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 2)
+	if err != nil {
+		return err
+	}
+
+	m.Announce, err = deserializeBool(fields[0])
+	if err != nil {
+		return err
+	}
+
+	items, err := types.DeserializeDynVec(fields[1])
+	if err != nil {
+		return err
+	}
+
+	m.Items = make([]Node2, len(items))
+	for i := 0; i < len(items); i++ {
+		err = m.Items[i].Deserialize(items[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
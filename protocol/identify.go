@@ -0,0 +1,146 @@
+package protocol
+
+import (
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// IdentifyMessage tentacle identify protocol message, addresses are
+// binary multiaddrs
+type IdentifyMessage struct {
+	ListenAddrs  []types.Bytes
+	ObservedAddr types.Bytes
+	Identify     types.Bytes
+}
+
+// Identify ckb identify payload, carried in IdentifyMessage.Identify
+type Identify struct {
+	Flag          types.Uint64
+	Name          string
+	ClientVersion string
+}
+
+func serializeAddress(a types.Bytes) ([]byte, error) {
+	b, err := a.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return types.SerializeTable([][]byte{b}), nil
+}
+
+func deserializeAddress(data []byte) (types.Bytes, error) {
+	fields, err := types.DeserializeTable(data, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	var a types.Bytes
+	err = a.Deserialize(fields[0])
+
+	return a, err
+}
+
+// Serialize identify message
+func (m *IdentifyMessage) Serialize() ([]byte, error) {
+	ls := make([][]byte, len(m.ListenAddrs))
+	for i := 0; i < len(m.ListenAddrs); i++ {
+		a, err := serializeAddress(m.ListenAddrs[i])
+		if err != nil {
+			return nil, err
+		}
+
+		ls[i] = a
+	}
+
+	o, err := serializeAddress(m.ObservedAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := m.Identify.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return types.SerializeTable([][]byte{types.SerializeDynVec(ls), o, id}), nil
+}
+
+// Deserialize identify message
+func (m *IdentifyMessage) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 3)
+	if err != nil {
+		return err
+	}
+
+	ls, err := types.DeserializeDynVec(fields[0])
+	if err != nil {
+		return err
+	}
+
+	m.ListenAddrs = make([]types.Bytes, len(ls))
+	for i := 0; i < len(ls); i++ {
+		m.ListenAddrs[i], err = deserializeAddress(ls[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	m.ObservedAddr, err = deserializeAddress(fields[1])
+	if err != nil {
+		return err
+	}
+
+	return m.Identify.Deserialize(fields[2])
+}
+
+// Serialize identify
+func (i *Identify) Serialize() ([]byte, error) {
+	f, err := i.Flag.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	name := types.Bytes(i.Name)
+	n, err := name.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	version := types.Bytes(i.ClientVersion)
+	v, err := version.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return types.SerializeTable([][]byte{f, n, v}), nil
+}
+
+// Deserialize identify
+func (i *Identify) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 3)
+	if err != nil {
+		return err
+	}
+
+	err = i.Flag.Deserialize(fields[0])
+	if err != nil {
+		return err
+	}
+
+	var name, version types.Bytes
+
+	err = name.Deserialize(fields[1])
+	if err != nil {
+		return err
+	}
+
+	err = version.Deserialize(fields[2])
+	if err != nil {
+		return err
+	}
+
+	i.Name = string(name)
+	i.ClientVersion = string(version)
+
+	return nil
+}
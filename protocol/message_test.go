@@ -0,0 +1,170 @@
+package protocol
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+func TestSyncMessage(t *testing.T) {
+	got, err := SerializeSyncMessage(&InIBD{})
+	if err != nil {
+		t.Errorf("fail to serialize: %s\n", err)
+		return
+	}
+
+	expectHex := "0800000004000000"
+	gotHex := hex.EncodeToString(got)
+
+	if gotHex != expectHex {
+		t.Errorf("mismatch result, expect %v, got %v", expectHex, gotHex)
+		return
+	}
+
+	locator, _ := types.ParseHash("0xa5f5c85987a15de25661e5a214f2c1449cd803f071acc7999820f25246471f40")
+	msg := &GetHeaders{
+		BlockLocatorHashes: []types.Hash{locator},
+	}
+
+	data, err := SerializeSyncMessage(msg)
+	if err != nil {
+		t.Errorf("fail to serialize: %s\n", err)
+		return
+	}
+
+	item, err := DeserializeSyncMessage(data)
+	if err != nil {
+		t.Errorf("fail to deserialize: %s\n", err)
+		return
+	}
+
+	back, ok := item.(*GetHeaders)
+	if !ok || len(back.BlockLocatorHashes) != 1 || back.BlockLocatorHashes[0] != locator {
+		t.Errorf("mismatch get headers, expect %+v, got %+v", msg, item)
+		return
+	}
+
+	_, err = DeserializeSyncMessage([]byte{0x05, 0x00, 0x00, 0x00, 0x04, 0x00, 0x00, 0x00})
+	if err == nil {
+		t.Errorf("expect error on unknown item id")
+		return
+	}
+}
+
+func TestRelayMessage(t *testing.T) {
+	h, _ := types.ParseHash("0x365698b50ca0da75dca2c87f9e7b563811d3b5813736b8cc62cc3b106faceb17")
+
+	msgs := []RelayMessageItem{
+		&RelayTransactionHashes{TxHashes: []types.Hash{h}},
+		&GetBlockTransactions{BlockHash: h, Indexes: []types.Uint32{1, 2}, UncleIndexes: []types.Uint32{}},
+		&GetBlockProposal{BlockHash: h, Proposals: []types.ProposalShortID{ShortID(h)}},
+	}
+
+	for _, msg := range msgs {
+		data, err := SerializeRelayMessage(msg)
+		if err != nil {
+			t.Errorf("fail to serialize: %s\n", err)
+			return
+		}
+
+		item, err := DeserializeRelayMessage(data)
+		if err != nil {
+			t.Errorf("fail to deserialize: %s\n", err)
+			return
+		}
+
+		if item.RelayItemID() != msg.RelayItemID() {
+			t.Errorf("mismatch item id, expect %v, got %v", msg.RelayItemID(), item.RelayItemID())
+			return
+		}
+
+		again, _ := item.Serialize()
+		expect, _ := msg.Serialize()
+		if hex.EncodeToString(again) != hex.EncodeToString(expect) {
+			t.Errorf("mismatch round trip, expect %x, got %x", expect, again)
+			return
+		}
+	}
+}
+
+func TestDiscoveryMessage(t *testing.T) {
+	port := uint16(8115)
+	msg := &GetNodes{Version: 0, Count: 1000, ListenPort: &port}
+
+	data, err := SerializeDiscoveryMessage(msg)
+	if err != nil {
+		t.Errorf("fail to serialize: %s\n", err)
+		return
+	}
+
+	item, err := DeserializeDiscoveryMessage(data)
+	if err != nil {
+		t.Errorf("fail to deserialize: %s\n", err)
+		return
+	}
+
+	back, ok := item.(*GetNodes)
+	if !ok || back.Count != 1000 || back.ListenPort == nil || *back.ListenPort != port {
+		t.Errorf("mismatch get nodes, expect %+v, got %+v", msg, item)
+		return
+	}
+
+	nodes := &Nodes{Announce: true, Items: []Node{{Addresses: []types.Bytes{{0x04, 0x7f, 0x00, 0x00, 0x01}}}}}
+
+	data, err = SerializeDiscoveryMessage(nodes)
+	if err != nil {
+		t.Errorf("fail to serialize: %s\n", err)
+		return
+	}
+
+	item, err = DeserializeDiscoveryMessage(data)
+	if err != nil {
+		t.Errorf("fail to deserialize: %s\n", err)
+		return
+	}
+
+	backNodes, ok := item.(*Nodes)
+	if !ok || !backNodes.Announce || !backNodes.Items[0].Addresses[0].Equal(nodes.Items[0].Addresses[0]) {
+		t.Errorf("mismatch nodes, expect %+v, got %+v", nodes, item)
+		return
+	}
+}
+
+func TestIdentifyMessage(t *testing.T) {
+	identify := &Identify{Flag: 1, Name: "/ckb/92b197aa", ClientVersion: "0.111.0"}
+
+	payload, err := identify.Serialize()
+	if err != nil {
+		t.Errorf("fail to serialize: %s\n", err)
+		return
+	}
+
+	msg := &IdentifyMessage{
+		ListenAddrs:  []types.Bytes{{0x04, 0x7f, 0x00, 0x00, 0x01}},
+		ObservedAddr: types.Bytes{0x04, 0x0a, 0x00, 0x00, 0x01},
+		Identify:     payload,
+	}
+
+	data, err := msg.Serialize()
+	if err != nil {
+		t.Errorf("fail to serialize: %s\n", err)
+		return
+	}
+
+	var back IdentifyMessage
+
+	err = back.Deserialize(data)
+	if err != nil {
+		t.Errorf("fail to deserialize: %s\n", err)
+		return
+	}
+
+	var backIdentify Identify
+
+	err = backIdentify.Deserialize(back.Identify)
+	if err != nil || backIdentify != *identify || !back.ObservedAddr.Equal(msg.ObservedAddr) {
+		t.Errorf("mismatch identify, expect %+v, got %+v: %v", identify, backIdentify, err)
+		return
+	}
+}
@@ -0,0 +1,135 @@
+package protocol
+
+import (
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// Molecule fixed sizes
+const (
+	uint32Size = 4
+)
+
+func serializeUint32s(ns []types.Uint32) ([]byte, error) {
+	items := make([][]byte, len(ns))
+	for i := 0; i < len(ns); i++ {
+		n, err := ns[i].Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		items[i] = n
+	}
+
+	return types.SerializeFixVec(items), nil
+}
+
+func deserializeUint32s(data []byte) ([]types.Uint32, error) {
+	items, err := types.DeserializeFixVec(data, uint32Size)
+	if err != nil {
+		return nil, err
+	}
+
+	ns := make([]types.Uint32, len(items))
+	for i := 0; i < len(items); i++ {
+		err = ns[i].Deserialize(items[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ns, nil
+}
+
+// serializeTransactions serialize TransactionVec, witnesses included
+func serializeTransactions(txs []types.Transaction) ([]byte, error) {
+	items := make([][]byte, len(txs))
+	for i := 0; i < len(txs); i++ {
+		tx, err := txs[i].Pack()
+		if err != nil {
+			return nil, err
+		}
+
+		items[i] = tx
+	}
+
+	return types.SerializeDynVec(items), nil
+}
+
+func deserializeTransactions(data []byte) ([]types.Transaction, error) {
+	items, err := types.DeserializeDynVec(data)
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]types.Transaction, len(items))
+	for i := 0; i < len(items); i++ {
+		err = txs[i].Unpack(items[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return txs, nil
+}
+
+func serializeUncles(us []types.UncleBlock) ([]byte, error) {
+	items := make([][]byte, len(us))
+	for i := 0; i < len(us); i++ {
+		u, err := us[i].Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		items[i] = u
+	}
+
+	return types.SerializeDynVec(items), nil
+}
+
+func deserializeUncles(data []byte) ([]types.UncleBlock, error) {
+	items, err := types.DeserializeDynVec(data)
+	if err != nil {
+		return nil, err
+	}
+
+	us := make([]types.UncleBlock, len(items))
+	for i := 0; i < len(items); i++ {
+		err = us[i].Deserialize(items[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return us, nil
+}
+
+func serializeBytesVec(bs []types.Bytes) ([]byte, error) {
+	items := make([][]byte, len(bs))
+	for i := 0; i < len(bs); i++ {
+		b, err := bs[i].Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		items[i] = b
+	}
+
+	return types.SerializeDynVec(items), nil
+}
+
+func deserializeBytesVec(data []byte) ([]types.Bytes, error) {
+	items, err := types.DeserializeDynVec(data)
+	if err != nil {
+		return nil, err
+	}
+
+	bs := make([]types.Bytes, len(items))
+	for i := 0; i < len(items); i++ {
+		err = bs[i].Deserialize(items[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bs, nil
+}
@@ -0,0 +1,309 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Multiaddr protocol codes
+const (
+	codeIP4  = 4
+	codeTCP  = 6
+	codeIP6  = 41
+	codeDNS4 = 54
+	codeDNS6 = 55
+	codeP2P  = 421
+	codeWS   = 477
+	codeWSS  = 478
+)
+
+// Multiaddr node address, as used by net rpc and discovery protocol
+/*
+ * Supported form:
+ *
+ *     /ip4|ip6|dns4|dns6/<host>/tcp/<port>[/ws|/wss][/p2p/<peer id>]
+ *
+ * Either IP or Host is set, depending on whether the address uses dns.
+ */
+type Multiaddr struct {
+	IP     net.IP
+	Host   string
+	DNS    string
+	Port   uint16
+	WS     string
+	PeerID string
+}
+
+// ParseMultiaddr parse multiaddr from string form
+func ParseMultiaddr(s string) (*Multiaddr, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) < 5 || parts[0] != "" {
+		return nil, fmt.Errorf("invalid multiaddr %q, should be /<ip>/<host>/tcp/<port>", s)
+	}
+
+	m := new(Multiaddr)
+
+	switch parts[1] {
+	case "ip4", "ip6":
+		m.IP = net.ParseIP(parts[2])
+		if m.IP == nil || (parts[1] == "ip4") != (m.IP.To4() != nil) {
+			return nil, fmt.Errorf("invalid multiaddr %s address %q", parts[1], parts[2])
+		}
+	case "dns4", "dns6":
+		m.DNS = parts[1]
+		m.Host = parts[2]
+	default:
+		return nil, fmt.Errorf("unsupported multiaddr protocol %q", parts[1])
+	}
+
+	if parts[3] != "tcp" {
+		return nil, fmt.Errorf("unsupported multiaddr protocol %q", parts[3])
+	}
+
+	port, err := strconv.ParseUint(parts[4], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid multiaddr port %q", parts[4])
+	}
+	m.Port = uint16(port)
+
+	rest := parts[5:]
+	if len(rest) > 0 && (rest[0] == "ws" || rest[0] == "wss") {
+		m.WS = rest[0]
+		rest = rest[1:]
+	}
+
+	if len(rest) > 0 {
+		if len(rest) != 2 || rest[0] != "p2p" || rest[1] == "" {
+			return nil, fmt.Errorf("invalid multiaddr %q, unexpected /%s", s, strings.Join(rest, "/"))
+		}
+
+		m.PeerID = rest[1]
+	}
+
+	return m, nil
+}
+
+// String multiaddr string form
+func (m *Multiaddr) String() string {
+	b := new(strings.Builder)
+
+	switch {
+	case m.DNS != "":
+		fmt.Fprintf(b, "/%s/%s", m.DNS, m.Host)
+	case m.IP.To4() != nil:
+		fmt.Fprintf(b, "/ip4/%s", m.IP.To4())
+	default:
+		fmt.Fprintf(b, "/ip6/%s", m.IP)
+	}
+
+	fmt.Fprintf(b, "/tcp/%d", m.Port)
+
+	if m.WS != "" {
+		fmt.Fprintf(b, "/%s", m.WS)
+	}
+
+	if m.PeerID != "" {
+		fmt.Fprintf(b, "/p2p/%s", m.PeerID)
+	}
+
+	return b.String()
+}
+
+// HostPort host and port joined for dialing or firewall rules
+func (m *Multiaddr) HostPort() string {
+	host := m.Host
+	if m.DNS == "" {
+		host = m.IP.String()
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(int(m.Port)))
+}
+
+// Bytes multiaddr binary form, as used by discovery protocol
+func (m *Multiaddr) Bytes() ([]byte, error) {
+	b := new(bytes.Buffer)
+
+	switch {
+	case m.DNS != "":
+		code := uint64(codeDNS4)
+		if m.DNS == "dns6" {
+			code = codeDNS6
+		}
+
+		writeUvarint(b, code)
+		writeUvarint(b, uint64(len(m.Host)))
+		b.WriteString(m.Host)
+	case m.IP.To4() != nil:
+		writeUvarint(b, codeIP4)
+		b.Write(m.IP.To4())
+	case m.IP.To16() != nil:
+		writeUvarint(b, codeIP6)
+		b.Write(m.IP.To16())
+	default:
+		return nil, fmt.Errorf("invalid multiaddr, no ip or host")
+	}
+
+	writeUvarint(b, codeTCP)
+	port := make([]byte, 2)
+	binary.BigEndian.PutUint16(port, m.Port)
+	b.Write(port)
+
+	switch m.WS {
+	case "ws":
+		writeUvarint(b, codeWS)
+	case "wss":
+		writeUvarint(b, codeWSS)
+	}
+
+	if m.PeerID != "" {
+		id, err := base58Decode(m.PeerID)
+		if err != nil {
+			return nil, err
+		}
+
+		writeUvarint(b, codeP2P)
+		writeUvarint(b, uint64(len(id)))
+		b.Write(id)
+	}
+
+	return b.Bytes(), nil
+}
+
+// DecodeMultiaddr decode multiaddr from binary form
+func DecodeMultiaddr(data []byte) (*Multiaddr, error) {
+	r := bytes.NewReader(data)
+	m := new(Multiaddr)
+
+	for r.Len() > 0 {
+		code, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+
+		switch code {
+		case codeIP4, codeIP6:
+			size := net.IPv4len
+			if code == codeIP6 {
+				size = net.IPv6len
+			}
+
+			ip := make([]byte, size)
+			_, err = readFull(r, ip)
+			m.IP = net.IP(ip)
+		case codeDNS4, codeDNS6:
+			m.DNS = "dns4"
+			if code == codeDNS6 {
+				m.DNS = "dns6"
+			}
+
+			var host []byte
+			host, err = readSized(r)
+			m.Host = string(host)
+		case codeTCP:
+			port := make([]byte, 2)
+			_, err = readFull(r, port)
+			m.Port = binary.BigEndian.Uint16(port)
+		case codeWS:
+			m.WS = "ws"
+		case codeWSS:
+			m.WS = "wss"
+		case codeP2P:
+			var id []byte
+			id, err = readSized(r)
+			m.PeerID = base58Encode(id)
+		default:
+			return nil, fmt.Errorf("unsupported multiaddr protocol code %d", code)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if m.IP == nil && m.Host == "" {
+		return nil, fmt.Errorf("invalid multiaddr, no ip or host")
+	}
+
+	return m, nil
+}
+
+func writeUvarint(b *bytes.Buffer, n uint64) {
+	buf := make([]byte, binary.MaxVarintLen64)
+	b.Write(buf[:binary.PutUvarint(buf, n)])
+}
+
+func readFull(r *bytes.Reader, b []byte) (int, error) {
+	if r.Len() < len(b) {
+		return 0, fmt.Errorf("invalid multiaddr, unexpected end")
+	}
+
+	return r.Read(b)
+}
+
+func readSized(r *bytes.Reader) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if uint64(r.Len()) < size {
+		return nil, fmt.Errorf("invalid multiaddr, unexpected end")
+	}
+
+	b := make([]byte, size)
+	_, err = r.Read(b)
+
+	return b, err
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58Encode bitcoin alphabet base58, used by peer id
+func base58Encode(b []byte) string {
+	n := new(big.Int).SetBytes(b)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+
+	for i := 0; i < len(b) && b[i] == 0; i++ {
+		out = append(out, base58Alphabet[0])
+	}
+
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+
+	return string(out)
+}
+
+func base58Decode(s string) ([]byte, error) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+
+	for i := 0; i < len(s); i++ {
+		d := strings.IndexByte(base58Alphabet, s[i])
+		if d < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", s[i])
+		}
+
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(d)))
+	}
+
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
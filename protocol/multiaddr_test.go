@@ -0,0 +1,109 @@
+package protocol
+
+import (
+	"testing"
+)
+
+func TestParseMultiaddr(t *testing.T) {
+	s := "/ip4/192.168.1.8/tcp/8115/p2p/QmaQwBtqbAJppfuZ8eQqJ4WJFp5epik3VeTsrUpR2cJm4G"
+
+	m, err := ParseMultiaddr(s)
+	if err != nil {
+		t.Errorf("fail to parse multiaddr: %s\n", err)
+		return
+	}
+
+	if m.IP.String() != "192.168.1.8" || m.Port != 8115 || m.PeerID != "QmaQwBtqbAJppfuZ8eQqJ4WJFp5epik3VeTsrUpR2cJm4G" {
+		t.Errorf("mismatch multiaddr fields, got %+v", m)
+		return
+	}
+
+	if m.String() != s || m.HostPort() != "192.168.1.8:8115" {
+		t.Errorf("mismatch format, got %v and %v", m.String(), m.HostPort())
+		return
+	}
+
+	b, err := m.Bytes()
+	if err != nil {
+		t.Errorf("fail to encode multiaddr: %s\n", err)
+		return
+	}
+
+	decoded, err := DecodeMultiaddr(b)
+	if err != nil {
+		t.Errorf("fail to decode multiaddr: %s\n", err)
+		return
+	}
+
+	if decoded.String() != s {
+		t.Errorf("mismatch binary round trip, expect %v, got %v", s, decoded.String())
+		return
+	}
+
+	// Peer id is a 34 bytes sha256 multihash
+	if len(b) != 1+4+1+2+2+1+34 {
+		t.Errorf("mismatch binary length, got %v", len(b))
+		return
+	}
+
+	for _, bad := range []string{
+		"/ip4/192.168.1.8",
+		"/ip4/::1/tcp/8115",
+		"/ip4/192.168.1.8/udp/8115",
+		"/ip4/192.168.1.8/tcp/8115/p2p",
+	} {
+		_, err = ParseMultiaddr(bad)
+		if err == nil {
+			t.Errorf("expect error on %v", bad)
+			return
+		}
+	}
+}
+
+func TestParseMultiaddrDNS(t *testing.T) {
+	s := "/dns4/bootnode.example.com/tcp/443/wss"
+
+	m, err := ParseMultiaddr(s)
+	if err != nil {
+		t.Errorf("fail to parse multiaddr: %s\n", err)
+		return
+	}
+
+	if m.String() != s || m.HostPort() != "bootnode.example.com:443" {
+		t.Errorf("mismatch format, got %v and %v", m.String(), m.HostPort())
+		return
+	}
+}
+
+func TestParseMultiaddrIP6(t *testing.T) {
+	s := "/ip6/2001:db8::1/tcp/8115"
+
+	m, err := ParseMultiaddr(s)
+	if err != nil {
+		t.Errorf("fail to parse multiaddr: %s\n", err)
+		return
+	}
+
+	if m.String() != s || m.HostPort() != "[2001:db8::1]:8115" {
+		t.Errorf("mismatch format, got %v and %v", m.String(), m.HostPort())
+		return
+	}
+
+	b, err := m.Bytes()
+	if err != nil {
+		t.Errorf("fail to encode multiaddr: %s\n", err)
+		return
+	}
+
+	d, err := DecodeMultiaddr(b)
+	if err != nil || d.String() != s {
+		t.Errorf("mismatch result, expect %v, got %v %v", s, d, err)
+		return
+	}
+
+	_, err = ParseMultiaddr("/ip6/192.168.1.8/tcp/8115")
+	if err == nil {
+		t.Errorf("expect error on ip4 address in ip6")
+		return
+	}
+}
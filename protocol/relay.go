@@ -0,0 +1,379 @@
+package protocol
+
+import (
+	"fmt"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// Relay message union item ids
+const (
+	CompactBlockID           uint32 = 0
+	RelayTransactionsID      uint32 = 1
+	RelayTransactionHashesID uint32 = 2
+	GetRelayTransactionsID   uint32 = 3
+	GetBlockTransactionsID   uint32 = 4
+	BlockTransactionsID      uint32 = 5
+	GetBlockProposalID       uint32 = 6
+	BlockProposalID          uint32 = 7
+)
+
+// RelayMessageItem item of ckb relay protocol message union
+type RelayMessageItem interface {
+	types.MolSerializer
+	types.MolDeserializer
+	RelayItemID() uint32
+}
+
+// RelayTransaction transaction with its verified cycles
+type RelayTransaction struct {
+	Cycles      types.Uint64
+	Transaction types.Transaction
+}
+
+// RelayTransactions ckb relay message, response to GetRelayTransactions
+type RelayTransactions struct {
+	Transactions []RelayTransaction
+}
+
+// RelayTransactionHashes ckb relay message, announce new transactions
+type RelayTransactionHashes struct {
+	TxHashes []types.Hash
+}
+
+// GetRelayTransactions ckb relay message, request announced transactions
+type GetRelayTransactions struct {
+	TxHashes []types.Hash
+}
+
+// GetBlockTransactions ckb relay message, request transactions and
+// uncles missing from compact block
+type GetBlockTransactions struct {
+	BlockHash    types.Hash
+	Indexes      []types.Uint32
+	UncleIndexes []types.Uint32
+}
+
+// BlockTransactions ckb relay message, response to GetBlockTransactions
+type BlockTransactions struct {
+	BlockHash    types.Hash
+	Transactions []types.Transaction
+	Uncles       []types.UncleBlock
+}
+
+// GetBlockProposal ckb relay message, request proposed transactions
+type GetBlockProposal struct {
+	BlockHash types.Hash
+	Proposals []types.ProposalShortID
+}
+
+// BlockProposal ckb relay message, response to GetBlockProposal
+type BlockProposal struct {
+	Transactions []types.Transaction
+}
+
+// RelayItemID union item id
+func (m *CompactBlock) RelayItemID() uint32 { return CompactBlockID }
+
+// RelayItemID union item id
+func (m *RelayTransactions) RelayItemID() uint32 { return RelayTransactionsID }
+
+// RelayItemID union item id
+func (m *RelayTransactionHashes) RelayItemID() uint32 { return RelayTransactionHashesID }
+
+// RelayItemID union item id
+func (m *GetRelayTransactions) RelayItemID() uint32 { return GetRelayTransactionsID }
+
+// RelayItemID union item id
+func (m *GetBlockTransactions) RelayItemID() uint32 { return GetBlockTransactionsID }
+
+// RelayItemID union item id
+func (m *BlockTransactions) RelayItemID() uint32 { return BlockTransactionsID }
+
+// RelayItemID union item id
+func (m *GetBlockProposal) RelayItemID() uint32 { return GetBlockProposalID }
+
+// RelayItemID union item id
+func (m *BlockProposal) RelayItemID() uint32 { return BlockProposalID }
+
+// SerializeRelayMessage serialize item into relay message union
+func SerializeRelayMessage(item RelayMessageItem) ([]byte, error) {
+	b, err := item.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return types.SerializeUnion(item.RelayItemID(), b), nil
+}
+
+// DeserializeRelayMessage deserialize relay message union into its item
+func DeserializeRelayMessage(data []byte) (RelayMessageItem, error) {
+	id, inner, err := types.DeserializeUnion(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var item RelayMessageItem
+	switch id {
+	case CompactBlockID:
+		item = new(CompactBlock)
+	case RelayTransactionsID:
+		item = new(RelayTransactions)
+	case RelayTransactionHashesID:
+		item = new(RelayTransactionHashes)
+	case GetRelayTransactionsID:
+		item = new(GetRelayTransactions)
+	case GetBlockTransactionsID:
+		item = new(GetBlockTransactions)
+	case BlockTransactionsID:
+		item = new(BlockTransactions)
+	case GetBlockProposalID:
+		item = new(GetBlockProposal)
+	case BlockProposalID:
+		item = new(BlockProposal)
+	default:
+		return nil, fmt.Errorf("unknown relay message item id %d", id)
+	}
+
+	err = item.Deserialize(inner)
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// Serialize relay transaction
+func (m *RelayTransaction) Serialize() ([]byte, error) {
+	c, err := m.Cycles.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := m.Transaction.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	return types.SerializeTable([][]byte{c, tx}), nil
+}
+
+// Deserialize relay transaction
+func (m *RelayTransaction) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 2)
+	if err != nil {
+		return err
+	}
+
+	err = m.Cycles.Deserialize(fields[0])
+	if err != nil {
+		return err
+	}
+
+	return m.Transaction.Unpack(fields[1])
+}
+
+// Serialize relay transactions
+func (m *RelayTransactions) Serialize() ([]byte, error) {
+	txs := make([][]byte, len(m.Transactions))
+	for i := 0; i < len(m.Transactions); i++ {
+		tx, err := m.Transactions[i].Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		txs[i] = tx
+	}
+
+	return types.SerializeTable([][]byte{types.SerializeDynVec(txs)}), nil
+}
+
+// Deserialize relay transactions
+func (m *RelayTransactions) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 1)
+	if err != nil {
+		return err
+	}
+
+	txs, err := types.DeserializeDynVec(fields[0])
+	if err != nil {
+		return err
+	}
+
+	m.Transactions = make([]RelayTransaction, len(txs))
+	for i := 0; i < len(txs); i++ {
+		err = m.Transactions[i].Deserialize(txs[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Serialize relay transaction hashes
+func (m *RelayTransactionHashes) Serialize() ([]byte, error) {
+	hs := types.SerializeByte32Vec(m.TxHashes)
+
+	return types.SerializeTable([][]byte{hs}), nil
+}
+
+// Deserialize relay transaction hashes
+func (m *RelayTransactionHashes) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 1)
+	if err != nil {
+		return err
+	}
+
+	m.TxHashes, err = types.DeserializeByte32Vec(fields[0])
+	return err
+}
+
+// Serialize get relay transactions
+func (m *GetRelayTransactions) Serialize() ([]byte, error) {
+	hs := types.SerializeByte32Vec(m.TxHashes)
+
+	return types.SerializeTable([][]byte{hs}), nil
+}
+
+// Deserialize get relay transactions
+func (m *GetRelayTransactions) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 1)
+	if err != nil {
+		return err
+	}
+
+	m.TxHashes, err = types.DeserializeByte32Vec(fields[0])
+	return err
+}
+
+// Serialize get block transactions
+func (m *GetBlockTransactions) Serialize() ([]byte, error) {
+	h, err := m.BlockHash.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	is, err := serializeUint32s(m.Indexes)
+	if err != nil {
+		return nil, err
+	}
+
+	us, err := serializeUint32s(m.UncleIndexes)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.SerializeTable([][]byte{h, is, us}), nil
+}
+
+// Deserialize get block transactions
+func (m *GetBlockTransactions) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 3)
+	if err != nil {
+		return err
+	}
+
+	err = m.BlockHash.Deserialize(fields[0])
+	if err != nil {
+		return err
+	}
+
+	m.Indexes, err = deserializeUint32s(fields[1])
+	if err != nil {
+		return err
+	}
+
+	m.UncleIndexes, err = deserializeUint32s(fields[2])
+	return err
+}
+
+// Serialize block transactions
+func (m *BlockTransactions) Serialize() ([]byte, error) {
+	h, err := m.BlockHash.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	txs, err := serializeTransactions(m.Transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	us, err := serializeUncles(m.Uncles)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.SerializeTable([][]byte{h, txs, us}), nil
+}
+
+// Deserialize block transactions
+func (m *BlockTransactions) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 3)
+	if err != nil {
+		return err
+	}
+
+	err = m.BlockHash.Deserialize(fields[0])
+	if err != nil {
+		return err
+	}
+
+	m.Transactions, err = deserializeTransactions(fields[1])
+	if err != nil {
+		return err
+	}
+
+	m.Uncles, err = deserializeUncles(fields[2])
+	return err
+}
+
+// Serialize get block proposal
+func (m *GetBlockProposal) Serialize() ([]byte, error) {
+	h, err := m.BlockHash.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	ps := types.SerializeProposalShortIDVec(m.Proposals)
+
+	return types.SerializeTable([][]byte{h, ps}), nil
+}
+
+// Deserialize get block proposal
+func (m *GetBlockProposal) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 2)
+	if err != nil {
+		return err
+	}
+
+	err = m.BlockHash.Deserialize(fields[0])
+	if err != nil {
+		return err
+	}
+
+	m.Proposals, err = types.DeserializeProposalShortIDVec(fields[1])
+	return err
+}
+
+// Serialize block proposal
+func (m *BlockProposal) Serialize() ([]byte, error) {
+	txs, err := serializeTransactions(m.Transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.SerializeTable([][]byte{txs}), nil
+}
+
+// Deserialize block proposal
+func (m *BlockProposal) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 1)
+	if err != nil {
+		return err
+	}
+
+	m.Transactions, err = deserializeTransactions(fields[0])
+	return err
+}
@@ -0,0 +1,231 @@
+package protocol
+
+import (
+	"fmt"
+
+	"github.com/zeroqn/ckb-types-go/jsonrpc/types"
+)
+
+// Sync message union item ids
+const (
+	GetHeadersID  uint32 = 0
+	SendHeadersID uint32 = 1
+	GetBlocksID   uint32 = 2
+	SendBlockID   uint32 = 3
+	InIBDID       uint32 = 8
+)
+
+// headerSize molecule Header size
+const headerSize = 208
+
+// SyncMessageItem item of ckb sync protocol message union
+type SyncMessageItem interface {
+	types.MolSerializer
+	types.MolDeserializer
+	SyncItemID() uint32
+}
+
+// GetHeaders ckb sync message, request headers after locator hashes
+type GetHeaders struct {
+	HashType           types.Uint32
+	BlockLocatorHashes []types.Hash
+	HashStop           types.Hash
+}
+
+// SendHeaders ckb sync message, response to GetHeaders
+type SendHeaders struct {
+	Headers []types.Header
+}
+
+// GetBlocks ckb sync message, request blocks by hash
+type GetBlocks struct {
+	BlockHashes []types.Hash
+}
+
+// SendBlock ckb sync message, response to GetBlocks
+type SendBlock struct {
+	Block types.Block
+}
+
+// InIBD ckb sync message, tell peer that we are in initial block download
+type InIBD struct{}
+
+// SyncItemID union item id
+func (m *GetHeaders) SyncItemID() uint32 { return GetHeadersID }
+
+// SyncItemID union item id
+func (m *SendHeaders) SyncItemID() uint32 { return SendHeadersID }
+
+// SyncItemID union item id
+func (m *GetBlocks) SyncItemID() uint32 { return GetBlocksID }
+
+// SyncItemID union item id
+func (m *SendBlock) SyncItemID() uint32 { return SendBlockID }
+
+// SyncItemID union item id
+func (m *InIBD) SyncItemID() uint32 { return InIBDID }
+
+// SerializeSyncMessage serialize item into sync message union
+func SerializeSyncMessage(item SyncMessageItem) ([]byte, error) {
+	b, err := item.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return types.SerializeUnion(item.SyncItemID(), b), nil
+}
+
+// DeserializeSyncMessage deserialize sync message union into its item
+func DeserializeSyncMessage(data []byte) (SyncMessageItem, error) {
+	id, inner, err := types.DeserializeUnion(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var item SyncMessageItem
+	switch id {
+	case GetHeadersID:
+		item = new(GetHeaders)
+	case SendHeadersID:
+		item = new(SendHeaders)
+	case GetBlocksID:
+		item = new(GetBlocks)
+	case SendBlockID:
+		item = new(SendBlock)
+	case InIBDID:
+		item = new(InIBD)
+	default:
+		return nil, fmt.Errorf("unknown sync message item id %d", id)
+	}
+
+	err = item.Deserialize(inner)
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// Serialize get headers
+func (m *GetHeaders) Serialize() ([]byte, error) {
+	t, err := m.HashType.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	ls := types.SerializeByte32Vec(m.BlockLocatorHashes)
+
+	s, err := m.HashStop.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return types.SerializeTable([][]byte{t, ls, s}), nil
+}
+
+// Deserialize get headers
+func (m *GetHeaders) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 3)
+	if err != nil {
+		return err
+	}
+
+	err = m.HashType.Deserialize(fields[0])
+	if err != nil {
+		return err
+	}
+
+	m.BlockLocatorHashes, err = types.DeserializeByte32Vec(fields[1])
+	if err != nil {
+		return err
+	}
+
+	return m.HashStop.Deserialize(fields[2])
+}
+
+// Serialize send headers
+func (m *SendHeaders) Serialize() ([]byte, error) {
+	hs := make([][]byte, len(m.Headers))
+	for i := 0; i < len(m.Headers); i++ {
+		h, err := m.Headers[i].Serialize()
+		if err != nil {
+			return nil, err
+		}
+
+		hs[i] = h
+	}
+
+	return types.SerializeTable([][]byte{types.SerializeFixVec(hs)}), nil
+}
+
+// Deserialize send headers
+func (m *SendHeaders) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 1)
+	if err != nil {
+		return err
+	}
+
+	hs, err := types.DeserializeFixVec(fields[0], headerSize)
+	if err != nil {
+		return err
+	}
+
+	m.Headers = make([]types.Header, len(hs))
+	for i := 0; i < len(hs); i++ {
+		err = m.Headers[i].Deserialize(hs[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Serialize get blocks
+func (m *GetBlocks) Serialize() ([]byte, error) {
+	hs := types.SerializeByte32Vec(m.BlockHashes)
+
+	return types.SerializeTable([][]byte{hs}), nil
+}
+
+// Deserialize get blocks
+func (m *GetBlocks) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 1)
+	if err != nil {
+		return err
+	}
+
+	m.BlockHashes, err = types.DeserializeByte32Vec(fields[0])
+	return err
+}
+
+// Serialize send block
+func (m *SendBlock) Serialize() ([]byte, error) {
+	b, err := m.Block.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return types.SerializeTable([][]byte{b}), nil
+}
+
+// Deserialize send block
+func (m *SendBlock) Deserialize(data []byte) error {
+	fields, err := types.DeserializeTable(data, 1)
+	if err != nil {
+		return err
+	}
+
+	return m.Block.Deserialize(fields[0])
+}
+
+// Serialize in ibd, empty table
+func (m *InIBD) Serialize() ([]byte, error) {
+	return types.SerializeTable(nil), nil
+}
+
+// Deserialize in ibd, empty table
+func (m *InIBD) Deserialize(data []byte) error {
+	_, err := types.DeserializeTable(data, 0)
+	return err
+}